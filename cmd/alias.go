@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/visionik/fizz/internal/alias"
+	"github.com/visionik/fizz/internal/config"
+)
+
+// aliasAnnotation marks the commands made from config aliases
+const aliasAnnotation = "fizz:alias"
+
+// aliasDepth counts the aliases running inside one another
+var aliasDepth int
+
+// loadAliases replaces the alias commands with those in the config file.
+// Aliases named after built-in commands are skipped with a warning.
+func loadAliases(errOut io.Writer) {
+	for _, c := range rootCmd.Commands() {
+		if c.Annotations[aliasAnnotation] != "" {
+			rootCmd.RemoveCommand(c)
+		}
+	}
+
+	settings, err := config.LoadSettings()
+	if err != nil {
+		fmt.Fprintf(errOut, "Warning: aliases not loaded: %v\n", err)
+		return
+	}
+	names := make([]string, 0, len(settings.Aliases))
+	for name := range settings.Aliases {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		switch {
+		case strings.ContainsAny(name, " \t") || strings.HasPrefix(name, "-"):
+			fmt.Fprintf(errOut, "Warning: alias %q ignored: not a valid command name\n", name)
+		case isBuiltin(name):
+			fmt.Fprintf(errOut, "Warning: alias %q ignored: it would shadow the built-in command\n", name)
+		default:
+			rootCmd.AddCommand(newAliasCmd(name, settings.Aliases[name].Steps))
+		}
+	}
+}
+
+// newAliasCmd makes the command for an alias. Its arguments are passed
+// through untouched, so the commands it runs parse their own flags.
+func newAliasCmd(name string, steps []string) *cobra.Command {
+	return &cobra.Command{
+		Use:                name + " [args...]",
+		Short:              alias.Describe(steps),
+		DisableFlagParsing: true,
+		// Each step reports its own errors
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		Annotations:   map[string]string{noClientAnnotation: "true", aliasAnnotation: "true"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out, errOut := cmd.OutOrStdout(), cmd.ErrOrStderr()
+			global, rest, _ := splitRootFlags(args)
+			commands, err := alias.Expand(name, steps, rest)
+			if err != nil {
+				fmt.Fprintf(errOut, "Error: %v\n", err)
+				return err
+			}
+			if aliasDepth >= alias.MaxDepth {
+				err := fmt.Errorf("alias %s runs too many aliases inside one another", name)
+				fmt.Fprintf(errOut, "Error: %v\n", err)
+				return err
+			}
+			aliasDepth++
+			defer func() { aliasDepth-- }()
+
+			for i, command := range commands {
+				err := Run(cmd.Context(), append(append([]string{}, global...), command...), out, errOut)
+				if err == nil {
+					continue
+				}
+				if len(commands) > 1 {
+					fmt.Fprintf(errOut, "Error: %s stopped at step %d: %s\n", name, i+1, strings.Join(command, " "))
+				}
+				return err
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/visionik/libfizz-go/fizzy"
+)
+
+func TestAliases(t *testing.T) {
+	f := newFizzTest(t)
+	fx := f.seed()
+	dir := isolateConfig(t)
+	writeConfig(t, dir, `aliases:
+  titles: cards list --board=`+fx.BoardID+` --format=json
+  ship:
+    - cards move $1 --column=`+fx.ColumnID+`
+    - cards close $1
+    - 'comments create $1 --body="Shipped #$1"'
+  broken:
+    - cards close 999
+    - cards close 2
+  boards: cards list
+  loop: loop
+`)
+	// A project file adds to the user's aliases
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "work", ".fizz.yaml"), []byte("aliases:\n  c: cards show\n"), 0o644))
+
+	help, errOut, err := f.runStderr("--help")
+	require.NoError(t, err)
+	assert.Contains(t, help, "Alias for: cards list --board=")
+	assert.Contains(t, help, "Macro: cards move $1")
+	assert.Equal(t, "Warning: alias \"boards\" ignored: it would shadow the built-in command\n", errOut)
+
+	// Arguments after a single command are passed on
+	assert.Contains(t, f.mustRun("titles", "--status=published"), "Upgrade the database cluster")
+	assert.Contains(t, f.mustRun("c", "2"), "Login fails with SSO")
+	assert.Contains(t, f.mustRun("boards", "list"), "Platform")
+
+	f.mustRun("ship", "1")
+	cards := "/" + f.srv.Account + "/cards/"
+	requests := f.srv.Requests()
+	assert.Contains(t, requests, "POST "+cards+"1/column")
+	assert.Contains(t, requests, "POST "+cards+"1/closure")
+	assert.Contains(t, requests, "POST "+cards+"1/comments")
+	assert.Contains(t, f.mustRun("comments", "list", "1"), "Shipped #1")
+
+	_, errOut, err = f.runStderr("ship")
+	require.Error(t, err)
+	assert.Contains(t, errOut, "Error: alias ship needs 1 argument(s), got 0\n", errOut)
+
+	_, errOut, err = f.runStderr("broken")
+	require.Error(t, err)
+	assert.Contains(t, errOut, "Error: broken stopped at step 1: cards close 999\n")
+	assert.NotContains(t, f.srv.Requests(), "POST "+cards+"2/closure")
+
+	_, errOut, err = f.runStderr("loop")
+	require.Error(t, err)
+	assert.Contains(t, errOut, "Error: alias loop runs too many aliases inside one another\n")
+}
+
+func TestAliasBeatsPlugin(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("test plugins are shell scripts")
+	}
+	f := newFizzTest(t)
+	f.seed()
+	dir := isolateConfig(t)
+	writeConfig(t, dir, "aliases:\n  hello: cards show 2\n")
+	bin := t.TempDir()
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+	writeScript(t, filepath.Join(bin, "fizz-hello"), "echo plugin\n")
+
+	assert.Contains(t, f.mustRun("hello"), "Login fails with SSO")
+	assert.Contains(t, f.mustRun("plugin", "list"), "shadowed by alias")
+}
+
+// TestReadmeShipMacro runs the ship macro from the README, moving by column
+// name with no WIP limits configured
+func TestReadmeShipMacro(t *testing.T) {
+	f := newFizzTest(t)
+	fx := f.seed()
+	dir := isolateConfig(t)
+	writeConfig(t, dir, `aliases:
+  todo: cards list --status=published
+  ship:
+    - cards move $1 --column=Done
+    - cards close $1
+    - 'comments create $1 --body="Shipped in #$2"'
+`)
+
+	f.mustRun("ship", "2", "1.8")
+
+	var columns []fizzy.Column
+	require.NoError(t, json.Unmarshal([]byte(f.mustRun("columns", "list", fx.BoardID, "--format=json")), &columns))
+	var card fizzy.Card
+	require.NoError(t, json.Unmarshal([]byte(f.mustRun("cards", "get", "2", "--format=json")), &card))
+	require.NotNil(t, card.ColumnID)
+	assert.Equal(t, "Done", columns[slices.IndexFunc(columns, func(c fizzy.Column) bool { return c.ID == *card.ColumnID })].Name)
+	assert.True(t, card.Closed)
+	assert.Contains(t, f.mustRun("comments", "list", "2"), "Shipped in #1.8")
+}
@@ -0,0 +1,296 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/visionik/fizz/internal/attachment"
+	"github.com/visionik/fizz/internal/client"
+	"github.com/visionik/fizz/internal/config"
+	"github.com/visionik/fizz/internal/format"
+	"github.com/visionik/fizz/internal/progress"
+	"github.com/visionik/libfizz-go/fizzy"
+)
+
+var cardsAttachCmd = &cobra.Command{
+	Use:   "attach <card-id-or-number> <file>...",
+	Short: "Attach files to a card",
+	Long: `Upload files and add them to the end of a card's description. A file
+named - is read from stdin and needs --name.`,
+	Args: cobra.MinimumNArgs(2),
+	Example: `  fizz cards attach 123 screenshot.png
+  fizz cards attach 123 design.pdf notes.txt
+  xclip -selection clipboard -t image/png -o | fizz cards attach 123 - --name=clipboard.png`,
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return completeCardArg(cmd, args, toComplete)
+		}
+		return nil, cobra.ShellCompDirectiveDefault
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c := GetClient()
+
+		cardID, err := c.ResolveCardID(cmd.Context(), args[0])
+		if err != nil {
+			return err
+		}
+		card, err := c.Cards.Get(cmd.Context(), cardID)
+		if err != nil {
+			return fmt.Errorf("failed to get card: %w", err)
+		}
+
+		// The API returns no source for the description, only plain text and
+		// the rendered HTML. The HTML goes back: its attachment tags keep
+		// their sgids, so earlier attachments survive.
+		var body string
+		if card.DescriptionHTML != nil {
+			body = *card.DescriptionHTML
+		} else if card.Description != nil {
+			body = *card.Description
+		}
+		if body, err = attachFiles(cmd, c, body, args[1:]); err != nil {
+			return err
+		}
+
+		card, err = c.Cards.Update(cmd.Context(), cardID, &fizzy.CardUpdateOptions{Body: &body})
+		if err != nil {
+			return fmt.Errorf("failed to update card: %w", err)
+		}
+
+		formatter, err := format.NewFormatter(GetFormat(), cmd.OutOrStdout())
+		if err != nil {
+			return err
+		}
+
+		return formatter.Format(card)
+	},
+}
+
+var cardsAttachmentsCmd = &cobra.Command{
+	Use:   "attachments <card-id-or-number>",
+	Short: "List the files attached to a card and its comments",
+	Args:  cobra.ExactArgs(1),
+	Example: `  fizz cards attachments 123
+  fizz cards attachments 123 --format=json`,
+	ValidArgsFunction: completeCardArg,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		list, err := cardAttachments(cmd.Context(), GetClient(), args[0])
+		if err != nil {
+			return err
+		}
+
+		formatter, err := format.NewFormatter(GetFormat(), cmd.OutOrStdout())
+		if err != nil {
+			return err
+		}
+		if GetFormat() == "table" {
+			if len(list) == 0 {
+				fmt.Fprintln(cmd.OutOrStdout(), "No attachments found")
+				return nil
+			}
+			return formatter.Format(format.ToAttachmentDisplaySlice(list))
+		}
+		return formatter.Format(list)
+	},
+}
+
+var cardsDownloadCmd = &cobra.Command{
+	Use:   "download <card-id-or-number>",
+	Short: "Download the files attached to a card and its comments",
+	Long: `Download every file attached to a card's description and comments into a
+directory. An interrupted download is resumed from its .part file on the
+next run, files already downloaded are skipped, and each download is checked
+against the Content-MD5 header, or the ETag of known object storage such as
+S3. Downloads without either are reported as unverified.`,
+	Args: cobra.ExactArgs(1),
+	Example: `  fizz cards download 123
+  fizz cards download 123 --dir=./evidence/123`,
+	ValidArgsFunction: completeCardArg,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c := GetClient()
+		ctx := cmd.Context()
+
+		list, err := cardAttachments(ctx, c, args[0])
+		if err != nil {
+			return err
+		}
+		if len(list) == 0 {
+			fmt.Fprintln(cmd.ErrOrStderr(), "No attachments found")
+			return nil
+		}
+		dir, _ := cmd.Flags().GetString("dir")
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("failed to create %s: %w", dir, err)
+		}
+
+		d := &attachment.Downloader{Token: c.Token, BaseURL: c.BaseURL}
+		if d.BaseURL == "" {
+			d.BaseURL = config.DefaultBaseURL
+		}
+		results := make([]attachment.Result, 0, len(list))
+		for i, path := range downloadPaths(dir, list) {
+			a := list[i]
+			var report func(int64)
+			var bar *progress.Bar
+			if a.Size >= progress.MinSize && progress.Enabled(cmd.ErrOrStderr()) {
+				bar = progress.New(cmd.ErrOrStderr(), a.Name, a.Size)
+				report = bar.Set
+			}
+			result, err := d.Download(ctx, a, path, report)
+			if bar != nil {
+				bar.Finish()
+			}
+			if err != nil {
+				return err
+			}
+			results = append(results, result)
+		}
+
+		formatter, err := format.NewFormatter(GetFormat(), cmd.OutOrStdout())
+		if err != nil {
+			return err
+		}
+		if GetFormat() == "table" {
+			return formatter.Format(format.ToDownloadDisplaySlice(results))
+		}
+		return formatter.Format(results)
+	},
+}
+
+// cardAttachments returns the files attached to a card's description and
+// then to its comments
+func cardAttachments(ctx context.Context, c *client.Client, ref string) ([]attachment.Attachment, error) {
+	cardID, err := c.ResolveCardID(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	card, err := c.Cards.Get(ctx, cardID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get card: %w", err)
+	}
+	comments, err := c.Comments.List(ctx, cardID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list comments: %w", err)
+	}
+
+	list := []attachment.Attachment{}
+	if card.DescriptionHTML != nil {
+		list = append(list, attachment.Parse(*card.DescriptionHTML, "description")...)
+	}
+	for _, comment := range comments {
+		list = append(list, attachment.Parse(comment.HTML, "comment "+comment.ID)...)
+	}
+	return list, nil
+}
+
+// downloadPaths picks a file in dir for each attachment, numbering names
+// that repeat
+func downloadPaths(dir string, list []attachment.Attachment) []string {
+	paths := make([]string, len(list))
+	used := map[string]bool{}
+	for i, a := range list {
+		name := attachment.SafeName(a.Name)
+		ext := filepath.Ext(name)
+		for n := 2; used[name]; n++ {
+			name = fmt.Sprintf("%s-%d%s", strings.TrimSuffix(attachment.SafeName(a.Name), ext), n, ext)
+		}
+		used[name] = true
+		paths[i] = filepath.Join(dir, name)
+	}
+	return paths
+}
+
+// attachFiles uploads files and returns body with them embedded at the end.
+// A file named - is read from stdin under the --name flag's name, and large
+// files show a progress bar on a terminal.
+func attachFiles(cmd *cobra.Command, c *client.Client, body string, files []string) (string, error) {
+	var attachments []client.Attachment
+	stdinRead := false
+	for _, file := range files {
+		path := file
+		if file == "-" {
+			if stdinRead {
+				return "", fmt.Errorf("only one file can be read from stdin")
+			}
+			stdinRead = true
+			var cleanup func()
+			var err error
+			path, cleanup, err = stdinFile(cmd)
+			if err != nil {
+				return "", err
+			}
+			defer cleanup()
+		}
+
+		info, err := os.Stat(path)
+		if err != nil {
+			return "", fmt.Errorf("failed to read %s: %w", file, err)
+		}
+		if info.IsDir() {
+			return "", fmt.Errorf("%s is a directory", file)
+		}
+
+		ctx := cmd.Context()
+		var bar *progress.Bar
+		if info.Size() >= progress.MinSize && progress.Enabled(cmd.ErrOrStderr()) {
+			bar = progress.New(cmd.ErrOrStderr(), filepath.Base(path), info.Size())
+			ctx = client.WithUploadProgress(ctx, bar.Set)
+		}
+		attachment, err := c.Upload(ctx, path)
+		if bar != nil {
+			bar.Finish()
+		}
+		if err != nil {
+			return "", err
+		}
+		attachments = append(attachments, attachment)
+	}
+	return client.WithAttachments(body, attachments), nil
+}
+
+// stdinFile copies stdin to a temporary file named by --name, since uploads
+// need the size and checksum before sending
+func stdinFile(cmd *cobra.Command) (string, func(), error) {
+	name, _ := cmd.Flags().GetString("name")
+	if name == "" {
+		return "", nil, fmt.Errorf("--name is required when reading a file from stdin")
+	}
+	if filepath.Base(name) != name {
+		return "", nil, fmt.Errorf("--name must be a file name, not a path")
+	}
+
+	dir, err := os.MkdirTemp("", "fizz-attach-*")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to read stdin: %w", err)
+	}
+	cleanup := func() { os.RemoveAll(dir) }
+	path := filepath.Join(dir, name)
+	f, err := os.Create(path)
+	if err != nil {
+		cleanup()
+		return "", nil, fmt.Errorf("failed to read stdin: %w", err)
+	}
+	_, err = io.Copy(f, cmd.InOrStdin())
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		cleanup()
+		return "", nil, fmt.Errorf("failed to read stdin: %w", err)
+	}
+	return path, cleanup, nil
+}
+
+func init() {
+	cardsAttachCmd.Flags().String("name", "", "File name for a file read from stdin")
+	cardsDownloadCmd.Flags().String("dir", ".", "Directory to save files in")
+
+	cardsCmd.AddCommand(cardsAttachCmd)
+	cardsCmd.AddCommand(cardsAttachmentsCmd)
+	cardsCmd.AddCommand(cardsDownloadCmd)
+}
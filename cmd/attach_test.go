@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/visionik/fizz/internal/attachment"
+	"github.com/visionik/libfizz-go/fizzy"
+)
+
+// blobIDs returns the uploads a rich text body embeds
+func blobIDs(body string) []string {
+	var ids []string
+	for _, m := range regexp.MustCompile(`sgid="([^"]+)"`).FindAllStringSubmatch(body, -1) {
+		ids = append(ids, m[1])
+	}
+	return ids
+}
+
+func TestCardsAttach(t *testing.T) {
+	f := newFizzTest(t)
+	fx := f.seed()
+	dir := t.TempDir()
+	shot := filepath.Join(dir, "shot.png")
+	require.NoError(t, os.WriteFile(shot, []byte("\x89PNG\r\n\x1a\nimage"), 0o644))
+	notes := filepath.Join(dir, "notes")
+	require.NoError(t, os.WriteFile(notes, []byte("plain notes\n"), 0o644))
+
+	var card fizzy.Card
+	out := f.mustRun("cards", "attach", fx.CardNumber, shot, notes, "--format=json")
+	require.NoError(t, json.Unmarshal([]byte(out), &card))
+	body := *card.DescriptionHTML
+	assert.True(t, strings.HasPrefix(body, "<p>Move to the new major version.</p><p>Schedule a maintenance window first.</p>"), body)
+	assert.Regexp(t, `content-type="image/png" url="[^"]+" filename="shot.png"`, body)
+	assert.Regexp(t, `content-type="text/plain; charset=utf-8" url="[^"]+" filename="notes"`, body)
+
+	ids := blobIDs(body)
+	require.Len(t, ids, 2)
+	upload, ok := f.srv.Upload(ids[1])
+	require.True(t, ok)
+	assert.Equal(t, "plain notes\n", string(upload.Data))
+
+	// A second attachment keeps the first
+	rootCmd.SetIn(strings.NewReader("from stdin"))
+	t.Cleanup(func() { rootCmd.SetIn(nil) })
+	out = f.mustRun("cards", "attach", fx.CardNumber, "-", "--name=paste.txt", "--format=json")
+	require.NoError(t, json.Unmarshal([]byte(out), &card))
+	first := ids
+	ids = blobIDs(*card.DescriptionHTML)
+	require.Len(t, ids, 3)
+	assert.Equal(t, first, ids[:2], "the rendered description keeps the earlier attachments' sgids")
+	assert.True(t, strings.HasPrefix(*card.DescriptionHTML, "<p>Move to the new major version.</p>"), *card.DescriptionHTML)
+	upload, _ = f.srv.Upload(ids[2])
+	assert.Equal(t, "paste.txt", upload.Filename)
+	assert.Equal(t, "from stdin", string(upload.Data))
+
+	_, err := f.run("cards", "attach", fx.CardNumber, "-")
+	assert.EqualError(t, err, "--name is required when reading a file from stdin")
+	_, err = f.run("cards", "attach", fx.CardNumber, filepath.Join(dir, "missing.png"))
+	assert.ErrorContains(t, err, "failed to read")
+}
+
+func TestCommentsCreateAttach(t *testing.T) {
+	f := newFizzTest(t)
+	fx := f.seed()
+	log := filepath.Join(t.TempDir(), "crash.log")
+	require.NoError(t, os.WriteFile(log, []byte("panic: oops\n"), 0o644))
+
+	var comment fizzy.Comment
+	out := f.mustRun("comments", "create", fx.CardNumber, "--body=Repro & trace:\nsee log", "--attach="+log, "--format=json")
+	require.NoError(t, json.Unmarshal([]byte(out), &comment))
+	assert.Regexp(t, `^<p>Repro &amp; trace:<br>see log</p><action-text-attachment sgid="[^"]+" content-type="[^"]+" url="[^"]+" filename="crash.log" filesize="12"></action-text-attachment>$`, comment.HTML)
+
+	// Attachments alone make a comment
+	f.mustRun("comments", "create", fx.CardNumber, "--attach="+log)
+
+	// Only one of the body and a file can come from stdin
+	rootCmd.SetIn(strings.NewReader("from stdin"))
+	t.Cleanup(func() { rootCmd.SetIn(nil) })
+	_, err := f.run("comments", "create", fx.CardNumber, "--body=-", "--attach=-", "--name=paste.txt")
+	assert.EqualError(t, err, "--body=- and --attach=- cannot both read stdin")
+	out = f.mustRun("comments", "create", fx.CardNumber, "--body=-", "--attach="+log, "--format=json")
+	require.NoError(t, json.Unmarshal([]byte(out), &comment))
+	assert.True(t, strings.HasPrefix(comment.HTML, "<p>from stdin</p>"), comment.HTML)
+}
+
+func TestCardsDownload(t *testing.T) {
+	f := newFizzTest(t)
+	fx := f.seed()
+	dir := t.TempDir()
+	for name, data := range map[string]string{"shot.png": "\x89PNG\r\n\x1a\nimage", "log.txt": "first"} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(data), 0o644))
+	}
+	f.mustRun("cards", "attach", fx.CardNumber, filepath.Join(dir, "shot.png"))
+	f.mustRun("comments", "create", fx.CardNumber, "--attach="+filepath.Join(dir, "log.txt"))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "log.txt"), []byte("second"), 0o644))
+	f.mustRun("comments", "create", fx.CardNumber, "--body=Again", "--attach="+filepath.Join(dir, "log.txt"))
+
+	var list []attachment.Attachment
+	require.NoError(t, json.Unmarshal([]byte(f.mustRun("cards", "attachments", fx.CardNumber, "--format=json")), &list))
+	require.Len(t, list, 3)
+	assert.Equal(t, "shot.png", list[0].Name)
+	assert.Equal(t, int64(13), list[0].Size)
+	assert.Equal(t, "description", list[0].Source)
+	assert.True(t, strings.HasPrefix(list[1].Source, "comment "))
+	assert.Contains(t, f.mustRun("cards", "attachments", fx.CardNumber), "image/png")
+	assert.Equal(t, "No attachments found\n", f.mustRun("cards", "attachments", "2"))
+
+	out := filepath.Join(t.TempDir(), "out")
+	var results []attachment.Result
+	require.NoError(t, json.Unmarshal([]byte(f.mustRun("cards", "download", fx.CardNumber, "--dir="+out, "--format=json")), &results))
+	require.Len(t, results, 3)
+	for _, r := range results {
+		assert.Equal(t, attachment.StatusDownloaded, r.Status)
+		assert.Equal(t, attachment.ChecksumVerified, r.Checksum)
+	}
+	for name, data := range map[string]string{"shot.png": "\x89PNG\r\n\x1a\nimage", "log.txt": "first", "log-2.txt": "second"} {
+		got, err := os.ReadFile(filepath.Join(out, name))
+		require.NoError(t, err)
+		assert.Equal(t, data, string(got))
+	}
+
+	// Running again skips what is already there
+	table := f.mustRun("cards", "download", fx.CardNumber, "--dir="+out)
+	assert.Equal(t, 3, strings.Count(table, "skipped"))
+}
@@ -57,6 +57,7 @@ var boardsGetCmd = &cobra.Command{
 	Args:  cobra.ExactArgs(1),
 	Example: `  fizz boards get 123
   fizz boards get 123 --format=json`,
+	ValidArgsFunction: completeBoardArg,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		client := GetClient()
 		boardID := args[0]
@@ -79,6 +80,28 @@ var boardsGetCmd = &cobra.Command{
 	},
 }
 
+var boardsOpenCmd = &cobra.Command{
+	Use:   "open <board-id>",
+	Short: "Open a board in the web browser",
+	Long: `Open a board in the web browser, using $BROWSER when set and the system
+default (xdg-open, open) otherwise. Without a browser the URL is printed.`,
+	Args: cobra.ExactArgs(1),
+	Example: `  fizz boards open 123
+  fizz boards open 123 --print
+  fizz boards open 123 --copy`,
+	ValidArgsFunction: completeBoardArg,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client := GetClient()
+
+		board, err := client.Boards.Get(cmd.Context(), args[0])
+		if err != nil {
+			return fmt.Errorf("failed to get board: %w", err)
+		}
+
+		return openURL(cmd, board.URL, board.Name)
+	},
+}
+
 var boardsCreateCmd = &cobra.Command{
 	Use:   "create",
 	Short: "Create a new board",
@@ -123,6 +146,7 @@ var boardsUpdateCmd = &cobra.Command{
 	Args:  cobra.ExactArgs(1),
 	Example: `  fizz boards update 123 --name="Updated Name"
   fizz boards update 123 --description="Updated description"`,
+	ValidArgsFunction: completeBoardArg,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		client := GetClient()
 		boardID := args[0]
@@ -153,10 +177,11 @@ var boardsUpdateCmd = &cobra.Command{
 }
 
 var boardsDeleteCmd = &cobra.Command{
-	Use:   "delete <board-id>",
-	Short: "Delete a board",
-	Args:  cobra.ExactArgs(1),
-	Example: `  fizz boards delete 123`,
+	Use:               "delete <board-id>",
+	Short:             "Delete a board",
+	Args:              cobra.ExactArgs(1),
+	Example:           `  fizz boards delete 123`,
+	ValidArgsFunction: completeBoardArg,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		client := GetClient()
 		boardID := args[0]
@@ -178,9 +203,11 @@ func init() {
 	boardsCreateCmd.Flags().String("input", "", "Read from file or stdin (-)")
 	boardsUpdateCmd.Flags().String("name", "", "New board name")
 	boardsUpdateCmd.Flags().String("description", "", "New board description")
+	addOpenFlags(boardsOpenCmd)
 
 	boardsCmd.AddCommand(boardsListCmd)
 	boardsCmd.AddCommand(boardsGetCmd)
+	boardsCmd.AddCommand(boardsOpenCmd)
 	boardsCmd.AddCommand(boardsCreateCmd)
 	boardsCmd.AddCommand(boardsUpdateCmd)
 	boardsCmd.AddCommand(boardsDeleteCmd)
@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/visionik/fizz/internal/config"
+	"github.com/visionik/fizz/internal/format"
+	"github.com/visionik/fizz/internal/httpcache"
+	"github.com/visionik/fizz/internal/progress"
+)
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Inspect or clear cached API responses",
+	Long: `fizz keeps the API's responses to reads on disk, in the http directory of
+the cache directory (~/.cache/fizz, or $FIZZ_CACHE_DIR), with a directory per
+account and token. A cached response is revalidated with its ETag or
+Last-Modified date, so an unchanged list costs a short 304 instead of a full
+download.
+
+--cache-ttl serves responses younger than the given age without asking the
+API at all, until fizz changes something through the same account.
+--no-cache neither uses nor stores cached responses.
+
+Responses not stored or revalidated for 30 days are removed, by fizz at most
+once a day as it saves responses, and by 'fizz cache info' for every
+account.`,
+}
+
+// cacheInfo is what `cache info` reports: every account's responses, and
+// those of the configured account if there is one
+type cacheInfo struct {
+	httpcache.Stats `yaml:",inline"`
+	Account         *httpcache.Stats `json:"account,omitempty" yaml:"account,omitempty"`
+}
+
+var cacheInfoCmd = &cobra.Command{
+	Use:         "info",
+	Short:       "Show what is cached",
+	Args:        cobra.NoArgs,
+	Annotations: map[string]string{noClientAnnotation: "true"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir, err := httpcache.Dir()
+		if err != nil {
+			return err
+		}
+		if _, err := httpcache.Prune(dir, time.Now().Add(-httpcache.MaxAge)); err != nil {
+			return err
+		}
+		stats, err := httpcache.Info(dir)
+		if err != nil {
+			return err
+		}
+		info := cacheInfo{Stats: stats}
+		if accountDir, ok := configuredCacheDir(); ok {
+			account, err := httpcache.Info(accountDir)
+			if err != nil {
+				return err
+			}
+			info.Account = &account
+		}
+
+		formatter, err := format.NewFormatter(GetFormat(), cmd.OutOrStdout())
+		if err != nil {
+			return err
+		}
+		if GetFormat() != "table" {
+			return formatter.Format(info)
+		}
+
+		fmt.Fprintf(cmd.OutOrStdout(), "Cache directory: %s\n\n", dir)
+		rows := []format.CacheDisplay{format.ToCacheDisplay(fmt.Sprintf("all accounts (%d)", stats.Accounts), stats)}
+		if info.Account != nil {
+			rows = append(rows, format.ToCacheDisplay("this account", *info.Account))
+		}
+		return formatter.Format(rows)
+	},
+}
+
+var cacheClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Delete cached responses",
+	Long: `Delete every account's cached responses, or with --account only those of
+the account fizz is configured for.`,
+	Args:        cobra.NoArgs,
+	Annotations: map[string]string{noClientAnnotation: "true"},
+	Example: `  fizz cache clear
+  fizz cache clear --account`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir, err := httpcache.Dir()
+		if err != nil {
+			return err
+		}
+		if account, _ := cmd.Flags().GetBool("account"); account {
+			var ok bool
+			if dir, ok = configuredCacheDir(); !ok {
+				return fmt.Errorf("--account needs FIZZY_TOKEN and FIZZY_ACCOUNT to tell which account")
+			}
+		}
+
+		stats, err := httpcache.Info(dir)
+		if err != nil {
+			return err
+		}
+		if err := httpcache.Clear(dir); err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Removed %d cached response(s), %s\n", stats.Responses, progress.Bytes(stats.Bytes))
+		return nil
+	},
+}
+
+// configuredCacheDir returns the cache directory of the configured account,
+// if fizz is configured
+func configuredCacheDir() (string, bool) {
+	cfg, err := config.LoadFromEnv()
+	if err != nil || cfg.ReplayPath != "" {
+		return "", false
+	}
+	dir, err := httpcache.AccountDir(cfg.Account, cfg.Token)
+	return dir, err == nil
+}
+
+func init() {
+	cacheClearCmd.Flags().Bool("account", false, "Only clear the configured account's responses")
+
+	cacheCmd.AddCommand(cacheInfoCmd)
+	cacheCmd.AddCommand(cacheClearCmd)
+	rootCmd.AddCommand(cacheCmd)
+}
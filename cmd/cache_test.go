@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/visionik/fizz/internal/httpcache"
+)
+
+func TestCache(t *testing.T) {
+	f := newFizzTest(t)
+	f.seed()
+	f.srv.ETags = true
+
+	first := f.mustRun("cards", "list", "--format=json")
+	second := f.mustRun("cards", "list", "--format=json")
+	assert.Equal(t, first, second, "a revalidated list is served in full")
+	assert.Contains(t, second, "Upgrade the database cluster")
+
+	var info struct {
+		Responses int `json:"responses"`
+		Accounts  int `json:"accounts"`
+		Account   *struct {
+			Responses int `json:"responses"`
+		} `json:"account"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(f.mustRun("cache", "info", "--format=json")), &info))
+	assert.Equal(t, 1, info.Accounts)
+	assert.Positive(t, info.Responses)
+	require.NotNil(t, info.Account)
+	assert.Equal(t, info.Responses, info.Account.Responses)
+
+	// Within the TTL nothing is asked of the API
+	f.mustRun("cards", "list", "--cache-ttl=1h")
+	before := len(f.srv.Requests())
+	f.mustRun("cards", "list", "--cache-ttl=1h")
+	assert.Len(t, f.srv.Requests(), before)
+
+	// until fizz changes something
+	f.mustRun("cards", "close", "1")
+	before = len(f.srv.Requests())
+	assert.Contains(t, f.mustRun("cards", "list", "--cache-ttl=1h"), "closed")
+	assert.Greater(t, len(f.srv.Requests()), before)
+
+	before = len(f.srv.Requests())
+	f.mustRun("cards", "list", "--cache-ttl=1h", "--no-cache")
+	assert.Greater(t, len(f.srv.Requests()), before)
+
+	_, err := f.run("cards", "list", "--cache-ttl=-1s")
+	require.Error(t, err)
+
+	assert.Contains(t, f.mustRun("cache", "clear"), "Removed ")
+	require.NoError(t, json.Unmarshal([]byte(f.mustRun("cache", "info", "--format=json")), &info))
+	assert.Zero(t, info.Responses)
+
+	// Info prunes responses nobody has used for long, in any account
+	dir, err := httpcache.Dir()
+	require.NoError(t, err)
+	stale := filepath.Join(dir, "abandoned", "stale.json")
+	require.NoError(t, os.MkdirAll(filepath.Dir(stale), 0o700))
+	require.NoError(t, os.WriteFile(stale, []byte(`{}`), 0o600))
+	old := time.Now().Add(-httpcache.MaxAge - time.Hour)
+	require.NoError(t, os.Chtimes(stale, old, old))
+	require.NoError(t, json.Unmarshal([]byte(f.mustRun("cache", "info", "--format=json")), &info))
+	assert.Zero(t, info.Responses)
+	assert.NoFileExists(t, stale)
+}
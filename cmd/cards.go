@@ -2,10 +2,15 @@ package cmd
 
 import (
 	"fmt"
-	"strconv"
+	"io"
+	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/visionik/fizz/internal/editor"
 	"github.com/visionik/fizz/internal/format"
+	"github.com/visionik/fizz/internal/kanban"
+	"github.com/visionik/fizz/internal/pager"
+	"github.com/visionik/fizz/internal/templates"
 	"github.com/visionik/libfizz-go/fizzy"
 )
 
@@ -19,15 +24,28 @@ var cardsCmd = &cobra.Command{
 var cardsListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List cards",
+	Long: `List cards, fetching them from the API a page at a time. With --limit, no
+more pages are fetched than needed; table and jsonl output start printing as
+the first page arrives.
+
+--page fetches that page alone and prints the next page's cursor to stderr,
+for walking a long list in steps.`,
 	Example: `  fizz cards list
   fizz cards list --board=03fbhiu9dgjo0viyrlya1x03a
-  fizz cards list --status=open --limit=20`,
+  fizz cards list --status=open --limit=20
+  fizz cards list --format=jsonl --page-size=100
+  fizz cards list --page-size=50 --page=2`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		client := GetClient()
 
 		limit, _ := cmd.Flags().GetInt("limit")
 		boardID, _ := cmd.Flags().GetString("board")
 		status, _ := cmd.Flags().GetString("status")
+		pageSize, _ := cmd.Flags().GetInt("page-size")
+		page, _ := cmd.Flags().GetString("page")
+		if pageSize < 0 {
+			return fmt.Errorf("--page-size must not be negative")
+		}
 
 		// Build list options
 		opts := &fizzy.CardListOptions{}
@@ -38,26 +56,8 @@ var cardsListCmd = &cobra.Command{
 			opts.Status = status
 		}
 
-		cards, err := client.Cards.ListAll(cmd.Context(), opts)
-		if err != nil {
-			return fmt.Errorf("failed to list cards: %w", err)
-		}
-
-		// Apply limit if specified
-		if limit > 0 && len(cards) > limit {
-			cards = cards[:limit]
-		}
-
-		formatter, err := format.NewFormatter(GetFormat(), cmd.OutOrStdout())
-		if err != nil {
-			return err
-		}
-
-		// Use compact display for table format, full data for JSON/YAML
-		if GetFormat() == "table" {
-			return formatter.Format(format.ToCardDisplaySlice(cards))
-		}
-		return formatter.Format(cards)
+		pager := client.CardPages(opts, page, pageSize)
+		return listCardPages(cmd, pager, limit, cmd.Flags().Changed("page"))
 	},
 }
 
@@ -68,6 +68,7 @@ var cardsGetCmd = &cobra.Command{
 	Args:  cobra.ExactArgs(1),
 	Example: `  fizz cards get 123
   fizz cards get abc-123-def --format=json`,
+	ValidArgsFunction: completeCardArg,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		client := GetClient()
 		
@@ -94,12 +95,117 @@ var cardsGetCmd = &cobra.Command{
 	},
 }
 
+// Show card as a document
+var cardsShowCmd = &cobra.Command{
+	Use:   "show <card-id-or-number>",
+	Short: "Show a card with its description, steps and comments",
+	Long: `Show a card as a readable document: a header with status, board, column,
+assignees and tags, the description rendered as terminal markdown, the step
+checklist and the comment thread with reactions.
+
+Output longer than the screen is paged through $PAGER (default: less).
+Set PAGER=cat or pass --no-pager to print directly.`,
+	Args: cobra.ExactArgs(1),
+	Example: `  fizz cards show 123
+  fizz cards show 123 --no-pager
+  fizz cards show 123 --format=json`,
+	ValidArgsFunction: completeCardArg,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client := GetClient()
+		ctx := cmd.Context()
+
+		cardID, err := client.ResolveCardID(ctx, args[0])
+		if err != nil {
+			return err
+		}
+
+		card, err := client.Cards.Get(ctx, cardID)
+		if err != nil {
+			return fmt.Errorf("failed to get card: %w", err)
+		}
+		doc := format.CardDocument{Card: *card}
+
+		if boardID := kanban.BoardID(card); card.ColumnID != nil && boardID != "" {
+			columns, err := client.Columns.List(ctx, boardID)
+			if err != nil {
+				return fmt.Errorf("failed to list columns: %w", err)
+			}
+			for _, col := range columns {
+				if col.ID == *card.ColumnID {
+					doc.Column = col.Name
+				}
+			}
+		}
+
+		if doc.Steps, err = client.Steps.List(ctx, cardID); err != nil {
+			return fmt.Errorf("failed to list steps: %w", err)
+		}
+
+		comments, err := client.Comments.List(ctx, cardID)
+		if err != nil {
+			return fmt.Errorf("failed to list comments: %w", err)
+		}
+		for _, comment := range comments {
+			reactions, err := client.Reactions.List(ctx, cardID, comment.ID)
+			if err != nil {
+				return fmt.Errorf("failed to list reactions: %w", err)
+			}
+			doc.Comments = append(doc.Comments, format.CommentDocument{Comment: comment, Reactions: reactions})
+		}
+
+		if GetFormat() != "table" {
+			formatter, err := format.NewFormatter(GetFormat(), cmd.OutOrStdout())
+			if err != nil {
+				return err
+			}
+			return formatter.Format(doc)
+		}
+
+		if noPager, _ := cmd.Flags().GetBool("no-pager"); noPager {
+			return format.WriteCardDocument(cmd.OutOrStdout(), doc)
+		}
+		return pager.Run(cmd.OutOrStdout(), func(w io.Writer) error {
+			return format.WriteCardDocument(w, doc)
+		})
+	},
+}
+
+// Open card in the browser
+var cardsOpenCmd = &cobra.Command{
+	Use:   "open <card-id-or-number>",
+	Short: "Open a card in the web browser",
+	Long: `Open a card in the web browser, using $BROWSER when set and the system
+default (xdg-open, open) otherwise. Without a browser the URL is printed.`,
+	Args: cobra.ExactArgs(1),
+	Example: `  fizz cards open 123
+  fizz cards open 123 --print
+  fizz cards open 123 --copy`,
+	ValidArgsFunction: completeCardArg,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client := GetClient()
+
+		cardID, err := client.ResolveCardID(cmd.Context(), args[0])
+		if err != nil {
+			return err
+		}
+
+		card, err := client.Cards.Get(cmd.Context(), cardID)
+		if err != nil {
+			return fmt.Errorf("failed to get card: %w", err)
+		}
+
+		return openURL(cmd, card.URL, fmt.Sprintf("#%d %s", card.Number, card.Title))
+	},
+}
+
 // Create card
 var cardsCreateCmd = &cobra.Command{
 	Use:   "create",
 	Short: "Create a new card",
 	Example: `  fizz cards create --board=03fbhiu9dgjo0viyrlya1x03a --title="Bug fix"
-  fizz cards create --board=03fbhiu9dgjo0viyrlya1x03a --title="Feature" --body="Description"`,
+  fizz cards create --board=03fbhiu9dgjo0viyrlya1x03a --title="Feature" --body="Description"
+  fizz cards create --board=03fbhiu9dgjo0viyrlya1x03a --edit
+  fizz cards create --template=release --var version=1.4`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		client := GetClient()
 
@@ -107,24 +213,52 @@ var cardsCreateCmd = &cobra.Command{
 		title, _ := cmd.Flags().GetString("title")
 		body, _ := cmd.Flags().GetString("body")
 
+		var tags []string
+		tmpl, err := loadTemplate(cmd)
+		if err != nil {
+			return err
+		}
+		if tmpl != nil {
+			if boardID == "" {
+				boardID = tmpl.Board
+			}
+			if title == "" {
+				title = tmpl.Title
+			}
+			if body == "" {
+				body = tmpl.Body
+			}
+			tags = tmpl.Tags
+		}
+
 		if boardID == "" {
-			return fmt.Errorf("--board is required")
+			if !interactive(cmd) {
+				return fmt.Errorf("--board is required")
+			}
+			if boardID, err = pick(cmd.Context(), client, cmd, boardPicker, args); err != nil {
+				return err
+			}
 		}
+
+		if useEditor(cmd, "body", "template") {
+			doc, err := editor.EditDocument(editor.Document{Title: title, Tags: tags, Body: body}, "fizz-card-*.md")
+			if err != nil {
+				return err
+			}
+			title, body, tags = doc.Title, doc.Body, doc.Tags
+		}
+
 		if title == "" {
 			return fmt.Errorf("--title is required")
 		}
 
-		opts := &fizzy.CardCreateOptions{
-			BoardID: boardID,
-			Title:   title,
-		}
-		if body != "" {
-			opts.Body = &body
+		if tmpl == nil {
+			tmpl = &templates.Template{}
 		}
-
-		card, err := client.Cards.Create(cmd.Context(), opts)
+		tmpl.Board, tmpl.Title, tmpl.Body, tmpl.Tags = boardID, title, body, tags
+		card, err := createFromTemplate(cmd.Context(), client, tmpl)
 		if err != nil {
-			return fmt.Errorf("failed to create card: %w", err)
+			return err
 		}
 
 		formatter, err := format.NewFormatter(GetFormat(), cmd.OutOrStdout())
@@ -142,7 +276,9 @@ var cardsUpdateCmd = &cobra.Command{
 	Short: "Update a card",
 	Args:  cobra.ExactArgs(1),
 	Example: `  fizz cards update 123 --title="Updated title"
-  fizz cards update 123 --body="New description"`,
+  fizz cards update 123 --body="New description"
+  fizz cards update 123 --edit`,
+	ValidArgsFunction: completeCardArg,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		client := GetClient()
 
@@ -154,6 +290,10 @@ var cardsUpdateCmd = &cobra.Command{
 		title, _ := cmd.Flags().GetString("title")
 		body, _ := cmd.Flags().GetString("body")
 
+		if useEditor(cmd, "title", "body") {
+			return editCard(cmd, cardID, title, body)
+		}
+
 		opts := &fizzy.CardUpdateOptions{}
 		if title != "" {
 			opts.Title = &title
@@ -176,11 +316,83 @@ var cardsUpdateCmd = &cobra.Command{
 	},
 }
 
+// editCard updates a card from an editor pre-filled with its current title,
+// tags and description; --title and --body override the pre-filled values
+func editCard(cmd *cobra.Command, cardID, title, body string) error {
+	client := GetClient()
+
+	card, err := client.Cards.Get(cmd.Context(), cardID)
+	if err != nil {
+		return fmt.Errorf("failed to get card: %w", err)
+	}
+
+	current := editor.Document{Title: card.Title}
+	if card.Description != nil {
+		current.Body = strings.TrimSpace(*card.Description)
+	}
+	for _, tag := range card.Tags {
+		current.Tags = append(current.Tags, tag.Name)
+	}
+	initial := current
+	if title != "" {
+		initial.Title = title
+	}
+	if body != "" {
+		initial.Body = body
+	}
+
+	doc, err := editor.EditDocument(initial, "fizz-card-*.md")
+	if err != nil {
+		return err
+	}
+	if doc.Title == "" {
+		return fmt.Errorf("aborted: title is empty")
+	}
+
+	opts := &fizzy.CardUpdateOptions{}
+	if doc.Title != current.Title {
+		opts.Title = &doc.Title
+	}
+	if doc.Body != current.Body {
+		opts.Body = &doc.Body
+	}
+
+	changed := opts.Title != nil || opts.Body != nil
+	if changed {
+		if card, err = client.Cards.Update(cmd.Context(), cardID, opts); err != nil {
+			return fmt.Errorf("failed to update card: %w", err)
+		}
+	}
+
+	tagged, err := syncTags(cmd.Context(), client, cardID, current.Tags, doc.Tags)
+	if err != nil {
+		return err
+	}
+	if tagged {
+		if card, err = client.Cards.Get(cmd.Context(), cardID); err != nil {
+			return fmt.Errorf("failed to get card: %w", err)
+		}
+	}
+
+	if !changed && !tagged {
+		fmt.Fprintf(cmd.ErrOrStderr(), "No changes to card %d\n", card.Number)
+		return nil
+	}
+
+	formatter, err := format.NewFormatter(GetFormat(), cmd.OutOrStdout())
+	if err != nil {
+		return err
+	}
+
+	return formatter.Format(card)
+}
+
 // Delete card
 var cardsDeleteCmd = &cobra.Command{
-	Use:   "delete <card-id-or-number>",
-	Short: "Delete a card",
-	Args:  cobra.ExactArgs(1),
+	Use:               "delete <card-id-or-number>",
+	Short:             "Delete a card",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeCardArg,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		client := GetClient()
 
@@ -194,16 +406,17 @@ var cardsDeleteCmd = &cobra.Command{
 			return fmt.Errorf("failed to delete card: %w", err)
 		}
 
-		fmt.Fprintf(cmd.OutOrStdout(), "Card %d deleted successfully\n", cardID)
+		fmt.Fprintf(cmd.OutOrStdout(), "Card %s deleted successfully\n", cardID)
 		return nil
 	},
 }
 
 // Close card
 var cardsCloseCmd = &cobra.Command{
-	Use:   "close <card-id-or-number>",
-	Short: "Close a card",
-	Args:  cobra.ExactArgs(1),
+	Use:               "close <card-id-or-number>",
+	Short:             "Close a card",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeCardArg,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		client := GetClient()
 
@@ -224,9 +437,10 @@ var cardsCloseCmd = &cobra.Command{
 
 // Reopen card
 var cardsReopenCmd = &cobra.Command{
-	Use:   "reopen <card-id-or-number>",
-	Short: "Reopen a closed card",
-	Args:  cobra.ExactArgs(1),
+	Use:               "reopen <card-id-or-number>",
+	Short:             "Reopen a closed card",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeCardArg,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		client := GetClient()
 
@@ -247,9 +461,10 @@ var cardsReopenCmd = &cobra.Command{
 
 // Postpone card
 var cardsPostponeCmd = &cobra.Command{
-	Use:   "postpone <card-id-or-number>",
-	Short: "Postpone a card",
-	Args:  cobra.ExactArgs(1),
+	Use:               "postpone <card-id-or-number>",
+	Short:             "Postpone a card",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeCardArg,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		client := GetClient()
 
@@ -270,9 +485,10 @@ var cardsPostponeCmd = &cobra.Command{
 
 // Triage card
 var cardsTriageCmd = &cobra.Command{
-	Use:   "triage <card-id-or-number>",
-	Short: "Triage a card",
-	Args:  cobra.ExactArgs(1),
+	Use:               "triage <card-id-or-number>",
+	Short:             "Triage a card",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeCardArg,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		client := GetClient()
 
@@ -296,7 +512,9 @@ var cardsAssignCmd = &cobra.Command{
 	Use:   "assign <card-id-or-number> <user-id>",
 	Short: "Assign a card to a user",
 	Args:  cobra.ExactArgs(2),
-	Example: `  fizz cards assign 123 user-456`,
+	Example: `  fizz cards assign 123 user-456
+  fizz cards assign 123 me`,
+	ValidArgsFunction: completeCardUserArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		client := GetClient()
 
@@ -305,7 +523,14 @@ var cardsAssignCmd = &cobra.Command{
 			return err
 		}
 
-		userID := args[1]
+		userID, err := client.ResolveUserID(cmd.Context(), args[1])
+		if err != nil {
+			return err
+		}
+
+		if err := checkAssignWIP(cmd, client, cardID, userID); err != nil {
+			return err
+		}
 
 	err = client.Cards.Assign(cmd.Context(), cardID, userID)
 	if err != nil {
@@ -319,10 +544,11 @@ var cardsAssignCmd = &cobra.Command{
 
 // Tag card
 var cardsTagCmd = &cobra.Command{
-	Use:   "tag <card-id-or-number> <tag-name>",
-	Short: "Add a tag to a card",
-	Args:  cobra.ExactArgs(2),
-	Example: `  fizz cards tag 123 bug`,
+	Use:               "tag <card-id-or-number> <tag-name>",
+	Short:             "Add a tag to a card",
+	Args:              cobra.ExactArgs(2),
+	Example:           `  fizz cards tag 123 bug`,
+	ValidArgsFunction: completeCardTagArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		client := GetClient()
 
@@ -348,7 +574,9 @@ var cardsMoveCmd = &cobra.Command{
 	Use:   "move <card-id-or-number>",
 	Short: "Move a card to a different column",
 	Args:  cobra.ExactArgs(1),
-	Example: `  fizz cards move 123 --column=456`,
+	Example: `  fizz cards move 123 --column=Doing
+  fizz cards move 123 --column=03fbhiu9dgjo0viyrlya1x03c`,
+	ValidArgsFunction: completeCardArg,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		client := GetClient()
 
@@ -357,26 +585,42 @@ var cardsMoveCmd = &cobra.Command{
 			return err
 		}
 
-		columnID, _ := cmd.Flags().GetInt("column")
-		if columnID == 0 {
-			return fmt.Errorf("--column is required")
+		// The picker lists the columns of the card's board
+		column, err := pickFlag(cmd, "column", columnPicker, args)
+		if err != nil {
+			return err
+		}
+
+		card, err := client.Cards.Get(cmd.Context(), cardID)
+		if err != nil {
+			return fmt.Errorf("failed to get card: %w", err)
 		}
+		col, err := client.ResolveColumn(cmd.Context(), kanban.BoardID(card), column)
+		if err != nil {
+			return err
+		}
+		columnID := col.ID
 
-	err = client.Cards.MoveToColumn(cmd.Context(), cardID, strconv.Itoa(columnID))
+		if err := checkMoveWIP(cmd, client, card, col); err != nil {
+			return err
+		}
+
+	err = client.Cards.MoveToColumn(cmd.Context(), cardID, columnID)
 	if err != nil {
 		return fmt.Errorf("failed to move card: %w", err)
 	}
 
-	fmt.Fprintf(cmd.OutOrStdout(), "Card %s moved to column %d\n", cardID, columnID)
+	fmt.Fprintf(cmd.OutOrStdout(), "Card %s moved to column %s\n", cardID, columnID)
 	return nil
 	},
 }
 
 // Watch card
 var cardsWatchCmd = &cobra.Command{
-	Use:   "watch <card-id-or-number>",
-	Short: "Watch a card for updates",
-	Args:  cobra.ExactArgs(1),
+	Use:               "watch <card-id-or-number>",
+	Short:             "Watch a card for updates",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeCardArg,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		client := GetClient()
 
@@ -397,9 +641,10 @@ var cardsWatchCmd = &cobra.Command{
 
 // Unwatch card
 var cardsUnwatchCmd = &cobra.Command{
-	Use:   "unwatch <card-id-or-number>",
-	Short: "Stop watching a card",
-	Args:  cobra.ExactArgs(1),
+	Use:               "unwatch <card-id-or-number>",
+	Short:             "Stop watching a card",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeCardArg,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		client := GetClient()
 
@@ -420,9 +665,10 @@ var cardsUnwatchCmd = &cobra.Command{
 
 // Golden card
 var cardsGoldenCmd = &cobra.Command{
-	Use:   "golden <card-id-or-number>",
-	Short: "Mark a card as golden",
-	Args:  cobra.ExactArgs(1),
+	Use:               "golden <card-id-or-number>",
+	Short:             "Mark a card as golden",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeCardArg,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		client := GetClient()
 
@@ -443,9 +689,10 @@ var cardsGoldenCmd = &cobra.Command{
 
 // Ungolden card
 var cardsUngoldenCmd = &cobra.Command{
-	Use:   "ungolden <card-id-or-number>",
-	Short: "Remove golden status from a card",
-	Args:  cobra.ExactArgs(1),
+	Use:               "ungolden <card-id-or-number>",
+	Short:             "Remove golden status from a card",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeCardArg,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		client := GetClient()
 
@@ -469,22 +716,43 @@ func init() {
 	cardsListCmd.Flags().String("board", "", "Filter by board ID")
 	cardsListCmd.Flags().String("status", "", "Filter by status")
 	cardsListCmd.Flags().Int("limit", 0, "Limit number of results (0 = all)")
+	cardsListCmd.Flags().Int("page-size", 0, "Cards to fetch per request (0 = API default)")
+	cardsListCmd.Flags().String("page", "", "Fetch only this page, by number or by the cursor a previous page printed")
+	cardsListCmd.RegisterFlagCompletionFunc("board", completeFlag(boardCandidates))
+
+	// Show flags
+	cardsShowCmd.Flags().Bool("no-pager", false, "Print directly instead of through $PAGER")
+
+	// Open flags
+	addOpenFlags(cardsOpenCmd)
 
 	// Create flags
 	cardsCreateCmd.Flags().String("board", "", "Board ID (required)")
 	cardsCreateCmd.Flags().String("title", "", "Card title (required)")
 	cardsCreateCmd.Flags().String("body", "", "Card body/description")
+	cardsCreateCmd.Flags().String("template", "", "Create from a card template (see 'fizz templates')")
+	cardsCreateCmd.Flags().StringArray("var", nil, "Template variable as key=value (repeatable)")
+	addEditFlag(cardsCreateCmd)
+	cardsCreateCmd.RegisterFlagCompletionFunc("board", completeFlag(boardCandidates))
 
 	// Update flags
 	cardsUpdateCmd.Flags().String("title", "", "New card title")
 	cardsUpdateCmd.Flags().String("body", "", "New card body")
+	addEditFlag(cardsUpdateCmd)
 
 	// Move flags
-	cardsMoveCmd.Flags().Int("column", 0, "Target column ID (required)")
+	cardsMoveCmd.Flags().String("column", "", "Target column, by name or ID (required)")
+	cardsMoveCmd.Flags().Bool("force", false, "Move even if it exceeds a WIP limit")
+	cardsMoveCmd.RegisterFlagCompletionFunc("column", completeFlag(columnCandidates))
+
+	// Assign flags
+	cardsAssignCmd.Flags().Bool("force", false, "Assign even if it exceeds a WIP limit")
 
 	// Add all subcommands
 	cardsCmd.AddCommand(cardsListCmd)
 	cardsCmd.AddCommand(cardsGetCmd)
+	cardsCmd.AddCommand(cardsShowCmd)
+	cardsCmd.AddCommand(cardsOpenCmd)
 	cardsCmd.AddCommand(cardsCreateCmd)
 	cardsCmd.AddCommand(cardsUpdateCmd)
 	cardsCmd.AddCommand(cardsDeleteCmd)
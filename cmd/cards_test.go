@@ -0,0 +1,157 @@
+package cmd
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/visionik/fizz/internal/fakefizzy"
+)
+
+func TestCardsLifecycle(t *testing.T) {
+	f := newFizzTest(t)
+	fx := f.seed()
+
+	out := f.mustRun("cards", "create", "--board="+fx.BoardID, "--title=Write release notes", "--format=json")
+	assert.Contains(t, out, `"title": "Write release notes"`)
+	assert.Contains(t, out, `"number": 3`)
+
+	assert.Equal(t, "Card 3 closed successfully\n", f.mustRun("cards", "close", "3"))
+	assert.Contains(t, f.mustRun("cards", "get", "3", "--format=json"), `"closed": true`)
+
+	assert.Equal(t, "Card 3 reopened successfully\n", f.mustRun("cards", "reopen", "3"))
+	assert.Equal(t, "Card 3 moved to column "+fx.ColumnID+"\n", f.mustRun("cards", "move", "3", "--column="+fx.ColumnID))
+	assert.Equal(t, "Card 3 tagged with 'bug'\n", f.mustRun("cards", "tag", "3", "bug"))
+	assert.Equal(t, "Card 3 marked as golden\n", f.mustRun("cards", "golden", "3"))
+
+	out = f.mustRun("cards", "get", "3", "--format=json")
+	assert.Contains(t, out, `"golden": true`)
+	assert.Contains(t, out, `"name": "bug"`)
+
+	assert.Equal(t, "Card 3 deleted successfully\n", f.mustRun("cards", "delete", "3"))
+	_, err := f.run("cards", "get", "3")
+	assert.Error(t, err)
+}
+
+func TestCardsMoveByName(t *testing.T) {
+	f := newFizzTest(t)
+	fx := f.seed()
+
+	// Without WIP limits in the config, names resolve all the same
+	f.golden("cards_move_by_name", f.mustRun("cards", "move", "2", "--column=doing"))
+	assert.Contains(t, f.mustRun("cards", "get", "2", "--format=json"), `"column_id": "`+fx.ColumnID+`"`)
+
+	_, err := f.run("cards", "move", "2", "--column=Shipped")
+	require.EqualError(t, err, `column "Shipped" not found on board `+fx.BoardID)
+}
+
+func TestCardsListLimit(t *testing.T) {
+	f := newFizzTest(t)
+	fx := f.seed()
+
+	out := f.mustRun("cards", "list", "--board="+fx.BoardID, "--limit=1", "--format=json")
+	assert.Contains(t, out, "Upgrade the database cluster")
+	assert.NotContains(t, out, "Login fails with SSO")
+
+	// Flags do not leak into the next run
+	out = f.mustRun("cards", "list", "--format=json")
+	assert.Contains(t, out, "Login fails with SSO")
+}
+
+func TestCardsListPages(t *testing.T) {
+	f := newFizzTest(t)
+	fx := f.seed()
+	for _, title := range []string{"Third", "Fourth", "Fifth"} {
+		f.srv.AddCard(fx.BoardID, title, "")
+	}
+	f.srv.PageSize = 2
+	pages := func() int {
+		n := 0
+		for _, r := range f.srv.Requests() {
+			if strings.HasPrefix(r, "GET /"+f.srv.Account+"/cards.json") {
+				n++
+			}
+		}
+		return n
+	}
+
+	// Every page is followed
+	out := f.mustRun("cards", "list", "--format=jsonl")
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	require.Len(t, lines, 5)
+	assert.Contains(t, lines[4], `"title":"Fifth"`)
+	assert.Equal(t, 3, pages())
+
+	// and only as many as the limit needs
+	before := pages()
+	out = f.mustRun("cards", "list", "--limit=3")
+	assert.Contains(t, out, "Third")
+	assert.NotContains(t, out, "Fourth")
+	assert.Equal(t, 2, pages()-before)
+
+	before = pages()
+	out = f.mustRun("cards", "list", "--limit=3", "--page-size=3", "--format=json")
+	assert.Contains(t, out, "Third")
+	assert.Equal(t, 1, pages()-before)
+
+	out, errOut, err := f.runStderr("cards", "list", "--page=2")
+	require.NoError(t, err)
+	assert.Contains(t, out, "Third")
+	assert.NotContains(t, out, "Upgrade the database cluster")
+	assert.NotContains(t, out, "Fifth")
+	assert.Equal(t, "Next page: --page=3\n", errOut)
+
+	out, errOut, err = f.runStderr("cards", "list", "--page=3")
+	require.NoError(t, err)
+	assert.Contains(t, out, "Fifth")
+	assert.Empty(t, errOut)
+
+	// Commands that need every card see them all
+	assert.Contains(t, f.mustRun("export", "csv", "--board="+fx.BoardID), "Fifth")
+
+	_, err = f.run("cards", "list", "--page-size=-1")
+	require.EqualError(t, err, "--page-size must not be negative")
+}
+
+func TestCardsRequiredFlags(t *testing.T) {
+	f := newFizzTest(t)
+	fx := f.seed()
+
+	_, err := f.run("cards", "create", "--title=No board")
+	require.EqualError(t, err, "--board is required")
+
+	_, err = f.run("cards", "create", "--board="+fx.BoardID)
+	require.EqualError(t, err, "--title is required")
+
+	_, err = f.run("cards", "move", fx.CardNumber)
+	require.EqualError(t, err, "--column is required")
+}
+
+func TestCardsAPIErrors(t *testing.T) {
+	f := newFizzTest(t)
+	f.seed()
+
+	_, err := f.run("cards", "get", "999")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "status 404")
+
+	f.srv.Inject(fakefizzy.Fault{Method: http.MethodGet, Path: "/cards", Status: http.StatusForbidden, Times: 1})
+	_, err = f.run("cards", "list")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to list cards")
+	assert.Contains(t, err.Error(), "status 403")
+
+	// The fault fired once and is gone
+	f.mustRun("cards", "list")
+}
+
+func TestAuthenticationFailure(t *testing.T) {
+	f := newFizzTest(t)
+	t.Setenv("FIZZY_TOKEN", "wrong-token")
+
+	_, err := f.run("boards", "list")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "status 401")
+}
@@ -4,7 +4,9 @@ import (
 	"fmt"
 
 	"github.com/spf13/cobra"
+	"github.com/visionik/fizz/internal/config"
 	"github.com/visionik/fizz/internal/format"
+	"github.com/visionik/fizz/internal/wip"
 	"github.com/visionik/libfizz-go/fizzy"
 )
 
@@ -17,7 +19,10 @@ var columnsCmd = &cobra.Command{
 var columnsListCmd = &cobra.Command{
 	Use:   "list <board-id>",
 	Short: "List columns in a board",
-	Args:  cobra.ExactArgs(1),
+	Long: `List the columns of a board. When the config file declares WIP limits
+for the board, each column also shows its open cards against its limit.`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeBoardArg,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		client := GetClient()
 		boardID := args[0]
@@ -32,14 +37,55 @@ var columnsListCmd = &cobra.Command{
 			return err
 		}
 
-		return formatter.Format(columns)
+		settings, err := config.LoadSettings()
+		if err != nil {
+			return err
+		}
+		limits := make([]*config.WIPLimit, len(columns))
+		limited := false
+		for i, col := range columns {
+			limits[i] = wip.Limit(settings.WIP, boardID, col)
+			limited = limited || limits[i] != nil
+		}
+		if !limited {
+			return formatter.Format(columns)
+		}
+
+		// With WIP limits, show each column's open cards against its limit
+		cards, err := client.ListAllCards(cmd.Context(), &fizzy.CardListOptions{BoardID: boardID})
+		if err != nil {
+			return fmt.Errorf("failed to list cards: %w", err)
+		}
+		if GetFormat() == "table" {
+			displays := make([]format.ColumnWIPDisplay, len(columns))
+			for i, col := range columns {
+				displays[i] = format.ToColumnWIPDisplay(col, wip.CountColumn(cards, col.ID, ""), limits[i])
+			}
+			return formatter.Format(displays)
+		}
+
+		type columnWIP struct {
+			fizzy.Column `yaml:",inline"`
+			Cards        int `json:"cards" yaml:"cards"`
+			Limit        int `json:"wip_limit,omitempty" yaml:"wip_limit,omitempty"`
+			PerPerson    int `json:"per_person_limit,omitempty" yaml:"per_person_limit,omitempty"`
+		}
+		entries := make([]columnWIP, len(columns))
+		for i, col := range columns {
+			entries[i] = columnWIP{Column: col, Cards: wip.CountColumn(cards, col.ID, "").Total}
+			if limits[i] != nil {
+				entries[i].Limit, entries[i].PerPerson = limits[i].Limit, limits[i].PerPerson
+			}
+		}
+		return formatter.Format(entries)
 	},
 }
 
 var columnsGetCmd = &cobra.Command{
-	Use:   "get <board-id> <column-id>",
-	Short: "Get a column",
-	Args:  cobra.ExactArgs(2),
+	Use:               "get <board-id> <column-id>",
+	Short:             "Get a column",
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: completeBoardColumnArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		client := GetClient()
 		boardID := args[0]
@@ -60,10 +106,11 @@ var columnsGetCmd = &cobra.Command{
 }
 
 var columnsCreateCmd = &cobra.Command{
-	Use:   "create <board-id>",
-	Short: "Create a column",
-	Args:  cobra.ExactArgs(1),
-	Example: `  fizz columns create 123 --name="In Progress"`,
+	Use:               "create <board-id>",
+	Short:             "Create a column",
+	Args:              cobra.ExactArgs(1),
+	Example:           `  fizz columns create 123 --name="In Progress"`,
+	ValidArgsFunction: completeBoardArg,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		client := GetClient()
 		boardID := args[0]
@@ -92,9 +139,10 @@ var columnsCreateCmd = &cobra.Command{
 }
 
 var columnsUpdateCmd = &cobra.Command{
-	Use:   "update <board-id> <column-id>",
-	Short: "Update a column",
-	Args:  cobra.ExactArgs(2),
+	Use:               "update <board-id> <column-id>",
+	Short:             "Update a column",
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: completeBoardColumnArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		client := GetClient()
 		boardID := args[0]
@@ -126,9 +174,10 @@ var columnsUpdateCmd = &cobra.Command{
 }
 
 var columnsDeleteCmd = &cobra.Command{
-	Use:   "delete <board-id> <column-id>",
-	Short: "Delete a column",
-	Args:  cobra.ExactArgs(2),
+	Use:               "delete <board-id> <column-id>",
+	Short:             "Delete a column",
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: completeBoardColumnArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		client := GetClient()
 		boardID := args[0]
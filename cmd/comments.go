@@ -2,8 +2,11 @@ package cmd
 
 import (
 	"fmt"
+	"io"
+	"slices"
 
 	"github.com/spf13/cobra"
+	"github.com/visionik/fizz/internal/editor"
 	"github.com/visionik/fizz/internal/format"
 	"github.com/visionik/libfizz-go/fizzy"
 )
@@ -20,6 +23,7 @@ var commentsListCmd = &cobra.Command{
 	Args:  cobra.ExactArgs(1),
 	Example: `  fizz comments list 123
   fizz comments list abc-def --format=json`,
+	ValidArgsFunction: completeCardArg,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		client := GetClient()
 
@@ -47,8 +51,17 @@ var commentsCreateCmd = &cobra.Command{
 	Short: "Create a comment on a card",
 	Args:  cobra.ExactArgs(1),
 	Example: `  fizz comments create 123 --body="Great work!"
-  echo "Long comment text" | fizz comments create 123 --body=-`,
+  echo "Long comment text" | fizz comments create 123 --body=-
+  fizz comments create 123 --edit
+  fizz comments create 123 --body="Repro attached" --attach=crash.log --attach=screen.png`,
+	ValidArgsFunction: completeCardArg,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		body, _ := cmd.Flags().GetString("body")
+		files, _ := cmd.Flags().GetStringArray("attach")
+		if body == "-" && slices.Contains(files, "-") {
+			return fmt.Errorf("--body=- and --attach=- cannot both read stdin")
+		}
+
 		client := GetClient()
 
 		cardID, err := client.ResolveCardID(cmd.Context(), args[0])
@@ -56,10 +69,26 @@ var commentsCreateCmd = &cobra.Command{
 			return err
 		}
 
-		body, _ := cmd.Flags().GetString("body")
-		if body == "" {
+		if body == "-" {
+			data, err := io.ReadAll(cmd.InOrStdin())
+			if err != nil {
+				return fmt.Errorf("failed to read stdin: %w", err)
+			}
+			body = string(data)
+		}
+		if useEditor(cmd, "body") {
+			if body, err = editor.EditText(body, "fizz-comment-*.md"); err != nil {
+				return err
+			}
+		}
+		if body == "" && len(files) == 0 {
 			return fmt.Errorf("--body is required")
 		}
+		if len(files) > 0 {
+			if body, err = attachFiles(cmd, client, body, files); err != nil {
+				return err
+			}
+		}
 
 		req := &fizzy.CommentCreateOptions{
 			Body: body,
@@ -80,10 +109,11 @@ var commentsCreateCmd = &cobra.Command{
 }
 
 var commentsUpdateCmd = &cobra.Command{
-	Use:   "update <card-id-or-number> <comment-id>",
-	Short: "Update a comment",
-	Args:  cobra.ExactArgs(2),
-	Example: `  fizz comments update 123 456 --body="Updated comment"`,
+	Use:               "update <card-id-or-number> <comment-id>",
+	Short:             "Update a comment",
+	Args:              cobra.ExactArgs(2),
+	Example:           `  fizz comments update 123 456 --body="Updated comment"`,
+	ValidArgsFunction: completeCardArg,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		client := GetClient()
 
@@ -117,9 +147,10 @@ var commentsUpdateCmd = &cobra.Command{
 }
 
 var commentsDeleteCmd = &cobra.Command{
-	Use:   "delete <card-id-or-number> <comment-id>",
-	Short: "Delete a comment",
-	Args:  cobra.ExactArgs(2),
+	Use:               "delete <card-id-or-number> <comment-id>",
+	Short:             "Delete a comment",
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: completeCardArg,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		client := GetClient()
 
@@ -141,7 +172,10 @@ var commentsDeleteCmd = &cobra.Command{
 }
 
 func init() {
-	commentsCreateCmd.Flags().String("body", "", "Comment body (required)")
+	commentsCreateCmd.Flags().String("body", "", "Comment body (required unless editing or attaching; - reads stdin)")
+	commentsCreateCmd.Flags().StringArray("attach", nil, "File to attach (repeatable; - reads stdin)")
+	commentsCreateCmd.Flags().String("name", "", "File name for an attachment read from stdin")
+	addEditFlag(commentsCreateCmd)
 	commentsUpdateCmd.Flags().String("body", "", "New comment body (required)")
 
 	commentsCmd.AddCommand(commentsListCmd)
@@ -1,7 +1,16 @@
 package cmd
 
 import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
 	"github.com/spf13/cobra"
+	"github.com/visionik/fizz/internal/client"
+	"github.com/visionik/fizz/internal/completion"
+	"github.com/visionik/fizz/internal/kanban"
+	"github.com/visionik/libfizz-go/fizzy"
 )
 
 var completionCmd = &cobra.Command{
@@ -33,7 +42,7 @@ Fish:
 	RunE: func(cmd *cobra.Command, args []string) error {
 		switch args[0] {
 		case "bash":
-			return rootCmd.GenBashCompletion(cmd.OutOrStdout())
+			return rootCmd.GenBashCompletionV2(cmd.OutOrStdout(), true)
 		case "zsh":
 			return rootCmd.GenZshCompletion(cmd.OutOrStdout())
 		case "fish":
@@ -46,3 +55,188 @@ Fish:
 func init() {
 	rootCmd.AddCommand(completionCmd)
 }
+
+// completionTimeout bounds the API calls made while completing, so a slow or
+// missing network does not hang the shell
+const completionTimeout = 3 * time.Second
+
+// candidateFunc lists the candidates for one argument or flag; args are the
+// positional arguments given so far
+type candidateFunc func(ctx context.Context, c *client.Client, cmd *cobra.Command, args []string) ([]completion.Candidate, error)
+
+// Completions for positional arguments, one function per argument
+var (
+	completeBoardArg        = completeArgs(boardCandidates)
+	completeCardArg         = completeArgs(cardCandidates)
+	completeBoardColumnArgs = completeArgs(boardCandidates, columnCandidates)
+	completeCardUserArgs    = completeArgs(cardCandidates, userCandidates)
+	completeCardTagArgs     = completeArgs(cardCandidates, tagCandidates)
+	completeNotificationArg = completeArgs(notificationCandidates)
+)
+
+// completeArgs completes each positional argument with the matching
+// function and offers nothing after them
+func completeArgs(fns ...candidateFunc) cobra.CompletionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+		if len(args) >= len(fns) {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return complete(fns[len(args)], cmd, args, toComplete)
+	}
+}
+
+// completeFlag completes a flag's value with fn
+func completeFlag(fn candidateFunc) cobra.CompletionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+		return complete(fn, cmd, args, toComplete)
+	}
+}
+
+// complete runs fn, offering nothing rather than an error when the API
+// cannot be reached or fizz is not configured
+func complete(fn candidateFunc, cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	// Retrying would only keep the user waiting
+	c := lazyClient(client.WithMaxRetries(0))
+	if c == nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	ctx, cancel := context.WithTimeout(cmd.Context(), completionTimeout)
+	defer cancel()
+
+	candidates, err := fn(ctx, c, cmd, args)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return completion.Filter(candidates, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// cachedCandidates returns the candidates for what, scoped to the account
+// and API so switching between them never offers stale IDs
+func cachedCandidates(c *client.Client, what string, fetch func() ([]completion.Candidate, error)) ([]completion.Candidate, error) {
+	cache, err := completion.DefaultCache()
+	if err != nil {
+		return fetch()
+	}
+	return cache.Get(strings.Join([]string{c.BaseURL, c.Account, what}, "\x00"), fetch)
+}
+
+func boardCandidates(ctx context.Context, c *client.Client, cmd *cobra.Command, args []string) ([]completion.Candidate, error) {
+	return cachedCandidates(c, "boards", func() ([]completion.Candidate, error) {
+		boards, err := c.Boards.List(ctx)
+		if err != nil {
+			return nil, err
+		}
+		var out []completion.Candidate
+		for _, b := range boards {
+			out = append(out, completion.Candidate{Value: b.ID, Description: b.Name})
+		}
+		return out, nil
+	})
+}
+
+// cardCandidates offers card numbers with their titles, from the board in
+// --board when the command has one
+func cardCandidates(ctx context.Context, c *client.Client, cmd *cobra.Command, args []string) ([]completion.Candidate, error) {
+	boardID := ""
+	if f := cmd.Flags().Lookup("board"); f != nil {
+		boardID = f.Value.String()
+	}
+	return cachedCandidates(c, "cards:"+boardID, func() ([]completion.Candidate, error) {
+		// One page is plenty to pick from
+		page, err := c.Cards.List(ctx, &fizzy.CardListOptions{BoardID: boardID})
+		if err != nil {
+			return nil, err
+		}
+		var out []completion.Candidate
+		for _, card := range page.Items {
+			out = append(out, completion.Candidate{Value: fmt.Sprint(card.Number), Description: card.Title})
+		}
+		return out, nil
+	})
+}
+
+// columnCandidates offers the columns of the board in --board, the board
+// argument, or the card argument's board
+func columnCandidates(ctx context.Context, c *client.Client, cmd *cobra.Command, args []string) ([]completion.Candidate, error) {
+	boardID := ""
+	if f := cmd.Flags().Lookup("board"); f != nil {
+		boardID = f.Value.String()
+	}
+	if boardID == "" && len(args) > 0 {
+		if strings.Contains(cmd.Use, "<board-id>") {
+			boardID = args[0]
+		} else {
+			card, err := c.Cards.Get(ctx, args[0])
+			if err != nil {
+				return nil, err
+			}
+			boardID = kanban.BoardID(card)
+		}
+	}
+	if boardID == "" {
+		return nil, nil
+	}
+	return cachedCandidates(c, "columns:"+boardID, func() ([]completion.Candidate, error) {
+		columns, err := c.Columns.List(ctx, boardID)
+		if err != nil {
+			return nil, err
+		}
+		var out []completion.Candidate
+		for _, col := range columns {
+			out = append(out, completion.Candidate{Value: col.ID, Description: col.Name})
+		}
+		return out, nil
+	})
+}
+
+// userCandidates offers user IDs and "me"
+func userCandidates(ctx context.Context, c *client.Client, cmd *cobra.Command, args []string) ([]completion.Candidate, error) {
+	users, err := cachedCandidates(c, "users", func() ([]completion.Candidate, error) {
+		users, err := c.Users.List(ctx)
+		if err != nil {
+			return nil, err
+		}
+		var out []completion.Candidate
+		for _, u := range users {
+			out = append(out, completion.Candidate{Value: u.ID, Description: u.Name})
+		}
+		return out, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return append([]completion.Candidate{{Value: "me", Description: "You"}}, users...), nil
+}
+
+// tagCandidates offers tag names
+func tagCandidates(ctx context.Context, c *client.Client, cmd *cobra.Command, args []string) ([]completion.Candidate, error) {
+	return cachedCandidates(c, "tags", func() ([]completion.Candidate, error) {
+		tags, err := c.Tags.List(ctx)
+		if err != nil {
+			return nil, err
+		}
+		var out []completion.Candidate
+		for _, tag := range tags {
+			out = append(out, completion.Candidate{Value: tag.Name})
+		}
+		return out, nil
+	})
+}
+
+// notificationCandidates offers notification IDs, which change too often
+// to cache
+func notificationCandidates(ctx context.Context, c *client.Client, cmd *cobra.Command, args []string) ([]completion.Candidate, error) {
+	notifications, err := c.Notifications.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var out []completion.Candidate
+	for _, n := range notifications {
+		desc := n.Type
+		if n.ReadAt == nil {
+			desc += ", unread"
+		}
+		out = append(out, completion.Candidate{Value: n.ID, Description: desc})
+	}
+	return out, nil
+}
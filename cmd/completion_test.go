@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/visionik/libfizz-go/fizzy"
+)
+
+func TestCompletion(t *testing.T) {
+	f := newFizzTest(t)
+	fx := f.seed()
+
+	out := f.mustRun("__complete", "cards", "show", "")
+	assert.Contains(t, out, "1\tUpgrade the database cluster\n")
+	assert.Contains(t, out, "2\tLogin fails with SSO\n")
+	assert.Contains(t, out, ":4\n")
+
+	out = f.mustRun("__complete", "cards", "assign", "1", "m")
+	assert.Equal(t, "me\tYou\n:4\n", out[:len("me\tYou\n:4\n")])
+
+	out = f.mustRun("__complete", "cards", "tag", "1", "")
+	assert.Contains(t, out, "infra\n")
+
+	out = f.mustRun("__complete", "cards", "move", "1", "--column", "")
+	assert.Contains(t, out, fx.ColumnID+"\tDoing\n")
+
+	out = f.mustRun("__complete", "columns", "get", fx.BoardID, "")
+	assert.Contains(t, out, fx.ColumnID+"\tDoing\n")
+
+	out = f.mustRun("__complete", "report", "--board", "")
+	assert.Contains(t, out, fx.BoardID+"\tPlatform\n")
+
+	// Cached lists are offered until they expire
+	f.srv.UpdateCard("1", func(c *fizzy.Card) { c.Title = "Renamed" })
+	out = f.mustRun("__complete", "cards", "show", "")
+	assert.Contains(t, out, "1\tUpgrade the database cluster\n")
+
+	// Offline or unconfigured completion offers nothing and no error
+	t.Setenv("FIZZY_BASE_URL", "http://127.0.0.1:1")
+	out, errOut, err := f.runStderr("__complete", "boards", "get", "")
+	assert.NoError(t, err)
+	assert.Equal(t, ":4\n", out)
+	assert.NotContains(t, errOut, "Error")
+
+	t.Setenv("FIZZY_TOKEN", "")
+	out = f.mustRun("__complete", "notifications", "read", "")
+	assert.Equal(t, ":4\n", out)
+}
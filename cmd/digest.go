@@ -0,0 +1,177 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/visionik/fizz/internal/digest"
+	"github.com/visionik/fizz/internal/format"
+	"github.com/visionik/fizz/internal/history"
+	"github.com/visionik/fizz/internal/kanban"
+	"github.com/visionik/libfizz-go/fizzy"
+)
+
+var digestCmd = &cobra.Command{
+	Use:   "digest",
+	Short: "Summarize recent activity for a standup",
+	Long: `Summarize what happened over a period: cards created and closed, cards
+moved between columns, comments and completed steps, grouped by person and
+board. Golden cards are marked with ★.
+
+Activity on a card is listed under its assignees, except that cards are
+listed under their creator and comments under their author. Moves come from
+the snapshots 'fizz sync' takes, since the API has no column history; without
+a snapshot from before the period they are left out.
+
+The digest prints as Markdown or plain text, ready to paste into chat or
+email, or as JSON or YAML with --format. --post adds it as a comment to a
+card instead, such as a standing "Daily standup" card.`,
+	Example: `  fizz digest
+  fizz digest --since=72h --board=03fbhiu9dgjo0viyrlya1x03a
+  fizz digest --user=me --style=plain
+  fizz digest --format=json
+  fizz digest --post=42`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client := GetClient()
+		ctx := cmd.Context()
+		out := cmd.OutOrStdout()
+
+		style, _ := cmd.Flags().GetString("style")
+		if style != digest.Markdown && style != digest.Plain {
+			return fmt.Errorf("invalid --style %q: use markdown or plain", style)
+		}
+		in := digest.Input{Until: time.Now(), Boards: map[string]string{}, Columns: map[string]string{}}
+		if value, _ := cmd.Flags().GetString("until"); value != "" {
+			t, err := parseTime(value)
+			if err != nil {
+				return fmt.Errorf("invalid --until: %w", err)
+			}
+			in.Until = t
+		}
+		value, _ := cmd.Flags().GetString("since")
+		since, err := parseSince(value, in.Until)
+		if err != nil {
+			return fmt.Errorf("invalid --since: %w", err)
+		}
+		if since.After(in.Until) {
+			return fmt.Errorf("--since is after --until")
+		}
+		in.Since = since
+
+		post, _ := cmd.Flags().GetString("post")
+		postID := ""
+		if post != "" {
+			card, err := client.Cards.Get(ctx, post)
+			if err != nil {
+				return fmt.Errorf("failed to get card: %w", err)
+			}
+			postID = strconv.Itoa(card.Number)
+		}
+
+		boardID, _ := cmd.Flags().GetString("board")
+		cards, err := listAllCards(ctx, client, boardID)
+		if err != nil {
+			return err
+		}
+		boards, err := client.Boards.List(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list boards: %w", err)
+		}
+		for _, b := range boards {
+			in.Boards[b.ID] = b.Name
+		}
+
+		active := map[string]bool{}
+		for _, card := range cards {
+			if !digest.Active(card, in.Since) {
+				continue
+			}
+			ca := digest.CardActivity{Card: card}
+			number := strconv.Itoa(card.Number)
+			if number != postID {
+				if ca.Comments, err = client.Comments.List(ctx, number); err != nil {
+					return fmt.Errorf("failed to list comments: %w", err)
+				}
+			}
+			if ca.Steps, err = client.Steps.List(ctx, number); err != nil {
+				return fmt.Errorf("failed to list steps: %w", err)
+			}
+			in.Cards = append(in.Cards, ca)
+			active[kanban.BoardID(&card)] = true
+		}
+
+		store, err := history.DefaultStore()
+		if err != nil {
+			return err
+		}
+		in.Before = map[string]*history.Snapshot{}
+		for id := range active {
+			columns, err := client.Columns.List(ctx, id)
+			if err != nil {
+				return fmt.Errorf("failed to list columns: %w", err)
+			}
+			for _, col := range columns {
+				in.Columns[col.ID] = col.Name
+			}
+			snaps, err := store.Load(id, "", in.Since.Local().Format("2006-01-02"))
+			if err != nil {
+				return err
+			}
+			for _, s := range snaps {
+				if !s.TakenAt.After(in.Since) {
+					in.Before[id] = s
+				}
+			}
+		}
+
+		d := digest.Build(in)
+		if users, _ := cmd.Flags().GetStringSlice("user"); len(users) > 0 {
+			for i, user := range users {
+				if users[i], err = client.ResolveUserID(ctx, user); err != nil {
+					return err
+				}
+			}
+			d.Filter(users)
+		}
+
+		if post != "" {
+			var body strings.Builder
+			if err := d.Write(&body, digest.HTML); err != nil {
+				return err
+			}
+			if _, err := client.Comments.Create(ctx, postID, &fizzy.CommentCreateOptions{Body: body.String()}); err != nil {
+				return fmt.Errorf("failed to create comment: %w", err)
+			}
+			fmt.Fprintf(out, "Posted digest to card %s: %s\n", post, d.SummaryLine())
+			return nil
+		}
+
+		if GetFormat() != "table" {
+			formatter, err := format.NewFormatter(GetFormat(), out)
+			if err != nil {
+				return err
+			}
+			if GetFormat() == "csv" {
+				return formatter.Format(d.Activities)
+			}
+			return formatter.Format(d)
+		}
+		return d.Write(out, style)
+	},
+}
+
+func init() {
+	digestCmd.Flags().String("since", "24h", "Start of the period: a duration such as 24h or 3d, or a date")
+	digestCmd.Flags().String("until", "", "End of the period, as YYYY-MM-DD or RFC 3339 (default: now)")
+	digestCmd.Flags().String("board", "", "Only this board (default: all boards)")
+	digestCmd.Flags().StringSlice("user", nil, "Only this person, by ID, name or 'me' (repeatable)")
+	digestCmd.Flags().String("style", digest.Markdown, "Text style: markdown or plain")
+	digestCmd.Flags().String("post", "", "Post the digest as a comment on this card instead of printing it")
+	digestCmd.RegisterFlagCompletionFunc("board", completeFlag(boardCandidates))
+	digestCmd.RegisterFlagCompletionFunc("user", completeFlag(userCandidates))
+	digestCmd.RegisterFlagCompletionFunc("post", completeFlag(cardCandidates))
+	rootCmd.AddCommand(digestCmd)
+}
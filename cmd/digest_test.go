@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/visionik/fizz/internal/history"
+	"github.com/visionik/libfizz-go/fizzy"
+)
+
+func TestDigest(t *testing.T) {
+	f := newFizzTest(t)
+	fx := f.seed()
+	isolateConfig(t)
+	third := f.srv.AddCard(fx.BoardID, "Rotate certificates", "")
+
+	// The period starts with this comment
+	var comment fizzy.Comment
+	require.NoError(t, json.Unmarshal([]byte(f.mustRun("comments", "create", "1", "--body=Cutover is done", "--format=json")), &comment))
+	since := comment.CreatedAt
+
+	// A snapshot from just before shows the new card in triage
+	store, err := history.DefaultStore()
+	require.NoError(t, err)
+	_, err = store.Save(&history.Snapshot{
+		Version: history.Version,
+		TakenAt: since.Add(-1),
+		Board:   history.Board{ID: fx.BoardID, Name: "Platform"},
+		Columns: []history.Column{{ID: fx.ColumnID, Name: "Doing", Position: 2}},
+		Cards:   []history.Card{{ID: third.ID, Number: third.Number, Status: "published"}},
+	})
+	require.NoError(t, err)
+
+	f.mustRun("cards", "move", "3", "--column="+fx.ColumnID)
+	f.mustRun("cards", "close", "2")
+	f.srv.AddStep("1", "Verify replicas", true)
+	f.srv.UpdateCard("1", func(c *fizzy.Card) { c.Golden = true })
+
+	out := f.mustRun("digest", "--since="+since.Format("2006-01-02T15:04:05.999999999Z07:00"))
+	assert.Contains(t, out, "1 closed, 1 moved, 1 comment, 1 step completed, 1 golden card ★.\n")
+	assert.Contains(t, out, `## Fizz Tester
+
+### Platform
+
+- Commented on #1 Upgrade the database cluster ★: “Cutover is done”
+`)
+	assert.Contains(t, out, `## Jordan Lee
+
+### Platform
+
+- Completed a step on #1 Upgrade the database cluster ★: Verify replicas
+`)
+	assert.Contains(t, out, `## Unassigned
+
+### Platform
+
+- Closed #2 Login fails with SSO
+- Moved #3 Rotate certificates: Triage → Doing
+`)
+
+	out = f.mustRun("digest", "--since="+since.Format("2006-01-02T15:04:05.999999999Z07:00"), "--user=me", "--style=plain")
+	assert.Contains(t, out, "0 cards created, 0 closed, 0 moved, 1 comment, 0 steps completed, 1 golden card ★.\n")
+	assert.Contains(t, out, "\nFizz Tester\n  Platform\n    - Commented on #1")
+	assert.NotContains(t, out, "Jordan Lee")
+
+	out = f.mustRun("digest", "--since=24h", "--format=json")
+	assert.Contains(t, out, `"activities": []`)
+	assert.Contains(t, out, `"moves_known": false`)
+
+	out = f.mustRun("digest", "--since="+since.Format("2006-01-02T15:04:05.999999999Z07:00"), "--post=2")
+	assert.Contains(t, out, "Posted digest to card 2: ")
+	card := f.mustRun("cards", "show", "2", "--no-pager")
+	assert.Contains(t, card, "Moved #3 Rotate certificates: Triage → Doing")
+
+	_, err = f.run("digest", "--style=html")
+	assert.EqualError(t, err, `invalid --style "html": use markdown or plain`)
+}
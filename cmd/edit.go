@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/visionik/fizz/internal/client"
+	"github.com/visionik/fizz/internal/editor"
+)
+
+// useEditor reports whether the command should open $VISUAL/$EDITOR. An
+// explicit --edit wins; otherwise the editor opens on a terminal when none
+// of the content flags were given and --no-input is not set.
+func useEditor(cmd *cobra.Command, contentFlags ...string) bool {
+	if flag := cmd.Flags().Lookup("edit"); flag != nil && flag.Changed {
+		edit, _ := cmd.Flags().GetBool("edit")
+		return edit
+	}
+	for _, name := range contentFlags {
+		if cmd.Flags().Changed(name) {
+			return false
+		}
+	}
+	return !noInputFlag && editor.IsInteractive(cmd.InOrStdin(), cmd.OutOrStdout())
+}
+
+// addEditFlag registers --edit on a command whose body can be written in an
+// editor
+func addEditFlag(cmd *cobra.Command) {
+	cmd.Flags().Bool("edit", false, "Write the body in $VISUAL/$EDITOR (default on a terminal when --body is omitted)")
+}
+
+// syncTags toggles tags on a card so it carries exactly want, given that it
+// currently carries have. It reports whether anything changed.
+func syncTags(ctx context.Context, c *client.Client, cardID string, have, want []string) (bool, error) {
+	haveSet := make(map[string]bool, len(have))
+	for _, tag := range have {
+		haveSet[tag] = true
+	}
+	wantSet := make(map[string]bool, len(want))
+	for _, tag := range want {
+		wantSet[tag] = true
+	}
+
+	changed := false
+	toggle := func(tag string) error {
+		if err := c.Cards.Tag(ctx, cardID, tag); err != nil {
+			return fmt.Errorf("failed to tag card with '%s': %w", tag, err)
+		}
+		changed = true
+		return nil
+	}
+	for _, tag := range want {
+		if !haveSet[tag] {
+			if err := toggle(tag); err != nil {
+				return changed, err
+			}
+			haveSet[tag] = true
+		}
+	}
+	for _, tag := range have {
+		if !wantSet[tag] {
+			if err := toggle(tag); err != nil {
+				return changed, err
+			}
+		}
+	}
+	return changed, nil
+}
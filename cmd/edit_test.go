@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeEditor points $VISUAL at a script that saves next as the edited file
+// and returns a function reporting what the editor was opened with
+func fakeEditor(t *testing.T, next string) func() string {
+	t.Helper()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "next"), []byte(next), 0o644))
+	script := filepath.Join(dir, "editor")
+	require.NoError(t, os.WriteFile(script, []byte(`#!/bin/sh
+cp "$1" "`+dir+`/seen"
+cat "`+dir+`/next" > "$1"
+`), 0o755))
+	t.Setenv("VISUAL", script)
+
+	return func() string {
+		seen, err := os.ReadFile(filepath.Join(dir, "seen"))
+		require.NoError(t, err)
+		return string(seen)
+	}
+}
+
+func TestCardsCreateEdit(t *testing.T) {
+	f := newFizzTest(t)
+	fx := f.seed()
+	seen := fakeEditor(t, "---\ntitle: Plan the migration\ntags: [bug, infra]\n---\nFirst paragraph.\n\nSecond paragraph.\n")
+
+	out := f.mustRun("cards", "create", "--board="+fx.BoardID, "--title=Draft", "--edit", "--format=json")
+	assert.Equal(t, "---\ntitle: Draft\ntags: []\n---\n", seen())
+	assert.Contains(t, out, `"title": "Plan the migration"`)
+	assert.Contains(t, out, `"description": "First paragraph.\n\nSecond paragraph."`)
+	assert.Contains(t, out, `"name": "bug"`)
+	assert.Contains(t, out, `"name": "infra"`)
+}
+
+func TestCardsUpdateEdit(t *testing.T) {
+	f := newFizzTest(t)
+	fx := f.seed()
+	seen := fakeEditor(t, "---\ntitle: Upgrade the database cluster\ntags: [bug]\n---\nMove to the new major version.\n")
+
+	out := f.mustRun("cards", "update", fx.CardNumber, "--edit", "--format=json")
+	assert.Equal(t, "---\ntitle: Upgrade the database cluster\ntags: [infra]\n---\nMove to the new major version.\n\nSchedule a maintenance window first.\n", seen())
+	assert.Contains(t, out, `"description": "Move to the new major version."`)
+	assert.Contains(t, out, `"name": "bug"`)
+	assert.NotContains(t, out, `"name": "infra"`)
+}
+
+func TestEditEmptyAborts(t *testing.T) {
+	f := newFizzTest(t)
+	fx := f.seed()
+	fakeEditor(t, "\n")
+
+	_, err := f.run("cards", "update", fx.CardNumber, "--edit")
+	require.EqualError(t, err, "aborted: empty file")
+
+	_, err = f.run("comments", "create", fx.CardNumber, "--edit")
+	require.EqualError(t, err, "aborted: empty file")
+	assert.Contains(t, f.mustRun("comments", "list", fx.CardNumber, "--format=json"), "Backups are verified.")
+	assert.NotContains(t, f.srv.Requests(), "POST /"+f.srv.Account+"/cards/"+fx.CardNumber+"/comments")
+}
+
+func TestCommentsCreateEdit(t *testing.T) {
+	f := newFizzTest(t)
+	fx := f.seed()
+	fakeEditor(t, "Looks good.\n\nShip it.\n")
+
+	out := f.mustRun("comments", "create", fx.CardNumber, "--edit", "--format=json")
+	assert.Contains(t, out, `Looks good.`)
+	assert.Contains(t, out, `Ship it.`)
+}
@@ -0,0 +1,316 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/visionik/fizz/internal/client"
+	"github.com/visionik/fizz/internal/format"
+	"github.com/visionik/fizz/internal/kanban"
+	"github.com/visionik/fizz/internal/report"
+	"github.com/visionik/libfizz-go/fizzy"
+	"golang.org/x/sync/errgroup"
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export cards as Markdown, CSV or an iCalendar file",
+	Long: `Export cards in a form people can read or other tools can load. The
+filters select cards the same way as 'fizz cards list', with --tag and
+--assignee as in 'fizz report'.
+
+Output goes to stdout, or to the file named by --output.`,
+}
+
+var exportMarkdownCmd = &cobra.Command{
+	Use:   "markdown",
+	Short: "Export cards as a Markdown document",
+	Long: `Write a Markdown document with a section per board and column, and under
+each card its fields, description, steps and comments. Cards outside the
+board's columns are listed under Triage, Not now or Closed.`,
+	Args: cobra.NoArgs,
+	Example: `  fizz export markdown --board=03fbhiu9dgjo0viyrlya1x03a -o platform.md
+  fizz export markdown --tag=release-4 --status=all`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c := GetClient()
+		cards, err := exportCards(cmd, c)
+		if err != nil {
+			return err
+		}
+		e, err := buildExport(cmd.Context(), c, cards, true)
+		if err != nil {
+			return err
+		}
+		return writeExport(cmd, e.Count(), "cards", func(w io.Writer) error {
+			return format.WriteMarkdown(w, e)
+		})
+	},
+}
+
+var exportCSVCmd = &cobra.Command{
+	Use:   "csv",
+	Short: "Export cards as a flat CSV file for spreadsheets",
+	Long: `Write one CSV row per card with its board, column, status, tags,
+assignees, dates, step progress, comment count, URL and description as
+plain text.`,
+	Args: cobra.NoArgs,
+	Example: `  fizz export csv -o cards.csv
+  fizz export csv --board=03fbhiu9dgjo0viyrlya1x03a --assignee=me`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c := GetClient()
+		cards, err := exportCards(cmd, c)
+		if err != nil {
+			return err
+		}
+		e, err := buildExport(cmd.Context(), c, cards, false)
+		if err != nil {
+			return err
+		}
+		return writeExport(cmd, e.Count(), "cards", func(w io.Writer) error {
+			return (&format.CSVFormatter{Writer: w}).Format(format.ToExportRows(e))
+		})
+	},
+}
+
+var exportICSCmd = &cobra.Command{
+	Use:   "ics",
+	Short: "Export postponed and due cards as an iCalendar file",
+	Long: `Write an iCalendar (.ics) file with an all-day event for each card in Not
+Now, on the day it was postponed, and for each open card on the day it falls
+due. Fizzy cards have no due date; an open card is due when it would be
+moved to Not now for lack of activity, --due-after days after it was last
+touched. Closed cards are left out.`,
+	Args: cobra.NoArgs,
+	Example: `  fizz export ics -o fizzy.ics
+  fizz export ics --board=03fbhiu9dgjo0viyrlya1x03a --due-after=14
+  fizz export ics --due-after=0 --status=not_now`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c := GetClient()
+		cards, err := exportCards(cmd, c)
+		if err != nil {
+			return err
+		}
+		dueAfter, _ := cmd.Flags().GetInt("due-after")
+		if dueAfter < 0 {
+			return fmt.Errorf("--due-after must not be negative")
+		}
+
+		name := "Fizzy"
+		if boardID, _ := cmd.Flags().GetString("board"); boardID != "" && len(cards) > 0 && cards[0].Board != nil {
+			name = cards[0].Board.Name
+		}
+		events := format.ToCalendarEvents(cards, dueAfter)
+		return writeExport(cmd, len(events), "events", func(w io.Writer) error {
+			return format.WriteICS(w, name, events, time.Now())
+		})
+	},
+}
+
+// exportCards lists the cards selected by the export filters, fetching only
+// as many pages as --limit needs
+func exportCards(cmd *cobra.Command, c *client.Client) ([]fizzy.Card, error) {
+	ctx := cmd.Context()
+	boardID, _ := cmd.Flags().GetString("board")
+	status, _ := cmd.Flags().GetString("status")
+	limit, _ := cmd.Flags().GetInt("limit")
+
+	tags, _ := cmd.Flags().GetStringSlice("tag")
+	assignees, _ := cmd.Flags().GetStringSlice("assignee")
+	for i, assignee := range assignees {
+		var err error
+		if assignees[i], err = c.ResolveUserID(ctx, assignee); err != nil {
+			return nil, err
+		}
+	}
+
+	statuses := []string{status}
+	if status == "all" {
+		statuses = []string{"", "closed"}
+	}
+	var cards []fizzy.Card
+	seen := map[string]bool{}
+	for _, status := range statuses {
+		pager := c.CardPages(&fizzy.CardListOptions{BoardID: boardID, Status: status}, "", 0)
+		for pager.More() && (limit <= 0 || len(cards) < limit) {
+			page, err := pager.Next(ctx)
+			if err != nil {
+				return nil, err
+			}
+			for _, card := range report.Filter(page, tags, assignees) {
+				if !seen[card.ID] {
+					seen[card.ID] = true
+					cards = append(cards, card)
+				}
+			}
+		}
+	}
+
+	if limit > 0 && len(cards) > limit {
+		cards = cards[:limit]
+	}
+	return cards, nil
+}
+
+// buildExport groups cards by board and column, in board order, and fetches
+// their steps and, if comments is set, their comments. The fetches run in
+// parallel, as many at once as the client allows; the first to fail cancels
+// the rest.
+func buildExport(ctx context.Context, c *client.Client, cards []fizzy.Card, comments bool) (format.Export, error) {
+	docs := make([]format.CardDocument, len(cards))
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(c.Concurrency)
+	for i, card := range cards {
+		g.Go(func() error {
+			var err error
+			docs[i], err = exportDocument(gctx, c, card, comments)
+			return err
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return format.Export{}, err
+	}
+
+	var e format.Export
+	boards := map[string]int{}
+	// columns maps each board's column IDs to their index in its Columns
+	var columns []map[string]int
+
+	for _, doc := range docs {
+		card := doc.Card
+		id := kanban.BoardID(&card)
+		i, ok := boards[id]
+		if !ok {
+			board, index, err := exportBoard(ctx, c, card)
+			if err != nil {
+				return e, err
+			}
+			i = len(e.Boards)
+			boards[id] = i
+			e.Boards = append(e.Boards, board)
+			columns = append(columns, index)
+		}
+		board := &e.Boards[i]
+
+		// Triage comes first, Not now and Closed last
+		col := 0
+		switch {
+		case card.Closed:
+			col = len(board.Columns) - 1
+		case card.Status == "not_now":
+			col = len(board.Columns) - 2
+		case card.ColumnID != nil:
+			if j, ok := columns[i][*card.ColumnID]; ok {
+				col = j
+			}
+		}
+		doc.Column = board.Columns[col].Name
+		board.Columns[col].Cards = append(board.Columns[col].Cards, doc)
+	}
+
+	// Columns without exported cards are left out
+	for i := range e.Boards {
+		var kept []format.ExportColumn
+		for _, col := range e.Boards[i].Columns {
+			if len(col.Cards) > 0 {
+				kept = append(kept, col)
+			}
+		}
+		e.Boards[i].Columns = kept
+	}
+	return e, nil
+}
+
+// exportDocument fetches a card's steps and, if comments is set, comments
+func exportDocument(ctx context.Context, c *client.Client, card fizzy.Card, comments bool) (format.CardDocument, error) {
+	doc := format.CardDocument{Card: card}
+	number := strconv.Itoa(card.Number)
+	var err error
+	if doc.Steps, err = c.Steps.List(ctx, number); err != nil {
+		return doc, fmt.Errorf("failed to list steps: %w", err)
+	}
+	if !comments {
+		return doc, nil
+	}
+	list, err := c.Comments.List(ctx, number)
+	if err != nil {
+		return doc, fmt.Errorf("failed to list comments: %w", err)
+	}
+	for _, comment := range list {
+		doc.Comments = append(doc.Comments, format.CommentDocument{Comment: comment})
+	}
+	return doc, nil
+}
+
+// exportBoard returns a card's board with its columns between Triage and
+// Not now and Closed, and the index of each column by ID
+func exportBoard(ctx context.Context, c *client.Client, card fizzy.Card) (format.ExportBoard, map[string]int, error) {
+	board := format.ExportBoard{}
+	if card.Board != nil {
+		board.Board = *card.Board
+	} else {
+		b, err := c.Boards.Get(ctx, card.BoardID)
+		if err != nil {
+			return board, nil, fmt.Errorf("failed to get board: %w", err)
+		}
+		board.Board = *b
+	}
+
+	list, err := c.Columns.List(ctx, board.Board.ID)
+	if err != nil {
+		return board, nil, fmt.Errorf("failed to list columns: %w", err)
+	}
+	index := map[string]int{}
+	board.Columns = append(board.Columns, format.ExportColumn{Name: kanban.TriageColumn})
+	for _, col := range list {
+		index[col.ID] = len(board.Columns)
+		board.Columns = append(board.Columns, format.ExportColumn{Name: col.Name})
+	}
+	board.Columns = append(board.Columns, format.ExportColumn{Name: kanban.NotNowColumn}, format.ExportColumn{Name: kanban.ClosedColumn})
+	return board, index, nil
+}
+
+// writeExport writes an export of count cards or events, as unit says, to
+// --output or stdout, with colors off so rich text comes out as Markdown
+func writeExport(cmd *cobra.Command, count int, unit string, write func(io.Writer) error) error {
+	noColor := color.NoColor
+	color.NoColor = true
+	defer func() { color.NoColor = noColor }()
+
+	output, _ := cmd.Flags().GetString("output")
+	if output == "" {
+		return write(cmd.OutOrStdout())
+	}
+
+	f, err := os.Create(output)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", output, err)
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write %s: %w", output, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", output, err)
+	}
+	fmt.Fprintf(cmd.ErrOrStderr(), "Wrote %s (%d %s)\n", output, count, unit)
+	return nil
+}
+
+func init() {
+	addFilterFlags(exportCmd, exportCmd.PersistentFlags())
+	exportCmd.PersistentFlags().String("status", "", "Filter by status; 'all' for open and closed cards")
+	exportCmd.PersistentFlags().Int("limit", 0, "Limit number of cards (0 = all)")
+	exportCmd.PersistentFlags().StringP("output", "o", "", "Write to this file instead of stdout")
+	exportICSCmd.Flags().Int("due-after", 30, "Days without activity after which an open card is due (0 = postponed cards only)")
+
+	exportCmd.AddCommand(exportMarkdownCmd)
+	exportCmd.AddCommand(exportCSVCmd)
+	exportCmd.AddCommand(exportICSCmd)
+	rootCmd.AddCommand(exportCmd)
+}
@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/visionik/fizz/internal/fakefizzy"
+	"github.com/visionik/libfizz-go/fizzy"
+)
+
+func TestExportMarkdown(t *testing.T) {
+	f := newFizzTest(t)
+	fx := f.seed()
+	f.srv.UpdateCard("2", func(c *fizzy.Card) { c.Status = "not_now" })
+
+	f.golden("export_markdown", f.mustRun("export", "markdown", "--board="+fx.BoardID))
+
+	out := f.mustRun("export", "markdown", "--tag=infra")
+	assert.Contains(t, out, "### #1 Upgrade the database cluster")
+	assert.NotContains(t, out, "Login fails with SSO")
+}
+
+func TestExportCSV(t *testing.T) {
+	f := newFizzTest(t)
+	f.seed()
+	path := filepath.Join(t.TempDir(), "cards.csv")
+
+	_, errOut, err := f.runStderr("export", "csv", "-o", path)
+	require.NoError(t, err)
+	assert.Equal(t, "Wrote "+path+" (2 cards)\n", errOut)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	rows, err := csv.NewReader(strings.NewReader(string(data))).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 3)
+	assert.Equal(t, []string{"number", "title", "board", "column", "status", "tags", "assignees", "creator", "created", "last_active", "closed", "steps", "comments", "url", "body"}, rows[0])
+	// Rows follow the board's columns, with Triage first
+	assert.Equal(t, []string{"2", "Login fails with SSO", "Platform", "Triage", "open"}, rows[1][:5])
+	assert.Equal(t, []string{"1", "Upgrade the database cluster", "Platform", "Doing", "open", "infra", "Jordan Lee"}, rows[2][:7])
+	assert.Equal(t, "1/2", rows[2][11])
+	assert.Equal(t, "1", rows[2][12])
+	assert.Equal(t, "Move to the new major version.\n\nSchedule a maintenance window first.", rows[2][14])
+}
+
+func TestExportICS(t *testing.T) {
+	f := newFizzTest(t)
+	f.seed()
+	f.srv.UpdateCard("2", func(c *fizzy.Card) { c.Status = "not_now" })
+
+	out := f.mustRun("export", "ics")
+	assert.True(t, strings.HasPrefix(out, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n"))
+	assert.Contains(t, out, "SUMMARY:Postponed: #2 Login fails with SSO\r\n")
+	assert.Contains(t, out, "SUMMARY:Due: #1 Upgrade the database cluster\r\n")
+	assert.Contains(t, out, "CATEGORIES:infra\r\n")
+	assert.True(t, strings.HasSuffix(out, "END:VCALENDAR\r\n"))
+
+	out = f.mustRun("export", "ics", "--due-after=0")
+	assert.Equal(t, 1, strings.Count(out, "BEGIN:VEVENT"))
+	assert.NotContains(t, out, "Due:")
+}
+
+func TestExportStopsAtFirstError(t *testing.T) {
+	f := newFizzTest(t)
+	fx := f.seed()
+	t.Setenv("FIZZ_CONCURRENCY", "1")
+	for _, title := range []string{"Rotate keys", "Renew certificates", "Prune backups"} {
+		f.srv.AddCard(fx.BoardID, title, "")
+	}
+	f.srv.Inject(fakefizzy.Fault{Method: http.MethodGet, Path: "/steps", Status: http.StatusNotFound})
+
+	_, err := f.run("export", "markdown")
+	require.Error(t, err)
+
+	steps := 0
+	for _, req := range f.srv.Requests() {
+		if strings.Contains(req, "/steps") {
+			steps++
+		}
+	}
+	assert.Equal(t, 1, steps, "the failed fetch cancels the rest")
+}
+
+func TestExportLimitPages(t *testing.T) {
+	f := newFizzTest(t)
+	fx := f.seed()
+	for _, title := range []string{"Rotate keys", "Renew certificates", "Prune backups"} {
+		f.srv.AddCard(fx.BoardID, title, "")
+	}
+	f.srv.PageSize = 2
+
+	out := f.mustRun("export", "markdown", "--limit=2")
+	assert.Equal(t, 2, strings.Count(out, "### #"))
+
+	pages := 0
+	for _, req := range f.srv.Requests() {
+		if strings.HasPrefix(req, "GET /"+f.srv.Account+"/cards.json") {
+			pages++
+		}
+	}
+	assert.Equal(t, 1, pages, "the first page holds enough cards")
+}
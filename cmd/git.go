@@ -0,0 +1,388 @@
+package cmd
+
+import (
+	"fmt"
+	"html"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/visionik/fizz/internal/config"
+	"github.com/visionik/fizz/internal/format"
+	"github.com/visionik/fizz/internal/git"
+	"github.com/visionik/fizz/internal/kanban"
+	"github.com/visionik/libfizz-go/fizzy"
+)
+
+// cardTrailer is the commit message trailer the hook adds
+const cardTrailer = "Fizzy-Card"
+
+// hookMarker identifies hooks installed by fizz
+const hookMarker = "# Installed by fizz"
+
+const hookScript = `#!/bin/sh
+` + hookMarker + `: adds the Fizzy card for this branch to commit messages
+command -v fizz >/dev/null 2>&1 || exit 0
+exec fizz git prepare-commit-msg "$@"
+`
+
+var gitCmd = &cobra.Command{
+	Use:   "git",
+	Short: "Git integration",
+	Long: `Work on cards from a git repository: start a branch for a card, reference
+the card in commit messages and post commits back to the card.
+
+Only a local repository is needed. When the origin remote points at a known
+host, commits in posted comments link to it.`,
+}
+
+var gitStartCmd = &cobra.Command{
+	Use:   "start <card-id-or-number>",
+	Short: "Create and check out a branch for a card",
+	Long: `Create and check out a branch for a card, assign the card to you and,
+optionally, move it to a column.
+
+The branch name comes from a Go template, set with --template or
+git.branch_template in the config file. Available fields are .Number, .Title,
+.ID, .Board and .User, with the functions slug and lower. The default is
+"{{.Number}}-{{slug .Title}}".
+
+Set git.start_column (a column name or ID) in the config file, or pass
+--column, to move the card when starting it.`,
+	Args: cobra.ExactArgs(1),
+	Example: `  fizz git start 123
+  fizz git start 123 --column=Doing
+  fizz git start 123 --template="feature/{{.Number}}-{{slug .Title}}"`,
+	ValidArgsFunction: completeCardArg,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client := GetClient()
+		ctx := cmd.Context()
+		out := cmd.OutOrStdout()
+
+		repo := git.Repo{}
+		if err := repo.Check(); err != nil {
+			return err
+		}
+
+		settings, err := config.LoadSettings()
+		if err != nil {
+			return err
+		}
+		tmpl := settings.Git.BranchTemplate
+		if cmd.Flags().Changed("template") {
+			tmpl, _ = cmd.Flags().GetString("template")
+		}
+		column := settings.Git.StartColumn
+		if cmd.Flags().Changed("column") {
+			column, _ = cmd.Flags().GetString("column")
+		}
+		noAssign, _ := cmd.Flags().GetBool("no-assign")
+
+		cardID, err := client.ResolveCardID(ctx, args[0])
+		if err != nil {
+			return err
+		}
+		card, err := client.Cards.Get(ctx, cardID)
+		if err != nil {
+			return fmt.Errorf("failed to get card: %w", err)
+		}
+
+		var me *fizzy.User
+		if !noAssign || strings.Contains(tmpl, ".User") {
+			if me, err = client.Me(ctx); err != nil {
+				return err
+			}
+		}
+
+		data := git.BranchData{Number: card.Number, Title: card.Title, ID: card.ID}
+		if card.Board != nil {
+			data.Board = card.Board.Name
+		}
+		if me != nil {
+			data.User = me.Name
+		}
+		branch, err := git.BranchName(tmpl, data)
+		if err != nil {
+			return err
+		}
+
+		created, err := repo.Start(branch, card.Number)
+		if err != nil {
+			return err
+		}
+		if created {
+			fmt.Fprintf(out, "Switched to a new branch '%s'\n", branch)
+		} else {
+			fmt.Fprintf(out, "Switched to branch '%s'\n", branch)
+		}
+
+		if !noAssign && !isAssigned(card, me.ID) {
+			if err := client.Cards.Assign(ctx, cardID, me.ID); err != nil {
+				return fmt.Errorf("failed to assign card: %w", err)
+			}
+			fmt.Fprintf(out, "Card %d assigned to %s\n", card.Number, me.Name)
+		}
+
+		if column != "" {
+			col, err := client.ResolveColumn(ctx, kanban.BoardID(card), column)
+			if err != nil {
+				return err
+			}
+			if card.ColumnID == nil || *card.ColumnID != col.ID {
+				if err := client.Cards.MoveToColumn(ctx, cardID, col.ID); err != nil {
+					return fmt.Errorf("failed to move card: %w", err)
+				}
+				fmt.Fprintf(out, "Card %d moved to %s\n", card.Number, col.Name)
+			}
+		}
+		return nil
+	},
+}
+
+var gitLinkCmd = &cobra.Command{
+	Use:   "link [card-id-or-number]",
+	Short: "Post the branch's commits as a comment on its card",
+	Long: `Post a list of commits as a comment on a card.
+
+The card defaults to the one the current branch was started for with 'fizz
+git start'. On other branches a number at the start of the branch name, as
+in 42-fix-login, is offered after asking. The commits default to those made
+since the branch was started, or since it left the default branch; pass
+--range to choose others.`,
+	Args: cobra.MaximumNArgs(1),
+	Example: `  fizz git link
+  fizz git link 123 --range=main..HEAD
+  fizz git link --print`,
+	ValidArgsFunction: completeCardArg,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		repo := git.Repo{}
+		if err := repo.Check(); err != nil {
+			return err
+		}
+		branch := repo.CurrentBranch()
+
+		var cardRef string
+		guessed := false
+		if len(args) == 1 {
+			cardRef = args[0]
+		} else if n, ok := repo.RecordedCard(branch); ok {
+			cardRef = strconv.Itoa(n)
+		} else if n, ok := git.BranchCard(branch); ok {
+			cardRef, guessed = strconv.Itoa(n), true
+		} else {
+			return fmt.Errorf("no card found for branch %q; pass a card number", branch)
+		}
+
+		revRange, _ := cmd.Flags().GetString("range")
+		if revRange == "" {
+			base, err := repo.Base(branch)
+			if err != nil {
+				return err
+			}
+			revRange = base + "..HEAD"
+		}
+		commits, err := repo.Log(revRange)
+		if err != nil {
+			return err
+		}
+		if len(commits) == 0 {
+			return fmt.Errorf("no commits in %s", revRange)
+		}
+
+		body := commitsComment(branch, commits, repo.WebURL())
+		if printOnly, _ := cmd.Flags().GetBool("print"); printOnly {
+			fmt.Fprintln(cmd.OutOrStdout(), format.RichText(body))
+			return nil
+		}
+
+		if guessed {
+			if !interactive(cmd) {
+				return fmt.Errorf("branch %q was not started with 'fizz git start'; pass the card number", branch)
+			}
+			ok, err := confirm(cmd, fmt.Sprintf("Post %d commit(s) to card %s, taken from the branch name?", len(commits), cardRef))
+			if err != nil {
+				return err
+			}
+			if !ok {
+				return fmt.Errorf("aborted: nothing posted")
+			}
+		}
+
+		client := GetClient()
+		cardID, err := client.ResolveCardID(cmd.Context(), cardRef)
+		if err != nil {
+			return err
+		}
+		comment, err := client.Comments.Create(cmd.Context(), cardID, &fizzy.CommentCreateOptions{Body: body})
+		if err != nil {
+			return fmt.Errorf("failed to create comment: %w", err)
+		}
+
+		if GetFormat() != "table" {
+			formatter, err := format.NewFormatter(GetFormat(), cmd.OutOrStdout())
+			if err != nil {
+				return err
+			}
+			return formatter.Format(comment)
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Linked %d commit(s) to card %s\n", len(commits), cardRef)
+		return nil
+	},
+}
+
+var gitHookCmd = &cobra.Command{
+	Use:   "hook",
+	Short: "Manage the prepare-commit-msg hook",
+	Long: `Install or remove a prepare-commit-msg hook that adds a "` + cardTrailer + `: #123"
+trailer to commit messages on branches started for a card with 'fizz git
+start'. Numbers in other branch names are not used.`,
+}
+
+var gitHookInstallCmd = &cobra.Command{
+	Use:         "install",
+	Short:       "Install the prepare-commit-msg hook in this repository",
+	Args:        cobra.NoArgs,
+	Annotations: map[string]string{noClientAnnotation: "true"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, err := hookPath()
+		if err != nil {
+			return err
+		}
+
+		force, _ := cmd.Flags().GetBool("force")
+		if existing, err := os.ReadFile(path); err == nil && !strings.Contains(string(existing), hookMarker) && !force {
+			return fmt.Errorf("%s already exists; use --force to replace it", path)
+		}
+
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return fmt.Errorf("failed to create hooks directory: %w", err)
+		}
+		if err := os.WriteFile(path, []byte(hookScript), 0o755); err != nil {
+			return fmt.Errorf("failed to write hook: %w", err)
+		}
+		if err := os.Chmod(path, 0o755); err != nil {
+			return fmt.Errorf("failed to write hook: %w", err)
+		}
+
+		fmt.Fprintf(cmd.OutOrStdout(), "Installed %s\n", path)
+		return nil
+	},
+}
+
+var gitHookUninstallCmd = &cobra.Command{
+	Use:         "uninstall",
+	Short:       "Remove the prepare-commit-msg hook installed by fizz",
+	Args:        cobra.NoArgs,
+	Annotations: map[string]string{noClientAnnotation: "true"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, err := hookPath()
+		if err != nil {
+			return err
+		}
+
+		existing, err := os.ReadFile(path)
+		if os.IsNotExist(err) {
+			fmt.Fprintln(cmd.OutOrStdout(), "No hook installed")
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read hook: %w", err)
+		}
+		if !strings.Contains(string(existing), hookMarker) {
+			return fmt.Errorf("%s was not installed by fizz; leaving it alone", path)
+		}
+		if err := os.Remove(path); err != nil {
+			return fmt.Errorf("failed to remove hook: %w", err)
+		}
+
+		fmt.Fprintf(cmd.OutOrStdout(), "Removed %s\n", path)
+		return nil
+	},
+}
+
+// gitPrepareCommitMsgCmd is run by the installed hook
+var gitPrepareCommitMsgCmd = &cobra.Command{
+	Use:         "prepare-commit-msg <message-file> [source] [sha]",
+	Short:       "Add the branch's card to a commit message (run by the git hook)",
+	Hidden:      true,
+	Args:        cobra.RangeArgs(1, 3),
+	Annotations: map[string]string{noClientAnnotation: "true"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) > 1 && args[1] == "merge" {
+			return nil
+		}
+
+		// Only a recorded card: a hook cannot ask about a guessed one
+		repo := git.Repo{}
+		n, ok := repo.RecordedCard(repo.CurrentBranch())
+		if !ok {
+			return nil
+		}
+		return repo.AddTrailer(args[0], cardTrailer, "#"+strconv.Itoa(n))
+	},
+}
+
+func hookPath() (string, error) {
+	repo := git.Repo{}
+	if err := repo.Check(); err != nil {
+		return "", err
+	}
+	dir, err := repo.HooksDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "prepare-commit-msg"), nil
+}
+
+func isAssigned(card *fizzy.Card, userID string) bool {
+	for _, u := range card.Assignees {
+		if u.ID == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// commitsComment renders commits as an HTML list, linking each to the web
+// host when webURL is known
+func commitsComment(branch string, commits []git.Commit, webURL string) string {
+	var b strings.Builder
+	if branch != "" {
+		fmt.Fprintf(&b, "<p>Commits on <code>%s</code>:</p>", html.EscapeString(branch))
+	} else {
+		b.WriteString("<p>Commits:</p>")
+	}
+	b.WriteString("<ul>")
+	for _, c := range commits {
+		sha := "<code>" + c.SHA[:7] + "</code>"
+		if webURL != "" {
+			sha = fmt.Sprintf(`<a href="%s/commit/%s">%s</a>`, html.EscapeString(webURL), c.SHA, sha)
+		}
+		fmt.Fprintf(&b, "<li>%s %s</li>", sha, html.EscapeString(c.Subject))
+	}
+	b.WriteString("</ul>")
+	return b.String()
+}
+
+func init() {
+	gitStartCmd.Flags().String("template", "", "Branch name template (default from config)")
+	gitStartCmd.Flags().String("column", "", "Move the card to this column, by name or ID")
+	gitStartCmd.Flags().Bool("no-assign", false, "Do not assign the card to yourself")
+	gitStartCmd.RegisterFlagCompletionFunc("column", completeFlag(columnCandidates))
+
+	gitLinkCmd.Flags().String("range", "", "Commit range to post (default: the branch's commits)")
+	gitLinkCmd.Flags().Bool("print", false, "Print the comment instead of posting it")
+
+	gitHookInstallCmd.Flags().Bool("force", false, "Replace an existing hook")
+
+	gitHookCmd.AddCommand(gitHookInstallCmd)
+	gitHookCmd.AddCommand(gitHookUninstallCmd)
+
+	gitCmd.AddCommand(gitStartCmd)
+	gitCmd.AddCommand(gitLinkCmd)
+	gitCmd.AddCommand(gitHookCmd)
+	gitCmd.AddCommand(gitPrepareCommitMsgCmd)
+	rootCmd.AddCommand(gitCmd)
+}
@@ -0,0 +1,175 @@
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newGitRepo creates a repository with one commit on main and makes it the
+// working directory
+func newGitRepo(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	dir := t.TempDir()
+	t.Setenv("GIT_CONFIG_GLOBAL", filepath.Join(dir, ".gitconfig-global"))
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	t.Setenv("GIT_AUTHOR_NAME", "Fizz Tester")
+	t.Setenv("GIT_AUTHOR_EMAIL", "fizz.tester@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "Fizz Tester")
+	t.Setenv("GIT_COMMITTER_EMAIL", "fizz.tester@example.com")
+	t.Setenv("FIZZ_CONFIG_DIR", filepath.Join(dir, ".fizz"))
+	t.Chdir(dir)
+
+	gitRun(t, "init", "--quiet", "--initial-branch=main")
+	gitCommit(t, "README.md", "Initial commit")
+	return dir
+}
+
+func gitRun(t *testing.T, args ...string) string {
+	t.Helper()
+	out, err := exec.Command("git", args...).CombinedOutput()
+	require.NoError(t, err, "git %v: %s", args, out)
+	return string(out)
+}
+
+func gitCommit(t *testing.T, file, subject string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(file, []byte(subject+"\n"), 0o644))
+	gitRun(t, "add", file)
+	gitRun(t, "commit", "--quiet", "-m", subject)
+}
+
+func TestGitStart(t *testing.T) {
+	f := newFizzTest(t)
+	fx := f.seed()
+	newGitRepo(t)
+
+	out := f.mustRun("git", "start", fx.CardNumber, "--column=done")
+	assert.Equal(t, "Switched to a new branch '1-upgrade-the-database-cluster'\n"+
+		"Card 1 assigned to Fizz Tester\n"+
+		"Card 1 moved to Done\n", out)
+	assert.Equal(t, "1-upgrade-the-database-cluster\n", gitRun(t, "branch", "--show-current"))
+
+	card := f.mustRun("cards", "get", fx.CardNumber, "--format=json")
+	assert.Contains(t, card, `"name": "Fizz Tester"`)
+	assert.Contains(t, card, `"name": "Jordan Lee"`)
+
+	// Starting again switches back without toggling the assignment off
+	gitRun(t, "checkout", "--quiet", "main")
+	out = f.mustRun("git", "start", fx.CardNumber)
+	assert.Equal(t, "Switched to branch '1-upgrade-the-database-cluster'\n", out)
+	assert.Contains(t, f.mustRun("cards", "get", fx.CardNumber, "--format=json"), `"name": "Fizz Tester"`)
+}
+
+func TestGitStartTemplateFromConfig(t *testing.T) {
+	f := newFizzTest(t)
+	f.seed()
+	dir := newGitRepo(t)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".fizz.yaml"),
+		[]byte("git:\n  branch_template: \"feature/{{.Number}}-{{slug .Title}}\"\n"), 0o644))
+
+	out := f.mustRun("git", "start", "2", "--no-assign")
+	assert.Equal(t, "Switched to a new branch 'feature/2-login-fails-with-sso'\n", out)
+}
+
+func TestGitLink(t *testing.T) {
+	f := newFizzTest(t)
+	fx := f.seed()
+	newGitRepo(t)
+
+	f.mustRun("git", "start", fx.CardNumber, "--no-assign")
+	gitCommit(t, "db.txt", "Add backup script")
+	gitCommit(t, "upgrade.txt", "Run <upgrade> & verify")
+	gitRun(t, "remote", "add", "origin", "git@github.com:example/platform.git")
+
+	out := f.mustRun("git", "link", "--print")
+	assert.Contains(t, out, "Commits on `1-upgrade-the-database-cluster`:")
+	assert.Regexp(t, `- \[`+"`"+`[0-9a-f]{7}`+"`"+`\]\(https://github.com/example/platform/commit/[0-9a-f]{40}\) Add backup script`, out)
+	assert.Contains(t, out, "Run <upgrade> & verify")
+	assert.NotContains(t, out, "Initial commit")
+
+	assert.Equal(t, "Linked 2 commit(s) to card 1\n", f.mustRun("git", "link"))
+	comments := f.mustRun("comments", "list", fx.CardNumber, "--format=yaml")
+	assert.Contains(t, comments, "Add backup script")
+	assert.Contains(t, comments, `Run &lt;upgrade&gt; &amp; verify`)
+}
+
+func TestGitLinkGuessedCard(t *testing.T) {
+	f := newFizzTest(t)
+	f.seed()
+	newGitRepo(t)
+	gitRun(t, "checkout", "--quiet", "-b", "2-sso-fix")
+	gitCommit(t, "sso.txt", "Fix SSO redirect")
+
+	// Without a terminal to confirm on, a number from the branch name is
+	// not used
+	_, err := f.run("git", "link")
+	assert.EqualError(t, err, `branch "2-sso-fix" was not started with 'fizz git start'; pass the card number`)
+
+	oldInteractive := interactive
+	interactive = func(cmd *cobra.Command) bool { return true }
+	t.Cleanup(func() { interactive = oldInteractive })
+	t.Cleanup(func() { rootCmd.SetIn(nil) })
+
+	rootCmd.SetIn(strings.NewReader("n\n"))
+	_, err = f.run("git", "link")
+	assert.EqualError(t, err, "aborted: nothing posted")
+	assert.NotContains(t, f.mustRun("comments", "list", "2"), "Fix SSO redirect")
+
+	rootCmd.SetIn(strings.NewReader("y\n"))
+	out := f.mustRun("git", "link")
+	assert.Equal(t, "Post 1 commit(s) to card 2, taken from the branch name? [y/N] Linked 1 commit(s) to card 2\n", out)
+	assert.Contains(t, f.mustRun("comments", "list", "2"), "Fix SSO redirect")
+}
+
+func TestGitHook(t *testing.T) {
+	f := newFizzTest(t)
+	fx := f.seed()
+	dir := newGitRepo(t)
+	gitRun(t, "checkout", "--quiet", "-b", "2026-release")
+	f.mustRun("git", "start", fx.CardNumber, "--no-assign")
+
+	// The hook runs without credentials
+	t.Setenv("FIZZY_TOKEN", "")
+
+	out := f.mustRun("git", "hook", "install")
+	hook := filepath.Join(dir, ".git", "hooks", "prepare-commit-msg")
+	assert.Equal(t, "Installed "+hook+"\n", out)
+	info, err := os.Stat(hook)
+	require.NoError(t, err)
+	assert.NotZero(t, info.Mode()&0o111)
+	f.mustRun("git", "hook", "install")
+
+	msg := filepath.Join(t.TempDir(), "COMMIT_EDITMSG")
+	require.NoError(t, os.WriteFile(msg, []byte("Add backup script\n"), 0o644))
+	f.mustRun("git", "prepare-commit-msg", msg, "message")
+	f.mustRun("git", "prepare-commit-msg", msg, "message")
+	data, err := os.ReadFile(msg)
+	require.NoError(t, err)
+	assert.Equal(t, "Add backup script\n\nFizzy-Card: #1\n", string(data))
+
+	// A number in the name of a branch fizz did not start is not used
+	gitRun(t, "checkout", "--quiet", "2026-release")
+	require.NoError(t, os.WriteFile(msg, []byte("Bump version\n"), 0o644))
+	f.mustRun("git", "prepare-commit-msg", msg, "message")
+	data, err = os.ReadFile(msg)
+	require.NoError(t, err)
+	assert.Equal(t, "Bump version\n", string(data))
+
+	assert.Equal(t, "Removed "+hook+"\n", f.mustRun("git", "hook", "uninstall"))
+
+	require.NoError(t, os.WriteFile(hook, []byte("#!/bin/sh\nexit 0\n"), 0o755))
+	_, err = f.run("git", "hook", "install")
+	assert.ErrorContains(t, err, "already exists; use --force to replace it")
+}
@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/stretchr/testify/require"
+	"github.com/visionik/fizz/internal/fakefizzy"
+	"github.com/visionik/libfizz-go/fizzy"
+)
+
+var update = flag.Bool("update", false, "rewrite golden files")
+
+// packageDir is where the tests started, so golden files are found after a
+// test changes directory
+var packageDir, _ = os.Getwd()
+
+// publicURL is used for links in responses so golden output is stable
+const publicURL = "https://app.fizzy.do"
+
+// fizzTest runs fizz commands against an in-memory fake of the Fizzy API
+type fizzTest struct {
+	t   *testing.T
+	srv *fakefizzy.Server
+}
+
+// newFizzTest starts a fake API and points fizz at it
+func newFizzTest(t *testing.T) *fizzTest {
+	t.Helper()
+
+	srv := fakefizzy.New()
+	srv.SetPublicURL(publicURL)
+	t.Cleanup(srv.Close)
+
+	t.Setenv("FIZZY_TOKEN", srv.Token)
+	t.Setenv("FIZZY_ACCOUNT", srv.Account)
+	t.Setenv("FIZZY_BASE_URL", srv.URL)
+	t.Setenv("FIZZ_RECORD", "")
+	t.Setenv("FIZZ_REPLAY", "")
+	t.Setenv("FIZZ_CACHE_DIR", t.TempDir())
+	// Aliases and settings in the developer's own config must not leak in
+	t.Setenv("FIZZ_CONFIG_DIR", t.TempDir())
+
+	noColor := color.NoColor
+	color.NoColor = true
+	t.Cleanup(func() { color.NoColor = noColor })
+
+	return &fizzTest{t: t, srv: srv}
+}
+
+// run executes fizz with args and returns its output
+func (f *fizzTest) run(args ...string) (string, error) {
+	out, _, err := f.runStderr(args...)
+	return out, err
+}
+
+// runStderr executes fizz with args and returns its output and error output
+func (f *fizzTest) runStderr(args ...string) (string, string, error) {
+	var out, errOut bytes.Buffer
+	err := Run(context.Background(), args, &out, &errOut)
+	return out.String(), errOut.String(), err
+}
+
+// mustRun executes fizz with args and fails the test on error
+func (f *fizzTest) mustRun(args ...string) string {
+	f.t.Helper()
+	out, err := f.run(args...)
+	require.NoError(f.t, err, "fizz %s", strings.Join(args, " "))
+	return out
+}
+
+// golden compares got with testdata/golden/<name>.golden, rewriting the file
+// when -update is set
+func (f *fizzTest) golden(name, got string) {
+	f.t.Helper()
+
+	path := filepath.Join(packageDir, "testdata", "golden", name+".golden")
+	if *update {
+		require.NoError(f.t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(f.t, os.WriteFile(path, []byte(got), 0o644))
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	require.NoError(f.t, err, "missing golden file; run go test ./cmd -update")
+	require.Equal(f.t, string(want), got, "output differs from %s", path)
+}
+
+// fixture is the IDs of the standard test data
+type fixture struct {
+	BoardID    string
+	ColumnID   string
+	CardNumber string
+	CommentID  string
+}
+
+// seed fills the fake API with a small, representative board
+func (f *fizzTest) seed() fixture {
+	srv := f.srv
+	jordan := srv.AddUser("Jordan Lee")
+
+	board := srv.AddBoard("Platform")
+	srv.AddBoard("Marketing")
+	srv.AddColumn(board.ID, "Backlog")
+	doing := srv.AddColumn(board.ID, "Doing")
+	srv.AddColumn(board.ID, "Done")
+	srv.AddTag("bug", "#ff0000")
+	infra := srv.AddTag("infra", "#0000ff")
+
+	card := srv.AddCard(board.ID, "Upgrade the database cluster", "Move to the new major version.\n\nSchedule a maintenance window first.")
+	ref := strconv.Itoa(card.Number)
+	srv.UpdateCard(ref, func(c *fizzy.Card) {
+		c.ColumnID = &doing.ID
+		c.Assignees = append(c.Assignees, jordan)
+		c.Tags = append(c.Tags, infra)
+	})
+	srv.AddStep(ref, "Take a backup", true)
+	srv.AddStep(ref, "Run the upgrade", false)
+	comment := srv.AddComment(ref, "Backups are verified.")
+	srv.AddReaction(comment.ID, "👍")
+
+	second := srv.AddCard(board.ID, "Login fails with SSO", "")
+	srv.AddNotification(strconv.Itoa(second.Number), "Event::Assignment")
+
+	return fixture{
+		BoardID:    board.ID,
+		ColumnID:   doing.ID,
+		CardNumber: "1",
+		CommentID:  comment.ID,
+	}
+}
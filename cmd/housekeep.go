@@ -0,0 +1,335 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/visionik/fizz/internal/client"
+	"github.com/visionik/fizz/internal/config"
+	"github.com/visionik/fizz/internal/format"
+	"github.com/visionik/fizz/internal/housekeep"
+	"github.com/visionik/fizz/internal/kanban"
+	"github.com/visionik/libfizz-go/fizzy"
+)
+
+// housekeepResult is a card a housekeeping rule acts on, or would act on
+// without --apply
+type housekeepResult struct {
+	Rule     string   `json:"rule"`
+	CardID   string   `json:"card_id"`
+	Card     int      `json:"card"`
+	Title    string   `json:"title"`
+	Column   string   `json:"column"`
+	IdleDays int      `json:"idle_days"`
+	Actions  []string `json:"actions"`
+	Applied  bool     `json:"applied"`
+	Error    string   `json:"error,omitempty"`
+}
+
+var housekeepCmd = &cobra.Command{
+	Use:   "housekeep [rule...]",
+	Short: "Tag, postpone or close cards nobody has touched",
+	Long: `Find open cards that have been idle for a while and act on them with the
+rules under "housekeeping" in ~/.config/fizz/config.yaml (or a project's
+.fizz.yaml, whose list replaces the user's):
+
+  housekeeping:
+    - name: stale-backlog
+      board: 03fbhiu9dgjo0viyrlya1x03a
+      column: Backlog
+      idle_days: 90
+      actions:
+        tag: stale
+        comment: No activity for {{days}} days, so this was tagged stale.
+    - name: close-abandoned
+      tags: [stale]
+      idle_days: 180
+      actions:
+        close: true
+
+A rule matches open cards whose last activity is at least idle_days old,
+narrowed by board, column (by name or ID; "Triage" for cards in no column)
+and tags. Its actions run in order: tag, comment, then postpone or close.
+Cards that already carry the rule's tag are skipped. Every matching rule
+runs, in the order listed, until one postpones or closes the card; a tag
+added by one rule counts for the rules after it.
+
+Without --apply the command only shows what would change. With --apply it
+makes the changes and records each in housekeep.log in the config
+directory; see 'fizz housekeep log'.`,
+	Example: `  fizz housekeep
+  fizz housekeep --apply
+  fizz housekeep stale-backlog --apply`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client := GetClient()
+		ctx := cmd.Context()
+		out := cmd.OutOrStdout()
+
+		settings, err := config.LoadSettings()
+		if err != nil {
+			return err
+		}
+		rules, err := housekeep.Rules(settings)
+		if err != nil {
+			return err
+		}
+		if len(rules) == 0 {
+			return fmt.Errorf("no housekeeping rules; add them under \"housekeeping\" in the config file (see 'fizz housekeep --help')")
+		}
+		if len(args) > 0 {
+			var selected []housekeep.Rule
+			for _, name := range args {
+				rule, err := housekeep.Find(rules, name)
+				if err != nil {
+					return err
+				}
+				selected = append(selected, *rule)
+			}
+			rules = selected
+		}
+
+		apply, _ := cmd.Flags().GetBool("apply")
+		now := time.Now()
+		if at, _ := cmd.Flags().GetString("at"); at != "" {
+			if now, err = time.Parse(time.RFC3339, at); err != nil {
+				return fmt.Errorf("invalid --at %q: use RFC 3339, e.g. 2026-01-05T09:00:00Z", at)
+			}
+		}
+
+		cards := map[string][]fizzy.Card{}
+		columns := map[string]map[string]string{}
+		removed := map[string]bool{}
+		// tagged holds the tags rules added, so later rules see them
+		tagged := map[string][]fizzy.Tag{}
+		results := []housekeepResult{}
+		failed := 0
+		for _, rule := range rules {
+			list, ok := cards[rule.Board]
+			if !ok {
+				if list, err = client.ListAllCards(ctx, &fizzy.CardListOptions{BoardID: rule.Board}); err != nil {
+					return fmt.Errorf("failed to list cards: %w", err)
+				}
+				cards[rule.Board] = list
+			}
+
+			for _, card := range list {
+				if removed[card.ID] {
+					continue
+				}
+				if added := tagged[card.ID]; len(added) > 0 {
+					card.Tags = slices.Concat(card.Tags, added)
+				}
+				column, err := columnName(ctx, client, columns, card)
+				if err != nil {
+					return err
+				}
+				if !rule.Match(card, column, now) {
+					continue
+				}
+
+				result := housekeepResult{
+					Rule:     rule.Name,
+					CardID:   card.ID,
+					Card:     card.Number,
+					Title:    card.Title,
+					Column:   column,
+					IdleDays: housekeep.IdleDays(card, now),
+					Actions:  rule.Describe(),
+				}
+				if rule.Removes() {
+					removed[card.ID] = true
+				}
+				if apply {
+					done, err := applyHousekeeping(ctx, client, rule, card, now)
+					entry := housekeep.Entry{
+						Time:     time.Now(),
+						Rule:     rule.Name,
+						CardID:   card.ID,
+						Card:     card.Number,
+						Title:    card.Title,
+						IdleDays: result.IdleDays,
+						Actions:  done,
+					}
+					if err != nil {
+						failed++
+						entry.Error = err.Error()
+						result.Error = err.Error()
+						fmt.Fprintf(cmd.ErrOrStderr(), "Error: card %d: %v\n", card.Number, err)
+					}
+					result.Applied = err == nil
+					result.Actions = done
+					if len(done) > 0 && rule.Actions.Tag != "" {
+						tagged[card.ID] = append(tagged[card.ID], fizzy.Tag{Name: rule.Actions.Tag})
+					}
+					if err := housekeep.Record(entry); err != nil {
+						return err
+					}
+				} else if rule.Actions.Tag != "" {
+					tagged[card.ID] = append(tagged[card.ID], fizzy.Tag{Name: rule.Actions.Tag})
+				}
+				results = append(results, result)
+			}
+		}
+
+		if err := writeHousekeepResults(cmd, results, apply); err != nil {
+			return err
+		}
+		if failed > 0 {
+			return fmt.Errorf("%d card(s) failed", failed)
+		}
+		if !apply && len(results) > 0 && GetFormat() == "table" {
+			fmt.Fprintf(out, "\n%d change(s) previewed; run with --apply to make them\n", len(results))
+		}
+		return nil
+	},
+}
+
+var housekeepLogCmd = &cobra.Command{
+	Use:         "log",
+	Short:       "Show the housekeeping audit log",
+	Args:        cobra.NoArgs,
+	Annotations: map[string]string{noClientAnnotation: "true"},
+	Example: `  fizz housekeep log
+  fizz housekeep log --limit=100 --format=json`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		entries, err := housekeep.ReadLog()
+		if err != nil {
+			return err
+		}
+		if entries == nil {
+			entries = []housekeep.Entry{}
+		}
+		if limit, _ := cmd.Flags().GetInt("limit"); limit > 0 && len(entries) > limit {
+			entries = entries[len(entries)-limit:]
+		}
+
+		formatter, err := format.NewFormatter(GetFormat(), cmd.OutOrStdout())
+		if err != nil {
+			return err
+		}
+		if GetFormat() == "table" {
+			displays := make([]format.HousekeepLogDisplay, len(entries))
+			for i, e := range entries {
+				displays[i] = format.ToHousekeepLogDisplay(e)
+			}
+			return formatter.Format(displays)
+		}
+		return formatter.Format(entries)
+	},
+}
+
+// applyHousekeeping runs a rule's actions on a card in order and returns
+// the ones that succeeded
+func applyHousekeeping(ctx context.Context, c *client.Client, rule housekeep.Rule, card fizzy.Card, now time.Time) ([]string, error) {
+	var done []string
+	number := strconv.Itoa(card.Number)
+	if rule.Actions.Tag != "" {
+		if err := c.Cards.Tag(ctx, number, rule.Actions.Tag); err != nil {
+			return done, fmt.Errorf("failed to tag card: %w", err)
+		}
+		done = append(done, "tag "+rule.Actions.Tag)
+	}
+	if rule.Actions.Comment != "" {
+		body := rule.Comment(card, now)
+		if _, err := c.Comments.Create(ctx, number, &fizzy.CommentCreateOptions{Body: body}); err != nil {
+			return done, fmt.Errorf("failed to create comment: %w", err)
+		}
+		done = append(done, "comment")
+	}
+	if rule.Actions.Postpone {
+		if err := c.Cards.Postpone(ctx, number); err != nil {
+			return done, fmt.Errorf("failed to postpone card: %w", err)
+		}
+		done = append(done, "postpone")
+	}
+	if rule.Actions.Close {
+		if err := c.Cards.Close(ctx, number); err != nil {
+			return done, fmt.Errorf("failed to close card: %w", err)
+		}
+		done = append(done, "close")
+	}
+	return done, nil
+}
+
+// columnName names the column a card is in, listing each board's columns
+// once into names
+func columnName(ctx context.Context, c *client.Client, names map[string]map[string]string, card fizzy.Card) (string, error) {
+	if card.ColumnID == nil || *card.ColumnID == "" {
+		return kanban.TriageColumn, nil
+	}
+	boardID := kanban.BoardID(&card)
+	board, ok := names[boardID]
+	if !ok {
+		columns, err := c.Columns.List(ctx, boardID)
+		if err != nil {
+			return "", fmt.Errorf("failed to list columns: %w", err)
+		}
+		board = map[string]string{}
+		for _, col := range columns {
+			board[col.ID] = col.Name
+		}
+		names[boardID] = board
+	}
+	if name, ok := board[*card.ColumnID]; ok {
+		return name, nil
+	}
+	return *card.ColumnID, nil
+}
+
+// writeHousekeepResults prints the cards housekeeping changed or would
+// change
+func writeHousekeepResults(cmd *cobra.Command, results []housekeepResult, apply bool) error {
+	out := cmd.OutOrStdout()
+	if GetFormat() != "table" {
+		formatter, err := format.NewFormatter(GetFormat(), out)
+		if err != nil {
+			return err
+		}
+		return formatter.Format(results)
+	}
+
+	if len(results) == 0 {
+		fmt.Fprintln(out, "No idle cards")
+		return nil
+	}
+	if apply {
+		for _, r := range results {
+			if r.Error == "" {
+				fmt.Fprintf(out, "%s: card %d %q: %s\n", r.Rule, r.Card, r.Title, strings.Join(r.Actions, ", "))
+			}
+		}
+		return nil
+	}
+
+	formatter, err := format.NewFormatter(GetFormat(), out)
+	if err != nil {
+		return err
+	}
+	displays := make([]format.HousekeepDisplay, len(results))
+	for i, r := range results {
+		displays[i] = format.HousekeepDisplay{
+			Rule:    r.Rule,
+			Card:    r.Card,
+			Title:   r.Title,
+			Column:  r.Column,
+			Idle:    strconv.Itoa(r.IdleDays) + "d",
+			Actions: strings.Join(r.Actions, ", "),
+		}
+	}
+	return formatter.Format(displays)
+}
+
+func init() {
+	housekeepCmd.Flags().Bool("apply", false, "Make the changes instead of previewing them")
+	housekeepCmd.Flags().String("at", "", "Measure idle time as of this time (RFC 3339) instead of now")
+
+	housekeepLogCmd.Flags().Int("limit", 50, "Number of most recent entries to show (0 for all)")
+
+	housekeepCmd.AddCommand(housekeepLogCmd)
+	rootCmd.AddCommand(housekeepCmd)
+}
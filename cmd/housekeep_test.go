@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/visionik/libfizz-go/fizzy"
+)
+
+func TestHousekeep(t *testing.T) {
+	f := newFizzTest(t)
+	fx := f.seed()
+	dir := isolateConfig(t)
+
+	// Card 1 is in Doing; card 2 is in triage and saw activity more recently
+	recent := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	f.srv.UpdateCard("2", func(c *fizzy.Card) { c.LastActiveAt = &recent })
+
+	config := `housekeeping:
+  - name: stale-doing
+    board: ` + fx.BoardID + `
+    column: doing
+    idle_days: 90
+    actions:
+      tag: stale
+      comment: Idle for {{days}} days ({{rule}})
+  - name: park-triage
+    column: Triage
+    idle_days: 60
+    actions:
+      postpone: true
+`
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "config"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config", "config.yaml"), []byte(config), 0o644))
+
+	// Card 2 has been idle for 31 days, so only card 1 matches
+	out := f.mustRun("housekeep", "--at=2026-06-01T09:00:00Z")
+	assert.Contains(t, out, "stale-doing")
+	assert.Contains(t, out, "tag stale, comment")
+	assert.Contains(t, out, "146d")
+	assert.NotContains(t, out, "park-triage")
+	assert.Contains(t, out, "1 change(s) previewed; run with --apply to make them")
+
+	// Previewing changes nothing and logs nothing
+	assert.NotContains(t, f.mustRun("cards", "show", "1", "--no-pager"), "stale")
+	assert.Equal(t, "[]\n", f.mustRun("housekeep", "log", "--format=json"))
+
+	out = f.mustRun("housekeep", "--at=2026-08-01T09:00:00Z", "--apply")
+	assert.Equal(t, "stale-doing: card 1 \"Upgrade the database cluster\": tag stale, comment\n"+
+		"park-triage: card 2 \"Login fails with SSO\": postpone\n", out)
+
+	card := f.mustRun("cards", "show", "1", "--no-pager")
+	assert.Contains(t, card, "stale")
+	assert.Contains(t, card, "Idle for 207 days (stale-doing)")
+	assert.Contains(t, f.mustRun("cards", "show", "2", "--format=json"), `"status": "not_now"`)
+
+	// The tagged card is skipped from now on and the postponed one is off
+	// the board
+	assert.Equal(t, "No idle cards\n", f.mustRun("housekeep", "--at=2026-12-01T09:00:00Z"))
+
+	out = f.mustRun("housekeep", "log", "--format=json")
+	assert.Contains(t, out, `"rule": "stale-doing"`)
+	assert.Contains(t, out, `"idle_days": 207`)
+	assert.Contains(t, out, `"postpone"`)
+	assert.Contains(t, f.mustRun("housekeep", "log"), "Login fails with SSO")
+}
+
+// TestHousekeepChain runs the README's rules: the tag the first adds makes
+// the second match in the same run
+func TestHousekeepChain(t *testing.T) {
+	f := newFizzTest(t)
+	fx := f.seed()
+	dir := isolateConfig(t)
+	writeConfig(t, dir, `housekeeping:
+  - name: stale-backlog
+    board: `+fx.BoardID+`
+    column: Backlog
+    idle_days: 90
+    actions:
+      tag: stale
+      comment: No activity for {{days}} days, so this was tagged stale.
+  - name: close-abandoned
+    tags: [stale]
+    idle_days: 180
+    actions:
+      close: true
+`)
+	var columns []fizzy.Column
+	require.NoError(t, json.Unmarshal([]byte(f.mustRun("columns", "list", fx.BoardID, "--format=json")), &columns))
+	backlog := columns[0].ID
+	require.Equal(t, "Backlog", columns[0].Name)
+	f.srv.UpdateCard("2", func(c *fizzy.Card) { c.ColumnID = &backlog })
+
+	want := "stale-backlog: card 2 \"Login fails with SSO\": tag stale, comment\n" +
+		"close-abandoned: card 2 \"Login fails with SSO\": close\n"
+	out := f.mustRun("housekeep", "--at=2026-08-01T09:00:00Z", "--format=json")
+	assert.Contains(t, out, `"rule": "close-abandoned"`, "the preview shows the chain too")
+
+	assert.Equal(t, want, f.mustRun("housekeep", "--at=2026-08-01T09:00:00Z", "--apply"))
+	card := f.mustRun("cards", "get", "2", "--format=json")
+	assert.Contains(t, card, `"closed": true`)
+	assert.Contains(t, card, `"name": "stale"`)
+}
+
+func TestHousekeepRules(t *testing.T) {
+	f := newFizzTest(t)
+	f.seed()
+	dir := isolateConfig(t)
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "config"), 0o755))
+
+	_, err := f.run("housekeep")
+	assert.ErrorContains(t, err, "no housekeeping rules")
+
+	config := `housekeeping:
+  - name: old
+    idle_days: 30
+    actions:
+      postpone: true
+      close: true
+`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config", "config.yaml"), []byte(config), 0o644))
+	_, err = f.run("housekeep")
+	assert.EqualError(t, err, "housekeeping rule old cannot both postpone and close")
+
+	config = `housekeeping:
+  - name: old
+    idle_days: 30
+    actions:
+      close: true
+`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config", "config.yaml"), []byte(config), 0o644))
+	_, err = f.run("housekeep", "nope")
+	assert.EqualError(t, err, "housekeeping rule nope not found")
+
+	out := f.mustRun("housekeep", "old", "--at=2026-03-01T00:00:00Z", "--apply", "--format=json")
+	assert.Contains(t, out, `"applied": true`)
+	assert.Contains(t, f.mustRun("cards", "show", "2", "--format=json"), `"closed": true`)
+}
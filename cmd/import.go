@@ -0,0 +1,318 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/visionik/fizz/internal/client"
+	"github.com/visionik/fizz/internal/format"
+	"github.com/visionik/fizz/internal/importer"
+	"github.com/visionik/libfizz-go/fizzy"
+)
+
+var importCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Import cards from Trello, GitHub Issues or CSV",
+	Long: `Create cards on a board from another tool's export. Lists become columns
+(created if the board has none of that name), labels tags, checklist items
+steps and comments comments; items closed in the source are closed.
+
+Without --apply an import only shows the cards it would create. With
+--apply it creates them and records each in import-ledger.json in the
+config directory, so running the same import again skips cards it already
+created on that board.`,
+}
+
+var importTrelloCmd = &cobra.Command{
+	Use:   "trello <export.json>",
+	Short: "Import a Trello board export",
+	Long: `Import the JSON export of a Trello board (board menu → Print, export and
+share → Export as JSON). Cards keep their list as their column, and archived
+cards or cards in archived lists are closed.`,
+	Args: cobra.ExactArgs(1),
+	Example: `  fizz import trello board.json --board=03fbhiu9dgjo0viyrlya1x03a
+  fizz import trello board.json --board=03fbhiu9dgjo0viyrlya1x03a --apply`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runImport(cmd, args, importer.Trello)
+	},
+}
+
+var importGitHubCmd = &cobra.Command{
+	Use:   "github-issues <issues.json>",
+	Short: "Import GitHub issues saved as JSON",
+	Long: `Import a JSON array of issues saved from the REST API or from gh:
+
+  gh api --paginate repos/OWNER/REPO/issues?state=all > issues.json
+  gh issue list --state=all --json number,title,body,state,labels,comments,url > issues.json
+
+Task list items in an issue body become steps. Only gh's output includes
+comments. Pull requests in the REST API's list are skipped. Issues have no
+column; --column puts them in one.`,
+	Args: cobra.ExactArgs(1),
+	Example: `  fizz import github-issues issues.json --board=03fbhiu9dgjo0viyrlya1x03a --column=Backlog
+  fizz import github-issues issues.json --board=03fbhiu9dgjo0viyrlya1x03a --apply`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runImport(cmd, args, importer.GitHubIssues)
+	},
+}
+
+var importCSVCmd = &cobra.Command{
+	Use:   "csv <file>",
+	Short: "Import cards from a CSV file",
+	Long: `Import one card per row of a CSV file with a header row. --map maps card
+fields to headers; a field without a mapping uses the header of the same
+name, ignoring case. The fields are:
+
+  id        stable ID, so a re-run recognizes the row (default: title and body)
+  title     card title (required)
+  body      description
+  column    column name
+  tags      tags, separated by commas or semicolons
+  steps     steps, separated by semicolons or newlines
+  comments  one comment per cell
+  closed    closes the card when true, yes, 1, closed, done or resolved
+
+A header that repeats, like Jira's Labels or Comment, contributes every cell.`,
+	Args: cobra.ExactArgs(1),
+	Example: `  fizz import csv jira.csv --board=03fbhiu9dgjo0viyrlya1x03a --map id="Issue key",title=Summary,tags=Labels,comments=Comment
+  fizz import csv cards.csv --board=03fbhiu9dgjo0viyrlya1x03a --apply`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		values, _ := cmd.Flags().GetStringArray("map")
+		mapping, err := importer.ParseMapping(values)
+		if err != nil {
+			return err
+		}
+		return runImport(cmd, args, func(r io.Reader) ([]importer.Card, error) {
+			return importer.CSV(r, mapping)
+		})
+	},
+}
+
+// importResult is a card an import created, skipped or, without --apply,
+// would create
+type importResult struct {
+	importer.Card
+	Action string `json:"action"`
+	Number int    `json:"card,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Import actions
+const (
+	importCreate  = "create"
+	importCreated = "created"
+	importSkip    = "skip"
+	importFailed  = "failed"
+)
+
+// runImport reads the export named by args[0] ("-" for stdin) with parse and
+// creates its cards on the --board board, or previews them without --apply
+func runImport(cmd *cobra.Command, args []string, parse func(io.Reader) ([]importer.Card, error)) error {
+	c := GetClient()
+	ctx := cmd.Context()
+
+	var in io.Reader = cmd.InOrStdin()
+	if args[0] != "-" {
+		f, err := os.Open(args[0])
+		if err != nil {
+			return fmt.Errorf("failed to read export: %w", err)
+		}
+		defer f.Close()
+		in = f
+	}
+	cards, err := parse(in)
+	if err != nil {
+		return err
+	}
+
+	boardID, err := pickFlag(cmd, "board", boardPicker, args)
+	if err != nil {
+		return err
+	}
+	if column, _ := cmd.Flags().GetString("column"); column != "" {
+		for i := range cards {
+			if cards[i].Column == "" {
+				cards[i].Column = column
+			}
+		}
+	}
+	apply, _ := cmd.Flags().GetBool("apply")
+
+	ledger, err := importer.LoadLedger()
+	if err != nil {
+		return err
+	}
+	columns, err := c.Columns.List(ctx, boardID)
+	if err != nil {
+		return fmt.Errorf("failed to list columns: %w", err)
+	}
+	columnIDs := map[string]string{}
+	for _, col := range columns {
+		columnIDs[strings.ToLower(col.Name)] = col.ID
+	}
+
+	results := make([]importResult, 0, len(cards))
+	failed := 0
+	for _, card := range cards {
+		result := importResult{Card: card, Action: importCreate}
+		if entry, ok := ledger.Get(boardID, card.Key); ok {
+			result.Action, result.Number = importSkip, entry.Card
+			results = append(results, result)
+			continue
+		}
+		if !apply {
+			results = append(results, result)
+			continue
+		}
+
+		number, err := importCard(ctx, c, boardID, card, columnIDs, ledger)
+		result.Action, result.Number = importCreated, number
+		if err != nil {
+			failed++
+			result.Action, result.Error = importFailed, err.Error()
+			fmt.Fprintf(cmd.ErrOrStderr(), "Error: %q: %v\n", card.Title, err)
+		}
+		results = append(results, result)
+	}
+
+	if err := writeImportResults(cmd, results, columnIDs, apply); err != nil {
+		return err
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d card(s) failed", failed)
+	}
+	return nil
+}
+
+// importCard creates a card with its tags, steps, comments and column, and
+// closes it if it was closed in the source. The card is recorded in the
+// ledger as soon as it exists, so a failure later on does not lead to a
+// duplicate on the next run.
+func importCard(ctx context.Context, c *client.Client, boardID string, card importer.Card, columnIDs map[string]string, ledger *importer.Ledger) (int, error) {
+	opts := &fizzy.CardCreateOptions{BoardID: boardID, Title: card.Title}
+	if card.Body != "" {
+		body := client.Paragraphs(card.Body)
+		opts.Body = &body
+	}
+	created, err := c.Cards.Create(ctx, opts)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create card: %w", err)
+	}
+	entry := importer.Entry{CardID: created.ID, Card: created.Number, Title: card.Title, ImportedAt: time.Now()}
+	if err := ledger.Record(boardID, card.Key, entry); err != nil {
+		return created.Number, err
+	}
+
+	number := strconv.Itoa(created.Number)
+	if _, err := syncTags(ctx, c, number, nil, card.Tags); err != nil {
+		return created.Number, err
+	}
+	for _, step := range card.Steps {
+		completed := step.Completed
+		if _, err := c.Steps.Create(ctx, number, &fizzy.StepCreateOptions{Content: step.Content, Completed: &completed}); err != nil {
+			return created.Number, fmt.Errorf("failed to create step: %w", err)
+		}
+	}
+	for _, body := range card.Comments {
+		if _, err := c.Comments.Create(ctx, number, &fizzy.CommentCreateOptions{Body: client.Paragraphs(body)}); err != nil {
+			return created.Number, fmt.Errorf("failed to create comment: %w", err)
+		}
+	}
+
+	if card.Column != "" {
+		key := strings.ToLower(card.Column)
+		if columnIDs[key] == "" {
+			col, err := c.Columns.Create(ctx, boardID, &fizzy.ColumnCreateOptions{Name: card.Column})
+			if err != nil {
+				return created.Number, fmt.Errorf("failed to create column: %w", err)
+			}
+			columnIDs[key] = col.ID
+		}
+		if err := c.Cards.MoveToColumn(ctx, number, columnIDs[key]); err != nil {
+			return created.Number, fmt.Errorf("failed to move card: %w", err)
+		}
+	}
+	if card.Closed {
+		if err := c.Cards.Close(ctx, number); err != nil {
+			return created.Number, fmt.Errorf("failed to close card: %w", err)
+		}
+	}
+	return created.Number, nil
+}
+
+// writeImportResults prints the cards an import created or would create
+func writeImportResults(cmd *cobra.Command, results []importResult, columnIDs map[string]string, apply bool) error {
+	out := cmd.OutOrStdout()
+	if GetFormat() != "table" {
+		formatter, err := format.NewFormatter(GetFormat(), out)
+		if err != nil {
+			return err
+		}
+		return formatter.Format(results)
+	}
+
+	if len(results) == 0 {
+		fmt.Fprintln(out, "Nothing to import")
+		return nil
+	}
+	if apply {
+		for _, r := range results {
+			switch r.Action {
+			case importCreated:
+				fmt.Fprintf(out, "Created card %d %q\n", r.Number, r.Title)
+			case importSkip:
+				fmt.Fprintf(out, "Skipped %q: already imported as card %d\n", r.Title, r.Number)
+			}
+		}
+		return nil
+	}
+
+	formatter, err := format.NewFormatter(GetFormat(), out)
+	if err != nil {
+		return err
+	}
+	toCreate := 0
+	newColumns := map[string]bool{}
+	displays := make([]format.ImportDisplay, len(results))
+	for i, r := range results {
+		action := "skip: card " + fmt.Sprint(r.Number)
+		if r.Action == importCreate {
+			toCreate++
+			action = "create"
+			if r.Column != "" && columnIDs[strings.ToLower(r.Column)] == "" {
+				action += ", new column"
+				newColumns[strings.ToLower(r.Column)] = true
+			}
+			if r.Closed {
+				action += ", close"
+			}
+		}
+		displays[i] = format.ToImportDisplay(r.Card, action)
+	}
+	if err := formatter.Format(displays); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "\n%d card(s) and %d column(s) to create, %d already imported; run with --apply to import\n",
+		toCreate, len(newColumns), len(results)-toCreate)
+	return nil
+}
+
+func init() {
+	for _, c := range []*cobra.Command{importTrelloCmd, importGitHubCmd, importCSVCmd} {
+		c.Flags().String("board", "", "Board to import into")
+		c.Flags().String("column", "", "Column for cards the source gives none")
+		c.Flags().Bool("apply", false, "Create the cards instead of previewing them")
+		c.RegisterFlagCompletionFunc("board", completeFlag(boardCandidates))
+		c.RegisterFlagCompletionFunc("column", completeFlag(columnCandidates))
+		importCmd.AddCommand(c)
+	}
+	importCSVCmd.Flags().StringArray("map", nil, "Map card fields to CSV headers, as field=Header[,field=Header...] (repeatable)")
+
+	rootCmd.AddCommand(importCmd)
+}
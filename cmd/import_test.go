@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/visionik/libfizz-go/fizzy"
+)
+
+func TestImportTrello(t *testing.T) {
+	f := newFizzTest(t)
+	isolateConfig(t)
+	board := f.srv.AddBoard("Migration")
+	f.srv.AddColumn(board.ID, "doing")
+	export := filepath.Join(packageDir, "..", "internal", "importer", "testdata", "trello.json")
+
+	// A dry run creates nothing
+	before := len(f.srv.Requests())
+	out := f.mustRun("import", "trello", export, "--board="+board.ID)
+	assert.Contains(t, out, "3 card(s) and 2 column(s) to create, 0 already imported; run with --apply to import")
+	assert.Contains(t, out, "create, new column, close")
+	for _, r := range f.srv.Requests()[before:] {
+		assert.True(t, strings.HasPrefix(r, "GET "), r)
+	}
+
+	out = f.mustRun("import", "trello", export, "--board="+board.ID, "--apply")
+	assert.Equal(t, `Created card 1 "Login fails with SSO"
+Created card 2 "Upgrade the database cluster"
+Created card 3 "Try a second CDN"
+`, out)
+
+	var columns []fizzy.Column
+	require.NoError(t, json.Unmarshal([]byte(f.mustRun("columns", "list", board.ID, "--format=json")), &columns))
+	var names []string
+	for _, col := range columns {
+		names = append(names, col.Name)
+	}
+	// The existing column is matched ignoring case
+	assert.Equal(t, []string{"doing", "To Do", "Old ideas"}, names)
+
+	var doc struct {
+		Card     fizzy.Card   `json:"card"`
+		Steps    []fizzy.Step `json:"steps"`
+		Comments []struct {
+			Body string `json:"body"`
+		} `json:"comments"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(f.mustRun("cards", "show", "2", "--format=json")), &doc))
+	assert.Equal(t, columns[0].ID, *doc.Card.ColumnID)
+	var tags []string
+	for _, tag := range doc.Card.Tags {
+		tags = append(tags, tag.Name)
+	}
+	assert.ElementsMatch(t, []string{"infra", "red"}, tags)
+	require.Len(t, doc.Steps, 3)
+	assert.True(t, doc.Steps[0].Completed)
+	require.Len(t, doc.Comments, 2)
+	assert.Contains(t, doc.Comments[0].Body, "Which version?")
+
+	// Text is sent as paragraphs, not raw HTML
+	var card fizzy.Card
+	require.NoError(t, json.Unmarshal([]byte(f.mustRun("cards", "get", "2", "--format=json")), &card))
+	assert.Equal(t, "<p>Move to the new major version.</p><p>Imported from https://trello.com/c/AbCdEf12</p>", *card.DescriptionHTML)
+	var comments []fizzy.Comment
+	require.NoError(t, json.Unmarshal([]byte(f.mustRun("comments", "list", "2", "--format=json")), &comments))
+	assert.Equal(t, "<p>Sam Park wrote on 2024-03-01:</p><p>Which version?</p>", comments[0].HTML)
+	assert.Contains(t, f.mustRun("cards", "get", "3", "--format=json"), `"closed": true`)
+
+	// Running again creates nothing
+	out = f.mustRun("import", "trello", export, "--board="+board.ID, "--apply")
+	assert.Contains(t, out, `Skipped "Try a second CDN": already imported as card 3`)
+	assert.NotContains(t, out, "Created")
+}
+
+func TestImportCSV(t *testing.T) {
+	f := newFizzTest(t)
+	fx := f.seed()
+	dir := isolateConfig(t)
+	path := filepath.Join(dir, "jira.csv")
+	require.NoError(t, os.WriteFile(path, []byte("Issue key,Summary,Labels\nOPS-1,Rotate keys,security\n"), 0o644))
+
+	var results []importResult
+	out := f.mustRun("import", "csv", path, "--board="+fx.BoardID, "--map", "id=Issue key,title=Summary", "--map", "tags=Labels", "--column=Backlog", "--format=json")
+	require.NoError(t, json.Unmarshal([]byte(out), &results))
+	require.Len(t, results, 1)
+	assert.Equal(t, "csv:OPS-1", results[0].Key)
+	assert.Equal(t, "Backlog", results[0].Column)
+	assert.Equal(t, []string{"security"}, results[0].Tags)
+	assert.Equal(t, importCreate, results[0].Action)
+
+	_, err := f.run("import", "csv", path, "--board="+fx.BoardID, "--map", "name=Summary")
+	assert.ErrorContains(t, err, `unknown field "name" in --map`)
+	_, err = f.run("import", "csv", path, "--map", "title=Summary", "--no-input")
+	assert.EqualError(t, err, "--board is required")
+}
@@ -34,9 +34,10 @@ var notificationsListCmd = &cobra.Command{
 }
 
 var notificationsReadCmd = &cobra.Command{
-	Use:   "read <notification-id>",
-	Short: "Mark a notification as read",
-	Args:  cobra.ExactArgs(1),
+	Use:               "read <notification-id>",
+	Short:             "Mark a notification as read",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeNotificationArg,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		client := GetClient()
 		notificationID := args[0]
@@ -52,9 +53,10 @@ var notificationsReadCmd = &cobra.Command{
 }
 
 var notificationsUnreadCmd = &cobra.Command{
-	Use:   "unread <notification-id>",
-	Short: "Mark a notification as unread",
-	Args:  cobra.ExactArgs(1),
+	Use:               "unread <notification-id>",
+	Short:             "Mark a notification as unread",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeNotificationArg,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		client := GetClient()
 		notificationID := args[0]
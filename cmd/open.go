@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/visionik/fizz/internal/browser"
+	"github.com/visionik/fizz/internal/clipboard"
+)
+
+// addOpenFlags registers the flags shared by the open commands
+func addOpenFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool("print", false, "Print the URL instead of opening a browser")
+	cmd.Flags().Bool("copy", false, "Copy a Markdown link to the clipboard (OSC 52, works over SSH)")
+}
+
+// openURL opens url in the browser, or prints it with --print. With --copy
+// it puts a Markdown link titled label on the clipboard instead of opening.
+func openURL(cmd *cobra.Command, url, label string) error {
+	if url == "" {
+		return fmt.Errorf("%s has no URL", label)
+	}
+
+	printURL, _ := cmd.Flags().GetBool("print")
+	copyLink, _ := cmd.Flags().GetBool("copy")
+
+	if copyLink {
+		link := fmt.Sprintf("[%s](%s)", linkLabel.Replace(label), url)
+		tty, done := terminal(cmd)
+		err := clipboard.Copy(tty, link)
+		done()
+		if err != nil {
+			return fmt.Errorf("failed to copy link: %w", err)
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Copied %s to clipboard\n", link)
+	}
+	if printURL {
+		fmt.Fprintln(cmd.OutOrStdout(), url)
+	}
+	if copyLink || printURL {
+		return nil
+	}
+
+	err := browser.Open(url)
+	if err == nil {
+		return nil
+	}
+	// Headless, remote or a broken $BROWSER: the link is still useful
+	fmt.Fprintln(cmd.OutOrStdout(), url)
+	if errors.Is(err, browser.ErrNoBrowser) {
+		fmt.Fprintf(cmd.ErrOrStderr(), "%v\n", err)
+		return nil
+	}
+	return err
+}
+
+// linkLabel escapes the characters that would end a Markdown link's label
+// early
+var linkLabel = strings.NewReplacer(`\`, `\\`, `]`, `\]`)
+
+// terminal returns where to write terminal control sequences: the
+// controlling terminal when there is one, so they never end up in piped
+// output, and stderr otherwise. Call the returned func when done.
+func terminal(cmd *cobra.Command) (io.Writer, func()) {
+	if _, ok := cmd.ErrOrStderr().(*os.File); ok {
+		if tty, err := os.OpenFile("/dev/tty", os.O_WRONLY, 0); err == nil {
+			return tty, func() { tty.Close() }
+		}
+	}
+	return cmd.ErrOrStderr(), func() {}
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/visionik/libfizz-go/fizzy"
+)
+
+func TestCardsOpen(t *testing.T) {
+	f := newFizzTest(t)
+	fx := f.seed()
+
+	opened := filepath.Join(t.TempDir(), "opened")
+	t.Setenv("BROWSER", "echo >"+opened)
+
+	assert.Empty(t, f.mustRun("cards", "open", fx.CardNumber))
+	data, err := os.ReadFile(opened)
+	require.NoError(t, err)
+	assert.Equal(t, publicURL+"/6130737/cards/1\n", string(data))
+}
+
+func TestCardsOpenPrint(t *testing.T) {
+	f := newFizzTest(t)
+	fx := f.seed()
+	t.Setenv("BROWSER", "false")
+
+	assert.Equal(t, publicURL+"/6130737/cards/1\n", f.mustRun("cards", "open", fx.CardNumber, "--print"))
+}
+
+func TestOpenCopy(t *testing.T) {
+	f := newFizzTest(t)
+	fx := f.seed()
+	t.Setenv("BROWSER", "false")
+	t.Setenv("TMUX", "")
+	t.Setenv("TERM", "xterm-256color")
+
+	link := "[#1 Upgrade the database cluster](" + publicURL + "/6130737/cards/1)"
+	out, errOut, err := f.runStderr("cards", "open", fx.CardNumber, "--copy")
+	require.NoError(t, err)
+	assert.Equal(t, "Copied "+link+" to clipboard\n", out)
+	assert.Equal(t, "\x1b]52;c;"+base64.StdEncoding.EncodeToString([]byte(link))+"\a", errOut)
+
+	out, _, err = f.runStderr("boards", "open", fx.BoardID, "--copy")
+	require.NoError(t, err)
+	assert.Equal(t, "Copied [Platform]("+publicURL+"/6130737/boards/"+fx.BoardID+") to clipboard\n", out)
+}
+
+func TestOpenCopyEscapesLabel(t *testing.T) {
+	f := newFizzTest(t)
+	fx := f.seed()
+	t.Setenv("BROWSER", "false")
+	t.Setenv("TMUX", "")
+	t.Setenv("TERM", "xterm-256color")
+	f.srv.UpdateCard("1", func(c *fizzy.Card) { c.Title = `Fix [a]\b` })
+
+	out := f.mustRun("cards", "open", fx.CardNumber, "--copy")
+	assert.Equal(t, `Copied [#1 Fix [a\]\\b](`+publicURL+"/6130737/cards/1) to clipboard\n", out)
+}
+
+func TestOpenBrowserFails(t *testing.T) {
+	f := newFizzTest(t)
+	fx := f.seed()
+	t.Setenv("BROWSER", "false")
+
+	out, err := f.run("cards", "open", fx.CardNumber)
+	assert.ErrorContains(t, err, "failed to open browser")
+	assert.True(t, strings.HasPrefix(out, publicURL+"/6130737/cards/1\n"), "the URL is printed instead: %q", out)
+}
@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestOutputGolden(t *testing.T) {
+	f := newFizzTest(t)
+	fx := f.seed()
+
+	commands := []struct {
+		name string
+		args []string
+	}{
+		{"identity_get", []string{"identity", "get"}},
+		{"boards_list", []string{"boards", "list"}},
+		{"boards_get", []string{"boards", "get", fx.BoardID}},
+		{"cards_list", []string{"cards", "list"}},
+		{"cards_get", []string{"cards", "get", fx.CardNumber}},
+		{"cards_show", []string{"cards", "show", fx.CardNumber}},
+		{"columns_list", []string{"columns", "list", fx.BoardID}},
+		{"comments_list", []string{"comments", "list", fx.CardNumber}},
+		{"reactions_list", []string{"reactions", "list", fx.CardNumber, fx.CommentID}},
+		{"steps_list", []string{"steps", "list", fx.CardNumber}},
+		{"tags_list", []string{"tags", "list"}},
+		{"users_list", []string{"users", "list"}},
+		{"notifications_list", []string{"notifications", "list"}},
+	}
+
+	for _, c := range commands {
+		for _, format := range []string{"table", "json", "yaml"} {
+			t.Run(c.name+"/"+format, func(t *testing.T) {
+				f.t = t
+				out := f.mustRun(append(c.args, "--format="+format)...)
+				f.golden(c.name+"."+format, out)
+			})
+		}
+	}
+}
+
+func TestUnsupportedFormat(t *testing.T) {
+	f := newFizzTest(t)
+
+	_, err := f.run("boards", "list", "--format=xml")
+	if err == nil || !strings.Contains(err.Error(), "unsupported format: xml") {
+		t.Fatalf("expected unsupported format error, got %v", err)
+	}
+}
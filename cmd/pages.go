@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/visionik/fizz/internal/client"
+	"github.com/visionik/fizz/internal/format"
+	"github.com/visionik/fizz/internal/progress"
+	"github.com/visionik/libfizz-go/fizzy"
+)
+
+// listCardPages prints the cards of pager page by page until limit cards
+// are printed, or only the first page with single. jsonl and table output
+// stream as pages arrive; other formats are written once all are fetched.
+// While more pages are coming, a count is shown on a terminal's stderr.
+func listCardPages(cmd *cobra.Command, pager *client.CardPager, limit int, single bool) error {
+	out := cmd.OutOrStdout()
+	formatter, err := format.NewFormatter(GetFormat(), out)
+	if err != nil {
+		return err
+	}
+
+	var write func(cards []fizzy.Card) error
+	var finish func() error
+	switch GetFormat() {
+	case "jsonl":
+		write = func(cards []fizzy.Card) error { return formatter.Format(cards) }
+		finish = func() error { return nil }
+	case "table":
+		stream := &format.TableStream{Writer: out}
+		write = func(cards []fizzy.Card) error { return stream.Append(format.ToCardDisplaySlice(cards)) }
+		finish = stream.Close
+	default:
+		var all []fizzy.Card
+		write = func(cards []fizzy.Card) error {
+			all = append(all, cards...)
+			return nil
+		}
+		finish = func() error { return formatter.Format(all) }
+	}
+
+	var counter *progress.Counter
+	if !single && progress.Enabled(cmd.ErrOrStderr()) {
+		counter = progress.NewCounter(cmd.ErrOrStderr(), "Fetching cards")
+		defer counter.Clear()
+	}
+
+	count := 0
+	for pager.More() && (limit <= 0 || count < limit) {
+		cards, err := pager.Next(cmd.Context())
+		if err != nil {
+			return err
+		}
+		if limit > 0 && count+len(cards) > limit {
+			cards = cards[:limit-count]
+		}
+		count += len(cards)
+
+		if counter != nil {
+			counter.Clear()
+		}
+		if err := write(cards); err != nil {
+			return err
+		}
+		if single {
+			break
+		}
+		if counter != nil && pager.More() && (limit <= 0 || count < limit) {
+			counter.Page(len(cards))
+		}
+	}
+	if err := finish(); err != nil {
+		return err
+	}
+
+	if cursor := pager.Cursor(); single && cursor != "" {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Next page: --page=%s\n", cursor)
+	}
+	return nil
+}
@@ -0,0 +1,178 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/visionik/fizz/internal/client"
+	"github.com/visionik/fizz/internal/completion"
+	"github.com/visionik/fizz/internal/editor"
+	"github.com/visionik/fizz/internal/picker"
+)
+
+// interactive reports whether fizz may prompt for what a command is
+// missing: stdin and stdout are terminals and --no-input is not set
+var interactive = func(cmd *cobra.Command) bool {
+	return !noInputFlag && editor.IsInteractive(cmd.InOrStdin(), cmd.OutOrStdout())
+}
+
+// choose shows a picker on the terminal; tests replace it
+var choose = func(cmd *cobra.Command, prompt string, items []picker.Item) (picker.Item, error) {
+	tty, ok := cmd.InOrStdin().(*os.File)
+	if !ok {
+		return picker.Item{}, errors.New("not a terminal")
+	}
+	return picker.Pick(tty, cmd.OutOrStdout(), prompt, items)
+}
+
+// confirm asks a yes or no question on the terminal; only yes is true
+func confirm(cmd *cobra.Command, question string) (bool, error) {
+	fmt.Fprintf(cmd.OutOrStdout(), "%s [y/N] ", question)
+	answer, err := bufio.NewReader(cmd.InOrStdin()).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return false, fmt.Errorf("failed to read answer: %w", err)
+	}
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true, nil
+	}
+	return false, nil
+}
+
+// pickKind is something a picker can choose: what it is called and where
+// its choices come from
+type pickKind struct {
+	noun       string
+	candidates candidateFunc
+}
+
+var (
+	boardPicker  = pickKind{"board", boardCandidates}
+	cardPicker   = pickKind{"card", cardCandidates}
+	columnPicker = pickKind{"column", columnCandidates}
+	userPicker   = pickKind{"user", userCandidates}
+	tagPicker    = pickKind{"tag", tagCandidates}
+)
+
+// argPickers are the pickers for missing arguments, by their placeholder
+var argPickers = map[string]pickKind{
+	"<board-id>":          boardPicker,
+	"<card-id-or-number>": cardPicker,
+	"<column-id>":         columnPicker,
+	"<user-id>":           userPicker,
+	"<tag-name>":          tagPicker,
+}
+
+// pick asks for a k and returns its value; args are the command's
+// arguments so far, which scope cards and columns
+func pick(ctx context.Context, c *client.Client, cmd *cobra.Command, k pickKind, args []string) (string, error) {
+	candidates, err := k.candidates(ctx, c, cmd, args)
+	if err != nil {
+		return "", fmt.Errorf("failed to list %ss: %w", k.noun, err)
+	}
+	if len(candidates) == 0 {
+		return "", fmt.Errorf("no %ss to choose from", k.noun)
+	}
+
+	items := make([]picker.Item, len(candidates))
+	for i, cand := range candidates {
+		items[i] = picker.Item{Value: cand.Value, Label: pickLabel(k, cand)}
+	}
+	prompt := strings.ToUpper(k.noun[:1]) + k.noun[1:] + ": "
+	item, err := choose(cmd, prompt, items)
+	if errors.Is(err, picker.ErrCanceled) {
+		return "", fmt.Errorf("no %s chosen", k.noun)
+	}
+	if err != nil {
+		return "", err
+	}
+	return item.Value, nil
+}
+
+func pickLabel(k pickKind, cand completion.Candidate) string {
+	switch {
+	case k.noun == cardPicker.noun:
+		return "#" + cand.Value + " " + cand.Description
+	case cand.Description == "":
+		return cand.Value
+	default:
+		return cand.Description + " (" + cand.Value + ")"
+	}
+}
+
+// pickFlag returns a required flag's value, asking for it with a picker
+// when it is missing and fizz may prompt
+func pickFlag(cmd *cobra.Command, name string, k pickKind, args []string) (string, error) {
+	value, _ := cmd.Flags().GetString(name)
+	if value != "" {
+		return value, nil
+	}
+	if !interactive(cmd) {
+		return "", fmt.Errorf("--%s is required", name)
+	}
+	return pick(cmd.Context(), GetClient(), cmd, k, args)
+}
+
+// parseArgs finds the command args would run and parses its flags, which
+// keep their values until the next reset. It returns the command, its
+// positional arguments and the placeholders of the arguments it requires
+// (the <...> words of its usage); the command is nil if args do not name
+// one that runs or do not parse.
+func parseArgs(args []string) (*cobra.Command, []string, []string) {
+	c, rest, err := rootCmd.Find(args)
+	if err != nil || c == rootCmd || !c.Runnable() {
+		return nil, nil, nil
+	}
+	resetFlags(rootCmd)
+	if err := c.ParseFlags(rest); err != nil {
+		return nil, nil, nil
+	}
+	if help := c.Flags().Lookup("help"); help != nil && help.Changed {
+		return nil, nil, nil
+	}
+
+	var placeholders []string
+	for _, word := range strings.Fields(c.Use)[1:] {
+		if strings.HasPrefix(word, "<") {
+			placeholders = append(placeholders, word)
+		}
+	}
+	return c, c.Flags().Args(), placeholders
+}
+
+// promptArgs asks for the arguments args leave out with pickers, when
+// fizz may prompt, and returns the completed arguments
+func promptArgs(ctx context.Context, args []string) ([]string, error) {
+	defer resetFlags(rootCmd)
+
+	var c *client.Client
+	for {
+		cmd, positional, placeholders := parseArgs(args)
+		if cmd == nil || len(positional) >= len(placeholders) || !interactive(cmd) {
+			return args, nil
+		}
+		k, ok := argPickers[placeholders[len(positional)]]
+		if !ok {
+			return args, nil
+		}
+		if c == nil {
+			// Retrying would only keep the user waiting for the picker
+			if c = lazyClient(client.WithMaxRetries(0)); c == nil {
+				// The command will say what is wrong with the configuration
+				return args, nil
+			}
+		}
+
+		value, err := pick(ctx, c, cmd, k, positional)
+		if err != nil {
+			return nil, err
+		}
+		args = append(append([]string{}, args...), value)
+	}
+}
@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/visionik/fizz/internal/picker"
+)
+
+// answerPickers makes fizz prompt as on a terminal and answers each picker
+// with the first item whose label contains the next answer, or cancels when
+// there is none. It returns the prompts shown.
+func answerPickers(t *testing.T, answers ...string) *[]string {
+	var prompts []string
+	oldInteractive, oldChoose := interactive, choose
+	interactive = func(cmd *cobra.Command) bool { return !noInputFlag }
+	choose = func(cmd *cobra.Command, prompt string, items []picker.Item) (picker.Item, error) {
+		prompts = append(prompts, prompt)
+		if len(answers) > 0 {
+			answer := answers[0]
+			answers = answers[1:]
+			for _, item := range items {
+				if strings.Contains(item.Label, answer) {
+					return item, nil
+				}
+			}
+		}
+		return picker.Item{}, picker.ErrCanceled
+	}
+	t.Cleanup(func() { interactive, choose = oldInteractive, oldChoose })
+	return &prompts
+}
+
+func TestPickMissingArgs(t *testing.T) {
+	f := newFizzTest(t)
+	fx := f.seed()
+	prompts := answerPickers(t, "#2 Login", "Jordan")
+
+	args, err := promptArgs(context.Background(), []string{"cards", "assign"})
+	require.NoError(t, err)
+	require.Len(t, args, 4)
+	assert.Equal(t, []string{"cards", "assign", "2"}, args[:3])
+	assert.Contains(t, f.mustRun("users", "list"), args[3]+" ")
+	assert.Equal(t, []string{"Card: ", "User: "}, *prompts)
+
+	// Nothing is asked when the arguments are there or help is wanted
+	for _, given := range [][]string{{"cards", "show", "1"}, {"cards", "show", "--help"}, {"boards", "list"}, {"nope"}} {
+		args, err = promptArgs(context.Background(), given)
+		require.NoError(t, err)
+		assert.Equal(t, given, args)
+	}
+	assert.Len(t, *prompts, 2)
+
+	// Columns come from the board argument
+	*prompts = nil
+	answerPickers(t, "Doing")
+	args, err = promptArgs(context.Background(), []string{"columns", "get", fx.BoardID})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"columns", "get", fx.BoardID, fx.ColumnID}, args)
+
+	_, err = promptArgs(context.Background(), []string{"boards", "get"})
+	assert.EqualError(t, err, "no board chosen")
+
+	args, err = promptArgs(context.Background(), []string{"boards", "get", "--no-input"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"boards", "get", "--no-input"}, args)
+}
+
+func TestPickRequiredFlags(t *testing.T) {
+	f := newFizzTest(t)
+	fx := f.seed()
+	prompts := answerPickers(t, "Platform", "Doing")
+
+	out := f.mustRun("cards", "create", "--title=Picked", "--format=json")
+	assert.Contains(t, out, `"title": "Picked"`)
+	assert.Contains(t, f.mustRun("cards", "list", "--board="+fx.BoardID), "Picked")
+
+	// The column picker lists the card's board
+	out = f.mustRun("cards", "move", "2")
+	assert.Contains(t, out, "moved")
+	assert.Equal(t, []string{"Board: ", "Column: "}, *prompts)
+
+	_, err := f.run("cards", "create", "--title=Scripted", "--no-input")
+	assert.EqualError(t, err, "--board is required")
+	_, err = f.run("cards", "move", "2")
+	assert.EqualError(t, err, "no column chosen")
+}
@@ -0,0 +1,196 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/visionik/fizz/internal/config"
+	"github.com/visionik/fizz/internal/format"
+	"github.com/visionik/fizz/internal/plugin"
+)
+
+var pluginCmd = &cobra.Command{
+	Use:   "plugin",
+	Short: "Manage plugins",
+	Long: `Plugins add commands to fizz without changing it. 'fizz <name>' runs an
+executable named fizz-<name> from the plugins directory
+(~/.config/fizz/plugins) or, failing that, from PATH. Built-in commands and
+aliases always win over plugins of the same name.
+
+A plugin gets its arguments, stdin and stdout, and fizz's resolved settings
+in environment variables: FIZZY_TOKEN, FIZZY_ACCOUNT, FIZZY_BASE_URL,
+FIZZ_FORMAT, FIZZ_DEBUG, FIZZ_NO_INPUT, FIZZ_CONFIG_DIR, FIZZ_PLUGIN and
+FIZZ_BIN (the fizz binary, for calling back). The same settings arrive as a
+JSON object on the file descriptor in FIZZ_CONTEXT_FD (3), except on
+Windows.`,
+}
+
+var pluginListCmd = &cobra.Command{
+	Use:         "list",
+	Short:       "List plugins",
+	Args:        cobra.NoArgs,
+	Annotations: map[string]string{noClientAnnotation: "true"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		list := plugin.List()
+		for i, p := range list {
+			switch c := findCommand(p.Name); {
+			case c == nil:
+			case c.Annotations[aliasAnnotation] != "":
+				list[i].Shadowed = "alias"
+			default:
+				list[i].Shadowed = "built-in command"
+			}
+		}
+		if list == nil {
+			list = []plugin.Plugin{}
+		}
+
+		formatter, err := format.NewFormatter(GetFormat(), cmd.OutOrStdout())
+		if err != nil {
+			return err
+		}
+		if GetFormat() == "table" {
+			if len(list) == 0 {
+				fmt.Fprintln(cmd.OutOrStdout(), "No plugins found")
+				return nil
+			}
+			return formatter.Format(format.ToPluginDisplaySlice(list))
+		}
+		return formatter.Format(list)
+	},
+}
+
+var pluginInstallCmd = &cobra.Command{
+	Use:   "install <path>",
+	Short: "Install a plugin into the plugins directory",
+	Long: `Copy an executable into the plugins directory so 'fizz <name>' runs it.
+The name defaults to the file name without a fizz- prefix or extension.`,
+	Args:        cobra.ExactArgs(1),
+	Annotations: map[string]string{noClientAnnotation: "true"},
+	Example: `  fizz plugin install ./fizz-incident
+  fizz plugin install ./sync.py --name=incident-sync
+  fizz plugin install ./fizz-incident --force`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name, _ := cmd.Flags().GetString("name")
+		if name == "" {
+			name = plugin.NameFromFile(args[0])
+		}
+		if isBuiltin(name) {
+			return fmt.Errorf("%q is a built-in command; choose another --name", name)
+		}
+		force, _ := cmd.Flags().GetBool("force")
+
+		p, err := plugin.Install(args[0], name, force)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Installed plugin %s to %s; run it with 'fizz %s'\n", p.Name, p.Path, p.Name)
+		return nil
+	},
+}
+
+// isBuiltin reports whether name is a built-in fizz command, which neither
+// an alias nor a plugin can replace
+func isBuiltin(name string) bool {
+	c := findCommand(name)
+	return c != nil && c.Annotations[aliasAnnotation] == ""
+}
+
+// findCommand returns the top-level command or alias called name, or nil
+func findCommand(name string) *cobra.Command {
+	c, _, err := rootCmd.Find([]string{name})
+	if err != nil || c == rootCmd {
+		return nil
+	}
+	return c
+}
+
+// findPlugin reports the plugin args run, if their first word names one
+// rather than a command. It returns the root flags before that word and the
+// arguments after it.
+func findPlugin(args []string) (p plugin.Plugin, global, rest []string, ok bool) {
+	global, rest, ok = splitRootFlags(args)
+	if !ok || len(rest) == 0 || strings.HasPrefix(rest[0], "-") || findCommand(rest[0]) != nil {
+		return plugin.Plugin{}, nil, nil, false
+	}
+
+	p, ok = plugin.Find(rest[0])
+	return p, global, rest[1:], ok
+}
+
+// splitRootFlags splits the root flags, such as --format, off the front of
+// args; ok is false if another flag comes first
+func splitRootFlags(args []string) (global, rest []string, ok bool) {
+	i := 0
+	for i < len(args) && strings.HasPrefix(args[i], "-") && args[i] != "--" {
+		name, _, hasValue := strings.Cut(strings.TrimLeft(args[i], "-"), "=")
+		flag := rootCmd.PersistentFlags().Lookup(name)
+		if flag == nil && len(name) == 1 {
+			flag = rootCmd.PersistentFlags().ShorthandLookup(name)
+		}
+		if flag == nil {
+			return nil, args, false
+		}
+		if !hasValue && flag.Value.Type() != "bool" {
+			i++
+		}
+		i++
+	}
+	i = min(i, len(args))
+	return args[:i], args[i:], true
+}
+
+// runPlugin runs the plugin args name, if they name one. It reports whether
+// they did, and the plugin's error.
+func runPlugin(ctx context.Context, args []string, in io.Reader, out, errOut io.Writer) (bool, error) {
+	p, global, rest, ok := findPlugin(args)
+	if !ok {
+		return false, nil
+	}
+
+	resetFlags(rootCmd)
+	defer resetFlags(rootCmd)
+	if err := rootCmd.ParseFlags(global); err != nil {
+		return true, err
+	}
+
+	pc := plugin.Context{
+		Version: plugin.ContextVersion,
+		Plugin:  p.Name,
+		Args:    rest,
+		BaseURL: config.DefaultBaseURL,
+		Format:  GetFormat(),
+		Debug:   debugFlag,
+		NoInput: noInputFlag,
+	}
+	if pc.Args == nil {
+		pc.Args = []string{}
+	}
+	// Plugins that work offline still run when fizz is not configured
+	if cfg, err := config.LoadFromEnv(); err == nil {
+		pc.Token, pc.Account = cfg.Token, cfg.Account
+		if cfg.BaseURL != "" {
+			pc.BaseURL = cfg.BaseURL
+		}
+	}
+	if dir, err := config.Dir(); err == nil {
+		pc.ConfigDir = dir
+	}
+	if exe, err := os.Executable(); err == nil {
+		pc.Executable = exe
+	}
+	return true, p.Run(ctx, pc, in, out, errOut)
+}
+
+func init() {
+	pluginInstallCmd.Flags().String("name", "", "Plugin name (default: from the file name)")
+	pluginInstallCmd.Flags().Bool("force", false, "Replace an installed plugin of the same name")
+
+	pluginCmd.AddCommand(pluginListCmd)
+	pluginCmd.AddCommand(pluginInstallCmd)
+	rootCmd.AddCommand(pluginCmd)
+}
@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/visionik/fizz/internal/plugin"
+)
+
+// writeScript writes an executable shell script
+func writeScript(t *testing.T, path, script string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(path, []byte("#!/bin/sh\n"+script), 0o755))
+}
+
+func TestPlugin(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("test plugins are shell scripts")
+	}
+	f := newFizzTest(t)
+	isolateConfig(t)
+	bin := t.TempDir()
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	writeScript(t, filepath.Join(bin, "fizz-hello"), `echo "args: $*"
+echo "format=$FIZZ_FORMAT account=$FIZZY_ACCOUNT plugin=$FIZZ_PLUGIN"
+cat <&$FIZZ_CONTEXT_FD
+echo
+exit ${HELLO_EXIT:-0}
+`)
+	out := f.mustRun("--format", "json", "hello", "world", "--loud")
+	lines := strings.Split(out, "\n")
+	assert.Equal(t, "args: world --loud", lines[0])
+	assert.Equal(t, "format=json account="+f.srv.Account+" plugin=hello", lines[1])
+
+	var pc plugin.Context
+	require.NoError(t, json.Unmarshal([]byte(lines[2]), &pc))
+	assert.Equal(t, []string{"world", "--loud"}, pc.Args)
+	assert.Equal(t, f.srv.URL, pc.BaseURL)
+	assert.Equal(t, f.srv.Token, pc.Token)
+	assert.Equal(t, "json", pc.Format)
+
+	t.Setenv("HELLO_EXIT", "3")
+	_, errOut, err := f.runStderr("hello")
+	var exitErr *plugin.ExitError
+	require.ErrorAs(t, err, &exitErr)
+	assert.Equal(t, 3, exitErr.Code)
+	assert.Equal(t, "Error: plugin hello exited with status 3\n", errOut)
+
+	// Built-in commands win over plugins
+	writeScript(t, filepath.Join(bin, "fizz-boards"), "echo plugin\n")
+	assert.NotContains(t, f.mustRun("boards", "list"), "plugin")
+
+	// Installed plugins come first
+	src := filepath.Join(t.TempDir(), "hello.sh")
+	writeScript(t, src, "echo installed\n")
+	out = f.mustRun("plugin", "install", src)
+	assert.Contains(t, out, "Installed plugin hello to ")
+	assert.Equal(t, "installed\n", f.mustRun("hello"))
+
+	var list []plugin.Plugin
+	require.NoError(t, json.Unmarshal([]byte(f.mustRun("plugin", "list", "--format=json")), &list))
+	installed := filepath.Join(os.Getenv("FIZZ_CONFIG_DIR"), "plugins", "fizz-hello")
+	// Ignore plugins that happen to be on the machine's PATH
+	ours := list[:0]
+	for _, p := range list {
+		if strings.HasPrefix(p.Path, bin) || p.Path == installed {
+			ours = append(ours, p)
+		}
+	}
+	assert.Equal(t, []plugin.Plugin{
+		{Name: "hello", Path: installed},
+		{Name: "boards", Path: filepath.Join(bin, "fizz-boards"), Shadowed: "built-in command"},
+		{Name: "hello", Path: filepath.Join(bin, "fizz-hello"), Shadowed: installed},
+	}, ours)
+	assert.Contains(t, f.mustRun("plugin", "list"), "shadowed by built-in command")
+
+	_, err = f.run("plugin", "install", src)
+	assert.ErrorContains(t, err, `plugin "hello" is already installed`)
+	f.mustRun("plugin", "install", src, "--force")
+	_, err = f.run("plugin", "install", src, "--name=cards")
+	assert.EqualError(t, err, `"cards" is a built-in command; choose another --name`)
+
+	_, err = f.run("nope")
+	assert.EqualError(t, err, `unknown command "nope" for "fizz"`)
+}
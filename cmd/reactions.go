@@ -15,9 +15,10 @@ var reactionsCmd = &cobra.Command{
 }
 
 var reactionsListCmd = &cobra.Command{
-	Use:   "list <card-id-or-number> <comment-id>",
-	Short: "List reactions on a comment",
-	Args:  cobra.ExactArgs(2),
+	Use:               "list <card-id-or-number> <comment-id>",
+	Short:             "List reactions on a comment",
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: completeCardArg,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		client := GetClient()
 
@@ -43,10 +44,11 @@ var reactionsListCmd = &cobra.Command{
 }
 
 var reactionsCreateCmd = &cobra.Command{
-	Use:   "create <card-id-or-number> <comment-id>",
-	Short: "Add a reaction to a comment",
-	Args:  cobra.ExactArgs(2),
-	Example: `  fizz reactions create 123 456 --emoji="👍"`,
+	Use:               "create <card-id-or-number> <comment-id>",
+	Short:             "Add a reaction to a comment",
+	Args:              cobra.ExactArgs(2),
+	Example:           `  fizz reactions create 123 456 --emoji="👍"`,
+	ValidArgsFunction: completeCardArg,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		client := GetClient()
 
@@ -80,9 +82,10 @@ var reactionsCreateCmd = &cobra.Command{
 }
 
 var reactionsDeleteCmd = &cobra.Command{
-	Use:   "delete <card-id-or-number> <comment-id> <reaction-id>",
-	Short: "Delete a reaction",
-	Args:  cobra.ExactArgs(3),
+	Use:               "delete <card-id-or-number> <comment-id> <reaction-id>",
+	Short:             "Delete a reaction",
+	Args:              cobra.ExactArgs(3),
+	ValidArgsFunction: completeCardArg,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		client := GetClient()
 
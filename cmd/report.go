@@ -0,0 +1,441 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/visionik/fizz/internal/chart"
+	"github.com/visionik/fizz/internal/client"
+	"github.com/visionik/fizz/internal/format"
+	"github.com/visionik/fizz/internal/history"
+	"github.com/visionik/fizz/internal/kanban"
+	"github.com/visionik/fizz/internal/report"
+	"github.com/visionik/libfizz-go/fizzy"
+)
+
+var reportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Flow metrics for boards",
+	Long: `Report weekly throughput, lead time and cycle time percentiles, work in
+progress per column and the age of open cards.
+
+Lead time runs from a card's creation to its closing. Cycle time runs from
+when a card left triage to its closing; the API does not expose that, so it
+comes from the snapshots taken by 'fizz sync' and is only reported once they
+exist.
+
+'fizz report cfd' and 'fizz report burndown' chart the same history.
+
+The table output draws sparkline and bar charts; --format=json and yaml give
+the full report and --format=csv a long-form section,key,label,value table.`,
+	Args: cobra.NoArgs,
+	Example: `  fizz report
+  fizz report --board=03fbhiu9dgjo0viyrlya1x03a --weeks=8
+  fizz report --tag=bug --assignee=me
+  fizz report --format=csv > flow.csv`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client := GetClient()
+
+		opts, cards, err := reportInput(cmd, client)
+		if err != nil {
+			return err
+		}
+		r := report.Build(cards, opts)
+
+		switch GetFormat() {
+		case "table", "":
+			return format.WriteReport(cmd.OutOrStdout(), r)
+		case "csv":
+			formatter, err := format.NewFormatter("csv", cmd.OutOrStdout())
+			if err != nil {
+				return err
+			}
+			return formatter.Format(r.Rows())
+		default:
+			formatter, err := format.NewFormatter(GetFormat(), cmd.OutOrStdout())
+			if err != nil {
+				return err
+			}
+			return formatter.Format(r)
+		}
+	},
+}
+
+// reportInput fetches and filters the cards a report covers, with the
+// columns of their boards
+func reportInput(cmd *cobra.Command, c *client.Client) (report.Options, []fizzy.Card, error) {
+	ctx := cmd.Context()
+	opts := report.Options{}
+	opts.Weeks, _ = cmd.Flags().GetInt("weeks")
+	opts.Oldest, _ = cmd.Flags().GetInt("oldest")
+	if opts.Weeks < 1 {
+		return opts, nil, fmt.Errorf("--weeks must be at least 1")
+	}
+
+	boardID, _ := cmd.Flags().GetString("board")
+	cards, err := listAllCards(ctx, c, boardID)
+	if err != nil {
+		return opts, nil, err
+	}
+
+	tags, _ := cmd.Flags().GetStringSlice("tag")
+	assignees, _ := cmd.Flags().GetStringSlice("assignee")
+	for i, assignee := range assignees {
+		if assignees[i], err = c.ResolveUserID(ctx, assignee); err != nil {
+			return opts, nil, err
+		}
+	}
+	cards = report.Filter(cards, tags, assignees)
+
+	boards := map[string]bool{}
+	for i := range cards {
+		id := kanban.BoardID(&cards[i])
+		if id == "" || boards[id] {
+			continue
+		}
+		boards[id] = true
+		columns, err := c.Columns.List(ctx, id)
+		if err != nil {
+			return opts, nil, fmt.Errorf("failed to list columns: %w", err)
+		}
+		opts.Columns = append(opts.Columns, columns...)
+	}
+	store, err := history.DefaultStore()
+	if err != nil {
+		return opts, nil, err
+	}
+	for id := range boards {
+		snaps, err := store.Load(id, "", "")
+		if err != nil {
+			return opts, nil, err
+		}
+		if len(snaps) == 0 {
+			continue
+		}
+		if opts.Starts == nil {
+			opts.Starts = map[string]time.Time{}
+		}
+		for cardID, start := range history.Starts(snaps) {
+			opts.Starts[cardID] = start
+		}
+	}
+
+	opts.Now = time.Now()
+	if until, _ := cmd.Flags().GetString("until"); until != "" {
+		if opts.Now, err = parseTime(until); err != nil {
+			return opts, nil, fmt.Errorf("invalid --until: %w", err)
+		}
+	}
+	return opts, cards, nil
+}
+
+// parseTime parses a date (YYYY-MM-DD, local midnight) or an RFC 3339 time
+func parseTime(value string) (time.Time, error) {
+	if t, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q is not a date (YYYY-MM-DD) or RFC 3339 time", value)
+	}
+	return t, nil
+}
+
+var reportCFDCmd = &cobra.Command{
+	Use:   "cfd",
+	Short: "Cumulative flow diagram of a board",
+	Long: `Chart how many cards were in each column of a board on each day, from the
+snapshots saved by 'fizz sync'.
+
+Write the chart with --output: the file extension picks SVG, PNG or CSV.
+Without --output the daily counts are printed in the --format.`,
+	Args: cobra.NoArgs,
+	Example: `  fizz report cfd --board=03fbhiu9dgjo0viyrlya1x03a --since=30d -o cfd.svg
+  fizz report cfd --board=03fbhiu9dgjo0viyrlya1x03a --since=2026-01-01 -o cfd.png
+  fizz report cfd --board=03fbhiu9dgjo0viyrlya1x03a --format=csv`,
+	Annotations: map[string]string{noClientAnnotation: "true"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		boardID, _ := cmd.Flags().GetString("board")
+		if boardID == "" {
+			return fmt.Errorf("--board is required")
+		}
+		store, days, err := chartInput(cmd)
+		if err != nil {
+			return err
+		}
+
+		snaps, err := store.Load(boardID, "", days[len(days)-1])
+		if err != nil {
+			return err
+		}
+		title := "Cumulative flow"
+		if len(snaps) > 0 {
+			title += ": " + snaps[len(snaps)-1].Board.Name
+		}
+		c, err := report.CFD(title, snaps, days)
+		if err != nil {
+			return err
+		}
+		return writeChart(cmd, c)
+	},
+}
+
+var reportBurndownCmd = &cobra.Command{
+	Use:   "burndown",
+	Short: "Burndown chart of a set of cards",
+	Long: `Chart the open cards remaining each day for the cards matching --tag and
+--assignee, with the total scope and an ideal line reaching zero on --due
+(default: the last day), from the snapshots saved by 'fizz sync'.
+
+Write the chart with --output: the file extension picks SVG, PNG or CSV.
+Without --output the daily counts are printed in the --format.`,
+	Args: cobra.NoArgs,
+	Example: `  fizz report burndown --tag=milestone-4 -o burndown.svg
+  fizz report burndown --tag=milestone-4 --since=2026-03-01 --due=2026-03-31 -o burndown.png
+  fizz report burndown --board=03fbhiu9dgjo0viyrlya1x03a --assignee=me --format=csv`,
+	Annotations: map[string]string{noClientAnnotation: "true"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		tags, _ := cmd.Flags().GetStringSlice("tag")
+		assignees, _ := cmd.Flags().GetStringSlice("assignee")
+		due, _ := cmd.Flags().GetString("due")
+		if due != "" {
+			t, err := parseTime(due)
+			if err != nil {
+				return fmt.Errorf("invalid --due: %w", err)
+			}
+			due = t.Local().Format("2006-01-02")
+		}
+
+		if slices.Contains(assignees, "me") {
+			// Snapshots are read without the API, but "me" needs it
+			c := lazyClient()
+			if c == nil {
+				return fmt.Errorf("--assignee=me needs fizz to be configured; pass a user ID instead")
+			}
+			for i, assignee := range assignees {
+				var err error
+				if assignees[i], err = c.ResolveUserID(cmd.Context(), assignee); err != nil {
+					return err
+				}
+			}
+		}
+
+		store, days, err := chartInput(cmd)
+		if err != nil {
+			return err
+		}
+		boardIDs := []string{}
+		if boardID, _ := cmd.Flags().GetString("board"); boardID != "" {
+			boardIDs = append(boardIDs, boardID)
+		} else if boardIDs, err = store.Boards(); err != nil {
+			return err
+		}
+
+		var boards [][]*history.Snapshot
+		for _, id := range boardIDs {
+			snaps, err := store.Load(id, "", days[len(days)-1])
+			if err != nil {
+				return err
+			}
+			boards = append(boards, snaps)
+		}
+
+		match := func(card history.Card) bool {
+			for _, tag := range tags {
+				if !kanban.HasTag(card.Tags, tag) {
+					return false
+				}
+			}
+			if len(assignees) == 0 {
+				return true
+			}
+			for _, id := range assignees {
+				if card.HasAssignee(id) {
+					return true
+				}
+			}
+			return false
+		}
+
+		title := "Burndown"
+		if len(tags) > 0 {
+			title += ": " + strings.Join(tags, ", ")
+		}
+		c, err := report.Burndown(title, boards, days, match, due)
+		if err != nil {
+			return err
+		}
+		return writeChart(cmd, c)
+	},
+}
+
+// chartInput returns the history store and the days covered by --since and
+// --until
+func chartInput(cmd *cobra.Command) (*history.Store, []string, error) {
+	until := time.Now()
+	if value, _ := cmd.Flags().GetString("until"); value != "" {
+		t, err := parseTime(value)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid --until: %w", err)
+		}
+		until = t
+	}
+	value, _ := cmd.Flags().GetString("since")
+	since, err := parseSince(value, until)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid --since: %w", err)
+	}
+	if since.After(until) {
+		return nil, nil, fmt.Errorf("--since is after --until")
+	}
+
+	store, err := history.DefaultStore()
+	if err != nil {
+		return nil, nil, err
+	}
+	return store, history.Days(since.Local(), until.Local()), nil
+}
+
+// writeChart writes c to --output, choosing the format from the file
+// extension, or prints its data in the --format
+func writeChart(cmd *cobra.Command, c *chart.Chart) error {
+	output, _ := cmd.Flags().GetString("output")
+	if output == "" {
+		switch GetFormat() {
+		case "table", "":
+			return format.WriteChartTable(cmd.OutOrStdout(), c)
+		case "csv":
+			return c.WriteCSV(cmd.OutOrStdout())
+		default:
+			formatter, err := format.NewFormatter(GetFormat(), cmd.OutOrStdout())
+			if err != nil {
+				return err
+			}
+			return formatter.Format(c)
+		}
+	}
+
+	kind := strings.TrimPrefix(strings.ToLower(filepath.Ext(output)), ".")
+	if kind != "svg" && kind != "png" && kind != "csv" {
+		return fmt.Errorf("cannot tell the chart format from %q: use a .svg, .png or .csv file", output)
+	}
+	width, _ := cmd.Flags().GetInt("width")
+	height, _ := cmd.Flags().GetInt("height")
+
+	f, err := os.Create(output)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", output, err)
+	}
+	if err := c.Write(f, kind, width, height); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write %s: %w", output, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", output, err)
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "Wrote %s (%d days, %s to %s)\n", output, len(c.Labels), c.Labels[0], c.Labels[len(c.Labels)-1])
+	return nil
+}
+
+// parseSince parses a time relative to now, such as 30d, 4w or 12h, or an
+// absolute date or time
+func parseSince(value string, now time.Time) (time.Time, error) {
+	if n := len(value); n > 1 {
+		if count, err := strconv.Atoi(value[:n-1]); err == nil && count >= 0 {
+			switch value[n-1] {
+			case 'h':
+				return now.Add(-time.Duration(count) * time.Hour), nil
+			case 'd':
+				return now.AddDate(0, 0, -count), nil
+			case 'w':
+				return now.AddDate(0, 0, -7*count), nil
+			}
+		}
+	}
+	t, err := parseTime(value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q is not a duration (30d, 4w, 12h), date (YYYY-MM-DD) or RFC 3339 time", value)
+	}
+	return t, nil
+}
+
+// addChartFlags registers the period and output flags of chart reports
+func addChartFlags(cmd *cobra.Command) {
+	cmd.Flags().String("since", "30d", "Start of the period: a duration such as 30d or 4w, or a date")
+	cmd.Flags().String("until", "", "End of the period, as YYYY-MM-DD or RFC 3339 (default: now)")
+	cmd.Flags().StringP("output", "o", "", "Write the chart to a .svg, .png or .csv file")
+	cmd.Flags().Int("width", chart.DefaultWidth, "Image width in pixels")
+	cmd.Flags().Int("height", chart.DefaultHeight, "Image height in pixels")
+}
+
+// listAllCards returns every card, open and closed, on a board or on all
+// boards when boardID is empty
+func listAllCards(ctx context.Context, c *client.Client, boardID string) ([]fizzy.Card, error) {
+	seen := map[string]bool{}
+	var cards []fizzy.Card
+	for _, status := range []string{"", "closed"} {
+		list, err := c.ListAllCards(ctx, &fizzy.CardListOptions{BoardID: boardID, Status: status})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list cards: %w", err)
+		}
+		for _, card := range list {
+			if !seen[card.ID] {
+				seen[card.ID] = true
+				cards = append(cards, card)
+			}
+		}
+	}
+	return cards, nil
+}
+
+// addReportFlags registers the filters shared by report commands
+func addReportFlags(cmd *cobra.Command) {
+	addFilterFlags(cmd, cmd.Flags())
+	cmd.Flags().Int("weeks", 12, "Number of weeks to report on")
+	cmd.Flags().String("until", "", "End of the period, as YYYY-MM-DD or RFC 3339 (default: now)")
+}
+
+// addFilterFlags registers the --board, --tag and --assignee filters in one
+// of cmd's flag sets, with completion
+func addFilterFlags(cmd *cobra.Command, flags *pflag.FlagSet) {
+	flags.String("board", "", "Only cards on this board (default: all boards)")
+	flags.StringSlice("tag", nil, "Only cards with this tag, by name or ID (repeatable)")
+	flags.StringSlice("assignee", nil, "Only cards assigned to this user, by ID, name or 'me' (repeatable)")
+	addFilterCompletion(cmd)
+}
+
+// addFilterCompletion completes the --board, --tag and --assignee filters
+func addFilterCompletion(cmd *cobra.Command) {
+	cmd.RegisterFlagCompletionFunc("board", completeFlag(boardCandidates))
+	cmd.RegisterFlagCompletionFunc("tag", completeFlag(tagCandidates))
+	cmd.RegisterFlagCompletionFunc("assignee", completeFlag(userCandidates))
+}
+
+func init() {
+	addReportFlags(reportCmd)
+	reportCmd.Flags().Int("oldest", 10, "Number of oldest open cards to list")
+
+	reportCFDCmd.Flags().String("board", "", "Board to chart (required)")
+	addChartFlags(reportCFDCmd)
+	reportCFDCmd.RegisterFlagCompletionFunc("board", completeFlag(boardCandidates))
+
+	reportBurndownCmd.Flags().String("board", "", "Only cards on this board (default: all boards with history)")
+	reportBurndownCmd.Flags().StringSlice("tag", nil, "Only cards with this tag name (repeatable)")
+	reportBurndownCmd.Flags().StringSlice("assignee", nil, "Only cards assigned to this user, by ID or 'me' (repeatable)")
+	reportBurndownCmd.Flags().String("due", "", "Date the ideal line reaches zero (default: the last day)")
+	addChartFlags(reportBurndownCmd)
+	addFilterCompletion(reportBurndownCmd)
+
+	reportCmd.AddCommand(reportCFDCmd)
+	reportCmd.AddCommand(reportBurndownCmd)
+	rootCmd.AddCommand(reportCmd)
+}
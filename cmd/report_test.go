@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/visionik/fizz/internal/history"
+	"github.com/visionik/libfizz-go/fizzy"
+)
+
+// seedReport closes some cards on the seeded board so reports have history
+func (f *fizzTest) seedReport() fixture {
+	fx := f.seed()
+	closeCard := func(title string, created, closed time.Time) {
+		card := f.srv.AddCard(fx.BoardID, title, "")
+		f.srv.UpdateCard(strconv.Itoa(card.Number), func(c *fizzy.Card) {
+			c.CreatedAt, c.ClosedAt, c.Closed, c.Status = created, &closed, true, "closed"
+		})
+	}
+	day := func(d int) time.Time { return time.Date(2026, time.January, d, 10, 0, 0, 0, time.UTC) }
+	closeCard("Rotate certificates", day(5), day(6))
+	closeCard("Add rate limiting", day(6), day(9))
+	closeCard("Fix flaky deploy", day(12), day(19))
+	return fx
+}
+
+func TestReport(t *testing.T) {
+	f := newFizzTest(t)
+	f.seedReport()
+	isolateConfig(t)
+
+	args := []string{"report", "--until=2026-01-21T12:00:00Z", "--weeks=3"}
+	f.golden("report.table", f.mustRun(args...))
+	f.golden("report.csv", f.mustRun(append(args, "--format=csv")...))
+
+	out := f.mustRun(append(args, "--format=json")...)
+	assert.Contains(t, out, `"cycle_time": null`)
+	assert.Contains(t, out, `"p50_days": 3`)
+}
+
+func TestReportFilters(t *testing.T) {
+	f := newFizzTest(t)
+	f.seedReport()
+	isolateConfig(t)
+
+	out := f.mustRun("report", "--until=2026-01-21T12:00:00Z", "--tag=infra", "--format=json")
+	assert.Contains(t, out, `"cards": 1`)
+
+	out = f.mustRun("report", "--until=2026-01-21T12:00:00Z", "--assignee=me", "--format=json")
+	assert.Contains(t, out, `"cards": 0`)
+
+	_, err := f.run("report", "--until=yesterday")
+	assert.ErrorContains(t, err, "invalid --until")
+}
+
+func TestSync(t *testing.T) {
+	f := newFizzTest(t)
+	fx := f.seedReport()
+	dir := isolateConfig(t)
+
+	out := f.mustRun("sync")
+	assert.Equal(t, "Saved snapshot of Platform: 5 cards in 3 columns\nSaved snapshot of Marketing: 0 cards in 0 columns\n", out)
+
+	store := &history.Store{Dir: filepath.Join(dir, "config", "history")}
+	snaps, err := store.Load(fx.BoardID, "", "")
+	require.NoError(t, err)
+	require.Len(t, snaps, 1)
+	assert.Equal(t, "Platform", snaps[0].Board.Name)
+	assert.Len(t, snaps[0].Cards, 5)
+
+	// With history, the report includes cycle time
+	out = f.mustRun("report", "--format=json")
+	assert.NotContains(t, out, `"cycle_time": null`)
+}
+
+// seedHistory writes three days of snapshots of a board
+func seedHistory(t *testing.T) {
+	store, err := history.DefaultStore()
+	require.NoError(t, err)
+
+	columns := []history.Column{{ID: "c1", Name: "Doing", Position: 1}, {ID: "c2", Name: "Done", Position: 2}}
+	day := func(d int, cards ...history.Card) {
+		_, err := store.Save(&history.Snapshot{
+			Version: history.Version,
+			TakenAt: time.Date(2026, time.March, d, 18, 0, 0, 0, time.Local),
+			Board:   history.Board{ID: "b1", Name: "Platform"},
+			Columns: columns,
+			Cards:   cards,
+		})
+		require.NoError(t, err)
+	}
+	m4 := []string{"milestone-4"}
+	day(2, history.Card{ID: "a", Tags: m4}, history.Card{ID: "b", Tags: m4}, history.Card{ID: "c"})
+	day(3, history.Card{ID: "a", ColumnID: "c1", Tags: m4}, history.Card{ID: "b", Tags: m4}, history.Card{ID: "c"})
+	day(4, history.Card{ID: "a", Closed: true, Tags: m4}, history.Card{ID: "b", ColumnID: "c1", Tags: m4}, history.Card{ID: "c", ColumnID: "c2"})
+}
+
+func TestReportCFD(t *testing.T) {
+	f := newFizzTest(t)
+	dir := isolateConfig(t)
+	seedHistory(t)
+
+	args := []string{"report", "cfd", "--board=b1", "--since=2026-03-01", "--until=2026-03-04"}
+	f.golden("report_cfd.table", f.mustRun(args...))
+	assert.Equal(t, `date,Closed,Done,Doing,Triage
+2026-03-02,0,0,0,3
+2026-03-03,0,0,1,2
+2026-03-04,1,1,1,0
+`, f.mustRun(append(args, "--format=csv")...))
+
+	out := f.mustRun(append(args, "-o", "cfd.svg")...)
+	assert.Equal(t, "Wrote cfd.svg (3 days, 2026-03-02 to 2026-03-04)\n", out)
+	svg, err := os.ReadFile(filepath.Join(dir, "work", "cfd.svg"))
+	require.NoError(t, err)
+	assert.Contains(t, string(svg), "Cumulative flow: Platform")
+
+	f.mustRun(append(args, "-o", "cfd.png")...)
+	assert.FileExists(t, filepath.Join(dir, "work", "cfd.png"))
+
+	_, err = f.run(append(args, "-o", "cfd.gif")...)
+	assert.ErrorContains(t, err, "use a .svg, .png or .csv file")
+
+	_, err = f.run("report", "cfd", "--board=b1", "--since=2026-01-01", "--until=2026-01-31")
+	assert.ErrorContains(t, err, "run 'fizz sync'")
+}
+
+func TestReportBurndown(t *testing.T) {
+	f := newFizzTest(t)
+	isolateConfig(t)
+	seedHistory(t)
+
+	out := f.mustRun("report", "burndown", "--tag=milestone-4", "--since=2026-03-02", "--until=2026-03-04", "--due=2026-03-06", "--format=csv")
+	assert.Equal(t, `date,Remaining,Scope,Ideal
+2026-03-02,2,2,2
+2026-03-03,2,2,1.5
+2026-03-04,1,2,1
+`, out)
+}
+
+func TestReportBurndownMe(t *testing.T) {
+	f := newFizzTest(t)
+	isolateConfig(t)
+	store, err := history.DefaultStore()
+	require.NoError(t, err)
+	me := []string{f.srv.Me().ID}
+	_, err = store.Save(&history.Snapshot{
+		Version: history.Version,
+		TakenAt: time.Date(2026, time.March, 2, 18, 0, 0, 0, time.Local),
+		Board:   history.Board{ID: "b1", Name: "Platform"},
+		Cards:   []history.Card{{ID: "a", Assignees: me}, {ID: "b", Assignees: me, Closed: true}, {ID: "c"}},
+	})
+	require.NoError(t, err)
+
+	out := f.mustRun("report", "burndown", "--assignee=me", "--since=2026-03-02", "--until=2026-03-02", "--format=csv")
+	assert.Equal(t, "date,Remaining,Scope,Ideal\n2026-03-02,1,2,0\n", out)
+}
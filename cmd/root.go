@@ -1,19 +1,31 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	"github.com/visionik/fizz/internal/aihelp"
 	"github.com/visionik/fizz/internal/client"
 	"github.com/visionik/fizz/internal/config"
+	"github.com/visionik/fizz/internal/plugin"
 )
 
+// noClientAnnotation marks commands that run without API credentials
+const noClientAnnotation = "fizz:no-client"
+
 var (
 	formatFlag string
 	debugFlag  bool
 	globalClient *client.Client
+	noInputFlag  bool
+	noCacheFlag  bool
+	cacheTTLFlag time.Duration
 )
 
 // rootCmd represents the base command
@@ -48,14 +60,82 @@ func Execute() {
 		}
 	}
 	
+	// Aliases come before plugins, which only run for unknown commands
+	loadAliases(os.Stderr)
+
+	// A word that is not a command may name a plugin
+	ctx := context.Background()
+	if ok, err := runPlugin(ctx, os.Args[1:], os.Stdin, os.Stdout, os.Stderr); ok {
+		var exitErr *plugin.ExitError
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.Code)
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	// On a terminal, pickers fill in missing arguments
+	args, err := promptArgs(ctx, os.Args[1:])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+	}
+	rootCmd.SetArgs(args)
+
 	if err := rootCmd.Execute(); err != nil {
 		os.Exit(1)
 	}
 }
 
+// Run executes fizz with the given arguments as if invoked from the command
+// line, writing output to out and errOut. Flags are reset before each run so
+// it can be called repeatedly within one process.
+func Run(ctx context.Context, args []string, out, errOut io.Writer) error {
+	// Commands run by an alias keep the aliases already loaded
+	if aliasDepth == 0 {
+		loadAliases(errOut)
+	}
+	if ok, err := runPlugin(ctx, args, rootCmd.InOrStdin(), out, errOut); ok {
+		if err != nil {
+			fmt.Fprintf(errOut, "Error: %v\n", err)
+		}
+		return err
+	}
+
+	resetFlags(rootCmd)
+	rootCmd.SetArgs(args)
+	rootCmd.SetOut(out)
+	rootCmd.SetErr(errOut)
+	return rootCmd.ExecuteContext(ctx)
+}
+
+// resetFlags restores every flag in the command tree to its default value
+func resetFlags(cmd *cobra.Command) {
+	reset := func(f *pflag.Flag) {
+		if sv, ok := f.Value.(pflag.SliceValue); ok {
+			sv.Replace(nil)
+		} else {
+			f.Value.Set(f.DefValue)
+		}
+		f.Changed = false
+	}
+	cmd.Flags().VisitAll(reset)
+	cmd.PersistentFlags().VisitAll(reset)
+
+	for _, sub := range cmd.Commands() {
+		resetFlags(sub)
+	}
+}
+
 func init() {
-	rootCmd.PersistentFlags().StringVar(&formatFlag, "format", "table", "Output format (table, json, yaml)")
+	rootCmd.PersistentFlags().StringVar(&formatFlag, "format", "table", "Output format (table, json, jsonl, yaml, csv)")
 	rootCmd.PersistentFlags().BoolVar(&debugFlag, "debug", false, "Enable debug output")
+	rootCmd.PersistentFlags().BoolVar(&noInputFlag, "no-input", false, "Never prompt; fail when required arguments are missing")
+	rootCmd.PersistentFlags().BoolVar(&noCacheFlag, "no-cache", false, "Neither use nor store cached API responses")
+	rootCmd.PersistentFlags().DurationVar(&cacheTTLFlag, "cache-ttl", 0, "Use cached API responses younger than this without revalidating (e.g. 30s, 5m)")
 	
 	// Add --ai-help flag
 	rootCmd.PersistentFlags().Bool("ai-help", false, "Show AI-powered help for this command")
@@ -66,8 +146,11 @@ func init() {
 
 // initClient initializes the Fizzy client
 func initClient(cmd *cobra.Command, args []string) error {
-	// Skip client initialization for certain commands
-	if cmd.Name() == "help" || cmd.Name() == "completion" {
+	// Skip client initialization for certain commands. Completion creates a
+	// client only when it needs one, so it stays quiet when fizz is not
+	// configured.
+	if cmd.Name() == "help" || cmd.Name() == "completion" || cmd.Annotations[noClientAnnotation] != "" ||
+		cmd.Name() == cobra.ShellCompRequestCmd || cmd.Name() == cobra.ShellCompNoDescRequestCmd {
 		return nil
 	}
 
@@ -79,15 +162,22 @@ func initClient(cmd *cobra.Command, args []string) error {
 		os.Exit(0)
 	}
 
-	cfg, err := config.LoadFromEnv()
-	if err != nil {
-		return err
+	if cacheTTLFlag < 0 {
+		return fmt.Errorf("--cache-ttl must not be negative")
 	}
 
-	globalClient, err = client.New(cfg, debugFlag)
-	if err != nil {
-		return fmt.Errorf("failed to create client: %w", err)
+	// Commands run from the shell share its client
+	if !reuseClient || globalClient == nil {
+		cfg, err := config.LoadFromEnv()
+		if err != nil {
+			return err
+		}
+		globalClient, err = client.New(cfg, debugFlag)
+		if err != nil {
+			return fmt.Errorf("failed to create client: %w", err)
+		}
 	}
+	globalClient.SetCache(noCacheFlag, cacheTTLFlag)
 
 	return nil
 }
@@ -97,6 +187,24 @@ func GetClient() *client.Client {
 	return globalClient
 }
 
+// lazyClient returns the shell's client, or a new one for code that runs
+// before initClient, such as completion and argument pickers; nil if fizz is
+// not configured
+func lazyClient(opts ...client.Option) *client.Client {
+	if reuseClient && globalClient != nil {
+		return globalClient
+	}
+	cfg, err := config.LoadFromEnv()
+	if err != nil {
+		return nil
+	}
+	c, err := client.New(cfg, false, opts...)
+	if err != nil {
+		return nil
+	}
+	return c
+}
+
 // GetFormat returns the format flag value
 func GetFormat() string {
 	return formatFlag
@@ -0,0 +1,305 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/visionik/fizz/internal/config"
+	"github.com/visionik/fizz/internal/format"
+	"github.com/visionik/fizz/internal/schedule"
+)
+
+var scheduleCmd = &cobra.Command{
+	Use:   "schedule",
+	Short: "Recurring cards",
+	Long: `Create recurring cards from cron rules in the config file.
+
+Rules live under "schedules" in ~/.config/fizz/config.yaml (or a project's
+.fizz.yaml, whose list replaces the user's). Each has a name, a five-field
+cron expression and either a template or a board and title:
+
+  schedules:
+    - name: on-call-handoff
+      cron: "0 9 * * mon"
+      timezone: Europe/Berlin
+      board: 03fbhiu9dgjo0viyrlya1x03a
+      title: On-call handoff {{date}}
+      tags: [on-call]
+    - name: dependency-review
+      cron: "@monthly"
+      template: dependency-review
+      vars:
+        team: platform
+
+Run 'fizz schedule run' from cron or a systemd timer. It creates a card for
+each rule whose latest occurrence has not been handled yet and records it in
+schedule-state.json in the config directory, so repeated runs never create a
+card twice. Occurrences missed while nothing ran collapse into one card; a
+new rule only catches up on occurrences from the past day.`,
+}
+
+var scheduleListCmd = &cobra.Command{
+	Use:         "list",
+	Short:       "List schedule rules with their last and next runs",
+	Args:        cobra.NoArgs,
+	Annotations: map[string]string{noClientAnnotation: "true"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		rules, err := loadSchedules()
+		if err != nil {
+			return err
+		}
+		state, err := schedule.LoadState()
+		if err != nil {
+			return err
+		}
+
+		formatter, err := format.NewFormatter(GetFormat(), cmd.OutOrStdout())
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		if GetFormat() == "table" {
+			displays := make([]format.ScheduleDisplay, len(rules))
+			for i, rule := range rules {
+				displays[i] = format.ToScheduleDisplay(rule, state.Rules[rule.Name].Last, rule.Next(now))
+			}
+			return formatter.Format(displays)
+		}
+
+		type entry struct {
+			config.ScheduleRule
+			LastRun *time.Time `json:"last_run,omitempty"`
+			Next    *time.Time `json:"next,omitempty"`
+		}
+		entries := make([]entry, len(rules))
+		for i, rule := range rules {
+			entries[i] = entry{ScheduleRule: rule.ScheduleRule}
+			if last := state.Rules[rule.Name].Last; !last.IsZero() {
+				entries[i].LastRun = &last
+			}
+			if next := rule.Next(now); !next.IsZero() {
+				entries[i].Next = &next
+			}
+		}
+		return formatter.Format(entries)
+	},
+}
+
+// occurrence is one upcoming scheduled card
+type occurrence struct {
+	Schedule string    `json:"schedule"`
+	At       time.Time `json:"at"`
+	Board    string    `json:"board,omitempty"`
+	Title    string    `json:"title"`
+}
+
+var scheduleNextCmd = &cobra.Command{
+	Use:         "next [name]",
+	Short:       "Show upcoming scheduled cards",
+	Args:        cobra.MaximumNArgs(1),
+	Annotations: map[string]string{noClientAnnotation: "true"},
+	Example: `  fizz schedule next
+  fizz schedule next on-call-handoff --count=3`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		rules, err := loadSchedules()
+		if err != nil {
+			return err
+		}
+		if len(args) == 1 {
+			rule, err := schedule.Find(rules, args[0])
+			if err != nil {
+				return err
+			}
+			rules = []schedule.Rule{*rule}
+		}
+		count, _ := cmd.Flags().GetInt("count")
+		if count < 1 {
+			return fmt.Errorf("--count must be at least 1")
+		}
+
+		now := time.Now()
+		var list []occurrence
+		for _, rule := range rules {
+			for _, at := range rule.Upcoming(now, count) {
+				o := occurrence{Schedule: rule.Name, At: at, Board: rule.Board, Title: rule.Title}
+				if card, err := rule.Card(at); err == nil {
+					o.Board, o.Title = card.Board, card.Title
+				}
+				list = append(list, o)
+			}
+		}
+		sort.SliceStable(list, func(i, j int) bool { return list[i].At.Before(list[j].At) })
+		if len(list) > count {
+			list = list[:count]
+		}
+
+		formatter, err := format.NewFormatter(GetFormat(), cmd.OutOrStdout())
+		if err != nil {
+			return err
+		}
+
+		if GetFormat() == "table" {
+			displays := make([]format.OccurrenceDisplay, len(list))
+			for i, o := range list {
+				displays[i] = format.OccurrenceDisplay{
+					When:     o.At.Format("Mon 2006-01-02 15:04 MST"),
+					Schedule: o.Schedule,
+					Title:    o.Title,
+				}
+			}
+			return formatter.Format(displays)
+		}
+		return formatter.Format(list)
+	},
+}
+
+// scheduleResult is a card created, or due with --dry-run, by schedule run
+type scheduleResult struct {
+	Schedule   string    `json:"schedule"`
+	Occurrence time.Time `json:"occurrence"`
+	Card       int       `json:"card,omitempty"`
+	Title      string    `json:"title"`
+	DryRun     bool      `json:"dry_run,omitempty"`
+}
+
+var scheduleRunCmd = &cobra.Command{
+	Use:   "run [name...]",
+	Short: "Create the cards that are due",
+	Long: `Create a card for each schedule rule that is due, or only for the named
+rules. Safe to run as often as you like: each occurrence creates one card.
+
+A crontab entry that checks every 15 minutes:
+
+  */15 * * * * fizz schedule run`,
+	Example: `  fizz schedule run
+  fizz schedule run --dry-run
+  fizz schedule run on-call-handoff`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client := GetClient()
+		ctx := cmd.Context()
+		out := cmd.OutOrStdout()
+
+		rules, err := loadSchedules()
+		if err != nil {
+			return err
+		}
+		if len(args) > 0 {
+			var selected []schedule.Rule
+			for _, name := range args {
+				rule, err := schedule.Find(rules, name)
+				if err != nil {
+					return err
+				}
+				selected = append(selected, *rule)
+			}
+			rules = selected
+		}
+
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		now := time.Now()
+		if at, _ := cmd.Flags().GetString("at"); at != "" {
+			if now, err = time.Parse(time.RFC3339, at); err != nil {
+				return fmt.Errorf("invalid --at %q: use RFC 3339, e.g. 2026-01-05T09:00:00Z", at)
+			}
+		}
+
+		if !dryRun {
+			unlock, err := schedule.Lock()
+			if err != nil {
+				return err
+			}
+			defer unlock()
+		}
+		state, err := schedule.LoadState()
+		if err != nil {
+			return err
+		}
+
+		var results []scheduleResult
+		var failed []error
+		for _, rule := range rules {
+			due, ok := rule.Due(state.Rules[rule.Name].Last, now)
+			if !ok {
+				continue
+			}
+			tmpl, err := rule.Card(due)
+			if err != nil {
+				failed = append(failed, err)
+				continue
+			}
+
+			result := scheduleResult{Schedule: rule.Name, Occurrence: due, Title: tmpl.Title, DryRun: dryRun}
+			if !dryRun {
+				card, err := createFromTemplate(ctx, client, tmpl)
+				if card != nil {
+					// Record the occurrence even if finishing the card failed,
+					// so the next run does not create it again
+					result.Card = card.Number
+					state.Rules[rule.Name] = schedule.RuleState{Last: due, Card: card.Number, RanAt: time.Now()}
+					if err := state.Save(); err != nil {
+						return err
+					}
+				}
+				if err != nil {
+					failed = append(failed, fmt.Errorf("schedule %s: %w", rule.Name, err))
+					continue
+				}
+			}
+			results = append(results, result)
+		}
+
+		if GetFormat() != "table" {
+			formatter, err := format.NewFormatter(GetFormat(), out)
+			if err != nil {
+				return err
+			}
+			if err := formatter.Format(results); err != nil {
+				return err
+			}
+		} else {
+			for _, r := range results {
+				when := r.Occurrence.Format("2006-01-02 15:04")
+				if r.DryRun {
+					fmt.Fprintf(out, "Would create %q for %s (%s)\n", r.Title, r.Schedule, when)
+				} else {
+					fmt.Fprintf(out, "Created card %d %q for %s (%s)\n", r.Card, r.Title, r.Schedule, when)
+				}
+			}
+			if len(results) == 0 && len(failed) == 0 {
+				fmt.Fprintln(out, "Nothing due")
+			}
+		}
+
+		for _, err := range failed {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Error: %v\n", err)
+		}
+		if len(failed) > 0 {
+			return fmt.Errorf("%d schedule(s) failed", len(failed))
+		}
+		return nil
+	},
+}
+
+// loadSchedules returns the validated schedule rules from the config files
+func loadSchedules() ([]schedule.Rule, error) {
+	settings, err := config.LoadSettings()
+	if err != nil {
+		return nil, err
+	}
+	return schedule.Rules(settings)
+}
+
+func init() {
+	scheduleNextCmd.Flags().Int("count", 10, "Number of occurrences to show")
+
+	scheduleRunCmd.Flags().Bool("dry-run", false, "Show what is due without creating cards")
+	scheduleRunCmd.Flags().String("at", "", "Evaluate the schedule as of this time (RFC 3339) instead of now")
+
+	scheduleCmd.AddCommand(scheduleListCmd)
+	scheduleCmd.AddCommand(scheduleNextCmd)
+	scheduleCmd.AddCommand(scheduleRunCmd)
+	rootCmd.AddCommand(scheduleCmd)
+}
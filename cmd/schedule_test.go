@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/visionik/fizz/internal/fakefizzy"
+)
+
+func TestScheduleRun(t *testing.T) {
+	f := newFizzTest(t)
+	fx := f.seed()
+	dir := isolateConfig(t)
+
+	config := `schedules:
+  - name: handoff
+    cron: "0 9 * * mon"
+    timezone: UTC
+    board: ` + fx.BoardID + `
+    title: On-call handoff {{date}}
+    tags: [infra]
+    assignees: [me]
+    column: Doing
+    steps: [Review alerts]
+`
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "config"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config", "config.yaml"), []byte(config), 0o644))
+
+	out := f.mustRun("schedule", "run", "--dry-run", "--at=2026-10-19T09:10:00Z")
+	assert.Equal(t, "Would create \"On-call handoff 2026-10-19\" for handoff (2026-10-19 09:00)\n", out)
+
+	out = f.mustRun("schedule", "run", "--at=2026-10-19T09:10:00Z")
+	assert.Equal(t, "Created card 3 \"On-call handoff 2026-10-19\" for handoff (2026-10-19 09:00)\n", out)
+
+	// Running again for the same occurrence does nothing
+	assert.Equal(t, "Nothing due\n", f.mustRun("schedule", "run", "--at=2026-10-19T12:00:00Z"))
+
+	card := f.mustRun("cards", "show", "3", "--no-pager")
+	assert.Contains(t, card, "infra")
+	assert.Contains(t, card, "Review alerts")
+	assert.Contains(t, card, "Doing")
+	assert.Contains(t, card, f.srv.Me().Name)
+
+	out = f.mustRun("schedule", "list")
+	assert.Contains(t, out, "handoff")
+	assert.Contains(t, out, "2026-10-19 09:00")
+
+	// A week later the next occurrence is due
+	out = f.mustRun("schedule", "run", "--at=2026-10-26T09:00:00Z", "--format=json")
+	assert.Contains(t, out, `"card": 4`)
+	assert.Contains(t, out, `"title": "On-call handoff 2026-10-26"`)
+}
+
+func TestScheduleRunPartialFailure(t *testing.T) {
+	f := newFizzTest(t)
+	fx := f.seed()
+	dir := isolateConfig(t)
+
+	config := `schedules:
+  - name: handoff
+    cron: "0 9 * * mon"
+    board: ` + fx.BoardID + `
+    title: On-call handoff
+    steps: [Review alerts]
+`
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "config"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config", "config.yaml"), []byte(config), 0o644))
+
+	// The card is created but its step is not
+	f.srv.Inject(fakefizzy.Fault{Method: http.MethodPost, Path: "/steps", Status: http.StatusUnprocessableEntity})
+	_, stderr, err := f.runStderr("schedule", "run", "--at=2026-10-19T09:10:00Z")
+	assert.EqualError(t, err, "1 schedule(s) failed")
+	assert.Contains(t, stderr, "schedule handoff: failed to create step")
+
+	// The occurrence is recorded, so it is not created twice
+	assert.Equal(t, "Nothing due\n", f.mustRun("schedule", "run", "--at=2026-10-19T12:00:00Z"))
+	assert.Contains(t, f.mustRun("cards", "get", "3", "--format=json"), `"title": "On-call handoff"`)
+}
+
+func TestScheduleNext(t *testing.T) {
+	f := newFizzTest(t)
+	dir := isolateConfig(t)
+
+	config := `schedules:
+  - name: review
+    cron: "@monthly"
+    board: b1
+    title: Dependency review {{date}}
+  - name: handoff
+    cron: "0 9 * * mon"
+    board: b1
+    title: Handoff
+`
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "config"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config", "config.yaml"), []byte(config), 0o644))
+
+	out := f.mustRun("schedule", "next", "review", "--count=2", "--format=json")
+	assert.Contains(t, out, `"title": "Dependency review `)
+	assert.NotContains(t, out, "Handoff")
+
+	out = f.mustRun("schedule", "next", "--count=3")
+	assert.Contains(t, out, "Handoff")
+
+	_, err := f.run("schedule", "next", "nope")
+	assert.EqualError(t, err, "schedule nope not found")
+}
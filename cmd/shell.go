@@ -0,0 +1,383 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+
+	"github.com/mattn/go-isatty"
+	"github.com/spf13/cobra"
+	"github.com/visionik/fizz/internal/shell"
+	"github.com/visionik/libfizz-go/fizzy"
+	"golang.org/x/term"
+)
+
+// reuseClient keeps the client created when the shell started for every
+// command it runs
+var reuseClient bool
+
+// errExit ends the shell
+var errExit = errors.New("exit")
+
+var shellCmd = &cobra.Command{
+	Use:   "shell",
+	Short: "Run fizz commands at an interactive prompt",
+	Long: `Start an interactive prompt that runs fizz commands without the "fizz"
+prefix, with one client for the whole session.
+
+Set a sticky context so commands fill in what you leave out:
+
+  use board Platform     board for --board and <board-id> arguments
+  use card 123           card for <card-id-or-number> arguments
+  use                    show the context
+  unset board|card|all   clear it
+  exit                   leave the shell (or Ctrl-D)
+
+Tab completes commands, flags and board names. History is kept in
+shell_history in the config directory. When input is not a terminal, the
+shell runs one command per line, so a file of commands can be piped in.`,
+	Example: `  fizz shell
+  fizz shell < commands.txt`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		s := &shellSession{ctx: cmd.Context(), out: cmd.OutOrStdout(), errOut: cmd.ErrOrStderr()}
+		reuseClient = true
+		defer func() { reuseClient = false }()
+
+		if f, ok := cmd.InOrStdin().(*os.File); ok && isatty.IsTerminal(f.Fd()) {
+			if o, ok := s.out.(*os.File); ok && isatty.IsTerminal(o.Fd()) {
+				return s.interactive(f)
+			}
+		}
+		return s.script(cmd.InOrStdin())
+	},
+}
+
+// shellSession is the state of a running shell
+type shellSession struct {
+	ctx         context.Context
+	out, errOut io.Writer
+
+	board *fizzy.Board
+	card  *fizzy.Card
+	// boards caches the board list for completion and 'use board'
+	boards []fizzy.Board
+}
+
+// interactive reads commands from a terminal with line editing
+func (s *shellSession) interactive(f *os.File) error {
+	history, err := shell.LoadHistory()
+	if err != nil {
+		return err
+	}
+	fd := int(f.Fd())
+	t := term.NewTerminal(struct {
+		io.Reader
+		io.Writer
+	}{f, s.out}, "")
+	t.History = history
+	t.AutoCompleteCallback = s.completer(t)
+
+	fmt.Fprintln(s.out, "fizz shell: 'use board <name>' sets a context, 'exit' or Ctrl-D leaves")
+	for {
+		if width, height, err := term.GetSize(fd); err == nil {
+			t.SetSize(width, height)
+		}
+		t.SetPrompt(s.prompt())
+
+		// The terminal is raw only while reading, so commands can use the
+		// pager and editor
+		state, err := term.MakeRaw(fd)
+		if err != nil {
+			return fmt.Errorf("failed to set up terminal: %w", err)
+		}
+		line, err := t.ReadLine()
+		term.Restore(fd, state)
+		if errors.Is(err, io.EOF) {
+			fmt.Fprintln(s.out)
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := s.execute(line); errors.Is(err, errExit) {
+			return nil
+		}
+	}
+}
+
+// script runs one command per line of in and reports how many failed
+func (s *shellSession) script(in io.Reader) error {
+	failed := 0
+	scanner := bufio.NewScanner(in)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		err := s.execute(line)
+		if errors.Is(err, errExit) {
+			break
+		}
+		if err != nil {
+			failed++
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read commands: %w", err)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d command(s) failed", failed)
+	}
+	return nil
+}
+
+func (s *shellSession) prompt() string {
+	var parts []string
+	if s.board != nil {
+		parts = append(parts, s.board.Name)
+	}
+	if s.card != nil {
+		parts = append(parts, fmt.Sprintf("#%d", s.card.Number))
+	}
+	if len(parts) == 0 {
+		return "fizz> "
+	}
+	return "fizz (" + strings.Join(parts, " ") + ")> "
+}
+
+// execute runs one line: a shell built-in or a fizz command
+func (s *shellSession) execute(line string) error {
+	words, err := shell.Split(line)
+	if err != nil {
+		fmt.Fprintf(s.errOut, "Error: %v\n", err)
+		return err
+	}
+	if len(words) > 0 && words[0] == "fizz" {
+		words = words[1:]
+	}
+	if len(words) == 0 {
+		return nil
+	}
+
+	switch words[0] {
+	case "exit", "quit":
+		return errExit
+	case "use":
+		err = s.use(words[1:])
+	case "unset":
+		err = s.unset(words[1:])
+	case "shell":
+		err = errors.New("already in the shell")
+	default:
+		// Ctrl-C cancels the running command, not the shell
+		ctx, stop := signal.NotifyContext(s.ctx, os.Interrupt)
+		defer stop()
+		args, err := promptArgs(ctx, s.fill(words))
+		if err != nil {
+			fmt.Fprintf(s.errOut, "Error: %v\n", err)
+			return err
+		}
+		return Run(ctx, args, s.out, s.errOut)
+	}
+	if err != nil {
+		fmt.Fprintf(s.errOut, "Error: %v\n", err)
+	}
+	return err
+}
+
+// use sets or shows the context
+func (s *shellSession) use(args []string) error {
+	if len(args) == 0 {
+		if s.board == nil && s.card == nil {
+			fmt.Fprintln(s.out, "No context; set one with 'use board <name>' or 'use card <number>'")
+		}
+		if s.board != nil {
+			fmt.Fprintf(s.out, "board: %s (%s)\n", s.board.Name, s.board.ID)
+		}
+		if s.card != nil {
+			fmt.Fprintf(s.out, "card:  #%d %s\n", s.card.Number, s.card.Title)
+		}
+		return nil
+	}
+	if len(args) != 2 {
+		return errors.New("usage: use board <name-or-id> | use card <number-or-id>")
+	}
+
+	switch args[0] {
+	case "board":
+		boards, err := s.listBoards()
+		if err != nil {
+			return err
+		}
+		for i, b := range boards {
+			if b.ID == args[1] || strings.EqualFold(b.Name, args[1]) {
+				s.board = &boards[i]
+				fmt.Fprintf(s.out, "Using board %s (%s)\n", b.Name, b.ID)
+				return nil
+			}
+		}
+		return fmt.Errorf("board %q not found", args[1])
+	case "card":
+		card, err := GetClient().Cards.Get(s.ctx, args[1])
+		if err != nil {
+			return fmt.Errorf("failed to get card: %w", err)
+		}
+		s.card = card
+		fmt.Fprintf(s.out, "Using card #%d %s\n", card.Number, card.Title)
+		return nil
+	default:
+		return fmt.Errorf("unknown context %q: use board or card", args[0])
+	}
+}
+
+// unset clears part of the context
+func (s *shellSession) unset(args []string) error {
+	if len(args) != 1 {
+		return errors.New("usage: unset board|card|all")
+	}
+	switch args[0] {
+	case "board":
+		s.board = nil
+	case "card":
+		s.card = nil
+	case "all":
+		s.board, s.card = nil, nil
+	default:
+		return fmt.Errorf("unknown context %q: use board, card or all", args[0])
+	}
+	return nil
+}
+
+// listBoards returns the boards, listing them once per session
+func (s *shellSession) listBoards() ([]fizzy.Board, error) {
+	if s.boards == nil {
+		boards, err := GetClient().Boards.List(s.ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list boards: %w", err)
+		}
+		s.boards = boards
+	}
+	return s.boards, nil
+}
+
+// fill adds the context to a command's arguments: the board as --board
+// when the command has that flag, and the board or card as the next
+// argument when the command takes one there
+func (s *shellSession) fill(args []string) []string {
+	if s.board == nil && s.card == nil {
+		return args
+	}
+	c, positional, placeholders := parseArgs(args)
+	if c == nil {
+		return args
+	}
+
+	filled := append([]string{}, args...)
+	if len(positional) < len(placeholders) {
+		switch {
+		case placeholders[len(positional)] == "<board-id>" && s.board != nil:
+			filled = append(filled, s.board.ID)
+		case placeholders[len(positional)] == "<card-id-or-number>" && s.card != nil:
+			filled = append(filled, fmt.Sprint(s.card.Number))
+		}
+	}
+	if f := c.Flags().Lookup("board"); f != nil && !f.Changed && s.board != nil {
+		filled = append(filled, "--board="+s.board.ID)
+	}
+	return filled
+}
+
+// completer returns the terminal's tab completion, which also clears the
+// line on Ctrl-C
+func (s *shellSession) completer(t *term.Terminal) func(string, int, rune) (string, int, bool) {
+	return func(line string, pos int, key rune) (string, int, bool) {
+		switch key {
+		case 3: // Ctrl-C
+			fmt.Fprintln(t, s.prompt()+line+"^C")
+			return "", 0, true
+		case '\t':
+		default:
+			return "", 0, false
+		}
+
+		head := line[:pos]
+		start := strings.LastIndexAny(head, " \t") + 1
+		words, err := shell.Split(head[:start])
+		if err != nil {
+			return "", 0, false
+		}
+		partial := head[start:]
+
+		completed, done, matches := shell.Complete(partial, s.candidates(words, partial))
+		if done {
+			completed += " "
+		} else if completed == partial && len(matches) > 1 {
+			fmt.Fprintln(t, strings.Join(matches, "  "))
+		}
+		return head[:start] + completed + line[pos:], start + len(completed), true
+	}
+}
+
+// candidates returns the completions for the word after words
+func (s *shellSession) candidates(words []string, partial string) []string {
+	if len(words) > 0 && words[0] == "fizz" {
+		words = words[1:]
+	}
+	if len(words) > 0 {
+		switch words[0] {
+		case "use":
+			switch {
+			case len(words) == 1:
+				return []string{"board", "card"}
+			case len(words) == 2 && words[1] == "board":
+				boards, err := s.listBoards()
+				if err != nil {
+					return nil
+				}
+				var names []string
+				for _, b := range boards {
+					names = append(names, shell.Quote(b.Name), b.ID)
+				}
+				return names
+			}
+			return nil
+		case "unset":
+			if len(words) == 1 {
+				return []string{"board", "card", "all"}
+			}
+			return nil
+		}
+	}
+
+	// Everything else comes from the command tree's own completion
+	var buf bytes.Buffer
+	args := append([]string{cobra.ShellCompNoDescRequestCmd}, s.fill(words)...)
+	if err := Run(s.ctx, append(args, partial), &buf, io.Discard); err != nil {
+		return nil
+	}
+	var candidates []string
+	if len(words) == 0 {
+		candidates = []string{"use", "unset", "exit"}
+	}
+	for _, line := range strings.Split(buf.String(), "\n") {
+		if strings.HasPrefix(line, ":") {
+			break
+		}
+		if line != "" && !strings.HasPrefix(line, "_activeHelp_") {
+			candidates = append(candidates, line)
+		}
+	}
+	return candidates
+}
+
+func init() {
+	rootCmd.AddCommand(shellCmd)
+}
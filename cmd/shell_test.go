@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/visionik/libfizz-go/fizzy"
+)
+
+func TestShell(t *testing.T) {
+	f := newFizzTest(t)
+	fx := f.seed()
+	isolateConfig(t)
+
+	rootCmd.SetIn(strings.NewReader(`use board platform
+# comments and blank lines are skipped
+
+columns list --format=json
+use card 1
+fizz cards show --no-pager
+cards create --title "From the shell" --format=json
+use
+unset all
+use
+use board Nowhere
+exit
+cards list
+`))
+	t.Cleanup(func() { rootCmd.SetIn(nil) })
+
+	out, errOut, err := f.runStderr("shell")
+	assert.EqualError(t, err, "1 command(s) failed")
+	assert.Contains(t, errOut, "Error: board \"Nowhere\" not found\n")
+	assert.Contains(t, out, "Using board Platform ("+fx.BoardID+")\n")
+	assert.Contains(t, out, `"name": "Doing"`)
+	assert.Contains(t, out, "Using card #1 Upgrade the database cluster\n")
+	assert.Contains(t, out, "Upgrade the database cluster\n")
+	assert.Contains(t, out, `"title": "From the shell"`)
+	assert.Contains(t, out, "board: Platform ("+fx.BoardID+")\ncard:  #1 Upgrade the database cluster\n")
+	assert.Contains(t, out, "No context; set one with")
+
+	// The new card went to the board in context
+	list := f.mustRun("cards", "list", "--board="+fx.BoardID)
+	assert.Contains(t, list, "From the shell")
+}
+
+func TestShellFill(t *testing.T) {
+	s := &shellSession{}
+	args := []string{"comments", "list"}
+	assert.Equal(t, args, s.fill(args))
+
+	s.board = &fizzy.Board{ID: "b1", Name: "Platform"}
+	s.card = &fizzy.Card{Number: 7}
+	assert.Equal(t, []string{"columns", "list", "b1"}, s.fill([]string{"columns", "list"}))
+	assert.Equal(t, []string{"comments", "list", "7"}, s.fill([]string{"comments", "list"}))
+	assert.Equal(t, []string{"comments", "list", "9"}, s.fill([]string{"comments", "list", "9"}))
+	assert.Equal(t, []string{"cards", "list", "--status=closed", "--board=b1"}, s.fill([]string{"cards", "list", "--status=closed"}))
+	assert.Equal(t, []string{"cards", "list", "--board=b2"}, s.fill([]string{"cards", "list", "--board=b2"}))
+}
+
+func TestShellCandidates(t *testing.T) {
+	f := newFizzTest(t)
+	f.seed()
+	f.mustRun("boards", "list")
+	reuseClient = true
+	t.Cleanup(func() { reuseClient = false })
+
+	s := &shellSession{ctx: context.Background()}
+	assert.Equal(t, []string{"board", "card"}, s.candidates([]string{"use"}, ""))
+	assert.Contains(t, s.candidates([]string{"use", "board"}, "P"), "Platform")
+	assert.Contains(t, s.candidates(nil, "ca"), "cards")
+	assert.Contains(t, s.candidates(nil, "u"), "use")
+	assert.Contains(t, s.candidates([]string{"cards"}, "li"), "list")
+	assert.Contains(t, s.candidates([]string{"cards", "list"}, "--st"), "--status")
+}
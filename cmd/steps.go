@@ -15,9 +15,10 @@ var stepsCmd = &cobra.Command{
 }
 
 var stepsListCmd = &cobra.Command{
-	Use:   "list <card-id-or-number>",
-	Short: "List steps on a card",
-	Args:  cobra.ExactArgs(1),
+	Use:               "list <card-id-or-number>",
+	Short:             "List steps on a card",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeCardArg,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		client := GetClient()
 		cardID, err := client.ResolveCardID(cmd.Context(), args[0])
@@ -40,9 +41,10 @@ var stepsListCmd = &cobra.Command{
 }
 
 var stepsGetCmd = &cobra.Command{
-	Use:   "get <card-id-or-number> <step-id>",
-	Short: "Get a step",
-	Args:  cobra.ExactArgs(2),
+	Use:               "get <card-id-or-number> <step-id>",
+	Short:             "Get a step",
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: completeCardArg,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		client := GetClient()
 		cardID, err := client.ResolveCardID(cmd.Context(), args[0])
@@ -71,6 +73,7 @@ var stepsCreateCmd = &cobra.Command{
 	Args:  cobra.ExactArgs(1),
 	Example: `  fizz steps create 123 --content="Review code"
   fizz steps create 123 --content="Deploy" --completed=true`,
+	ValidArgsFunction: completeCardArg,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		client := GetClient()
 		cardID, err := client.ResolveCardID(cmd.Context(), args[0])
@@ -107,9 +110,10 @@ var stepsCreateCmd = &cobra.Command{
 }
 
 var stepsUpdateCmd = &cobra.Command{
-	Use:   "update <card-id-or-number> <step-id>",
-	Short: "Update a step",
-	Args:  cobra.ExactArgs(2),
+	Use:               "update <card-id-or-number> <step-id>",
+	Short:             "Update a step",
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: completeCardArg,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		client := GetClient()
 		cardID, err := client.ResolveCardID(cmd.Context(), args[0])
@@ -144,9 +148,10 @@ var stepsUpdateCmd = &cobra.Command{
 }
 
 var stepsDeleteCmd = &cobra.Command{
-	Use:   "delete <card-id-or-number> <step-id>",
-	Short: "Delete a step",
-	Args:  cobra.ExactArgs(2),
+	Use:               "delete <card-id-or-number> <step-id>",
+	Short:             "Delete a step",
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: completeCardArg,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		client := GetClient()
 		cardID, err := client.ResolveCardID(cmd.Context(), args[0])
@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/visionik/fizz/internal/format"
+	"github.com/visionik/fizz/internal/history"
+	"github.com/visionik/libfizz-go/fizzy"
+)
+
+// syncResult describes one saved snapshot
+type syncResult struct {
+	BoardID string `json:"board_id"`
+	Board   string `json:"board"`
+	Cards   int    `json:"cards"`
+	Columns int    `json:"columns"`
+	Path    string `json:"path"`
+}
+
+var syncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Save a snapshot of boards to the local history",
+	Long: `Save today's state of every board, or one board, to the local history used
+by 'fizz report cfd', 'fizz report burndown' and cycle time in 'fizz report'.
+
+Snapshots are kept per board and per day in the history directory of the fizz
+config directory ($FIZZ_HISTORY_DIR overrides it); syncing again on the same
+day replaces that day's snapshot. Run it daily from cron or a systemd timer:
+
+  0 18 * * * fizz sync`,
+	Args: cobra.NoArgs,
+	Example: `  fizz sync
+  fizz sync --board=03fbhiu9dgjo0viyrlya1x03a`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client := GetClient()
+		ctx := cmd.Context()
+
+		store, err := history.DefaultStore()
+		if err != nil {
+			return err
+		}
+
+		var boards []fizzy.Board
+		if boardID, _ := cmd.Flags().GetString("board"); boardID != "" {
+			board, err := client.Boards.Get(ctx, boardID)
+			if err != nil {
+				return fmt.Errorf("failed to get board: %w", err)
+			}
+			boards = append(boards, *board)
+		} else if boards, err = client.Boards.List(ctx); err != nil {
+			return fmt.Errorf("failed to list boards: %w", err)
+		}
+
+		now := time.Now()
+		results := make([]syncResult, 0, len(boards))
+		for _, board := range boards {
+			columns, err := client.Columns.List(ctx, board.ID)
+			if err != nil {
+				return fmt.Errorf("failed to list columns: %w", err)
+			}
+			cards, err := listAllCards(ctx, client, board.ID)
+			if err != nil {
+				return err
+			}
+
+			path, err := store.Save(history.New(board, columns, cards, now))
+			if err != nil {
+				return err
+			}
+			results = append(results, syncResult{
+				BoardID: board.ID,
+				Board:   board.Name,
+				Cards:   len(cards),
+				Columns: len(columns),
+				Path:    path,
+			})
+		}
+
+		if GetFormat() != "table" {
+			formatter, err := format.NewFormatter(GetFormat(), cmd.OutOrStdout())
+			if err != nil {
+				return err
+			}
+			return formatter.Format(results)
+		}
+		for _, r := range results {
+			fmt.Fprintf(cmd.OutOrStdout(), "Saved snapshot of %s: %d cards in %d columns\n", r.Board, r.Cards, r.Columns)
+		}
+		return nil
+	},
+}
+
+func init() {
+	syncCmd.Flags().String("board", "", "Only snapshot this board (default: all boards)")
+	syncCmd.RegisterFlagCompletionFunc("board", completeFlag(boardCandidates))
+
+	rootCmd.AddCommand(syncCmd)
+}
@@ -0,0 +1,299 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	"github.com/visionik/fizz/internal/client"
+	"github.com/visionik/fizz/internal/editor"
+	"github.com/visionik/fizz/internal/format"
+	"github.com/visionik/fizz/internal/git"
+	"github.com/visionik/fizz/internal/kanban"
+	"github.com/visionik/fizz/internal/templates"
+	"github.com/visionik/libfizz-go/fizzy"
+	"gopkg.in/yaml.v3"
+)
+
+// templateSkeleton is the starting point for a template written in an editor
+const templateSkeleton = `# Placeholders like {{version}} are filled from --var version=... when the
+# template is used; vars sets their defaults. {{date}} is today's date.
+description: ""
+board: ""
+title: ""
+body: |
+
+tags: []
+assignees: []
+column: ""
+steps: []
+vars: {}
+`
+
+var templatesCmd = &cobra.Command{
+	Use:   "templates",
+	Short: "Card templates",
+	Long: `Manage card templates for recurring work.
+
+Templates are YAML files in the templates directory of the fizz config
+directory (~/.config/fizz/templates) or in a project's .fizz/templates
+directory, which takes precedence. Each defines a title and body with
+{{var}} placeholders, default tags, assignees, a target column and a step
+checklist. Use one with: fizz cards create --template=<name> --var key=value`,
+}
+
+var templatesListCmd = &cobra.Command{
+	Use:         "list",
+	Short:       "List templates",
+	Args:        cobra.NoArgs,
+	Annotations: map[string]string{noClientAnnotation: "true"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		list, err := templates.List()
+		if err != nil {
+			return err
+		}
+
+		formatter, err := format.NewFormatter(GetFormat(), cmd.OutOrStdout())
+		if err != nil {
+			return err
+		}
+
+		if GetFormat() == "table" {
+			return formatter.Format(format.ToTemplateDisplaySlice(list))
+		}
+		return formatter.Format(list)
+	},
+}
+
+var templatesShowCmd = &cobra.Command{
+	Use:         "show <name>",
+	Short:       "Show a template",
+	Args:        cobra.ExactArgs(1),
+	Annotations: map[string]string{noClientAnnotation: "true"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		t, err := templates.Load(args[0])
+		if err != nil {
+			return err
+		}
+
+		if GetFormat() != "table" {
+			formatter, err := format.NewFormatter(GetFormat(), cmd.OutOrStdout())
+			if err != nil {
+				return err
+			}
+			return formatter.Format(t)
+		}
+
+		data, err := os.ReadFile(t.Path)
+		if err != nil {
+			return fmt.Errorf("failed to read template: %w", err)
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "# %s\n%s", t.Path, data)
+		return nil
+	},
+}
+
+var templatesCreateCmd = &cobra.Command{
+	Use:   "create <name>",
+	Short: "Create a template",
+	Long: `Create a template from flags or, with --edit (the default on a terminal when
+--title is omitted), by writing its YAML in $VISUAL/$EDITOR.
+
+Templates are saved in the user's config directory, or with --project in the
+project's .fizz/templates directory (created at the git repository root).`,
+	Args:        cobra.ExactArgs(1),
+	Annotations: map[string]string{noClientAnnotation: "true"},
+	Example: `  fizz templates create release --title="Release {{version}}" \
+    --step="Tag {{version}}" --step="Publish notes" --tag=release --column=Doing
+  fizz templates create bug --project --edit`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := args[0]
+		if err := templates.ValidateName(name); err != nil {
+			return err
+		}
+
+		t := &templates.Template{}
+		if useEditor(cmd, "title") {
+			text, err := editor.EditText(templateSkeleton, "fizz-template-*.yaml")
+			if err != nil {
+				return err
+			}
+			if err := yaml.Unmarshal([]byte(text), t); err != nil {
+				return fmt.Errorf("failed to parse template: %w", err)
+			}
+		} else {
+			t.Title, _ = cmd.Flags().GetString("title")
+			t.Body, _ = cmd.Flags().GetString("body")
+			t.Description, _ = cmd.Flags().GetString("description")
+			t.Board, _ = cmd.Flags().GetString("board")
+			t.Column, _ = cmd.Flags().GetString("column")
+			t.Tags, _ = cmd.Flags().GetStringSlice("tag")
+			t.Assignees, _ = cmd.Flags().GetStringSlice("assignee")
+			t.Steps, _ = cmd.Flags().GetStringArray("step")
+			pairs, _ := cmd.Flags().GetStringArray("var")
+			vars, err := templates.ParseVars(pairs)
+			if err != nil {
+				return err
+			}
+			if len(vars) > 0 {
+				t.Vars = vars
+			}
+		}
+		t.Name = name
+
+		dir, err := templatesDir(cmd)
+		if err != nil {
+			return err
+		}
+		force, _ := cmd.Flags().GetBool("force")
+		if err := templates.Save(t, dir, force); err != nil {
+			return err
+		}
+
+		fmt.Fprintf(cmd.OutOrStdout(), "Template %s saved to %s\n", name, t.Path)
+		return nil
+	},
+}
+
+var templatesDeleteCmd = &cobra.Command{
+	Use:         "delete <name>",
+	Short:       "Delete a template",
+	Args:        cobra.ExactArgs(1),
+	Annotations: map[string]string{noClientAnnotation: "true"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		t, err := templates.Delete(args[0])
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(cmd.OutOrStdout(), "Template %s deleted (%s)\n", t.Name, t.Path)
+		return nil
+	},
+}
+
+// templatesDir returns where templates create writes: the user's templates
+// directory, or the project's with --project
+func templatesDir(cmd *cobra.Command) (string, error) {
+	if project, _ := cmd.Flags().GetBool("project"); !project {
+		return templates.UserDir()
+	}
+	if dir := templates.ProjectDir(); dir != "" {
+		return dir, nil
+	}
+
+	root, err := git.Repo{}.Run("rev-parse", "--show-toplevel")
+	if err != nil {
+		if root, err = os.Getwd(); err != nil {
+			return "", err
+		}
+	}
+	return filepath.Join(root, templates.ProjectDirName), nil
+}
+
+// loadTemplate loads and renders the template named by --template, if any
+func loadTemplate(cmd *cobra.Command) (*templates.Template, error) {
+	name, _ := cmd.Flags().GetString("template")
+	if name == "" {
+		return nil, nil
+	}
+
+	t, err := templates.Load(name)
+	if err != nil {
+		return nil, err
+	}
+	pairs, _ := cmd.Flags().GetStringArray("var")
+	vars, err := templates.ParseVars(pairs)
+	if err != nil {
+		return nil, err
+	}
+	return t.Render(vars)
+}
+
+// createFromTemplate creates a card from a rendered template, with its tags,
+// assignees, steps and column; cards create passes one holding its flags
+// when no template is named. If a later step fails the card is returned
+// with the error.
+func createFromTemplate(ctx context.Context, c *client.Client, t *templates.Template) (*fizzy.Card, error) {
+	opts := &fizzy.CardCreateOptions{BoardID: t.Board, Title: t.Title}
+	if t.Body != "" {
+		opts.Body = &t.Body
+	}
+	card, err := c.Cards.Create(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create card: %w", err)
+	}
+	if len(t.Tags) == 0 && len(t.Assignees) == 0 && len(t.Steps) == 0 && t.Column == "" {
+		return card, nil
+	}
+
+	number := strconv.Itoa(card.Number)
+	if _, err := syncTags(ctx, c, number, nil, t.Tags); err != nil {
+		return card, err
+	}
+	if err := applyTemplate(ctx, c, card, t); err != nil {
+		return card, err
+	}
+	updated, err := c.Cards.Get(ctx, number)
+	if err != nil {
+		return card, fmt.Errorf("failed to get card: %w", err)
+	}
+	return updated, nil
+}
+
+// applyTemplate adds a rendered template's assignees, steps and column to a
+// new card
+func applyTemplate(ctx context.Context, c *client.Client, card *fizzy.Card, t *templates.Template) error {
+	number := strconv.Itoa(card.Number)
+	for _, assignee := range t.Assignees {
+		userID, err := c.ResolveUserID(ctx, assignee)
+		if err != nil {
+			return err
+		}
+		if err := c.Cards.Assign(ctx, number, userID); err != nil {
+			return fmt.Errorf("failed to assign card: %w", err)
+		}
+	}
+
+	for _, content := range t.Steps {
+		if _, err := c.Steps.Create(ctx, number, &fizzy.StepCreateOptions{Content: content}); err != nil {
+			return fmt.Errorf("failed to create step: %w", err)
+		}
+	}
+
+	if t.Column != "" {
+		col, err := c.ResolveColumn(ctx, kanban.BoardID(card), t.Column)
+		if err != nil {
+			return err
+		}
+		if err := c.Cards.MoveToColumn(ctx, number, col.ID); err != nil {
+			return fmt.Errorf("failed to move card: %w", err)
+		}
+	}
+	return nil
+}
+
+func init() {
+	templatesCreateCmd.Flags().String("title", "", "Title pattern (required unless editing)")
+	templatesCreateCmd.Flags().String("body", "", "Body pattern")
+	templatesCreateCmd.Flags().String("description", "", "What the template is for")
+	templatesCreateCmd.Flags().String("board", "", "Default board ID")
+	templatesCreateCmd.Flags().String("column", "", "Column to move new cards to, by name or ID")
+	templatesCreateCmd.Flags().StringSlice("tag", nil, "Default tag (repeatable)")
+	templatesCreateCmd.Flags().StringSlice("assignee", nil, "Default assignee user ID or 'me' (repeatable)")
+	templatesCreateCmd.Flags().StringArray("step", nil, "Checklist step (repeatable)")
+	templatesCreateCmd.Flags().StringArray("var", nil, "Default variable value as key=value (repeatable)")
+	templatesCreateCmd.Flags().Bool("project", false, "Save in the project's .fizz/templates directory")
+	templatesCreateCmd.Flags().Bool("force", false, "Replace an existing template")
+	templatesCreateCmd.Flags().Bool("edit", false, "Write the template in $VISUAL/$EDITOR (default on a terminal when --title is omitted)")
+	addFilterCompletion(templatesCreateCmd)
+	templatesCreateCmd.RegisterFlagCompletionFunc("column", completeFlag(columnCandidates))
+
+	templatesCmd.AddCommand(templatesListCmd)
+	templatesCmd.AddCommand(templatesShowCmd)
+	templatesCmd.AddCommand(templatesCreateCmd)
+	templatesCmd.AddCommand(templatesDeleteCmd)
+	rootCmd.AddCommand(templatesCmd)
+}
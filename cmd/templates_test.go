@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// isolateConfig points the config directory at a temporary one and runs from
+// an empty working directory
+func isolateConfig(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("FIZZ_CONFIG_DIR", filepath.Join(dir, "config"))
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "work"), 0o755))
+	t.Chdir(filepath.Join(dir, "work"))
+	return dir
+}
+
+func TestTemplatesCRUD(t *testing.T) {
+	f := newFizzTest(t)
+	dir := isolateConfig(t)
+	// Template commands need no credentials
+	t.Setenv("FIZZY_TOKEN", "")
+
+	assert.Equal(t, "No results found\n", f.mustRun("templates", "list"))
+
+	path := filepath.Join(dir, "config", "templates", "release.yaml")
+	out := f.mustRun("templates", "create", "release",
+		"--title=Release {{version}}", "--description=Ship a version",
+		"--tag=release", "--step=Tag {{version}}", "--step=Publish notes, announce",
+		"--column=Doing", "--var=channel=stable")
+	assert.Equal(t, "Template release saved to "+path+"\n", out)
+
+	_, err := f.run("templates", "create", "release", "--title=Again")
+	assert.ErrorContains(t, err, "already exists")
+
+	out = f.mustRun("templates", "list")
+	assert.Contains(t, out, "release")
+	assert.Contains(t, out, "version")
+
+	out = f.mustRun("templates", "show", "release")
+	assert.Equal(t, "# "+path+`
+description: Ship a version
+title: Release {{version}}
+tags:
+  - release
+column: Doing
+steps:
+  - Tag {{version}}
+  - Publish notes, announce
+vars:
+  channel: stable
+`, out)
+
+	assert.Equal(t, "Template release deleted ("+path+")\n", f.mustRun("templates", "delete", "release"))
+	_, err = f.run("templates", "show", "release")
+	assert.EqualError(t, err, "template not found: release")
+}
+
+func TestTemplatesProjectOverridesUser(t *testing.T) {
+	f := newFizzTest(t)
+	dir := isolateConfig(t)
+
+	f.mustRun("templates", "create", "bug", "--title=User bug")
+	f.mustRun("templates", "create", "bug", "--title=Project bug", "--project")
+
+	out := f.mustRun("templates", "show", "bug", "--format=json")
+	assert.Contains(t, out, `"title": "Project bug"`)
+	assert.Contains(t, out, `"source": "project"`)
+	assert.FileExists(t, filepath.Join(dir, "work", ".fizz", "templates", "bug.yaml"))
+}
+
+func TestCardsCreateFromTemplate(t *testing.T) {
+	f := newFizzTest(t)
+	fx := f.seed()
+	isolateConfig(t)
+
+	f.mustRun("templates", "create", "release",
+		"--board="+fx.BoardID,
+		"--title=Release {{version}}", "--body=Ship {{version}} to {{channel}}.",
+		"--tag=release", "--tag=infra", "--assignee=me", "--column=Done",
+		"--step=Tag v{{version}}", "--step=Publish notes", "--var=channel=stable")
+
+	_, err := f.run("cards", "create", "--template=release")
+	assert.EqualError(t, err, "template release needs --var for: version")
+
+	out := f.mustRun("cards", "create", "--template=release", "--var", "version=1.4", "--format=json")
+	assert.Contains(t, out, `"title": "Release 1.4"`)
+	assert.Contains(t, out, `"description": "Ship 1.4 to stable."`)
+	assert.Contains(t, out, `"name": "release"`)
+	assert.Contains(t, out, `"name": "infra"`)
+	assert.Contains(t, out, `"name": "Fizz Tester"`)
+
+	steps := f.mustRun("steps", "list", "3", "--format=json")
+	assert.Contains(t, steps, `"content": "Tag v1.4"`)
+	assert.Contains(t, steps, `"content": "Publish notes"`)
+
+	show := f.mustRun("cards", "show", "3", "--no-pager")
+	assert.Contains(t, show, "Column:    Done")
+}
@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/visionik/fizz/internal/client"
+	"github.com/visionik/fizz/internal/config"
+	"github.com/visionik/fizz/internal/kanban"
+	"github.com/visionik/fizz/internal/wip"
+	"github.com/visionik/libfizz-go/fizzy"
+)
+
+// checkMoveWIP checks moving a card to a column against the WIP limits.
+// Without limits in the config it makes no API calls.
+func checkMoveWIP(cmd *cobra.Command, c *client.Client, card *fizzy.Card, col *fizzy.Column) error {
+	settings, err := config.LoadSettings()
+	if err != nil {
+		return err
+	}
+	if len(settings.WIP.Limits) == 0 {
+		return nil
+	}
+
+	boardID := kanban.BoardID(card)
+	limit := wip.Limit(settings.WIP, boardID, *col)
+	if limit == nil || (card.ColumnID != nil && *card.ColumnID == col.ID) {
+		return nil
+	}
+
+	count, err := countColumn(cmd.Context(), c, boardID, col.ID, card.ID)
+	if err != nil {
+		return err
+	}
+	return enforceWIP(cmd, settings.WIP, wip.CheckMove(*limit, col.Name, count, *card))
+}
+
+// checkAssignWIP checks assigning a card to a user against the per-person
+// WIP limit of the card's column
+func checkAssignWIP(cmd *cobra.Command, c *client.Client, cardID, userID string) error {
+	settings, err := config.LoadSettings()
+	if err != nil {
+		return err
+	}
+	if len(settings.WIP.Limits) == 0 {
+		return nil
+	}
+
+	ctx := cmd.Context()
+	card, err := c.Cards.Get(ctx, cardID)
+	if err != nil {
+		return fmt.Errorf("failed to get card: %w", err)
+	}
+	if card.ColumnID == nil || *card.ColumnID == "" || card.Closed || card.Status == "not_now" {
+		return nil
+	}
+	// Assigning toggles, so this removes an existing assignee
+	for _, user := range card.Assignees {
+		if user.ID == userID {
+			return nil
+		}
+	}
+
+	boardID := kanban.BoardID(card)
+	col, err := c.ResolveColumn(ctx, boardID, *card.ColumnID)
+	if err != nil {
+		return err
+	}
+	limit := wip.Limit(settings.WIP, boardID, *col)
+	if limit == nil {
+		return nil
+	}
+	count, err := countColumn(ctx, c, boardID, col.ID, card.ID)
+	if err != nil {
+		return err
+	}
+	return enforceWIP(cmd, settings.WIP, wip.CheckAssign(*limit, col.Name, count, userID))
+}
+
+// countColumn counts the open cards in a column, leaving out skip
+func countColumn(ctx context.Context, c *client.Client, boardID, columnID, skip string) (wip.Count, error) {
+	cards, err := c.ListAllCards(ctx, &fizzy.CardListOptions{BoardID: boardID, ColumnID: columnID})
+	if err != nil {
+		return wip.Count{}, fmt.Errorf("failed to list cards: %w", err)
+	}
+	return wip.CountColumn(cards, columnID, skip), nil
+}
+
+// enforceWIP refuses a change over a limit when limits are enforced and
+// --force is not set, and otherwise warns about it
+func enforceWIP(cmd *cobra.Command, settings config.WIPSettings, violations []wip.Violation) error {
+	if len(violations) == 0 {
+		return nil
+	}
+	force, _ := cmd.Flags().GetBool("force")
+	if settings.Enforce && !force {
+		messages := make([]string, len(violations))
+		for i, v := range violations {
+			messages[i] = v.String()
+		}
+		return fmt.Errorf("%s; use --force to go over the limit", strings.Join(messages, "; "))
+	}
+	for _, v := range violations {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Warning: %s\n", v)
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/visionik/libfizz-go/fizzy"
+)
+
+// writeConfig writes the user config file of an isolated config directory
+func writeConfig(t *testing.T, dir, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "config"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config", "config.yaml"), []byte(content), 0o644))
+}
+
+func TestWIPLimits(t *testing.T) {
+	f := newFizzTest(t)
+	fx := f.seed()
+	dir := isolateConfig(t)
+
+	sam := f.srv.AddUser("Sam Park")
+	f.srv.UpdateCard("1", func(c *fizzy.Card) { c.Assignees = append(c.Assignees, sam) })
+	third := f.srv.AddCard(fx.BoardID, "Rotate certificates", "")
+	f.srv.UpdateCard(strconv.Itoa(third.Number), func(c *fizzy.Card) { c.Assignees = append(c.Assignees, sam) })
+
+	writeConfig(t, dir, `wip:
+  limits:
+    - column: Doing
+      limit: 1
+      per_person: 1
+`)
+
+	// Without enforce, going over the limit only warns
+	out, errOut, err := f.runStderr("cards", "move", "2", "--column=doing")
+	require.NoError(t, err)
+	assert.Contains(t, out, "moved to column "+fx.ColumnID)
+	assert.Equal(t, "Warning: Doing would have 2 cards, over its WIP limit of 1\n", errOut)
+
+	// Card 3's assignee already has a card in Doing
+	writeConfig(t, dir, `wip:
+  enforce: true
+  limits:
+    - column: Doing
+      limit: 1
+    - board: `+fx.BoardID+`
+      column: Doing
+      limit: 5
+      per_person: 1
+`)
+	_, err = f.run("cards", "move", "3", "--column=Doing")
+	assert.EqualError(t, err, "Sam Park would have 2 cards in Doing, over the per-person WIP limit of 1; use --force to go over the limit")
+
+	_, err = f.run("cards", "assign", "2", sam.ID)
+	assert.EqualError(t, err, "Sam Park would have 2 cards in Doing, over the per-person WIP limit of 1; use --force to go over the limit")
+	out = f.mustRun("cards", "assign", "2", sam.ID, "--force")
+	assert.Contains(t, out, "assigned to "+sam.ID)
+
+	// Unassigning toggles the assignee off and is never refused
+	f.mustRun("cards", "assign", "2", sam.ID)
+
+	// Moving within a column, or to a column without a limit, is fine
+	f.mustRun("cards", "move", "1", "--column=Doing")
+	f.mustRun("cards", "move", "3", "--column=Backlog")
+
+	out = f.mustRun("columns", "list", fx.BoardID)
+	assert.Contains(t, out, "PER PERSON")
+	assert.Contains(t, out, "2/5")
+	assert.Contains(t, out, "Jordan Lee 1/1")
+
+	out = f.mustRun("columns", "list", fx.BoardID, "--format=json")
+	assert.Contains(t, out, `"wip_limit": 5`)
+	assert.Contains(t, out, `"cards": 2`)
+}
@@ -46,7 +46,7 @@ All commands follow the pattern: ` + "`" + `fizz <noun> <verb> [flags]` + "`" +
 
 ### Global Flags
 
-- ` + "`" + `--format FORMAT` + "`" + ` - Output format: table (default), json, yaml
+- ` + "`" + `--format FORMAT` + "`" + ` - Output format: table (default), json, jsonl, yaml, csv
 - ` + "`" + `--debug` + "`" + ` - Enable debug output
 - ` + "`" + `--ai-help` + "`" + ` - Show this AI-oriented help
 
@@ -156,6 +156,9 @@ fizz cards list --format=json
 # List limited cards
 fizz cards list --limit=10 --format=json
 
+# Stream a long list, one JSON object per line
+fizz cards list --format=jsonl
+
 # Get specific card (by ID or number)
 fizz cards get CARD_ID --format=json
 fizz cards get 123 --format=json
@@ -0,0 +1,114 @@
+// Package alias expands user-defined aliases from the config file: short
+// names for a fizz command line, or macros that run several in order, with
+// $1, $2… replaced by the alias's arguments.
+package alias
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/visionik/fizz/internal/shell"
+)
+
+// MaxDepth bounds aliases that run other aliases, so one that runs itself
+// fails instead of looping
+const MaxDepth = 8
+
+// Expand returns the command lines an alias runs for args. $N is the Nth
+// argument, ${N} the same inside a longer word, $@ all of them (as separate
+// words when it stands alone) and $$ a dollar sign. Arguments left over
+// are added to the end of a single command and are an error in a macro.
+func Expand(name string, steps []string, args []string) ([][]string, error) {
+	x := &expander{args: args}
+	var commands [][]string
+	for _, step := range steps {
+		words, err := shell.Split(step)
+		if err != nil {
+			return nil, fmt.Errorf("alias %s: %w", name, err)
+		}
+		var command []string
+		for _, word := range words {
+			if word == "$@" {
+				command = append(command, args...)
+				x.all = true
+				continue
+			}
+			command = append(command, x.substitute(word))
+		}
+		if len(command) == 0 {
+			return nil, fmt.Errorf("alias %s has an empty command", name)
+		}
+		commands = append(commands, command)
+	}
+
+	if x.highest > len(args) {
+		return nil, fmt.Errorf("alias %s needs %d argument(s), got %d", name, x.highest, len(args))
+	}
+	if extra := args[x.highest:]; !x.all && len(extra) > 0 {
+		if len(commands) > 1 {
+			return nil, fmt.Errorf("alias %s takes %d argument(s), got %d", name, x.highest, len(args))
+		}
+		commands[0] = append(commands[0], extra...)
+	}
+	return commands, nil
+}
+
+// expander substitutes arguments, recording which were used
+type expander struct {
+	args []string
+	// highest is the highest $N seen, and all whether $@ was
+	highest int
+	all     bool
+}
+
+func (x *expander) substitute(word string) string {
+	var b strings.Builder
+	for i := 0; i < len(word); i++ {
+		if word[i] != '$' || i == len(word)-1 {
+			b.WriteByte(word[i])
+			continue
+		}
+		rest := word[i+1:]
+		switch {
+		case rest[0] == '$':
+			b.WriteByte('$')
+			i++
+		case rest[0] == '@':
+			b.WriteString(strings.Join(x.args, " "))
+			x.all = true
+			i++
+		case rest[0] == '{':
+			end := strings.IndexByte(rest, '}')
+			n, err := strconv.Atoi(rest[1:max(end, 1)])
+			if end < 0 || err != nil || n < 1 {
+				b.WriteByte('$')
+				continue
+			}
+			b.WriteString(x.arg(n))
+			i += end + 1
+		case rest[0] >= '1' && rest[0] <= '9':
+			b.WriteString(x.arg(int(rest[0] - '0')))
+			i++
+		default:
+			b.WriteByte('$')
+		}
+	}
+	return b.String()
+}
+
+func (x *expander) arg(n int) string {
+	x.highest = max(x.highest, n)
+	if n > len(x.args) {
+		return ""
+	}
+	return x.args[n-1]
+}
+
+// Describe summarizes an alias for help output
+func Describe(steps []string) string {
+	if len(steps) == 1 {
+		return "Alias for: " + steps[0]
+	}
+	return "Macro: " + strings.Join(steps, "; ")
+}
@@ -0,0 +1,43 @@
+package alias
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpand(t *testing.T) {
+	ship := []string{
+		"cards move $1 --column=Done",
+		"cards close $1",
+		`comments create $1 --body="Shipped #$1 for $$5"`,
+	}
+	commands, err := Expand("ship", ship, []string{"42"})
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"cards", "move", "42", "--column=Done"},
+		{"cards", "close", "42"},
+		{"comments", "create", "42", "--body=Shipped #42 for $5"},
+	}, commands)
+
+	_, err = Expand("ship", ship, nil)
+	assert.EqualError(t, err, "alias ship needs 1 argument(s), got 0")
+	_, err = Expand("ship", ship, []string{"1", "2"})
+	assert.EqualError(t, err, "alias ship takes 1 argument(s), got 2")
+
+	// A single command gets leftover arguments
+	commands, err = Expand("mine", []string{"cards list --status=published"}, []string{"--format=json"})
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"cards", "list", "--status=published", "--format=json"}}, commands)
+
+	commands, err = Expand("note", []string{"comments create ${1} --body='$@'", "steps list $@"}, []string{"7", "a b"})
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"comments", "create", "7", "--body=7 a b"},
+		{"steps", "list", "7", "a b"},
+	}, commands)
+
+	_, err = Expand("bad", []string{`cards list "oops`}, nil)
+	assert.EqualError(t, err, `alias bad: unterminated " quote`)
+}
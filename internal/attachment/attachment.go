@@ -0,0 +1,371 @@
+// Package attachment finds the files embedded in Fizzy rich text and
+// downloads them, resuming partial downloads and verifying checksums.
+package attachment
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// PartSuffix marks a download in progress, which the next attempt resumes
+const PartSuffix = ".part"
+
+// Attachment is a file embedded in a card description or comment
+type Attachment struct {
+	Name        string `json:"name"`
+	Size        int64  `json:"size,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+	URL         string `json:"url"`
+	// Source is where the file is embedded: "description" or "comment <id>"
+	Source string `json:"source"`
+}
+
+// Parse returns the files embedded in a rich text body, as Action Text's
+// <action-text-attachment> elements or Trix's <figure data-trix-attachment>.
+// Mentions and other attachments without a file are left out.
+func Parse(body, source string) []Attachment {
+	doc, err := html.Parse(strings.NewReader(body))
+	if err != nil {
+		return nil
+	}
+
+	var found []Attachment
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			if a, ok := fromNode(n); ok {
+				a.Source = source
+				found = append(found, a)
+				return
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(doc)
+	return found
+}
+
+func fromNode(n *html.Node) (Attachment, bool) {
+	var a Attachment
+	switch {
+	case n.Data == "action-text-attachment":
+		a = Attachment{
+			Name:        firstNonEmpty(attr(n, "filename"), attr(n, "caption")),
+			ContentType: attr(n, "content-type"),
+			URL:         firstNonEmpty(attr(n, "url"), attr(n, "href")),
+		}
+		a.Size, _ = strconv.ParseInt(attr(n, "filesize"), 10, 64)
+	case n.Data == "figure" && attr(n, "data-trix-attachment") != "":
+		var trix struct {
+			ContentType string `json:"contentType"`
+			Filename    string `json:"filename"`
+			Filesize    int64  `json:"filesize"`
+			URL         string `json:"url"`
+			Href        string `json:"href"`
+		}
+		if json.Unmarshal([]byte(attr(n, "data-trix-attachment")), &trix) != nil {
+			return Attachment{}, false
+		}
+		a = Attachment{Name: trix.Filename, Size: trix.Filesize, ContentType: trix.ContentType, URL: firstNonEmpty(trix.URL, trix.Href)}
+	default:
+		return Attachment{}, false
+	}
+
+	if a.URL == "" || strings.HasPrefix(a.ContentType, "application/vnd.actiontext") {
+		return Attachment{}, false
+	}
+	if a.Name == "" {
+		a.Name = nameFromURL(a.URL)
+	}
+	return a, true
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func nameFromURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "attachment"
+	}
+	name := u.Path[strings.LastIndex(u.Path, "/")+1:]
+	if name == "" {
+		return "attachment"
+	}
+	return name
+}
+
+// SafeName turns an attachment name into a file name that stays inside the
+// download directory
+func SafeName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if r == '/' || r == '\\' || r < ' ' {
+			return '_'
+		}
+		return r
+	}, name)
+	if name == "" || name == "." || name == ".." {
+		return "attachment"
+	}
+	return name
+}
+
+// Download outcomes
+const (
+	StatusDownloaded = "downloaded"
+	StatusResumed    = "resumed"
+	StatusSkipped    = "skipped"
+)
+
+// Checksum outcomes
+const (
+	ChecksumVerified   = "verified"
+	ChecksumUnverified = "unverified"
+)
+
+// Result reports one download
+type Result struct {
+	Name     string `json:"name"`
+	Path     string `json:"path"`
+	Size     int64  `json:"size"`
+	Status   string `json:"status"`
+	Checksum string `json:"checksum"`
+}
+
+// Downloader fetches attachments
+type Downloader struct {
+	Client *http.Client
+	// Token authorizes requests to BaseURL's host. Other hosts, such as the
+	// storage a download redirects to, never see it.
+	Token   string
+	BaseURL string
+}
+
+// Download saves a to path. It resumes a partial download left next to path
+// by an earlier attempt, and leaves alone a file already at path with the
+// expected size. progress, if set, is called with the bytes saved so far.
+func (d *Downloader) Download(ctx context.Context, a Attachment, path string, progress func(int64)) (Result, error) {
+	result := Result{Name: a.Name, Path: path, Size: a.Size}
+	if info, err := os.Stat(path); err == nil && a.Size > 0 && info.Size() == a.Size {
+		result.Status, result.Checksum = StatusSkipped, ChecksumUnverified
+		return result, nil
+	}
+
+	result, err := d.fetch(ctx, a, path, progress)
+	// Resuming a file that changed on the server splices two versions, so
+	// start again from scratch
+	if errors.Is(err, errChecksum) && result.Status == StatusResumed {
+		result, err = d.fetch(ctx, a, path, progress)
+	}
+	return result, err
+}
+
+var errChecksum = errors.New("checksum mismatch")
+
+func (d *Downloader) fetch(ctx context.Context, a Attachment, path string, progress func(int64)) (Result, error) {
+	result := Result{Name: a.Name, Path: path, Size: a.Size, Status: StatusDownloaded}
+	target, err := d.resolve(a.URL)
+	if err != nil {
+		return result, err
+	}
+
+	part := path + PartSuffix
+	var offset int64
+	if info, err := os.Stat(part); err == nil {
+		offset = info.Size()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target.String(), nil)
+	if err != nil {
+		return result, fmt.Errorf("failed to download %s: %w", a.Name, err)
+	}
+	if base, err := url.Parse(d.BaseURL); err == nil && d.Token != "" && base.Host == target.Host {
+		req.Header.Set("Authorization", "Bearer "+d.Token)
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+
+	client := d.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return result, fmt.Errorf("failed to download %s: %w", a.Name, err)
+	}
+	defer resp.Body.Close()
+
+	flags := os.O_CREATE | os.O_WRONLY
+	switch resp.StatusCode {
+	case http.StatusOK:
+		flags |= os.O_TRUNC
+		offset = 0
+	case http.StatusPartialContent:
+		flags |= os.O_APPEND
+		result.Status = StatusResumed
+	case http.StatusRequestedRangeNotSatisfiable:
+		// The part file already holds everything
+		flags |= os.O_APPEND
+		result.Status = StatusResumed
+		resp.Body = io.NopCloser(bytes.NewReader(nil))
+	default:
+		return result, fmt.Errorf("failed to download %s: %s", a.Name, resp.Status)
+	}
+
+	f, err := os.OpenFile(part, flags, 0o644)
+	if err != nil {
+		return result, fmt.Errorf("failed to save %s: %w", a.Name, err)
+	}
+	var body io.Reader = resp.Body
+	if progress != nil {
+		body = &progressReader{r: body, done: offset, report: progress}
+	}
+	_, err = io.Copy(f, body)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return result, fmt.Errorf("failed to download %s: %w", a.Name, err)
+	}
+
+	size, sum, err := digest(part)
+	if err != nil {
+		return result, err
+	}
+	result.Size = size
+	if a.Size > 0 && size != a.Size {
+		os.Remove(part)
+		return result, fmt.Errorf("failed to download %s: got %d bytes, expected %d", a.Name, size, a.Size)
+	}
+	result.Checksum = ChecksumUnverified
+	if want, ok := expectedMD5(resp); ok {
+		if !bytes.Equal(sum, want) {
+			os.Remove(part)
+			return result, fmt.Errorf("failed to download %s: %w", a.Name, errChecksum)
+		}
+		result.Checksum = ChecksumVerified
+	}
+
+	if err := os.Rename(part, path); err != nil {
+		return result, fmt.Errorf("failed to save %s: %w", a.Name, err)
+	}
+	return result, nil
+}
+
+// resolve makes a URL relative to the Fizzy host absolute
+func (d *Downloader) resolve(raw string) (*url.URL, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid attachment URL %q: %w", raw, err)
+	}
+	if u.IsAbs() {
+		return u, nil
+	}
+	base, err := url.Parse(d.BaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base URL %q: %w", d.BaseURL, err)
+	}
+	return base.ResolveReference(u), nil
+}
+
+func digest(path string) (int64, []byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to verify %s: %w", path, err)
+	}
+	defer f.Close()
+	h := md5.New()
+	n, err := io.Copy(h, f)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to verify %s: %w", path, err)
+	}
+	return n, h.Sum(nil), nil
+}
+
+var hexMD5 = regexp.MustCompile(`^"?([0-9a-fA-F]{32})"?$`)
+
+// storageHosts are object stores whose ETag for an object uploaded in one
+// part is its hex MD5, matched by domain suffix. Elsewhere a 32-digit hex
+// ETag may be any hash of anything, so it is not trusted.
+var storageHosts = []string{
+	"amazonaws.com",
+	"storage.googleapis.com",
+	"r2.cloudflarestorage.com",
+	"digitaloceanspaces.com",
+	"backblazeb2.com",
+}
+
+// objectStorage reports whether host is one of storageHosts
+func objectStorage(host string) bool {
+	for _, suffix := range storageHosts {
+		if host == suffix || strings.HasSuffix(host, "."+suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// expectedMD5 returns the MD5 of the whole file a response declares, from
+// Content-MD5 when the response is the whole file, or from the ETag of known
+// object storage, the hex MD5 of objects uploaded in one part
+func expectedMD5(resp *http.Response) ([]byte, bool) {
+	if v := resp.Header.Get("Content-MD5"); v != "" && resp.StatusCode == http.StatusOK {
+		if sum, err := base64.StdEncoding.DecodeString(v); err == nil && len(sum) == md5.Size {
+			return sum, true
+		}
+	}
+	if resp.Request == nil || !objectStorage(resp.Request.URL.Hostname()) {
+		return nil, false
+	}
+	if m := hexMD5.FindStringSubmatch(resp.Header.Get("ETag")); m != nil {
+		sum, _ := hex.DecodeString(m[1])
+		return sum, true
+	}
+	return nil, false
+}
+
+type progressReader struct {
+	r      io.Reader
+	done   int64
+	report func(int64)
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	p.done += int64(n)
+	p.report(p.done)
+	return n, err
+}
@@ -0,0 +1,156 @@
+package attachment
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	body := `<p>See <action-text-attachment sgid="u1" content-type="application/vnd.actiontext.mention"></action-text-attachment></p>
+<action-text-attachment sgid="b1" content-type="image/png" url="https://app.fizzy.do/rails/active_storage/blobs/b1/shot.png" filename="shot.png" filesize="2048"></action-text-attachment>
+<figure data-trix-attachment='{"contentType":"application/pdf","filename":"spec.pdf","filesize":10,"url":"/rails/active_storage/blobs/b2/spec.pdf"}'><img src="x"></figure>
+<action-text-attachment sgid="b3" url="https://files.example.com/b3/trace.log"></action-text-attachment>`
+
+	assert.Equal(t, []Attachment{
+		{Name: "shot.png", Size: 2048, ContentType: "image/png", URL: "https://app.fizzy.do/rails/active_storage/blobs/b1/shot.png", Source: "description"},
+		{Name: "spec.pdf", Size: 10, ContentType: "application/pdf", URL: "/rails/active_storage/blobs/b2/spec.pdf", Source: "description"},
+		{Name: "trace.log", URL: "https://files.example.com/b3/trace.log", Source: "description"},
+	}, Parse(body, "description"))
+	assert.Empty(t, Parse("<p>No files</p>", "comment 1"))
+}
+
+func TestSafeName(t *testing.T) {
+	assert.Equal(t, "shot.png", SafeName("shot.png"))
+	assert.Equal(t, ".._.._etc_passwd", SafeName("../../etc/passwd"))
+	assert.Equal(t, "attachment", SafeName(".."))
+}
+
+// fileServer serves data with an S3-style ETag, recording the Range and
+// Authorization headers it is sent. With contentMD5 set, whole-file
+// responses carry the MD5 of what is served.
+type fileServer struct {
+	*httptest.Server
+	etag       string
+	contentMD5 bool
+	ranges     []string
+	tokens     []string
+	current    []byte
+}
+
+func newFileServer(t *testing.T, data []byte) *fileServer {
+	s := &fileServer{etag: fmt.Sprintf(`"%x"`, md5.Sum(data)), current: data}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.ranges = append(s.ranges, r.Header.Get("Range"))
+		s.tokens = append(s.tokens, r.Header.Get("Authorization"))
+		w.Header().Set("ETag", s.etag)
+		if s.contentMD5 && r.Header.Get("Range") == "" {
+			sum := md5.Sum(s.current)
+			w.Header().Set("Content-MD5", base64.StdEncoding.EncodeToString(sum[:]))
+		}
+		http.ServeContent(w, r, "file", time.Time{}, bytes.NewReader(s.current))
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// trustETags treats the server's host as object storage whose ETags are
+// MD5s
+func (s *fileServer) trustETags(t *testing.T) {
+	old := storageHosts
+	u, err := url.Parse(s.URL)
+	require.NoError(t, err)
+	storageHosts = append([]string{u.Hostname()}, old...)
+	t.Cleanup(func() { storageHosts = old })
+}
+
+func TestDownload(t *testing.T) {
+	data := []byte(strings.Repeat("fizz attachment ", 1000))
+	srv := newFileServer(t, data)
+	srv.trustETags(t)
+	d := &Downloader{Token: "secret", BaseURL: srv.URL}
+	a := Attachment{Name: "log.txt", Size: int64(len(data)), URL: "/blobs/log.txt"}
+	path := filepath.Join(t.TempDir(), "log.txt")
+
+	// Resume from a part file left by an interrupted run
+	require.NoError(t, os.WriteFile(path+PartSuffix, data[:100], 0o644))
+	var last int64
+	result, err := d.Download(context.Background(), a, path, func(n int64) { last = n })
+	require.NoError(t, err)
+	assert.Equal(t, Result{Name: "log.txt", Path: path, Size: int64(len(data)), Status: StatusResumed, Checksum: ChecksumVerified}, result)
+	assert.Equal(t, []string{"bytes=100-"}, srv.ranges)
+	assert.Equal(t, []string{"Bearer secret"}, srv.tokens)
+	assert.Equal(t, int64(len(data)), last)
+	got, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, data, got)
+	assert.NoFileExists(t, path+PartSuffix)
+
+	// A finished file is left alone
+	result, err = d.Download(context.Background(), a, path, nil)
+	require.NoError(t, err)
+	assert.Equal(t, StatusSkipped, result.Status)
+	assert.Len(t, srv.ranges, 1)
+}
+
+func TestDownloadChecksum(t *testing.T) {
+	data := []byte("original contents")
+	srv := newFileServer(t, data)
+	srv.trustETags(t)
+	d := &Downloader{BaseURL: "https://app.fizzy.do"}
+	a := Attachment{Name: "notes.txt", URL: srv.URL + "/notes.txt"}
+	path := filepath.Join(t.TempDir(), "notes.txt")
+
+	// A stale part file from another version fails verification, and the
+	// download starts again
+	require.NoError(t, os.WriteFile(path+PartSuffix, []byte("OLD"), 0o644))
+	result, err := d.Download(context.Background(), a, path, nil)
+	require.NoError(t, err)
+	assert.Equal(t, StatusDownloaded, result.Status)
+	assert.Equal(t, ChecksumVerified, result.Checksum)
+	assert.Equal(t, []string{"bytes=3-", ""}, srv.ranges)
+	// Other hosts never see the token
+	assert.Equal(t, []string{"", ""}, srv.tokens)
+
+	srv.current = []byte("corrupted contents")
+	_, err = d.Download(context.Background(), a, filepath.Join(t.TempDir(), "notes.txt"), nil)
+	assert.ErrorContains(t, err, "failed to download notes.txt: checksum mismatch")
+}
+
+func TestDownloadUntrustedETag(t *testing.T) {
+	srv := newFileServer(t, []byte("original contents"))
+	d := &Downloader{BaseURL: "https://app.fizzy.do"}
+	a := Attachment{Name: "notes.txt", URL: srv.URL + "/notes.txt"}
+
+	// An MD5-like ETag from a host that is not known object storage could
+	// be anything, so the download is kept but not verified
+	srv.etag = fmt.Sprintf(`"%x"`, md5.Sum([]byte("something else")))
+	result, err := d.Download(context.Background(), a, filepath.Join(t.TempDir(), "notes.txt"), nil)
+	require.NoError(t, err)
+	assert.Equal(t, StatusDownloaded, result.Status)
+	assert.Equal(t, ChecksumUnverified, result.Checksum)
+
+	// Content-MD5 is checked wherever it comes from
+	srv.contentMD5 = true
+	result, err = d.Download(context.Background(), a, filepath.Join(t.TempDir(), "notes.txt"), nil)
+	require.NoError(t, err)
+	assert.Equal(t, ChecksumVerified, result.Checksum)
+
+	assert.True(t, objectStorage("my-bucket.s3.eu-west-1.amazonaws.com"))
+	assert.True(t, objectStorage("storage.googleapis.com"))
+	assert.False(t, objectStorage("notamazonaws.com"))
+	assert.False(t, objectStorage("app.fizzy.do"))
+}
@@ -0,0 +1,86 @@
+// Package browser opens URLs in the user's web browser.
+package browser
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// ErrNoBrowser is returned when no way to open a browser was found
+var ErrNoBrowser = errors.New("no browser found; set $BROWSER")
+
+// Open launches url in $BROWSER or, when that is unset, the platform's
+// default handler (xdg-open, open or the Windows URL handler)
+func Open(url string) error {
+	if browser := os.Getenv("BROWSER"); browser != "" {
+		return openWith(browser, url)
+	}
+
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", url)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+	default:
+		opener := ""
+		for _, name := range []string{"xdg-open", "wslview", "sensible-browser"} {
+			if _, err := exec.LookPath(name); err == nil {
+				opener = name
+				break
+			}
+		}
+		if opener == "" {
+			return ErrNoBrowser
+		}
+		cmd = exec.Command(opener, url)
+	}
+
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to open browser: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// openWith runs each command in a $BROWSER list (separated by ":") until one
+// succeeds. A "%s" in a command is replaced by the URL; otherwise the URL is
+// appended.
+func openWith(browser, url string) error {
+	var lastErr error
+	for _, command := range strings.Split(browser, string(os.PathListSeparator)) {
+		command = strings.TrimSpace(command)
+		if command == "" {
+			continue
+		}
+
+		var cmd *exec.Cmd
+		if strings.Contains(command, "%s") {
+			cmd = shell(strings.ReplaceAll(command, "%s", `"$1"`), url)
+		} else {
+			cmd = shell(command+` "$1"`, url)
+		}
+		cmd.Stdin = os.Stdin
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		if lastErr = cmd.Run(); lastErr == nil {
+			return nil
+		}
+	}
+	if lastErr == nil {
+		return ErrNoBrowser
+	}
+	return fmt.Errorf("failed to open browser: %w", lastErr)
+}
+
+// shell runs script with url as $1
+func shell(script, url string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		fields := strings.Fields(strings.ReplaceAll(script, `"$1"`, url))
+		return exec.Command(fields[0], fields[1:]...)
+	}
+	return exec.Command("sh", "-c", script, "browser", url)
+}
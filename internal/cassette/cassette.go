@@ -0,0 +1,308 @@
+package cassette
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Placeholders written to cassettes in place of sensitive values
+const (
+	SecretPlaceholder  = "REDACTED"
+	AccountPlaceholder = "ACCOUNT"
+)
+
+// headers that are never written to a cassette, either because they are
+// sensitive or because they change on every run
+var droppedHeaders = []string{"Authorization", "Cookie", "Set-Cookie", "Content-Length", "Date"}
+
+// Request is the recorded part of an HTTP request
+type Request struct {
+	Method string `yaml:"method"`
+	URL    string `yaml:"url"`
+	Body   string `yaml:"body,omitempty"`
+}
+
+// Response is the recorded part of an HTTP response
+type Response struct {
+	Status  int         `yaml:"status"`
+	Headers http.Header `yaml:"headers,omitempty"`
+	Body    string      `yaml:"body,omitempty"`
+}
+
+// Interaction is a single request/response pair
+type Interaction struct {
+	Request  Request  `yaml:"request"`
+	Response Response `yaml:"response"`
+}
+
+// Cassette is an ordered list of recorded interactions
+type Cassette struct {
+	Interactions []Interaction `yaml:"interactions"`
+
+	path string
+	mu   sync.Mutex
+	used []bool
+}
+
+// Options controls how traffic is sanitized
+type Options struct {
+	// Secrets are replaced with SecretPlaceholder wherever they appear
+	Secrets []string
+
+	// Account is replaced with AccountPlaceholder in URL paths and quoted
+	// JSON values when recording, and restored in replayed responses
+	Account string
+}
+
+func (o Options) scrub(s string) string {
+	for _, secret := range o.Secrets {
+		if secret != "" {
+			s = strings.ReplaceAll(s, secret, SecretPlaceholder)
+		}
+	}
+	if o.Account != "" {
+		s = replaceSegment(s, o.Account, AccountPlaceholder)
+		s = strings.ReplaceAll(s, `"`+o.Account+`"`, `"`+AccountPlaceholder+`"`)
+	}
+	return s
+}
+
+func (o Options) restore(s string) string {
+	if o.Account != "" {
+		s = replaceSegment(s, AccountPlaceholder, o.Account)
+		s = strings.ReplaceAll(s, `"`+AccountPlaceholder+`"`, `"`+o.Account+`"`)
+	}
+	return s
+}
+
+// replaceSegment replaces old with new where it is a whole URL path segment,
+// so that a card number starting with the account ID is left alone
+func replaceSegment(s, old, new string) string {
+	re := regexp.MustCompile(`/` + regexp.QuoteMeta(old) + `([/?#"\s]|$)`)
+	return re.ReplaceAllStringFunc(s, func(match string) string {
+		return "/" + new + match[1+len(old):]
+	})
+}
+
+// Cassettes are shared per path so that every client created in a process
+// appends to, or replays from, the same position
+var (
+	registryMu sync.Mutex
+	registry   = map[string]*Cassette{}
+)
+
+func open(path string, load bool) (*Cassette, error) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if c, ok := registry[path]; ok {
+		return c, nil
+	}
+
+	c := &Cassette{path: path}
+	if load {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read cassette: %w", err)
+		}
+		if err := yaml.Unmarshal(data, c); err != nil {
+			return nil, fmt.Errorf("failed to parse cassette %s: %w", path, err)
+		}
+		c.used = make([]bool, len(c.Interactions))
+	}
+
+	registry[path] = c
+	return c, nil
+}
+
+// save writes the cassette to disk; the caller must hold c.mu
+func (c *Cassette) save() error {
+	if dir := filepath.Dir(c.path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("failed to create cassette directory: %w", err)
+		}
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(c); err != nil {
+		return fmt.Errorf("failed to encode cassette: %w", err)
+	}
+	encoder.Close()
+
+	return os.WriteFile(c.path, buf.Bytes(), 0o600)
+}
+
+// Recorder returns a RoundTripper that forwards requests to next and
+// appends every exchange to the cassette at path. The file is rewritten
+// after each request so nothing is lost if the process exits early.
+func Recorder(path string, next http.RoundTripper, opts Options) (http.RoundTripper, error) {
+	c, err := open(path, false)
+	if err != nil {
+		return nil, err
+	}
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &recorder{cassette: c, next: next, opts: opts}, nil
+}
+
+// Replayer returns a RoundTripper that answers requests from the cassette
+// at path without touching the network. Requests are matched on method and
+// URL, taking recorded interactions in order so that repeated requests see
+// the responses they saw when recorded.
+func Replayer(path string, opts Options) (http.RoundTripper, error) {
+	c, err := open(path, true)
+	if err != nil {
+		return nil, err
+	}
+	return &replayer{cassette: c, opts: opts}, nil
+}
+
+type recorder struct {
+	cassette *Cassette
+	next     http.RoundTripper
+	opts     Options
+}
+
+// RoundTrip implements http.RoundTripper
+func (r *recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqBody, err := readBody(&req.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := r.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := readBody(&resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	headers := http.Header{}
+	for key, values := range resp.Header {
+		scrubbed := make([]string, len(values))
+		for i, v := range values {
+			scrubbed[i] = r.opts.scrub(v)
+		}
+		headers[key] = scrubbed
+	}
+	for _, key := range droppedHeaders {
+		headers.Del(key)
+	}
+
+	interaction := Interaction{
+		Request: Request{
+			Method: req.Method,
+			URL:    r.opts.scrub(req.URL.String()),
+			Body:   r.opts.scrub(reqBody),
+		},
+		Response: Response{
+			Status:  resp.StatusCode,
+			Headers: headers,
+			Body:    r.opts.scrub(respBody),
+		},
+	}
+
+	c := r.cassette
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.Interactions = append(c.Interactions, interaction)
+	if err := c.save(); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+type replayer struct {
+	cassette *Cassette
+	opts     Options
+}
+
+// RoundTrip implements http.RoundTripper
+func (r *replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+
+	key := requestKey(r.opts.scrub(req.URL.String()))
+
+	c := r.cassette
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for i, interaction := range c.Interactions {
+		if c.used[i] || interaction.Request.Method != req.Method {
+			continue
+		}
+		if requestKey(interaction.Request.URL) != key {
+			continue
+		}
+		c.used[i] = true
+
+		headers := http.Header{}
+		for k, values := range interaction.Response.Headers {
+			restored := make([]string, len(values))
+			for j, v := range values {
+				restored[j] = r.opts.restore(v)
+			}
+			headers[k] = restored
+		}
+
+		body := r.opts.restore(interaction.Response.Body)
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", interaction.Response.Status, http.StatusText(interaction.Response.Status)),
+			StatusCode:    interaction.Response.Status,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        headers,
+			Body:          io.NopCloser(strings.NewReader(body)),
+			ContentLength: int64(len(body)),
+			Request:       req,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("cassette %s: no recorded interaction for %s %s", c.path, req.Method, key)
+}
+
+// requestKey reduces a URL to its path and query so that cassettes replay
+// against any base URL
+func requestKey(rawURL string) string {
+	if i := strings.Index(rawURL, "://"); i >= 0 {
+		rest := rawURL[i+3:]
+		if j := strings.Index(rest, "/"); j >= 0 {
+			return rest[j:]
+		}
+		return "/"
+	}
+	return rawURL
+}
+
+// readBody drains *body and replaces it with an equivalent reader
+func readBody(body *io.ReadCloser) (string, error) {
+	if *body == nil || *body == http.NoBody {
+		return "", nil
+	}
+	data, err := io.ReadAll(*body)
+	(*body).Close()
+	if err != nil {
+		return "", fmt.Errorf("failed to read body: %w", err)
+	}
+	*body = io.NopCloser(bytes.NewReader(data))
+	return string(data), nil
+}
@@ -0,0 +1,27 @@
+package cassette
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScrubAccount(t *testing.T) {
+	o := Options{Secrets: []string{"s3cret"}, Account: "6130737"}
+
+	for _, tc := range []struct{ raw, scrubbed string }{
+		{"https://app.fizzy.do/6130737/cards/61307371.json", "https://app.fizzy.do/ACCOUNT/cards/61307371.json"},
+		{"https://app.fizzy.do/6130737", "https://app.fizzy.do/ACCOUNT"},
+		{"https://app.fizzy.do/6130737?page=2", "https://app.fizzy.do/ACCOUNT?page=2"},
+		{`{"url":"https://app.fizzy.do/6130737/cards/42","account":"6130737"}`,
+			`{"url":"https://app.fizzy.do/ACCOUNT/cards/42","account":"ACCOUNT"}`},
+		{"Bearer s3cret", "Bearer REDACTED"},
+	} {
+		assert.Equal(t, tc.scrubbed, o.scrub(tc.raw))
+	}
+
+	// Restoring leaves card numbers and words that start with the
+	// placeholder alone
+	assert.Equal(t, "/6130737/cards/61307371", o.restore("/ACCOUNT/cards/61307371"))
+	assert.Equal(t, "/ACCOUNTING/6130737", o.restore("/ACCOUNTING/ACCOUNT"))
+}
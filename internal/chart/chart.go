@@ -0,0 +1,210 @@
+// Package chart draws line and stacked area charts of daily series as SVG
+// or PNG, in pure Go.
+package chart
+
+import (
+	"encoding/csv"
+	"fmt"
+	"image/color"
+	"io"
+	"math"
+	"strconv"
+)
+
+// Chart is a set of series sharing the same x labels
+type Chart struct {
+	Title  string   `json:"title"`
+	Labels []string `json:"labels"`
+	Series []Series `json:"series"`
+	// Stacked draws the series as stacked areas, the first at the bottom;
+	// otherwise they are lines
+	Stacked bool `json:"stacked"`
+}
+
+// Series is one named line or area
+type Series struct {
+	Name   string    `json:"name"`
+	Values []float64 `json:"values"`
+	// Dashed draws a line series dashed, e.g. for a target
+	Dashed bool `json:"dashed,omitempty"`
+}
+
+// Default image size
+const (
+	DefaultWidth  = 900
+	DefaultHeight = 450
+)
+
+// Layout margins around the plot area
+const (
+	marginLeft   = 56
+	marginRight  = 160
+	marginTop    = 44
+	marginBottom = 48
+)
+
+var palette = []color.RGBA{
+	{0x4c, 0x9f, 0x70, 0xff}, // green
+	{0x3b, 0x82, 0xf6, 0xff}, // blue
+	{0xf5, 0x9e, 0x0b, 0xff}, // amber
+	{0xa8, 0x55, 0xf7, 0xff}, // purple
+	{0xef, 0x44, 0x44, 0xff}, // red
+	{0x14, 0xb8, 0xa6, 0xff}, // teal
+	{0xec, 0x48, 0x99, 0xff}, // pink
+	{0x84, 0xcc, 0x16, 0xff}, // lime
+	{0x64, 0x74, 0x8b, 0xff}, // slate
+}
+
+var (
+	gridColor = color.RGBA{0xe5, 0xe7, 0xeb, 0xff}
+	axisColor = color.RGBA{0x6b, 0x72, 0x80, 0xff}
+	textColor = color.RGBA{0x11, 0x18, 0x27, 0xff}
+)
+
+// colorOf returns the color for series i
+func colorOf(i int) color.RGBA {
+	return palette[i%len(palette)]
+}
+
+// geometry maps values to pixel coordinates for a chart of a given size
+type geometry struct {
+	c             *Chart
+	width, height int
+	yMax          float64
+	yStep         float64
+	// tops holds, per series, the y value of its upper edge: the running
+	// total when stacked, the value itself otherwise
+	tops [][]float64
+}
+
+func newGeometry(c *Chart, width, height int) *geometry {
+	if width <= 0 {
+		width = DefaultWidth
+	}
+	if height <= 0 {
+		height = DefaultHeight
+	}
+	g := &geometry{c: c, width: width, height: height, tops: make([][]float64, len(c.Series))}
+
+	peak := 0.0
+	for i, s := range c.Series {
+		g.tops[i] = make([]float64, len(c.Labels))
+		for d := range c.Labels {
+			v := 0.0
+			if d < len(s.Values) {
+				v = s.Values[d]
+			}
+			if c.Stacked && i > 0 {
+				v += g.tops[i-1][d]
+			}
+			g.tops[i][d] = v
+			peak = math.Max(peak, v)
+		}
+	}
+	g.yStep = niceStep(peak / 5)
+	g.yMax = math.Max(g.yStep, math.Ceil(peak/g.yStep)*g.yStep)
+	return g
+}
+
+// niceStep rounds a raw tick interval up to 1, 2 or 5 times a power of ten,
+// and to at least 1 since values are counts
+func niceStep(raw float64) float64 {
+	if raw <= 1 {
+		return 1
+	}
+	pow := math.Pow(10, math.Floor(math.Log10(raw)))
+	for _, m := range []float64{1, 2, 5, 10} {
+		if raw <= m*pow {
+			return m * pow
+		}
+	}
+	return 10 * pow
+}
+
+func (g *geometry) plotLeft() float64   { return marginLeft }
+func (g *geometry) plotRight() float64  { return float64(g.width - marginRight) }
+func (g *geometry) plotTop() float64    { return marginTop }
+func (g *geometry) plotBottom() float64 { return float64(g.height - marginBottom) }
+
+// x returns the pixel column of label d
+func (g *geometry) x(d int) float64 {
+	n := len(g.c.Labels)
+	if n <= 1 {
+		return g.plotLeft()
+	}
+	return g.plotLeft() + float64(d)*(g.plotRight()-g.plotLeft())/float64(n-1)
+}
+
+// y returns the pixel row of value v
+func (g *geometry) y(v float64) float64 {
+	return g.plotBottom() - v/g.yMax*(g.plotBottom()-g.plotTop())
+}
+
+// base returns the lower edge of series i at label d
+func (g *geometry) base(i, d int) float64 {
+	if g.c.Stacked && i > 0 {
+		return g.tops[i-1][d]
+	}
+	return 0
+}
+
+// labelEvery spaces x labels so roughly eight fit
+func (g *geometry) labelEvery() int {
+	n := len(g.c.Labels)
+	every := (n + 7) / 8
+	if every < 1 {
+		every = 1
+	}
+	return every
+}
+
+// yTicks returns the values of the horizontal grid lines
+func (g *geometry) yTicks() []float64 {
+	var ticks []float64
+	for v := 0.0; v <= g.yMax+g.yStep/2; v += g.yStep {
+		ticks = append(ticks, v)
+	}
+	return ticks
+}
+
+// WriteCSV writes the chart's data with a row per label and a column per
+// series
+func (c *Chart) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	header := []string{"date"}
+	for _, s := range c.Series {
+		header = append(header, s.Name)
+	}
+	cw.Write(header)
+	for d, label := range c.Labels {
+		row := []string{label}
+		for _, s := range c.Series {
+			v := 0.0
+			if d < len(s.Values) {
+				v = s.Values[d]
+			}
+			row = append(row, strconv.FormatFloat(v, 'f', -1, 64))
+		}
+		cw.Write(row)
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// Write renders the chart in the named format: svg, png or csv
+func (c *Chart) Write(w io.Writer, format string, width, height int) error {
+	switch format {
+	case "svg":
+		return c.WriteSVG(w, width, height)
+	case "png":
+		return c.WritePNG(w, width, height)
+	case "csv":
+		return c.WriteCSV(w)
+	default:
+		return fmt.Errorf("unsupported chart format: %s (supported: svg, png, csv)", format)
+	}
+}
+
+func formatTick(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
@@ -0,0 +1,67 @@
+package chart
+
+import (
+	"bytes"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sample(stacked bool) *Chart {
+	return &Chart{
+		Title:   "Flow <Platform>",
+		Labels:  []string{"2026-03-01", "2026-03-02", "2026-03-03"},
+		Stacked: stacked,
+		Series: []Series{
+			{Name: "Done", Values: []float64{0, 1, 3}},
+			{Name: "Doing", Values: []float64{2, 2, 1}},
+			{Name: "Ideal", Values: []float64{2, 1, 0}, Dashed: true},
+		},
+	}
+}
+
+func TestWriteSVG(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, sample(true).WriteSVG(&buf, 600, 300))
+	svg := buf.String()
+	assert.True(t, strings.HasPrefix(svg, `<svg xmlns="http://www.w3.org/2000/svg" width="600" height="300"`))
+	assert.Contains(t, svg, "Flow &lt;Platform&gt;")
+	assert.Equal(t, 3, strings.Count(svg, "<polygon"))
+	assert.Contains(t, svg, "<title>Doing</title>")
+
+	buf.Reset()
+	require.NoError(t, sample(false).WriteSVG(&buf, 0, 0))
+	svg = buf.String()
+	assert.Contains(t, svg, `width="900" height="450"`)
+	assert.Equal(t, 3, strings.Count(svg, "<polyline"))
+	assert.Equal(t, 1, strings.Count(svg, "stroke-dasharray"))
+}
+
+func TestWritePNG(t *testing.T) {
+	for _, stacked := range []bool{true, false} {
+		var buf bytes.Buffer
+		require.NoError(t, sample(stacked).WritePNG(&buf, 400, 200))
+		img, err := png.Decode(&buf)
+		require.NoError(t, err)
+		assert.Equal(t, 400, img.Bounds().Dx())
+		assert.Equal(t, 200, img.Bounds().Dy())
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, sample(true).Write(&buf, "csv", 0, 0))
+	assert.Equal(t, "date,Done,Doing,Ideal\n2026-03-01,0,2,2\n2026-03-02,1,2,1\n2026-03-03,3,1,0\n", buf.String())
+
+	assert.Error(t, sample(true).Write(&buf, "gif", 0, 0))
+}
+
+func TestNiceStep(t *testing.T) {
+	assert.Equal(t, 1.0, niceStep(0.4))
+	assert.Equal(t, 2.0, niceStep(1.6))
+	assert.Equal(t, 5.0, niceStep(4.2))
+	assert.Equal(t, 50.0, niceStep(42))
+}
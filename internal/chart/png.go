@@ -0,0 +1,133 @@
+package chart
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"math"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+// WritePNG renders the chart as a PNG image
+func (c *Chart) WritePNG(w io.Writer, width, height int) error {
+	g := newGeometry(c, width, height)
+	img := image.NewRGBA(image.Rect(0, 0, g.width, g.height))
+	draw.Draw(img, img.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+
+	if c.Title != "" {
+		drawText(img, c.Title, marginLeft, 26, textColor)
+	}
+
+	for _, v := range g.yTicks() {
+		y := g.y(v)
+		drawLine(img, g.plotLeft(), y, g.plotRight(), y, gridColor, 1, false)
+		label := formatTick(v)
+		drawText(img, label, int(g.plotLeft())-6-textWidth(label), int(y)+4, axisColor)
+	}
+	every := g.labelEvery()
+	for d, label := range c.Labels {
+		if d%every == 0 {
+			drawText(img, label, int(g.x(d))-textWidth(label)/2, int(g.plotBottom())+18, axisColor)
+		}
+	}
+
+	for i, s := range c.Series {
+		col := colorOf(i)
+		if c.Stacked {
+			fillBand(img, g, i, col)
+			continue
+		}
+		for d := 1; d < len(c.Labels); d++ {
+			drawLine(img, g.x(d-1), g.y(g.tops[i][d-1]), g.x(d), g.y(g.tops[i][d]), col, 2, s.Dashed)
+		}
+	}
+
+	drawLine(img, g.plotLeft(), g.plotBottom(), g.plotRight(), g.plotBottom(), axisColor, 1, false)
+
+	for n := range c.Series {
+		i := n
+		if c.Stacked {
+			i = len(c.Series) - 1 - n
+		}
+		y := int(g.plotTop()) + n*20
+		x := int(g.plotRight()) + 16
+		draw.Draw(img, image.Rect(x, y, x+12, y+12), image.NewUniform(colorOf(i)), image.Point{}, draw.Src)
+		drawText(img, c.Series[i].Name, x+18, y+10, textColor)
+	}
+
+	return png.Encode(w, img)
+}
+
+// fillBand fills the area of stacked series i between its base and top,
+// one pixel column at a time
+func fillBand(img *image.RGBA, g *geometry, i int, col color.RGBA) {
+	n := len(g.c.Labels)
+	if n == 1 {
+		top, bottom := g.y(g.tops[i][0]), g.y(g.base(i, 0))
+		fillSpan(img, int(g.x(0)), top, bottom, col)
+		return
+	}
+	for px := int(g.plotLeft()); px <= int(g.plotRight()); px++ {
+		pos := (float64(px) - g.plotLeft()) / (g.plotRight() - g.plotLeft()) * float64(n-1)
+		d := int(math.Min(math.Floor(pos), float64(n-2)))
+		t := pos - float64(d)
+		top := lerp(g.tops[i][d], g.tops[i][d+1], t)
+		bottom := lerp(g.base(i, d), g.base(i, d+1), t)
+		fillSpan(img, px, g.y(top), g.y(bottom), col)
+	}
+}
+
+func fillSpan(img *image.RGBA, x int, top, bottom float64, col color.RGBA) {
+	for y := int(math.Round(top)); y < int(math.Round(bottom)); y++ {
+		img.SetRGBA(x, y, col)
+	}
+}
+
+func lerp(a, b, t float64) float64 {
+	return a + (b-a)*t
+}
+
+// drawLine draws a line of the given thickness, skipping every other
+// segment of 6 pixels when dashed
+func drawLine(img *image.RGBA, x0, y0, x1, y1 float64, col color.RGBA, thickness int, dashed bool) {
+	length := math.Hypot(x1-x0, y1-y0)
+	steps := int(math.Ceil(length))
+	if steps == 0 {
+		steps = 1
+	}
+	for s := 0; s <= steps; s++ {
+		if dashed && (s/6)%2 == 1 {
+			continue
+		}
+		t := float64(s) / float64(steps)
+		x := int(math.Round(lerp(x0, x1, t)))
+		y := int(math.Round(lerp(y0, y1, t)))
+		for dy := 0; dy < thickness; dy++ {
+			for dx := 0; dx < thickness; dx++ {
+				img.SetRGBA(x+dx-thickness/2, y+dy-thickness/2, col)
+			}
+		}
+	}
+}
+
+var face = basicfont.Face7x13
+
+// drawText draws text with its baseline at y
+func drawText(img *image.RGBA, text string, x, y int, col color.RGBA) {
+	d := font.Drawer{
+		Dst:  img,
+		Src:  image.NewUniform(col),
+		Face: face,
+		Dot:  fixed.P(x, y),
+	}
+	d.DrawString(text)
+}
+
+func textWidth(text string) int {
+	return font.MeasureString(face, text).Round()
+}
@@ -0,0 +1,89 @@
+package chart
+
+import (
+	"fmt"
+	"html"
+	"image/color"
+	"io"
+	"strings"
+)
+
+// WriteSVG renders the chart as an SVG document
+func (c *Chart) WriteSVG(w io.Writer, width, height int) error {
+	g := newGeometry(c, width, height)
+	var b strings.Builder
+
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="sans-serif" font-size="12">`+"\n",
+		g.width, g.height, g.width, g.height)
+	fmt.Fprintf(&b, `<rect width="%d" height="%d" fill="#ffffff"/>`+"\n", g.width, g.height)
+	if c.Title != "" {
+		fmt.Fprintf(&b, `<text x="%d" y="26" font-size="16" font-weight="bold" fill="%s">%s</text>`+"\n",
+			marginLeft, hexColor(textColor), html.EscapeString(c.Title))
+	}
+
+	for _, v := range g.yTicks() {
+		y := g.y(v)
+		fmt.Fprintf(&b, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="%s"/>`+"\n",
+			g.plotLeft(), y, g.plotRight(), y, hexColor(gridColor))
+		fmt.Fprintf(&b, `<text x="%.1f" y="%.1f" text-anchor="end" fill="%s">%s</text>`+"\n",
+			g.plotLeft()-6, y+4, hexColor(axisColor), formatTick(v))
+	}
+	every := g.labelEvery()
+	for d, label := range c.Labels {
+		if d%every != 0 {
+			continue
+		}
+		fmt.Fprintf(&b, `<text x="%.1f" y="%.1f" text-anchor="middle" fill="%s">%s</text>`+"\n",
+			g.x(d), g.plotBottom()+18, hexColor(axisColor), html.EscapeString(label))
+	}
+
+	for i, s := range c.Series {
+		col := hexColor(colorOf(i))
+		if c.Stacked {
+			var points []string
+			for d := range c.Labels {
+				points = append(points, fmt.Sprintf("%.1f,%.1f", g.x(d), g.y(g.tops[i][d])))
+			}
+			for d := len(c.Labels) - 1; d >= 0; d-- {
+				points = append(points, fmt.Sprintf("%.1f,%.1f", g.x(d), g.y(g.base(i, d))))
+			}
+			fmt.Fprintf(&b, `<polygon points="%s" fill="%s" fill-opacity="0.85" stroke="%s"><title>%s</title></polygon>`+"\n",
+				strings.Join(points, " "), col, col, html.EscapeString(s.Name))
+			continue
+		}
+
+		var points []string
+		for d := range c.Labels {
+			points = append(points, fmt.Sprintf("%.1f,%.1f", g.x(d), g.y(g.tops[i][d])))
+		}
+		dash := ""
+		if s.Dashed {
+			dash = ` stroke-dasharray="6 4"`
+		}
+		fmt.Fprintf(&b, `<polyline points="%s" fill="none" stroke="%s" stroke-width="2"%s><title>%s</title></polyline>`+"\n",
+			strings.Join(points, " "), col, dash, html.EscapeString(s.Name))
+	}
+
+	fmt.Fprintf(&b, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="%s"/>`+"\n",
+		g.plotLeft(), g.plotBottom(), g.plotRight(), g.plotBottom(), hexColor(axisColor))
+
+	// Legend, top series first to match a stacked chart's order
+	for n := range c.Series {
+		i := n
+		if c.Stacked {
+			i = len(c.Series) - 1 - n
+		}
+		y := int(g.plotTop()) + n*20
+		x := int(g.plotRight()) + 16
+		fmt.Fprintf(&b, `<rect x="%d" y="%d" width="12" height="12" fill="%s"/>`+"\n", x, y, hexColor(colorOf(i)))
+		fmt.Fprintf(&b, `<text x="%d" y="%d" fill="%s">%s</text>`+"\n", x+18, y+10, hexColor(textColor), html.EscapeString(c.Series[i].Name))
+	}
+
+	b.WriteString("</svg>\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func hexColor(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
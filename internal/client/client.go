@@ -3,32 +3,147 @@ package client
 import (
 	"fmt"
 	"log"
+	"net/http"
+	"time"
 
+	"github.com/visionik/fizz/internal/cassette"
 	"github.com/visionik/fizz/internal/config"
+	"github.com/visionik/fizz/internal/httpcache"
 	"github.com/visionik/libfizz-go/fizzy"
 )
 
 // Client wraps the libfizz-go client
 type Client struct {
 	*fizzy.Client
+	Account string
+	BaseURL string
+	// Token authorizes requests the libfizz-go client does not make, such as
+	// attachment downloads
+	Token string
 	Debug bool
+	// Concurrency is the number of requests the client lets run at once
+	Concurrency int
+
+	// cache is the response cache, or nil when recording or replaying
+	cache *httpcache.Transport
+}
+
+// Option adjusts a client made by New
+type Option func(*options)
+
+type options struct {
+	maxRetries int
+}
+
+// WithMaxRetries sets how many times fizz retries a rate limited or failed
+// request, instead of DefaultMaxRetries
+func WithMaxRetries(n int) Option {
+	return func(o *options) { o.maxRetries = n }
 }
 
 // New creates a new Fizzy client from configuration
-func New(cfg *config.Config, debug bool) (*Client, error) {
+func New(cfg *config.Config, debug bool, opts ...Option) (*Client, error) {
 	if cfg == nil {
 		return nil, fmt.Errorf("config cannot be nil")
 	}
+	o := options{maxRetries: DefaultMaxRetries}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	var fizzyOpts []fizzy.ClientOption
+	if cfg.BaseURL != "" {
+		fizzyOpts = append(fizzyOpts, fizzy.WithBaseURL(cfg.BaseURL))
+	}
+
+	transport, err := newTransport(cfg)
+	if err != nil {
+		return nil, err
+	}
+	// fizz retries below libfizz-go, which would also resend requests that
+	// are not safe to repeat, and caches below it too: libfizz-go's own
+	// ETag cache answers a 304 with nothing
+	fizzyOpts = append(fizzyOpts, fizzy.WithMaxRetries(0), fizzy.WithCache(false))
+	retries := o.maxRetries
+	if cfg.ReplayPath != "" {
+		// A missing interaction will not appear by retrying
+		retries = 0
+	}
 
-	client := fizzy.NewClient(cfg.Token, cfg.Account)
+	transport = newLimitTransport(progressTransport{next: transport}, cfg.Concurrency, cfg.RateLimit, retries, debug)
+	// Cached responses would hide traffic from a cassette
+	var cache *httpcache.Transport
+	if cfg.RecordPath == "" && cfg.ReplayPath == "" {
+		if dir, err := httpcache.AccountDir(cfg.Account, cfg.Token); err == nil {
+			cache = httpcache.NewTransport(transport, dir, false, 0, debug)
+			transport = cache
+		}
+	}
+	installTransport(transport)
+	client := fizzy.NewClient(cfg.Token, cfg.Account, fizzyOpts...)
 
 	if debug {
 		log.Println("Debug mode enabled")
 		log.Printf("Account ID: %s", cfg.Account)
+		if cfg.RecordPath != "" {
+			log.Printf("Recording HTTP traffic to %s", cfg.RecordPath)
+		}
+		if cfg.ReplayPath != "" {
+			log.Printf("Replaying HTTP traffic from %s", cfg.ReplayPath)
+		}
 	}
 
 	return &Client{
-		Client: client,
-		Debug:  debug,
+		Client:      client,
+		Account:     cfg.Account,
+		BaseURL:     cfg.BaseURL,
+		Token:       cfg.Token,
+		Debug:       debug,
+		Concurrency: max(cfg.Concurrency, 1),
+		cache:       cache,
 	}, nil
 }
+
+// SetCache turns the response cache off, or on with responses served
+// without revalidation for ttl
+func (c *Client) SetCache(disabled bool, ttl time.Duration) {
+	if c.cache != nil {
+		c.cache.Configure(disabled, ttl)
+	}
+}
+
+// newTransport builds the HTTP transport for the configured mode
+func newTransport(cfg *config.Config) (http.RoundTripper, error) {
+	opts := cassette.Options{
+		Secrets: []string{cfg.Token},
+		Account: cfg.Account,
+	}
+
+	switch {
+	case cfg.ReplayPath != "":
+		transport, err := cassette.Replayer(cfg.ReplayPath, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open replay cassette: %w", err)
+		}
+		return transport, nil
+	case cfg.RecordPath != "":
+		transport, err := cassette.Recorder(cfg.RecordPath, baseTransport, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open record cassette: %w", err)
+		}
+		return transport, nil
+	default:
+		return baseTransport, nil
+	}
+}
+
+// baseTransport is the transport in place before fizz installs its own
+var baseTransport = http.DefaultTransport
+
+// installTransport routes all HTTP traffic through transport. libfizz-go
+// builds its retry and caching middleware on top of http.DefaultTransport
+// and sends upload contents with a bare http.Client, so the transport has
+// to be installed process-wide rather than passed in.
+func installTransport(transport http.RoundTripper) {
+	http.DefaultTransport = transport
+}
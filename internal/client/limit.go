@@ -0,0 +1,376 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Retry timing: the backoff doubles from retryBase up to retryCap, with
+// jitter, unless the API says how long to wait, for up to maxRetryAfter
+const (
+	retryBase     = 500 * time.Millisecond
+	retryCap      = 30 * time.Second
+	maxRetryAfter = time.Minute
+)
+
+// DefaultMaxRetries is how many times a request is retried
+const DefaultMaxRetries = 3
+
+// idempotent methods may be sent again after a server or network error,
+// because sending them twice has the same effect as once
+var idempotent = map[string]bool{
+	http.MethodGet:     true,
+	http.MethodHead:    true,
+	http.MethodOptions: true,
+	http.MethodPut:     true,
+	http.MethodDelete:  true,
+}
+
+// limitTransport keeps fizz's API traffic polite when commands fan out: it
+// caps the requests in flight, paces them with a token bucket that follows
+// the API's rate limit headers, retries with jittered backoff, and lets
+// identical GETs in flight at the same time share one response.
+type limitTransport struct {
+	next       http.RoundTripper
+	slots      chan struct{}
+	bucket     *bucket
+	maxRetries int
+	flights    flightGroup
+	debug      bool
+
+	// sleep waits for d or until ctx is done; tests replace it
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+func newLimitTransport(next http.RoundTripper, concurrency int, rate float64, maxRetries int, debug bool) *limitTransport {
+	return &limitTransport{
+		next:       next,
+		slots:      make(chan struct{}, max(concurrency, 1)),
+		bucket:     newBucket(rate, time.Now),
+		maxRetries: maxRetries,
+		debug:      debug,
+		sleep:      sleep,
+	}
+}
+
+func (t *limitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	key, ok := flightKey(req)
+	if !ok {
+		return t.send(req)
+	}
+	shared, err := t.flights.do(req.Context(), key, func() (*sharedResponse, error) {
+		resp, err := t.send(req)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+		return &sharedResponse{resp: resp, body: body}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return shared.copyFor(req), nil
+}
+
+// send makes a request, retrying it while retryDelay allows
+func (t *limitTransport) send(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	attemptReq := req
+	for attempt := 0; ; attempt++ {
+		resp, err := t.attempt(attemptReq)
+		wait, retry := t.retryDelay(req, resp, err, attempt)
+		if !retry {
+			return resp, err
+		}
+
+		reason := "error"
+		if resp != nil {
+			reason = resp.Status
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		if t.debug {
+			log.Printf("Retrying %s %s in %s after %s", req.Method, req.URL.Path, wait.Round(time.Millisecond), reason)
+		}
+		if err := t.sleep(ctx, wait); err != nil {
+			return nil, err
+		}
+
+		attemptReq = req.Clone(ctx)
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			attemptReq.Body = body
+		}
+	}
+}
+
+// attempt sends a request once it has a slot and a token
+func (t *limitTransport) attempt(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	select {
+	case t.slots <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	defer func() { <-t.slots }()
+
+	for {
+		wait := t.bucket.reserve()
+		if wait <= 0 {
+			break
+		}
+		if err := t.sleep(ctx, wait); err != nil {
+			return nil, err
+		}
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err == nil {
+		t.bucket.observe(resp)
+	}
+	return resp, err
+}
+
+// retryDelay reports whether a request should be sent again after resp or
+// err, and how long to wait first. A rate limited request was not
+// processed, so it is retried whatever its method; server and network
+// errors are retried only for idempotent methods.
+func (t *limitTransport) retryDelay(req *http.Request, resp *http.Response, err error, attempt int) (time.Duration, bool) {
+	if attempt >= t.maxRetries || req.Context().Err() != nil {
+		return 0, false
+	}
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return 0, false
+	}
+
+	switch {
+	case err != nil:
+		if !idempotent[req.Method] {
+			return 0, false
+		}
+	case resp.StatusCode == http.StatusTooManyRequests:
+	case resp.StatusCode >= 500 && idempotent[req.Method]:
+	default:
+		return 0, false
+	}
+
+	if resp != nil {
+		if wait, ok := retryAfter(resp.Header, time.Now()); ok {
+			return wait, true
+		}
+	}
+	return backoff(attempt), true
+}
+
+// backoff returns the wait before retry attempt+1: half the exponential
+// delay plus a random part of the other half, so clients that failed
+// together do not retry together
+func backoff(attempt int) time.Duration {
+	d := min(retryBase<<attempt, retryCap)
+	return d/2 + rand.N(d/2+1)
+}
+
+// retryAfter reads a Retry-After header, in seconds or as an HTTP date
+func retryAfter(h http.Header, now time.Time) (time.Duration, bool) {
+	value := h.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	var wait time.Duration
+	if seconds, err := strconv.Atoi(value); err == nil {
+		wait = time.Duration(seconds) * time.Second
+	} else if t, err := http.ParseTime(value); err == nil {
+		wait = t.Sub(now)
+	} else {
+		return 0, false
+	}
+	return min(max(wait, 0), maxRetryAfter), true
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// bucket is a token bucket that paces requests. It starts at the
+// configured rate, or unlimited, and follows the API's rate limit headers:
+// the requests left in the window are spread over the time until it
+// resets, and when none are left nothing is sent until then.
+type bucket struct {
+	mu sync.Mutex
+	// limit is the configured ceiling, and rate the current pace, in
+	// requests per second; 0 means none
+	limit  float64
+	rate   float64
+	tokens float64
+	last   time.Time
+	// paused holds requests until the rate limit window resets
+	paused time.Time
+	now    func() time.Time
+}
+
+func newBucket(limit float64, now func() time.Time) *bucket {
+	return &bucket{limit: limit, rate: limit, tokens: max(limit, 1), last: now(), now: now}
+}
+
+// reserve takes a token and returns 0, or returns how long to wait before
+// trying again
+func (b *bucket) reserve() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	if now.Before(b.paused) {
+		return b.paused.Sub(now)
+	}
+	if b.rate == 0 {
+		return 0
+	}
+	// Up to a second's worth of requests may go at once
+	b.tokens = min(max(b.rate, 1), b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+	if b.tokens >= 1 {
+		b.tokens--
+		return 0
+	}
+	return time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+}
+
+// observe adapts the pace to a response's rate limit headers
+func (b *bucket) observe(resp *http.Response) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	if resp.StatusCode == http.StatusTooManyRequests {
+		if wait, ok := retryAfter(resp.Header, now); ok {
+			b.paused = now.Add(wait)
+		}
+	}
+
+	remaining, reset, ok := rateLimit(resp.Header, now)
+	if !ok {
+		return
+	}
+	if remaining <= 0 {
+		b.paused = now.Add(min(reset, maxRetryAfter))
+		return
+	}
+	rate := float64(remaining) / max(reset.Seconds(), 1)
+	if b.limit > 0 {
+		rate = min(rate, b.limit)
+	}
+	b.rate = rate
+	b.tokens = min(b.tokens, max(rate, 1))
+}
+
+// rateLimit reads the requests remaining and the time until the window
+// resets from RateLimit-* or X-RateLimit-* headers. A reset larger than a
+// day is a Unix time rather than a number of seconds.
+func rateLimit(h http.Header, now time.Time) (remaining int, reset time.Duration, ok bool) {
+	for _, prefix := range []string{"RateLimit-", "X-RateLimit-"} {
+		r, err1 := strconv.Atoi(strings.TrimSpace(h.Get(prefix + "Remaining")))
+		s, err2 := strconv.ParseInt(strings.TrimSpace(h.Get(prefix+"Reset")), 10, 64)
+		if err1 != nil || err2 != nil {
+			continue
+		}
+		reset = time.Duration(s) * time.Second
+		if s > 24*60*60 {
+			reset = time.Unix(s, 0).Sub(now)
+		}
+		return r, max(reset, 0), true
+	}
+	return 0, 0, false
+}
+
+// flightKey returns the key under which identical API reads share a
+// response. Only JSON GETs qualify, so file downloads stream as usual.
+func flightKey(req *http.Request) (string, bool) {
+	if req.Method != http.MethodGet || (req.Body != nil && req.Body != http.NoBody) ||
+		!strings.Contains(req.Header.Get("Accept"), "application/json") || req.Header.Get("Range") != "" {
+		return "", false
+	}
+	return strings.Join([]string{
+		req.URL.String(),
+		req.Header.Get("Authorization"),
+		req.Header.Get("If-None-Match"),
+	}, "\n"), true
+}
+
+// sharedResponse is a response read in full so several callers can have it
+type sharedResponse struct {
+	resp *http.Response
+	body []byte
+}
+
+// copyFor returns a copy of the response with its own body, for req
+func (s *sharedResponse) copyFor(req *http.Request) *http.Response {
+	resp := *s.resp
+	resp.Header = s.resp.Header.Clone()
+	resp.Body = io.NopCloser(bytes.NewReader(s.body))
+	resp.ContentLength = int64(len(s.body))
+	resp.Request = req
+	return &resp
+}
+
+// flightGroup runs one call per key at a time; callers asking for a key
+// already in flight wait for its result. The call runs under the first
+// caller's context, and the others stop waiting when theirs is done.
+type flightGroup struct {
+	mu    sync.Mutex
+	calls map[string]*flight
+}
+
+type flight struct {
+	done chan struct{}
+	res  *sharedResponse
+	err  error
+}
+
+func (g *flightGroup) do(ctx context.Context, key string, fn func() (*sharedResponse, error)) (*sharedResponse, error) {
+	g.mu.Lock()
+	if f, ok := g.calls[key]; ok {
+		g.mu.Unlock()
+		select {
+		case <-f.done:
+			return f.res, f.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	if g.calls == nil {
+		g.calls = map[string]*flight{}
+	}
+	f := &flight{done: make(chan struct{})}
+	g.calls[key] = f
+	g.mu.Unlock()
+
+	f.res, f.err = fn()
+
+	g.mu.Lock()
+	delete(g.calls, key)
+	g.mu.Unlock()
+	close(f.done)
+	return f.res, f.err
+}
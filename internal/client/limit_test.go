@@ -0,0 +1,249 @@
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/visionik/fizz/internal/config"
+)
+
+// newTestTransport returns a limitTransport on a fake clock that records
+// its waits instead of sleeping
+func newTestTransport(concurrency int, waits *[]time.Duration) *limitTransport {
+	t := newLimitTransport(http.DefaultTransport, concurrency, 0, DefaultMaxRetries, false)
+	var mu sync.Mutex
+	now := time.Now()
+	t.bucket.now = func() time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+		return now
+	}
+	t.sleep = func(ctx context.Context, d time.Duration) error {
+		mu.Lock()
+		defer mu.Unlock()
+		*waits = append(*waits, d)
+		now = now.Add(d)
+		return ctx.Err()
+	}
+	return t
+}
+
+func get(t *testing.T, rt http.RoundTripper, url string) (*http.Response, string) {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	require.NoError(t, err)
+	req.Header.Set("Accept", "application/json")
+	resp, err := rt.RoundTrip(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp, string(body)
+}
+
+func TestLimitTransportRetries(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := calls.Add(1)
+		switch {
+		case r.URL.Path == "/flaky" && n < 3:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case r.URL.Path == "/limited" && n == 1:
+			w.Header().Set("Retry-After", "7")
+			w.WriteHeader(http.StatusTooManyRequests)
+		case r.URL.Path == "/broken":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			io.WriteString(w, "ok")
+		}
+	}))
+	defer srv.Close()
+
+	var waits []time.Duration
+	rt := newTestTransport(4, &waits)
+
+	resp, body := get(t, rt, srv.URL+"/flaky")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "ok", body)
+	require.Len(t, waits, 2)
+	assert.GreaterOrEqual(t, waits[0], retryBase/2)
+	assert.LessOrEqual(t, waits[0], retryBase)
+	assert.GreaterOrEqual(t, waits[1], retryBase)
+	assert.LessOrEqual(t, waits[1], 2*retryBase)
+
+	// A rate limited POST is sent again after the Retry-After
+	calls.Store(0)
+	waits = nil
+	req, _ := http.NewRequest(http.MethodPost, srv.URL+"/limited", strings.NewReader("{}"))
+	resp, err := rt.RoundTrip(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, int32(2), calls.Load())
+	assert.Contains(t, waits, 7*time.Second)
+
+	// A POST that failed on the server may have done its work, so it is not
+	// sent again
+	calls.Store(0)
+	req, _ = http.NewRequest(http.MethodPost, srv.URL+"/broken", strings.NewReader("{}"))
+	resp, err = rt.RoundTrip(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+	assert.Equal(t, int32(1), calls.Load())
+
+	// An idempotent request gives up after the last retry
+	calls.Store(0)
+	resp, _ = get(t, rt, srv.URL+"/broken")
+	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+	assert.Equal(t, int32(DefaultMaxRetries+1), calls.Load())
+}
+
+func TestLimitTransportConcurrency(t *testing.T) {
+	var inFlight, most atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		for {
+			m := most.Load()
+			if n <= m || most.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		inFlight.Add(-1)
+	}))
+	defer srv.Close()
+
+	var waits []time.Duration
+	rt := newTestTransport(2, &waits)
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// Distinct URLs, so no requests are coalesced
+			req, _ := http.NewRequest(http.MethodGet, srv.URL+"/"+string(rune('a'+i)), nil)
+			resp, err := rt.RoundTrip(req)
+			if assert.NoError(t, err) {
+				resp.Body.Close()
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(2), most.Load())
+}
+
+func TestLimitTransportCoalescesGets(t *testing.T) {
+	var calls atomic.Int32
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		<-release
+		w.Header().Set("ETag", `"v1"`)
+		io.WriteString(w, `{"id":1}`)
+	}))
+	defer srv.Close()
+
+	var waits []time.Duration
+	rt := newTestTransport(4, &waits)
+	bodies := make([]string, 5)
+	var wg sync.WaitGroup
+	for i := range bodies {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, body := get(t, rt, srv.URL+"/boards")
+			assert.Equal(t, `"v1"`, resp.Header.Get("ETag"))
+			bodies[i] = body
+		}()
+	}
+	// Let every request join the first before it is answered
+	time.Sleep(100 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), calls.Load())
+	for _, body := range bodies {
+		assert.Equal(t, `{"id":1}`, body)
+	}
+
+	// Once it is answered, the next request goes to the server
+	get(t, rt, srv.URL+"/boards")
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestBucket(t *testing.T) {
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	b := newBucket(0, func() time.Time { return now })
+	assert.Zero(t, b.reserve(), "unlimited until the API says otherwise")
+
+	limited := func(remaining, reset string) *http.Response {
+		h := http.Header{}
+		h.Set("X-RateLimit-Remaining", remaining)
+		h.Set("X-RateLimit-Reset", reset)
+		return &http.Response{StatusCode: http.StatusOK, Header: h}
+	}
+
+	// 5 requests left in 10 seconds: one every 2 seconds
+	b.observe(limited("5", "10"))
+	assert.Zero(t, b.reserve())
+	assert.Equal(t, 2*time.Second, b.reserve())
+	now = now.Add(2 * time.Second)
+	assert.Zero(t, b.reserve())
+
+	// None left: wait for the window to reset, given as a Unix time
+	b.observe(limited("0", strconv.FormatInt(now.Add(20*time.Second).Unix(), 10)))
+	assert.Equal(t, 20*time.Second, b.reserve())
+
+	// A configured limit caps what the headers allow
+	b = newBucket(1, func() time.Time { return now })
+	b.observe(limited("100", "10"))
+	assert.Equal(t, 1.0, b.rate)
+
+	// A 429 pauses requests for its Retry-After
+	b = newBucket(0, func() time.Time { return now })
+	b.observe(&http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{"Retry-After": {"3"}}})
+	assert.Equal(t, 3*time.Second, b.reserve())
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	wait, ok := retryAfter(http.Header{"Retry-After": {"Sun, 01 Mar 2026 09:00:30 GMT"}}, now)
+	assert.True(t, ok)
+	assert.Equal(t, 30*time.Second, wait)
+
+	wait, _ = retryAfter(http.Header{"Retry-After": {"3600"}}, now)
+	assert.Equal(t, maxRetryAfter, wait)
+
+	_, ok = retryAfter(http.Header{}, now)
+	assert.False(t, ok)
+}
+
+func TestNewWithMaxRetries(t *testing.T) {
+	t.Setenv("FIZZ_CACHE_DIR", t.TempDir())
+	defer installTransport(http.DefaultTransport)
+
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	cfg := &config.Config{Token: "token", Account: "1", BaseURL: srv.URL}
+	c, err := New(cfg, false, WithMaxRetries(0))
+	require.NoError(t, err)
+	_, err = c.Boards.List(context.Background())
+	require.Error(t, err)
+	assert.Equal(t, int32(1), calls.Load(), "neither fizz nor libfizz-go retries")
+}
@@ -0,0 +1,156 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/visionik/fizz/internal/config"
+	"github.com/visionik/libfizz-go/fizzy"
+)
+
+// CardPager fetches a card list one page at a time, following the API's
+// Link header. libfizz-go's Cards.List ignores that header, so its ListAll
+// only ever returns the first page.
+type CardPager struct {
+	c       *Client
+	query   url.Values
+	perPage int
+	next    string
+	done    bool
+}
+
+// CardPages returns a pager for the cards matching opts, starting at page,
+// a cursor from an earlier page or empty for the first. perPage asks the
+// API for that many cards per page, or its own default when 0.
+func (c *Client) CardPages(opts *fizzy.CardListOptions, page string, perPage int) *CardPager {
+	query := url.Values{}
+	if opts != nil {
+		if opts.BoardID != "" {
+			query.Set("board_id", opts.BoardID)
+		}
+		if opts.Status != "" {
+			query.Set("status", opts.Status)
+		}
+		if opts.ColumnID != "" {
+			query.Set("column_id", opts.ColumnID)
+		}
+		if len(opts.TagIDs) > 0 {
+			query.Set("tag_ids", strings.Join(opts.TagIDs, ","))
+		}
+	}
+	return &CardPager{c: c, query: query, perPage: perPage, next: page}
+}
+
+// More reports whether there is a page left to fetch
+func (p *CardPager) More() bool {
+	return !p.done
+}
+
+// Cursor returns the page Next fetches, which --page accepts, or empty
+// once the last page is fetched
+func (p *CardPager) Cursor() string {
+	if p.done {
+		return ""
+	}
+	return p.next
+}
+
+// Next fetches the next page
+func (p *CardPager) Next(ctx context.Context) ([]fizzy.Card, error) {
+	if p.done {
+		return nil, nil
+	}
+	query := url.Values{}
+	for key, values := range p.query {
+		query[key] = values
+	}
+	if p.next != "" {
+		query.Set("page", p.next)
+	}
+	if p.perPage > 0 {
+		query.Set("per_page", strconv.Itoa(p.perPage))
+	}
+
+	var cards []fizzy.Card
+	header, err := p.c.get(ctx, "/"+p.c.Account+"/cards.json", query, &cards)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cards: %w", err)
+	}
+	p.next = nextPage(header.Get("Link"))
+	p.done = p.next == ""
+	return cards, nil
+}
+
+// ListAllCards returns the cards matching opts from every page
+func (c *Client) ListAllCards(ctx context.Context, opts *fizzy.CardListOptions) ([]fizzy.Card, error) {
+	pager := c.CardPages(opts, "", 0)
+	var cards []fizzy.Card
+	for pager.More() {
+		page, err := pager.Next(ctx)
+		if err != nil {
+			return nil, err
+		}
+		cards = append(cards, page...)
+	}
+	return cards, nil
+}
+
+// get makes an API read the way libfizz-go does, decoding the response into
+// result and returning its headers
+func (c *Client) get(ctx context.Context, path string, query url.Values, result interface{}) (http.Header, error) {
+	base := c.BaseURL
+	if base == "" {
+		base = config.DefaultBaseURL
+	}
+	target := strings.TrimSuffix(base, "/") + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.Token)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		body, _ := io.ReadAll(resp.Body)
+		message := string(body)
+		if message == "" {
+			message = http.StatusText(resp.StatusCode)
+		}
+		return nil, &fizzy.FizzyError{StatusCode: resp.StatusCode, Message: message, RequestID: resp.Header.Get("X-Request-Id")}
+	}
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return resp.Header, nil
+}
+
+// nextPage returns the page parameter of a Link header's next URL, or empty
+// if there is no next page
+func nextPage(link string) string {
+	for _, part := range strings.Split(link, ",") {
+		target, params, ok := strings.Cut(part, ";")
+		if !ok || !strings.Contains(strings.ReplaceAll(params, " ", ""), `rel="next"`) {
+			continue
+		}
+		u, err := url.Parse(strings.Trim(strings.TrimSpace(target), "<>"))
+		if err != nil {
+			return ""
+		}
+		return u.Query().Get("page")
+	}
+	return ""
+}
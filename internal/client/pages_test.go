@@ -0,0 +1,14 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNextPage(t *testing.T) {
+	assert.Equal(t, "3", nextPage(`<https://app.fizzy.do/1/cards.json?board_id=b&page=3>; rel="next"`))
+	assert.Equal(t, "abc", nextPage(`<https://app.fizzy.do/1/cards.json?page=1>; rel="prev", <https://app.fizzy.do/1/cards.json?page=abc>; rel="next"`))
+	assert.Empty(t, nextPage(`<https://app.fizzy.do/1/cards.json?page=1>; rel="prev"`))
+	assert.Empty(t, nextPage(""))
+}
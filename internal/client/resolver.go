@@ -2,8 +2,11 @@ package client
 
 import (
 	"context"
-)
+	"fmt"
+	"strings"
 
+	"github.com/visionik/libfizz-go/fizzy"
+)
 
 // ResolveCardID takes a card identifier (number or UUID) and returns the card ID
 // The Fizzy API uses string IDs, so we just return the input as-is
@@ -11,3 +14,52 @@ func (c *Client) ResolveCardID(ctx context.Context, input string) (string, error
 	// Just return the input - API accepts both IDs and numbers as strings
 	return input, nil
 }
+
+// Me returns the signed-in user for the configured account
+func (c *Client) Me(ctx context.Context) (*fizzy.User, error) {
+	identity, err := c.Identity.Get(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get identity: %w", err)
+	}
+	for _, account := range identity.Accounts {
+		if account.User == nil {
+			continue
+		}
+		if account.ID == c.Account || strings.Trim(account.Slug, "/") == c.Account || len(identity.Accounts) == 1 {
+			return account.User, nil
+		}
+	}
+	return nil, fmt.Errorf("no user found for account %s", c.Account)
+}
+
+// ResolveUserID returns the user ID for input, which may be "me"
+func (c *Client) ResolveUserID(ctx context.Context, input string) (string, error) {
+	if input != "me" {
+		return input, nil
+	}
+	me, err := c.Me(ctx)
+	if err != nil {
+		return "", err
+	}
+	return me.ID, nil
+}
+
+// ResolveColumn finds a column on a board by ID or case-insensitive name
+func (c *Client) ResolveColumn(ctx context.Context, boardID, input string) (*fizzy.Column, error) {
+	columns, err := c.Columns.List(ctx, boardID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list columns: %w", err)
+	}
+	for i := range columns {
+		if columns[i].ID == input {
+			return &columns[i], nil
+		}
+	}
+	for i := range columns {
+		if strings.EqualFold(columns[i].Name, input) {
+			return &columns[i], nil
+		}
+	}
+	return nil, fmt.Errorf("column %q not found on board %s", input, boardID)
+}
+
@@ -0,0 +1,129 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Attachment is an uploaded file, ready to embed in a rich text body
+type Attachment struct {
+	BlobID      string
+	Filename    string
+	ContentType string
+}
+
+// HTML returns the Action Text markup that embeds the attachment
+func (a Attachment) HTML() string {
+	return fmt.Sprintf(`<action-text-attachment sgid="%s" content-type="%s" filename="%s"></action-text-attachment>`,
+		html.EscapeString(a.BlobID), html.EscapeString(a.ContentType), html.EscapeString(a.Filename))
+}
+
+// Upload uploads the file at path under its own name
+func (c *Client) Upload(ctx context.Context, path string) (Attachment, error) {
+	contentType, err := detectContentType(path)
+	if err != nil {
+		return Attachment{}, err
+	}
+	blobID, err := c.Uploads.UploadFile(ctx, path, contentType)
+	if err != nil {
+		return Attachment{}, fmt.Errorf("failed to upload %s: %w", filepath.Base(path), err)
+	}
+	return Attachment{BlobID: blobID, Filename: filepath.Base(path), ContentType: contentType}, nil
+}
+
+// detectContentType guesses a file's type from its extension, or else from
+// its first bytes
+func detectContentType(path string) (string, error) {
+	if t := mime.TypeByExtension(filepath.Ext(path)); t != "" {
+		return t, nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to open file: %w", err)
+	}
+	defer f.Close()
+	head := make([]byte, 512)
+	n, err := io.ReadFull(f, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return "", fmt.Errorf("failed to read file: %w", err)
+	}
+	return http.DetectContentType(head[:n]), nil
+}
+
+// WithAttachments appends attachments to a rich text body. A body that does
+// not start with a tag is plain text and becomes paragraphs first.
+func WithAttachments(body string, attachments []Attachment) string {
+	var b strings.Builder
+	if strings.HasPrefix(strings.TrimSpace(body), "<") {
+		b.WriteString(body)
+	} else {
+		b.WriteString(Paragraphs(body))
+	}
+	for _, a := range attachments {
+		b.WriteString(a.HTML())
+	}
+	return b.String()
+}
+
+// Paragraphs turns plain text into escaped rich text paragraphs, one per
+// blank-line separated block, keeping single line breaks
+func Paragraphs(text string) string {
+	var b strings.Builder
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	for _, para := range strings.Split(strings.TrimSpace(text), "\n\n") {
+		if para = strings.Trim(para, "\n"); para == "" {
+			continue
+		}
+		lines := strings.Split(para, "\n")
+		for i := range lines {
+			lines[i] = html.EscapeString(lines[i])
+		}
+		fmt.Fprintf(&b, "<p>%s</p>", strings.Join(lines, "<br>"))
+	}
+	return b.String()
+}
+
+type progressKey struct{}
+
+// WithUploadProgress returns a context whose uploads call fn with the bytes
+// of file contents sent so far
+func WithUploadProgress(ctx context.Context, fn func(sent int64)) context.Context {
+	return context.WithValue(ctx, progressKey{}, fn)
+}
+
+// progressTransport reports the contents sent by uploads whose context came
+// from WithUploadProgress. libfizz-go sends them as a PUT to the direct
+// upload URL.
+type progressTransport struct {
+	next http.RoundTripper
+}
+
+func (t progressTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	fn, ok := req.Context().Value(progressKey{}).(func(int64))
+	if !ok || req.Method != http.MethodPut || req.Body == nil {
+		return t.next.RoundTrip(req)
+	}
+	req = req.Clone(req.Context())
+	req.Body = &progressBody{ReadCloser: req.Body, report: fn}
+	return t.next.RoundTrip(req)
+}
+
+type progressBody struct {
+	io.ReadCloser
+	sent   int64
+	report func(int64)
+}
+
+func (b *progressBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.sent += int64(n)
+	b.report(b.sent)
+	return n, err
+}
@@ -0,0 +1,49 @@
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithAttachments(t *testing.T) {
+	a := Attachment{BlobID: "blob\"1", Filename: "a&b.png", ContentType: "image/png"}
+	assert.Equal(t, `<p>One<br>two</p><p>x &lt; y</p><action-text-attachment sgid="blob&#34;1" content-type="image/png" filename="a&amp;b.png"></action-text-attachment>`,
+		WithAttachments("One\ntwo\n\nx < y", []Attachment{a}))
+	assert.Equal(t, "<div>Rich</div>"+a.HTML(), WithAttachments("<div>Rich</div>", []Attachment{a}))
+}
+
+func TestParagraphs(t *testing.T) {
+	assert.Equal(t, "<p>**Steps**<br>1. Run &lt;cmd&gt;</p><p>Imported from a &amp; b</p>",
+		Paragraphs("**Steps**\r\n1. Run <cmd>\r\n\r\n\n\nImported from a & b\n"))
+	assert.Equal(t, "<p>&lt;b&gt;not rich&lt;/b&gt;</p>", Paragraphs("<b>not rich</b>"))
+	assert.Equal(t, "", Paragraphs(""))
+}
+
+func TestProgressTransport(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+	}))
+	defer srv.Close()
+
+	var sent []int64
+	ctx := WithUploadProgress(context.Background(), func(n int64) { sent = append(sent, n) })
+	hc := &http.Client{Transport: progressTransport{next: http.DefaultTransport}}
+	for _, method := range []string{http.MethodPost, http.MethodPut} {
+		req, err := http.NewRequestWithContext(ctx, method, srv.URL, strings.NewReader("0123456789"))
+		require.NoError(t, err)
+		resp, err := hc.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+	}
+
+	// Only the PUT carries upload contents
+	require.NotEmpty(t, sent)
+	assert.Equal(t, int64(10), sent[len(sent)-1])
+}
@@ -0,0 +1,31 @@
+// Package clipboard copies text to the system clipboard through the terminal
+// using the OSC 52 escape sequence, which also works over SSH.
+package clipboard
+
+import (
+	"encoding/base64"
+	"io"
+	"os"
+	"strings"
+)
+
+// Sequence returns the OSC 52 escape sequence that sets the clipboard to
+// text, wrapped for tmux or screen when running inside one
+func Sequence(text string) string {
+	seq := "\x1b]52;c;" + base64.StdEncoding.EncodeToString([]byte(text)) + "\a"
+
+	switch {
+	case os.Getenv("TMUX") != "":
+		// tmux passes through DCS sequences with their escapes doubled
+		return "\x1bPtmux;" + strings.ReplaceAll(seq, "\x1b", "\x1b\x1b") + "\x1b\\"
+	case strings.HasPrefix(os.Getenv("TERM"), "screen"):
+		return "\x1bP" + seq + "\x1b\\"
+	}
+	return seq
+}
+
+// Copy writes the sequence for text to w, which should be the terminal
+func Copy(w io.Writer, text string) error {
+	_, err := io.WriteString(w, Sequence(text))
+	return err
+}
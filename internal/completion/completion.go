@@ -0,0 +1,108 @@
+// Package completion caches the live values offered by shell completion,
+// such as board IDs and card numbers, so pressing tab does not wait on the
+// API every time.
+package completion
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/visionik/fizz/internal/config"
+)
+
+// DefaultTTL is how long cached candidates are offered before they are
+// fetched again
+const DefaultTTL = 2 * time.Minute
+
+// Candidate is one completion value with an optional description
+type Candidate struct {
+	Value       string `json:"value"`
+	Description string `json:"description,omitempty"`
+}
+
+// String formats c the way cobra expects completions: the value, then a tab
+// and the description if there is one
+func (c Candidate) String() string {
+	if c.Description == "" {
+		return c.Value
+	}
+	// A newline or tab in a title would break the completion protocol
+	desc := strings.Join(strings.Fields(c.Description), " ")
+	return c.Value + "\t" + desc
+}
+
+// Filter returns the candidates whose values start with prefix, formatted
+// for cobra
+func Filter(candidates []Candidate, prefix string) []string {
+	var out []string
+	for _, c := range candidates {
+		if strings.HasPrefix(c.Value, prefix) {
+			out = append(out, c.String())
+		}
+	}
+	return out
+}
+
+// Cache keeps candidates in files named by a hash of their key
+type Cache struct {
+	Dir string
+	TTL time.Duration
+}
+
+// entry is the file format of a cached list
+type entry struct {
+	SavedAt    time.Time   `json:"saved_at"`
+	Candidates []Candidate `json:"candidates"`
+}
+
+// DefaultCache returns the cache in the completion directory of the fizz
+// cache directory
+func DefaultCache() (*Cache, error) {
+	dir, err := config.CacheDir()
+	if err != nil {
+		return nil, err
+	}
+	return &Cache{Dir: filepath.Join(dir, "completion"), TTL: DefaultTTL}, nil
+}
+
+// Get returns the candidates cached under key, calling fetch and caching
+// its result when there are none or they are older than the TTL. A cache
+// that cannot be read or written just means fetching every time.
+func (c *Cache) Get(key string, fetch func() ([]Candidate, error)) ([]Candidate, error) {
+	path := c.path(key)
+	if data, err := os.ReadFile(path); err == nil {
+		var e entry
+		if json.Unmarshal(data, &e) == nil && time.Since(e.SavedAt) < c.TTL {
+			return e.Candidates, nil
+		}
+	}
+
+	candidates, err := fetch()
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(entry{SavedAt: time.Now(), Candidates: candidates})
+	if err == nil && os.MkdirAll(c.Dir, 0o700) == nil {
+		// Write and rename so a concurrent completion never reads half a file
+		if f, err := os.CreateTemp(c.Dir, "*.tmp"); err == nil {
+			_, err = f.Write(data)
+			if f.Close() == nil && err == nil {
+				err = os.Rename(f.Name(), path)
+			}
+			if err != nil {
+				os.Remove(f.Name())
+			}
+		}
+	}
+	return candidates, nil
+}
+
+func (c *Cache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.Dir, hex.EncodeToString(sum[:12])+".json")
+}
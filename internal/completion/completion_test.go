@@ -0,0 +1,35 @@
+package completion
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCache(t *testing.T) {
+	c := &Cache{Dir: t.TempDir(), TTL: time.Hour}
+	calls := 0
+	fetch := func() ([]Candidate, error) {
+		calls++
+		return []Candidate{{Value: "12", Description: "Fix\tthe\nlogin"}, {Value: "130"}}, nil
+	}
+
+	got, err := c.Get("cards", fetch)
+	require.NoError(t, err)
+	got, err = c.Get("cards", fetch)
+	require.NoError(t, err)
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, []string{"12\tFix the login", "130"}, Filter(got, "1"))
+	assert.Equal(t, []string{"130"}, Filter(got, "13"))
+
+	// Expired entries are fetched again, and errors are not cached
+	c.TTL = 0
+	_, err = c.Get("cards", func() ([]Candidate, error) { return nil, errors.New("offline") })
+	assert.EqualError(t, err, "offline")
+	_, err = c.Get("cards", fetch)
+	require.NoError(t, err)
+	assert.Equal(t, 2, calls)
+}
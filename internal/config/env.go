@@ -3,18 +3,64 @@ package config
 import (
 	"fmt"
 	"os"
+	"strconv"
 )
 
+// Placeholder credentials used when replaying a cassette without real ones
+const (
+	ReplayToken   = "replay-token"
+	ReplayAccount = "replay-account"
+)
+
+// DefaultBaseURL is the Fizzy API used when FIZZY_BASE_URL is not set
+const DefaultBaseURL = "https://app.fizzy.do"
+
+// DefaultConcurrency is the number of API requests in flight at once when
+// FIZZ_CONCURRENCY is not set
+const DefaultConcurrency = 4
+
 // Config holds the application configuration
 type Config struct {
 	Token   string
 	Account string
+
+	// BaseURL overrides the Fizzy API endpoint (FIZZY_BASE_URL)
+	BaseURL string
+
+	// RecordPath captures HTTP traffic to a cassette file (FIZZ_RECORD)
+	RecordPath string
+
+	// ReplayPath serves HTTP traffic from a cassette file (FIZZ_REPLAY)
+	ReplayPath string
+
+	// Concurrency caps the API requests in flight at once (FIZZ_CONCURRENCY)
+	Concurrency int
+
+	// RateLimit caps API requests per second; 0 leaves pacing to the API's
+	// rate limit headers (FIZZ_RATE_LIMIT)
+	RateLimit float64
 }
 
 // LoadFromEnv loads configuration from environment variables
 func LoadFromEnv() (*Config, error) {
 	token := os.Getenv("FIZZY_TOKEN")
 	account := os.Getenv("FIZZY_ACCOUNT")
+	recordPath := os.Getenv("FIZZ_RECORD")
+	replayPath := os.Getenv("FIZZ_REPLAY")
+
+	if recordPath != "" && replayPath != "" {
+		return nil, fmt.Errorf("FIZZ_RECORD and FIZZ_REPLAY cannot be used together")
+	}
+
+	// Replayed traffic never reaches the API, so credentials are optional
+	if replayPath != "" {
+		if token == "" {
+			token = ReplayToken
+		}
+		if account == "" {
+			account = ReplayAccount
+		}
+	}
 
 	if token == "" {
 		return nil, fmt.Errorf(`FIZZY_TOKEN environment variable is not set
@@ -40,8 +86,27 @@ Setup instructions:
 You can add this to your ~/.zshrc or ~/.bashrc to make it permanent.`)
 	}
 
-	return &Config{
-		Token:   token,
-		Account: account,
-	}, nil
+	cfg := &Config{
+		Token:       token,
+		Account:     account,
+		BaseURL:     os.Getenv("FIZZY_BASE_URL"),
+		RecordPath:  recordPath,
+		ReplayPath:  replayPath,
+		Concurrency: DefaultConcurrency,
+	}
+	if value := os.Getenv("FIZZ_CONCURRENCY"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 {
+			return nil, fmt.Errorf("FIZZ_CONCURRENCY must be a whole number of at least 1, not %q", value)
+		}
+		cfg.Concurrency = n
+	}
+	if value := os.Getenv("FIZZ_RATE_LIMIT"); value != "" {
+		rate, err := strconv.ParseFloat(value, 64)
+		if err != nil || rate < 0 {
+			return nil, fmt.Errorf("FIZZ_RATE_LIMIT must be requests per second, such as 5 or 0.5, not %q", value)
+		}
+		cfg.RateLimit = rate
+	}
+	return cfg, nil
 }
@@ -0,0 +1,213 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FileName is the user configuration file inside the config directory
+const FileName = "config.yaml"
+
+// ProjectFileName is the per-repository configuration file, found in the
+// working directory or any parent
+const ProjectFileName = ".fizz.yaml"
+
+// Settings is the optional YAML configuration. Values in a project's
+// .fizz.yaml override those in the user's config file.
+type Settings struct {
+	Git GitSettings `yaml:"git,omitempty"`
+	// Schedules are the rules `fizz schedule run` creates cards from. A
+	// project file's list replaces the user's.
+	Schedules []ScheduleRule `yaml:"schedules,omitempty"`
+	// Housekeeping are the rules `fizz housekeep` applies to idle cards. A
+	// project file's list replaces the user's.
+	Housekeeping []HousekeepRule `yaml:"housekeeping,omitempty"`
+	WIP          WIPSettings     `yaml:"wip,omitempty"`
+	// Aliases are extra commands, each running one or more fizz commands. A
+	// project file's aliases add to the user's.
+	Aliases map[string]Alias `yaml:"aliases,omitempty"`
+}
+
+// Alias is a fizz command line without the "fizz", or a list of them run in
+// order, where $1, $2… stand for the alias's arguments
+type Alias struct {
+	Steps []string
+}
+
+// UnmarshalYAML accepts a single command or a list of them
+func (a *Alias) UnmarshalYAML(value *yaml.Node) error {
+	switch value.Kind {
+	case yaml.ScalarNode:
+		a.Steps = []string{value.Value}
+		return nil
+	case yaml.SequenceNode:
+		return value.Decode(&a.Steps)
+	default:
+		return fmt.Errorf("line %d: an alias is a command or a list of commands", value.Line)
+	}
+}
+
+// GitSettings configures the git integration
+type GitSettings struct {
+	// BranchTemplate names branches created by `fizz git start`
+	BranchTemplate string `yaml:"branch_template,omitempty"`
+	// StartColumn is the column `fizz git start` moves cards to, by name or ID
+	StartColumn string `yaml:"start_column,omitempty"`
+}
+
+// WIPSettings declares work in progress limits, which Fizzy does not have
+type WIPSettings struct {
+	// Enforce makes `cards move` and `cards assign` refuse to exceed a limit
+	// without --force; otherwise they only warn
+	Enforce bool `yaml:"enforce,omitempty"`
+	// Limits replace the user's when set in a project file
+	Limits []WIPLimit `yaml:"limits,omitempty"`
+}
+
+// WIPLimit caps the open cards in a column, in total and per assignee. A
+// limit without a board applies to columns of that name on every board.
+type WIPLimit struct {
+	Board     string `yaml:"board,omitempty" json:"board,omitempty"`
+	Column    string `yaml:"column" json:"column"`
+	Limit     int    `yaml:"limit,omitempty" json:"limit,omitempty"`
+	PerPerson int    `yaml:"per_person,omitempty" json:"per_person,omitempty"`
+}
+
+// ScheduleRule creates a card whenever its cron expression matches. The card
+// comes from Template, if set, with the other fields overriding it.
+type ScheduleRule struct {
+	Name     string `yaml:"name" json:"name"`
+	Cron     string `yaml:"cron" json:"cron"`
+	Timezone string `yaml:"timezone,omitempty" json:"timezone,omitempty"`
+
+	Template  string            `yaml:"template,omitempty" json:"template,omitempty"`
+	Vars      map[string]string `yaml:"vars,omitempty" json:"vars,omitempty"`
+	Board     string            `yaml:"board,omitempty" json:"board,omitempty"`
+	Title     string            `yaml:"title,omitempty" json:"title,omitempty"`
+	Body      string            `yaml:"body,omitempty" json:"body,omitempty"`
+	Tags      []string          `yaml:"tags,omitempty" json:"tags,omitempty"`
+	Assignees []string          `yaml:"assignees,omitempty" json:"assignees,omitempty"`
+	Column    string            `yaml:"column,omitempty" json:"column,omitempty"`
+	Steps     []string          `yaml:"steps,omitempty" json:"steps,omitempty"`
+}
+
+// HousekeepRule acts on open cards nobody has touched for IdleDays. Board,
+// Column and Tags narrow the cards it looks at.
+type HousekeepRule struct {
+	Name     string   `yaml:"name" json:"name"`
+	Board    string   `yaml:"board,omitempty" json:"board,omitempty"`
+	Column   string   `yaml:"column,omitempty" json:"column,omitempty"`
+	Tags     []string `yaml:"tags,omitempty" json:"tags,omitempty"`
+	IdleDays int      `yaml:"idle_days" json:"idle_days"`
+
+	Actions HousekeepActions `yaml:"actions" json:"actions"`
+}
+
+// HousekeepActions are what a housekeeping rule does to each matching card,
+// in this order
+type HousekeepActions struct {
+	Tag      string `yaml:"tag,omitempty" json:"tag,omitempty"`
+	Comment  string `yaml:"comment,omitempty" json:"comment,omitempty"`
+	Postpone bool   `yaml:"postpone,omitempty" json:"postpone,omitempty"`
+	Close    bool   `yaml:"close,omitempty" json:"close,omitempty"`
+}
+
+// Default settings
+const (
+	DefaultBranchTemplate = "{{.Number}}-{{slug .Title}}"
+)
+
+// Dir returns the fizz configuration directory: $FIZZ_CONFIG_DIR, or fizz
+// inside the user's config directory (~/.config/fizz on Linux)
+func Dir() (string, error) {
+	if dir := os.Getenv("FIZZ_CONFIG_DIR"); dir != "" {
+		return dir, nil
+	}
+	base, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find config directory: %w", err)
+	}
+	return filepath.Join(base, "fizz"), nil
+}
+
+// CacheDir returns the fizz cache directory: $FIZZ_CACHE_DIR, or fizz inside
+// the user's cache directory (~/.cache/fizz on Linux)
+func CacheDir() (string, error) {
+	if dir := os.Getenv("FIZZ_CACHE_DIR"); dir != "" {
+		return dir, nil
+	}
+	base, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find cache directory: %w", err)
+	}
+	return filepath.Join(base, "fizz"), nil
+}
+
+// ProjectFile returns the nearest .fizz.yaml in the working directory or its
+// parents, or "" if there is none
+func ProjectFile() string {
+	return FindUp(ProjectFileName)
+}
+
+// FindUp returns the nearest path named name in the working directory or its
+// parents, or "" if there is none
+func FindUp(name string) string {
+	dir, err := os.Getwd()
+	if err != nil {
+		return ""
+	}
+	for {
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// LoadSettings reads the user config file and the project file, if they
+// exist, and fills in defaults
+func LoadSettings() (*Settings, error) {
+	settings := &Settings{}
+
+	dir, err := Dir()
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range []string{filepath.Join(dir, FileName), ProjectFile()} {
+		if err := readSettings(path, settings); err != nil {
+			return nil, err
+		}
+	}
+
+	if settings.Git.BranchTemplate == "" {
+		settings.Git.BranchTemplate = DefaultBranchTemplate
+	}
+	return settings, nil
+}
+
+// readSettings decodes path over settings, so only the keys it sets change
+func readSettings(path string, settings *Settings) error {
+	if path == "" {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read config: %w", err)
+	}
+	if err := yaml.Unmarshal(data, settings); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return nil
+}
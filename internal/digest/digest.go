@@ -0,0 +1,320 @@
+// Package digest summarizes what happened on boards over a period, grouped
+// by person and board, for pasting into chat or email.
+package digest
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/visionik/fizz/internal/history"
+	"github.com/visionik/fizz/internal/kanban"
+	"github.com/visionik/libfizz-go/fizzy"
+)
+
+// Kinds of activity, in the order a digest lists them
+const (
+	Created   = "created"
+	Closed    = "closed"
+	Moved     = "moved"
+	Commented = "commented"
+	Step      = "step"
+)
+
+var kindOrder = map[string]int{Created: 0, Closed: 1, Moved: 2, Commented: 3, Step: 4}
+
+// Unassigned is the person activity on cards without assignees is listed
+// under
+const Unassigned = "Unassigned"
+
+// Activity is one thing that happened to a card
+type Activity struct {
+	Kind     string    `json:"kind"`
+	PersonID string    `json:"person_id,omitempty"`
+	Person   string    `json:"person"`
+	Board    string    `json:"board"`
+	Card     int       `json:"card"`
+	Title    string    `json:"title"`
+	Golden   bool      `json:"golden,omitempty"`
+	At       time.Time `json:"at"`
+	// Detail is the move's columns, the comment's first line or the step
+	Detail string `json:"detail,omitempty"`
+	// event identifies what happened, which is shared by the activities of
+	// each person involved
+	event string
+}
+
+// Summary counts what happened, once however many people were involved
+type Summary struct {
+	Created  int `json:"created"`
+	Closed   int `json:"closed"`
+	Moved    int `json:"moved"`
+	Comments int `json:"comments"`
+	Steps    int `json:"steps"`
+	// Golden counts golden cards with activity
+	Golden int `json:"golden"`
+	// MovesKnown is false without history to tell where cards were
+	MovesKnown bool `json:"moves_known"`
+}
+
+// Digest is the activity between two times
+type Digest struct {
+	Since      time.Time  `json:"since"`
+	Until      time.Time  `json:"until"`
+	Summary    Summary    `json:"summary"`
+	Activities []Activity `json:"activities"`
+}
+
+// CardActivity is a card with the comments and steps it has now
+type CardActivity struct {
+	Card     fizzy.Card
+	Comments []fizzy.Comment
+	Steps    []fizzy.Step
+}
+
+// Input is what a digest is built from
+type Input struct {
+	Since, Until time.Time
+	Cards        []CardActivity
+	// Boards names boards by ID for cards that do not embed theirs
+	Boards map[string]string
+	// Columns names columns by ID
+	Columns map[string]string
+	// Before has, per board ID, the latest snapshot taken at or before
+	// Since; boards without one have no moves
+	Before map[string]*history.Snapshot
+}
+
+// Active reports whether a card saw any activity at or after since, so
+// its comments and steps are worth fetching
+func Active(card fizzy.Card, since time.Time) bool {
+	for _, t := range []*time.Time{card.LastActiveAt, &card.UpdatedAt, &card.CreatedAt, card.ClosedAt} {
+		if t != nil && !t.Before(since) {
+			return true
+		}
+	}
+	return false
+}
+
+// Build collects the activity in the input's period
+func Build(in Input) *Digest {
+	d := &Digest{Since: in.Since, Until: in.Until, Activities: []Activity{}}
+	within := func(t time.Time) bool { return !t.Before(in.Since) && !t.After(in.Until) }
+
+	for _, ca := range in.Cards {
+		card := ca.Card
+		board := in.Boards[kanban.BoardID(&card)]
+		if card.Board != nil && card.Board.Name != "" {
+			board = card.Board.Name
+		}
+		base := Activity{Board: board, Card: card.Number, Title: card.Title, Golden: card.Golden}
+		// add records an activity for each person it belongs to
+		add := func(kind string, at time.Time, detail string, people []fizzy.User) {
+			if len(people) == 0 {
+				people = []fizzy.User{{Name: Unassigned}}
+			}
+			event := fmt.Sprintf("%s %s %s %s", kind, card.ID, at.Format(time.RFC3339Nano), detail)
+			for _, p := range people {
+				a := base
+				a.Kind, a.At, a.Detail, a.PersonID, a.Person, a.event = kind, at, detail, p.ID, p.Name, event
+				d.Activities = append(d.Activities, a)
+			}
+		}
+
+		if within(card.CreatedAt) {
+			var creator []fizzy.User
+			if card.Creator != nil {
+				creator = []fizzy.User{*card.Creator}
+			}
+			add(Created, card.CreatedAt, "", creator)
+		}
+
+		closed := card.ClosedAt != nil && within(*card.ClosedAt)
+		if closed {
+			add(Closed, *card.ClosedAt, "", card.Assignees)
+		}
+
+		if from, to, ok := moved(card, in); ok && !closed {
+			at := in.Until
+			if card.LastActiveAt != nil {
+				at = *card.LastActiveAt
+			}
+			add(Moved, at, from+" → "+to, card.Assignees)
+		}
+
+		for _, c := range ca.Comments {
+			if !within(c.CreatedAt) {
+				continue
+			}
+			var author []fizzy.User
+			if c.Creator != nil {
+				author = []fizzy.User{*c.Creator}
+			}
+			add(Commented, c.CreatedAt, excerpt(c), author)
+		}
+
+		for _, s := range ca.Steps {
+			if s.Completed && within(s.UpdatedAt) {
+				add(Step, s.UpdatedAt, s.Content, card.Assignees)
+			}
+		}
+	}
+
+	sort.SliceStable(d.Activities, func(i, j int) bool {
+		a, b := d.Activities[i], d.Activities[j]
+		if a.Person != b.Person {
+			return personLess(a.Person, b.Person)
+		}
+		if a.Board != b.Board {
+			return a.Board < b.Board
+		}
+		if a.Kind != b.Kind {
+			return kindOrder[a.Kind] < kindOrder[b.Kind]
+		}
+		return a.At.Before(b.At)
+	})
+	d.summarize(len(in.Before) > 0)
+	return d
+}
+
+// summarize counts the digest's activities
+func (d *Digest) summarize(movesKnown bool) {
+	d.Summary = Summary{MovesKnown: movesKnown}
+	events := map[string]bool{}
+	golden := map[int]bool{}
+	for _, a := range d.Activities {
+		if a.Golden {
+			golden[a.Card] = true
+		}
+		if events[a.event] {
+			continue
+		}
+		events[a.event] = true
+		switch a.Kind {
+		case Created:
+			d.Summary.Created++
+		case Closed:
+			d.Summary.Closed++
+		case Moved:
+			d.Summary.Moved++
+		case Commented:
+			d.Summary.Comments++
+		case Step:
+			d.Summary.Steps++
+		}
+	}
+	d.Summary.Golden = len(golden)
+}
+
+// Filter keeps the activities of the given people, by user ID or name
+func (d *Digest) Filter(people []string) {
+	keep := map[string]bool{}
+	for _, p := range people {
+		keep[strings.ToLower(p)] = true
+	}
+	activities := []Activity{}
+	for _, a := range d.Activities {
+		if (a.PersonID != "" && keep[strings.ToLower(a.PersonID)]) || keep[strings.ToLower(a.Person)] {
+			activities = append(activities, a)
+		}
+	}
+	d.Activities = activities
+	d.summarize(d.Summary.MovesKnown)
+}
+
+// moved returns the columns a card moved between since the snapshot
+// before the period
+func moved(card fizzy.Card, in Input) (string, string, bool) {
+	before := in.Before[kanban.BoardID(&card)]
+	if before == nil {
+		return "", "", false
+	}
+	var then *history.Card
+	for i := range before.Cards {
+		if before.Cards[i].ID == card.ID {
+			then = &before.Cards[i]
+			break
+		}
+	}
+	if then == nil {
+		return "", "", false
+	}
+
+	names := map[string]string{}
+	for _, col := range before.Columns {
+		names[col.ID] = col.Name
+	}
+	for id, name := range in.Columns {
+		names[id] = name
+	}
+	from := columnName(then.ColumnID, then.Status, names)
+	to := ""
+	if card.ColumnID != nil {
+		to = *card.ColumnID
+	}
+	to = columnName(to, card.Status, names)
+	return from, to, from != to
+}
+
+func columnName(id, status string, names map[string]string) string {
+	switch {
+	case status == "not_now":
+		return kanban.NotNowColumn
+	case id == "":
+		return kanban.TriageColumn
+	case names[id] != "":
+		return names[id]
+	default:
+		return id
+	}
+}
+
+var tags = regexp.MustCompile(`<[^>]*>`)
+
+// excerpt returns a comment's first line of text, shortened
+func excerpt(c fizzy.Comment) string {
+	text := c.PlainText
+	if text == "" {
+		text = tags.ReplaceAllString(c.Body, " ")
+	}
+	text = strings.TrimSpace(text)
+	if i := strings.IndexByte(text, '\n'); i >= 0 {
+		text = strings.TrimSpace(text[:i]) + " …"
+	}
+	if r := []rune(text); len(r) > 80 {
+		text = string(r[:79]) + "…"
+	}
+	return text
+}
+
+// personLess orders people by name with Unassigned last
+func personLess(a, b string) bool {
+	if (a == Unassigned) != (b == Unassigned) {
+		return b == Unassigned
+	}
+	return a < b
+}
+
+// Line describes an activity in one line, without its person and board
+func (a Activity) Line() string {
+	card := fmt.Sprintf("#%d %s", a.Card, a.Title)
+	if a.Golden {
+		card += " ★"
+	}
+	switch a.Kind {
+	case Created:
+		return "Created " + card
+	case Closed:
+		return "Closed " + card
+	case Moved:
+		return "Moved " + card + ": " + a.Detail
+	case Commented:
+		return "Commented on " + card + ": “" + a.Detail + "”"
+	case Step:
+		return "Completed a step on " + card + ": " + a.Detail
+	default:
+		return card
+	}
+}
@@ -0,0 +1,55 @@
+package digest
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/visionik/libfizz-go/fizzy"
+)
+
+func TestBuild(t *testing.T) {
+	since := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	until := since.Add(24 * time.Hour)
+	at := since.Add(time.Hour)
+	ada := fizzy.User{ID: "u1", Name: "Ada"}
+	bo := fizzy.User{ID: "u2", Name: "Bo"}
+
+	in := Input{
+		Since:  since,
+		Until:  until,
+		Boards: map[string]string{"b1": "Platform"},
+		Cards: []CardActivity{
+			{Card: fizzy.Card{ID: "c1", Number: 1, BoardID: "b1", Title: "Fix *all* the things", CreatedAt: since.Add(-time.Hour), ClosedAt: &at, Assignees: []fizzy.User{bo, ada}}},
+			{
+				Card: fizzy.Card{ID: "c2", Number: 2, BoardID: "b1", Title: "Old", CreatedAt: at, Creator: &ada},
+				Comments: []fizzy.Comment{
+					{PlainText: "Before", CreatedAt: since.Add(-time.Minute), Creator: &bo},
+					{Body: "<p>First line</p>\nSecond", CreatedAt: at, Creator: &bo},
+				},
+			},
+		},
+	}
+	d := Build(in)
+
+	// The closed card counts once though it is listed for both assignees
+	assert.Equal(t, Summary{Created: 1, Closed: 1, Comments: 1}, d.Summary)
+	require.Len(t, d.Activities, 4)
+	assert.Equal(t, "Ada", d.Activities[0].Person)
+	assert.Equal(t, Created, d.Activities[0].Kind)
+	assert.Equal(t, "Commented on #2 Old: “First line …”", d.Activities[3].Line())
+
+	var b strings.Builder
+	require.NoError(t, d.Write(&b, Markdown))
+	assert.Contains(t, b.String(), "- Closed #1 Fix \\*all\\* the things\n")
+	assert.Contains(t, b.String(), "Moves are not shown; run 'fizz sync' daily to record columns.")
+
+	d.Filter([]string{"bo"})
+	assert.Equal(t, Summary{Closed: 1, Comments: 1}, d.Summary)
+
+	b.Reset()
+	require.NoError(t, d.Write(&b, HTML))
+	assert.Contains(t, b.String(), "<h2>Bo</h2>\n<h3>Platform</h3>\n<ul>\n<li>Closed #1 Fix *all* the things</li>\n")
+}
@@ -0,0 +1,119 @@
+package digest
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"strings"
+)
+
+// Styles a digest can be written in
+const (
+	Markdown = "markdown"
+	Plain    = "plain"
+	HTML     = "html"
+)
+
+// Write renders the digest in a style: markdown, plain or html
+func (d *Digest) Write(w io.Writer, style string) error {
+	var b strings.Builder
+	switch style {
+	case Markdown:
+		d.render(&b, "# %s\n\n", "%s\n", "\n## %s\n", "\n### %s\n\n", "- %s\n", markdownEscape)
+	case Plain:
+		d.render(&b, "%s\n", "%s\n", "\n%s\n", "  %s\n", "    - %s\n", nil)
+	case HTML:
+		d.render(&b, "<h1>%s</h1>\n", "<p>%s</p>\n", "<h2>%s</h2>\n", "<h3>%s</h3>\n", "<li>%s</li>\n", html.EscapeString)
+	default:
+		return fmt.Errorf("unsupported digest style: %s (supported: markdown, plain, html)", style)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// render writes the title, summary, people, boards and activity lines with
+// the given line formats, escaping text with escape
+func (d *Digest) render(b *strings.Builder, title, summary, person, board, item string, escape func(string) string) {
+	if escape == nil {
+		escape = func(s string) string { return s }
+	}
+	fmt.Fprintf(b, title, escape(d.Title()))
+	fmt.Fprintf(b, summary, escape(d.SummaryLine()))
+
+	list := strings.HasPrefix(item, "<li>")
+	inList := false
+	closeList := func() {
+		if inList {
+			b.WriteString("</ul>\n")
+			inList = false
+		}
+	}
+	lastPerson, lastBoard := "", ""
+	for i, a := range d.Activities {
+		if i == 0 || a.Person != lastPerson {
+			closeList()
+			fmt.Fprintf(b, person, escape(a.Person))
+			lastPerson, lastBoard = a.Person, ""
+		}
+		if a.Board != lastBoard || i == 0 {
+			closeList()
+			name := a.Board
+			if name == "" {
+				name = "Unknown board"
+			}
+			fmt.Fprintf(b, board, escape(name))
+			lastBoard = a.Board
+		}
+		if list && !inList {
+			b.WriteString("<ul>\n")
+			inList = true
+		}
+		fmt.Fprintf(b, item, escape(a.Line()))
+	}
+	closeList()
+}
+
+// Title names the digest's period
+func (d *Digest) Title() string {
+	return fmt.Sprintf("Digest for %s to %s", d.Since.Local().Format("Mon 2006-01-02 15:04"), d.Until.Local().Format("Mon 2006-01-02 15:04"))
+}
+
+// SummaryLine counts the digest's activity in a sentence
+func (d *Digest) SummaryLine() string {
+	if len(d.Activities) == 0 {
+		return "Nothing happened."
+	}
+	s := d.Summary
+	parts := []string{
+		count(s.Created, "card created", "cards created"),
+		count(s.Closed, "closed", "closed"),
+	}
+	if s.MovesKnown {
+		parts = append(parts, count(s.Moved, "moved", "moved"))
+	}
+	parts = append(parts,
+		count(s.Comments, "comment", "comments"),
+		count(s.Steps, "step completed", "steps completed"),
+	)
+	if s.Golden > 0 {
+		parts = append(parts, count(s.Golden, "golden card ★", "golden cards ★"))
+	}
+	line := strings.Join(parts, ", ") + "."
+	if !s.MovesKnown {
+		line += " Moves are not shown; run 'fizz sync' daily to record columns."
+	}
+	return line
+}
+
+func count(n int, one, many string) string {
+	if n == 1 {
+		return "1 " + one
+	}
+	return fmt.Sprintf("%d %s", n, many)
+}
+
+// markdownEscape escapes the characters that would start markup inside a
+// line of text
+func markdownEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "*", `\*`, "_", `\_`, "`", "\\`", "[", `\[`, "]", `\]`, "<", `\<`).Replace(s)
+}
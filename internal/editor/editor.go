@@ -0,0 +1,189 @@
+// Package editor opens the user's text editor on a temporary file and parses
+// the result, optionally with a front-matter header for card fields.
+package editor
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/mattn/go-isatty"
+	"gopkg.in/yaml.v3"
+)
+
+// ErrEmpty is returned when the edited file is saved empty
+var ErrEmpty = errors.New("aborted: empty file")
+
+// frontMatter delimits the header of a document
+const frontMatter = "---"
+
+// Document is the text being edited: an optional header and a body
+type Document struct {
+	Title string   `yaml:"title"`
+	Tags  []string `yaml:"tags,flow"`
+	Body  string   `yaml:"-"`
+}
+
+// Command returns the editor to run, from $VISUAL, then $EDITOR, then a
+// platform default
+func Command() string {
+	if e := os.Getenv("VISUAL"); e != "" {
+		return e
+	}
+	if e := os.Getenv("EDITOR"); e != "" {
+		return e
+	}
+	if runtime.GOOS == "windows" {
+		return "notepad"
+	}
+	return "vi"
+}
+
+// IsInteractive reports whether in and out are both terminals, so an editor
+// can take over the screen
+func IsInteractive(in io.Reader, out io.Writer) bool {
+	return isTerminal(in) && isTerminal(out)
+}
+
+func isTerminal(v interface{}) bool {
+	f, ok := v.(*os.File)
+	if !ok {
+		return false
+	}
+	return isatty.IsTerminal(f.Fd()) || isatty.IsCygwinTerminal(f.Fd())
+}
+
+// Edit opens the editor on a temporary file containing initial and returns
+// what was saved. The pattern names the file, e.g. "fizz-card-*.md".
+func Edit(initial, pattern string) (string, error) {
+	file, err := os.CreateTemp("", pattern)
+	if err != nil {
+		return "", fmt.Errorf("failed to create temp file: %w", err)
+	}
+	path := file.Name()
+	defer os.Remove(path)
+
+	if _, err := file.WriteString(initial); err != nil {
+		file.Close()
+		return "", fmt.Errorf("failed to write temp file: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return "", fmt.Errorf("failed to write temp file: %w", err)
+	}
+
+	editor := Command()
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		fields := strings.Fields(editor)
+		cmd = exec.Command(fields[0], append(fields[1:], path)...)
+	} else {
+		// Run through the shell, as git does, so $EDITOR may carry arguments
+		cmd = exec.Command("sh", "-c", editor+` "$@"`, editor, path)
+	}
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("editor %q failed: %w", editor, err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read temp file: %w", err)
+	}
+	return string(data), nil
+}
+
+// EditText edits plain text, returning ErrEmpty if nothing was saved
+func EditText(initial, pattern string) (string, error) {
+	text, err := Edit(initial, pattern)
+	if err != nil {
+		return "", err
+	}
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return "", ErrEmpty
+	}
+	return text, nil
+}
+
+// EditDocument edits doc with its title and tags in a front-matter header,
+// returning ErrEmpty if nothing was saved
+func EditDocument(doc Document, pattern string) (Document, error) {
+	text, err := Edit(doc.String(), pattern)
+	if err != nil {
+		return Document{}, err
+	}
+	return Parse(text)
+}
+
+// String renders the document with its front-matter header
+func (d Document) String() string {
+	var buf bytes.Buffer
+	buf.WriteString(frontMatter + "\n")
+
+	header := d
+	if header.Tags == nil {
+		header.Tags = []string{}
+	}
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	enc.Encode(header)
+	enc.Close()
+
+	buf.WriteString(frontMatter + "\n")
+	if d.Body != "" {
+		buf.WriteString(strings.TrimRight(d.Body, "\n") + "\n")
+	}
+	return buf.String()
+}
+
+// Parse reads a document saved by the editor. The header is optional; text
+// without one is all body.
+func Parse(text string) (Document, error) {
+	var doc Document
+	if strings.TrimSpace(text) == "" {
+		return doc, ErrEmpty
+	}
+
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	body := lines
+	if strings.TrimSpace(lines[0]) == frontMatter {
+		end := -1
+		for i := 1; i < len(lines); i++ {
+			if strings.TrimSpace(lines[i]) == frontMatter {
+				end = i
+				break
+			}
+		}
+		if end < 0 {
+			return doc, fmt.Errorf("unterminated front matter: missing closing %q", frontMatter)
+		}
+
+		header := strings.Join(lines[1:end], "\n")
+		if err := yaml.Unmarshal([]byte(header), &doc); err != nil {
+			return doc, fmt.Errorf("failed to parse front matter: %w", err)
+		}
+		body = lines[end+1:]
+	}
+
+	doc.Title = strings.TrimSpace(doc.Title)
+	doc.Body = strings.TrimSpace(strings.Join(body, "\n"))
+	tags := doc.Tags[:0]
+	for _, tag := range doc.Tags {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	doc.Tags = tags
+
+	if doc.Title == "" && doc.Body == "" && len(doc.Tags) == 0 {
+		return doc, ErrEmpty
+	}
+	return doc, nil
+}
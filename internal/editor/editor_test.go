@@ -0,0 +1,38 @@
+package editor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDocumentRoundTrip(t *testing.T) {
+	doc := Document{Title: "Fix: login", Tags: []string{"bug", "auth"}, Body: "Steps\n\n- one\n- two"}
+
+	text := doc.String()
+	assert.Equal(t, "---\ntitle: 'Fix: login'\ntags: [bug, auth]\n---\nSteps\n\n- one\n- two\n", text)
+
+	parsed, err := Parse(text)
+	require.NoError(t, err)
+	assert.Equal(t, doc, parsed)
+}
+
+func TestParse(t *testing.T) {
+	doc, err := Parse("Just a body\n")
+	require.NoError(t, err)
+	assert.Equal(t, Document{Body: "Just a body"}, doc)
+
+	doc, err = Parse("---\r\ntitle: Only a title\r\n---\r\n")
+	require.NoError(t, err)
+	assert.Equal(t, "Only a title", doc.Title)
+
+	_, err = Parse("  \n\n")
+	assert.ErrorIs(t, err, ErrEmpty)
+
+	_, err = Parse("---\ntitle: \"\"\ntags: []\n---\n\n")
+	assert.ErrorIs(t, err, ErrEmpty)
+
+	_, err = Parse("---\ntitle: Missing end\n")
+	assert.EqualError(t, err, `unterminated front matter: missing closing "---"`)
+}
@@ -0,0 +1,783 @@
+package fakefizzy
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/visionik/libfizz-go/fizzy"
+)
+
+func (s *Server) serveMy(w http.ResponseWriter, r *http.Request, parts []string) {
+	switch {
+	case len(parts) == 1 && parts[0] == "identity":
+		me := s.me
+		s.writeJSON(w, r, http.StatusOK, fizzy.Identity{
+			Accounts: []fizzy.Account{{
+				ID:        s.Account,
+				Name:      "Fake Account",
+				Slug:      s.Account,
+				CreatedAt: epoch,
+				User:      &me,
+			}},
+		})
+	case len(parts) == 1 && parts[0] == "notifications":
+		list := make([]fizzy.Notification, 0, len(s.notifications))
+		for _, n := range s.notifications {
+			list = append(list, *n)
+		}
+		s.writeJSON(w, r, http.StatusOK, list)
+	case len(parts) == 2 && parts[0] == "notifications" && parts[1] == "read_all":
+		now := s.now()
+		for _, n := range s.notifications {
+			if n.ReadAt == nil {
+				n.ReadAt = &now
+			}
+		}
+		noContent(w)
+	case len(parts) == 3 && parts[0] == "notifications":
+		for _, n := range s.notifications {
+			if n.ID != parts[1] {
+				continue
+			}
+			switch parts[2] {
+			case "read":
+				now := s.now()
+				n.ReadAt = &now
+			case "unread":
+				n.ReadAt = nil
+			default:
+				writeError(w, http.StatusNotFound, "Not Found")
+				return
+			}
+			noContent(w)
+			return
+		}
+		writeError(w, http.StatusNotFound, "Notification not found")
+	default:
+		writeError(w, http.StatusNotFound, "Not Found")
+	}
+}
+
+func (s *Server) serveBoards(w http.ResponseWriter, r *http.Request, parts []string) {
+	if len(parts) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			list := make([]fizzy.Board, 0, len(s.boards))
+			for _, b := range s.boards {
+				list = append(list, *b)
+			}
+			s.writeJSON(w, r, http.StatusOK, list)
+		case http.MethodPost:
+			var opts fizzy.BoardCreateOptions
+			if err := decode(r, "board", &opts); err != nil || opts.Name == "" {
+				writeError(w, http.StatusUnprocessableEntity, "Name can't be blank")
+				return
+			}
+			board := s.addBoard(opts.Name, opts.Description)
+			created(w, fmt.Sprintf("/%s/boards/%s.json", s.Account, board.ID))
+		default:
+			methodNotAllowed(w)
+		}
+		return
+	}
+
+	board := s.findBoard(parts[0])
+	if board == nil {
+		writeError(w, http.StatusNotFound, "Board not found")
+		return
+	}
+
+	if len(parts) > 1 {
+		switch parts[1] {
+		case "columns":
+			s.serveColumns(w, r, board, parts[2:])
+		case "cards":
+			s.serveBoardCards(w, r, board, parts[2:])
+		default:
+			writeError(w, http.StatusNotFound, "Not Found")
+		}
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		s.writeJSON(w, r, http.StatusOK, board)
+	case http.MethodPatch, http.MethodPut:
+		var opts fizzy.BoardUpdateOptions
+		if err := decode(r, "board", &opts); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if opts.Name != nil {
+			board.Name = *opts.Name
+		}
+		if opts.Description != nil {
+			board.Description = opts.Description
+		}
+		if opts.Position != nil {
+			board.Position = *opts.Position
+		}
+		board.UpdatedAt = s.now()
+		noContent(w)
+	case http.MethodDelete:
+		for i, b := range s.boards {
+			if b == board {
+				s.boards = append(s.boards[:i], s.boards[i+1:]...)
+				break
+			}
+		}
+		cards := s.cards[:0]
+		for _, c := range s.cards {
+			if c.BoardID != board.ID {
+				cards = append(cards, c)
+			}
+		}
+		s.cards = cards
+		noContent(w)
+	default:
+		methodNotAllowed(w)
+	}
+}
+
+func (s *Server) serveColumns(w http.ResponseWriter, r *http.Request, board *fizzy.Board, parts []string) {
+	if len(parts) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			list := []fizzy.Column{}
+			for _, c := range s.columns {
+				if c.BoardID == board.ID {
+					list = append(list, *c)
+				}
+			}
+			s.writeJSON(w, r, http.StatusOK, list)
+		case http.MethodPost:
+			var opts fizzy.ColumnCreateOptions
+			if err := decode(r, "column", &opts); err != nil || opts.Name == "" {
+				writeError(w, http.StatusUnprocessableEntity, "Name can't be blank")
+				return
+			}
+			column := s.addColumn(board.ID, opts.Name)
+			created(w, fmt.Sprintf("/%s/boards/%s/columns/%s", s.Account, board.ID, column.ID))
+		default:
+			methodNotAllowed(w)
+		}
+		return
+	}
+
+	column := s.findColumn(board.ID, parts[0])
+	if column == nil {
+		writeError(w, http.StatusNotFound, "Column not found")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		s.writeJSON(w, r, http.StatusOK, column)
+	case http.MethodPatch, http.MethodPut:
+		var opts fizzy.ColumnUpdateOptions
+		if err := decode(r, "column", &opts); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if opts.Name != nil {
+			column.Name = *opts.Name
+		}
+		if opts.Position != nil {
+			column.Position = *opts.Position
+		}
+		column.UpdatedAt = s.now()
+		s.writeJSON(w, r, http.StatusOK, column)
+	case http.MethodDelete:
+		for i, c := range s.columns {
+			if c == column {
+				s.columns = append(s.columns[:i], s.columns[i+1:]...)
+				break
+			}
+		}
+		for _, c := range s.cards {
+			if c.ColumnID != nil && *c.ColumnID == column.ID {
+				c.ColumnID = nil
+			}
+		}
+		noContent(w)
+	default:
+		methodNotAllowed(w)
+	}
+}
+
+func (s *Server) serveBoardCards(w http.ResponseWriter, r *http.Request, board *fizzy.Board, parts []string) {
+	if len(parts) != 0 || r.Method != http.MethodPost {
+		methodNotAllowed(w)
+		return
+	}
+
+	var opts struct {
+		Title       string  `json:"title"`
+		Description *string `json:"description"`
+	}
+	if err := decode(r, "card", &opts); err != nil || opts.Title == "" {
+		writeError(w, http.StatusUnprocessableEntity, "Title can't be blank")
+		return
+	}
+
+	body := ""
+	if opts.Description != nil {
+		body = *opts.Description
+	}
+	card := s.addCard(board.ID, opts.Title, body)
+	created(w, fmt.Sprintf("/%s/cards/%d.json", s.Account, card.Number))
+}
+
+func (s *Server) serveCards(w http.ResponseWriter, r *http.Request, parts []string) {
+	if len(parts) == 0 {
+		if r.Method != http.MethodGet {
+			methodNotAllowed(w)
+			return
+		}
+		s.listCards(w, r)
+		return
+	}
+
+	card := s.findCard(parts[0])
+	if card == nil {
+		writeError(w, http.StatusNotFound, "Card not found")
+		return
+	}
+
+	if len(parts) == 1 {
+		switch r.Method {
+		case http.MethodGet:
+			s.writeJSON(w, r, http.StatusOK, card)
+		case http.MethodPatch, http.MethodPut:
+			var opts struct {
+				Title       *string `json:"title"`
+				Description *string `json:"description"`
+			}
+			if err := decode(r, "card", &opts); err != nil {
+				writeError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			if opts.Title != nil {
+				card.Title = *opts.Title
+			}
+			if opts.Description != nil {
+				s.setDescription(card, *opts.Description)
+			}
+			s.touch(card)
+			s.writeJSON(w, r, http.StatusOK, card)
+		case http.MethodDelete:
+			for i, c := range s.cards {
+				if c == card {
+					s.cards = append(s.cards[:i], s.cards[i+1:]...)
+					break
+				}
+			}
+			noContent(w)
+		default:
+			methodNotAllowed(w)
+		}
+		return
+	}
+
+	switch parts[1] {
+	case "comments":
+		s.serveComments(w, r, card, parts[2:])
+		return
+	case "steps":
+		s.serveSteps(w, r, card, parts[2:])
+		return
+	case "assignments":
+		if len(parts) != 4 || parts[3] != "toggle" || r.Method != http.MethodPost {
+			writeError(w, http.StatusNotFound, "Not Found")
+			return
+		}
+		s.toggleAssignee(w, card, parts[2])
+		return
+	case "tags":
+		if len(parts) != 4 || parts[3] != "toggle" || r.Method != http.MethodPost {
+			writeError(w, http.StatusNotFound, "Not Found")
+			return
+		}
+		s.toggleTag(card, parts[2])
+		noContent(w)
+		return
+	}
+
+	if len(parts) != 2 {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	now := s.now()
+	switch {
+	case parts[1] == "closure" && r.Method == http.MethodPost:
+		card.Closed = true
+		card.ClosedAt = &now
+		card.Status = "closed"
+	case parts[1] == "closure" && r.Method == http.MethodDelete:
+		card.Closed = false
+		card.ClosedAt = nil
+		card.Status = "published"
+	case parts[1] == "not_now" && r.Method == http.MethodPost:
+		card.Status = "not_now"
+		card.ColumnID = nil
+	case parts[1] == "triage" && r.Method == http.MethodPost:
+		card.Status = "maybe"
+		card.ColumnID = nil
+	case parts[1] == "column" && r.Method == http.MethodPost:
+		var body struct {
+			ColumnID string `json:"column_id"`
+		}
+		if err := decode(r, "", &body); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if s.findColumn(card.BoardID, body.ColumnID) == nil {
+			writeError(w, http.StatusNotFound, "Column not found")
+			return
+		}
+		card.ColumnID = &body.ColumnID
+		card.Status = "published"
+	case parts[1] == "watch" && r.Method == http.MethodPost:
+		s.watchers[card.ID] = true
+	case parts[1] == "unwatch" && r.Method == http.MethodPost:
+		delete(s.watchers, card.ID)
+	case parts[1] == "golden" && r.Method == http.MethodPost:
+		card.Golden = true
+	case parts[1] == "ungolden" && r.Method == http.MethodPost:
+		card.Golden = false
+	default:
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	card.UpdatedAt = now
+	card.LastActiveAt = &now
+	noContent(w)
+}
+
+// listCards serves the filtered, optionally paginated card list
+func (s *Server) listCards(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	boardID := query.Get("board_id")
+	status := query.Get("status")
+	columnID := query.Get("column_id")
+	var tagIDs []string
+	if v := query.Get("tag_ids"); v != "" {
+		tagIDs = strings.Split(v, ",")
+	}
+
+	list := []fizzy.Card{}
+	for _, c := range s.cards {
+		if boardID != "" && c.BoardID != boardID {
+			continue
+		}
+		if columnID != "" && (c.ColumnID == nil || *c.ColumnID != columnID) {
+			continue
+		}
+		if !matchStatus(c, status) || !hasTags(c, tagIDs) {
+			continue
+		}
+		list = append(list, *c)
+	}
+
+	size := s.PageSize
+	if perPage, _ := strconv.Atoi(query.Get("per_page")); perPage > 0 {
+		size = perPage
+	}
+	if size > 0 {
+		page, _ := strconv.Atoi(query.Get("page"))
+		if page < 1 {
+			page = 1
+		}
+		start := (page - 1) * size
+		if start > len(list) {
+			start = len(list)
+		}
+		end := start + size
+		if end < len(list) {
+			next := *r.URL
+			q := next.Query()
+			q.Set("page", strconv.Itoa(page+1))
+			next.RawQuery = q.Encode()
+			w.Header().Set("Link", fmt.Sprintf(`<%s>; rel="next"`, s.url("%s", next.RequestURI())))
+		} else {
+			end = len(list)
+		}
+		list = list[start:end]
+	}
+
+	s.writeJSON(w, r, http.StatusOK, list)
+}
+
+func matchStatus(c *fizzy.Card, status string) bool {
+	switch status {
+	case "":
+		return true
+	case "open":
+		return !c.Closed
+	case "closed":
+		return c.Closed
+	default:
+		return c.Status == status
+	}
+}
+
+func hasTags(c *fizzy.Card, tagIDs []string) bool {
+	for _, id := range tagIDs {
+		found := false
+		for _, t := range c.Tags {
+			if t.ID == id {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *Server) toggleAssignee(w http.ResponseWriter, card *fizzy.Card, userID string) {
+	user := s.findUser(userID)
+	if user == nil {
+		writeError(w, http.StatusNotFound, "User not found")
+		return
+	}
+	for i, a := range card.Assignees {
+		if a.ID == userID {
+			card.Assignees = append(card.Assignees[:i], card.Assignees[i+1:]...)
+			s.touch(card)
+			noContent(w)
+			return
+		}
+	}
+	card.Assignees = append(card.Assignees, *user)
+	s.touch(card)
+	noContent(w)
+}
+
+func (s *Server) toggleTag(card *fizzy.Card, name string) {
+	for i, t := range card.Tags {
+		if t.Name == name {
+			card.Tags = append(card.Tags[:i], card.Tags[i+1:]...)
+			s.touch(card)
+			return
+		}
+	}
+
+	var tag *fizzy.Tag
+	for _, t := range s.tags {
+		if t.Name == name {
+			tag = t
+			break
+		}
+	}
+	if tag == nil {
+		tag = s.addTag(name, "")
+	}
+	card.Tags = append(card.Tags, *tag)
+	s.touch(card)
+}
+
+func (s *Server) serveComments(w http.ResponseWriter, r *http.Request, card *fizzy.Card, parts []string) {
+	if len(parts) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			list := []fizzy.Comment{}
+			for _, c := range s.comments {
+				if c.CardID == card.ID {
+					list = append(list, *c)
+				}
+			}
+			s.writeJSON(w, r, http.StatusOK, list)
+		case http.MethodPost:
+			var opts fizzy.CommentCreateOptions
+			if err := decode(r, "comment", &opts); err != nil || opts.Body == "" {
+				writeError(w, http.StatusUnprocessableEntity, "Body can't be blank")
+				return
+			}
+			comment := s.addComment(card, opts.Body)
+			created(w, fmt.Sprintf("/%s/cards/%d/comments/%s", s.Account, card.Number, comment.ID))
+		default:
+			methodNotAllowed(w)
+		}
+		return
+	}
+
+	comment := s.findComment(card, parts[0])
+	if comment == nil {
+		writeError(w, http.StatusNotFound, "Comment not found")
+		return
+	}
+
+	if len(parts) > 1 {
+		if parts[1] != "reactions" {
+			writeError(w, http.StatusNotFound, "Not Found")
+			return
+		}
+		s.serveReactions(w, r, card, comment, parts[2:])
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		s.writeJSON(w, r, http.StatusOK, comment)
+	case http.MethodPatch, http.MethodPut:
+		var opts fizzy.CommentUpdateOptions
+		if err := decode(r, "comment", &opts); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		comment.Body = plainText(opts.Body)
+		comment.PlainText = plainText(opts.Body)
+		comment.HTML = s.richText(opts.Body)
+		comment.UpdatedAt = s.now()
+		s.writeJSON(w, r, http.StatusOK, comment)
+	case http.MethodDelete:
+		for i, c := range s.comments {
+			if c == comment {
+				s.comments = append(s.comments[:i], s.comments[i+1:]...)
+				break
+			}
+		}
+		card.CommentsCount--
+		delete(s.reactions, comment.ID)
+		noContent(w)
+	default:
+		methodNotAllowed(w)
+	}
+}
+
+func (s *Server) serveReactions(w http.ResponseWriter, r *http.Request, card *fizzy.Card, comment *fizzy.Comment, parts []string) {
+	if len(parts) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			list := []fizzy.Reaction{}
+			for _, re := range s.reactions[comment.ID] {
+				list = append(list, *re)
+			}
+			s.writeJSON(w, r, http.StatusOK, list)
+		case http.MethodPost:
+			var opts fizzy.ReactionCreateOptions
+			if err := decode(r, "reaction", &opts); err != nil || opts.Content == "" {
+				writeError(w, http.StatusUnprocessableEntity, "Content can't be blank")
+				return
+			}
+			reaction := s.addReaction(comment.ID, opts.Content)
+			created(w, fmt.Sprintf("/%s/cards/%d/comments/%s/reactions/%s", s.Account, card.Number, comment.ID, reaction.ID))
+		default:
+			methodNotAllowed(w)
+		}
+		return
+	}
+
+	list := s.reactions[comment.ID]
+	for i, re := range list {
+		if re.ID != parts[0] {
+			continue
+		}
+		switch r.Method {
+		case http.MethodGet:
+			s.writeJSON(w, r, http.StatusOK, re)
+		case http.MethodDelete:
+			s.reactions[comment.ID] = append(list[:i], list[i+1:]...)
+			noContent(w)
+		default:
+			methodNotAllowed(w)
+		}
+		return
+	}
+	writeError(w, http.StatusNotFound, "Reaction not found")
+}
+
+func (s *Server) serveSteps(w http.ResponseWriter, r *http.Request, card *fizzy.Card, parts []string) {
+	if len(parts) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			list := []fizzy.Step{}
+			for _, st := range s.steps {
+				if st.CardID == card.ID {
+					list = append(list, *st)
+				}
+			}
+			s.writeJSON(w, r, http.StatusOK, list)
+		case http.MethodPost:
+			var opts fizzy.StepCreateOptions
+			if err := decode(r, "step", &opts); err != nil || opts.Content == "" {
+				writeError(w, http.StatusUnprocessableEntity, "Content can't be blank")
+				return
+			}
+			step := s.addStep(card, opts.Content, opts.Completed != nil && *opts.Completed)
+			created(w, fmt.Sprintf("/%s/cards/%d/steps/%s", s.Account, card.Number, step.ID))
+		default:
+			methodNotAllowed(w)
+		}
+		return
+	}
+
+	step := s.findStep(card, parts[0])
+	if step == nil {
+		writeError(w, http.StatusNotFound, "Step not found")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		s.writeJSON(w, r, http.StatusOK, step)
+	case http.MethodPatch, http.MethodPut:
+		var opts fizzy.StepUpdateOptions
+		if err := decode(r, "step", &opts); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if opts.Content != nil {
+			step.Content = *opts.Content
+		}
+		if opts.Completed != nil {
+			step.Completed = *opts.Completed
+		}
+		step.UpdatedAt = s.now()
+		s.touch(card)
+		s.writeJSON(w, r, http.StatusOK, step)
+	case http.MethodDelete:
+		for i, st := range s.steps {
+			if st == step {
+				s.steps = append(s.steps[:i], s.steps[i+1:]...)
+				break
+			}
+		}
+		noContent(w)
+	default:
+		methodNotAllowed(w)
+	}
+}
+
+func (s *Server) serveTags(w http.ResponseWriter, r *http.Request, parts []string) {
+	if len(parts) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			list := make([]fizzy.Tag, 0, len(s.tags))
+			for _, t := range s.tags {
+				list = append(list, *t)
+			}
+			s.writeJSON(w, r, http.StatusOK, list)
+		case http.MethodPost:
+			var opts fizzy.TagCreateOptions
+			if err := decode(r, "", &opts); err != nil || opts.Name == "" {
+				writeError(w, http.StatusUnprocessableEntity, "Name can't be blank")
+				return
+			}
+			color := ""
+			if opts.Color != nil {
+				color = *opts.Color
+			}
+			tag := s.addTag(opts.Name, color)
+			created(w, fmt.Sprintf("/%s/tags/%s", s.Account, tag.ID))
+		default:
+			methodNotAllowed(w)
+		}
+		return
+	}
+
+	for _, t := range s.tags {
+		if t.ID == parts[0] && r.Method == http.MethodGet {
+			s.writeJSON(w, r, http.StatusOK, t)
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, "Tag not found")
+}
+
+func (s *Server) serveUsers(w http.ResponseWriter, r *http.Request, parts []string) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w)
+		return
+	}
+	if len(parts) == 0 {
+		s.writeJSON(w, r, http.StatusOK, s.users)
+		return
+	}
+	if user := s.findUser(parts[0]); user != nil {
+		s.writeJSON(w, r, http.StatusOK, user)
+		return
+	}
+	writeError(w, http.StatusNotFound, "User not found")
+}
+
+func (s *Server) serveDirectUploads(w http.ResponseWriter, r *http.Request, parts []string) {
+	if strings.Join(parts, "/") != "active_storage/direct_uploads" || r.Method != http.MethodPost {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	var req fizzy.DirectUploadRequest
+	if err := decode(r, "", &req); err != nil || req.Blob.Filename == "" {
+		writeError(w, http.StatusUnprocessableEntity, "Filename can't be blank")
+		return
+	}
+
+	key := s.nextID()
+	contentType := req.Blob.ContentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	s.uploads[key] = &Upload{
+		BlobID:      key,
+		Filename:    req.Blob.Filename,
+		ContentType: contentType,
+		Checksum:    req.Blob.Checksum,
+	}
+
+	s.writeJSON(w, r, http.StatusOK, fizzy.DirectUploadResponse{
+		DirectUploadURL: s.Server.URL + "/uploads/" + key,
+		Headers:         map[string]string{"Content-Type": contentType},
+		BlobID:          key,
+	})
+}
+
+// serveBlob accepts direct upload contents and serves stored files back
+func (s *Server) serveBlob(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+
+	if parts[0] == "uploads" && len(parts) == 2 && r.Method == http.MethodPut {
+		upload, ok := s.uploads[parts[1]]
+		if !ok {
+			writeError(w, http.StatusNotFound, "Upload not found")
+			return
+		}
+		var buf bytes.Buffer
+		if _, err := buf.ReadFrom(r.Body); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		upload.Data = buf.Bytes()
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	// /rails/active_storage/blobs/<blob-id>/<filename>
+	if len(parts) >= 4 && r.Method == http.MethodGet {
+		if upload, ok := s.uploads[parts[3]]; ok && upload.Data != nil {
+			w.Header().Set("Content-Type", upload.ContentType)
+			// Like S3, the ETag of a single-part object is its hex MD5; as
+			// the fake is not object storage, whole files also carry
+			// Content-MD5 so downloads can be verified
+			if sum, err := base64.StdEncoding.DecodeString(upload.Checksum); err == nil && len(sum) > 0 {
+				w.Header().Set("ETag", fmt.Sprintf(`"%x"`, sum))
+				if r.Header.Get("Range") == "" {
+					w.Header().Set("Content-MD5", upload.Checksum)
+				}
+			}
+			http.ServeContent(w, r, upload.Filename, epoch, bytes.NewReader(upload.Data))
+			return
+		}
+	}
+
+	writeError(w, http.StatusNotFound, "Not Found")
+}
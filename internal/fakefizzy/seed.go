@@ -0,0 +1,360 @@
+package fakefizzy
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/visionik/libfizz-go/fizzy"
+)
+
+// AddUser adds a user to the account
+func (s *Server) AddUser(name string) fizzy.User {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := s.nextID()
+	email := strings.ToLower(strings.ReplaceAll(name, " ", ".")) + "@example.com"
+	user := fizzy.User{
+		ID:           id,
+		Name:         name,
+		Role:         "member",
+		Active:       true,
+		EmailAddress: &email,
+		CreatedAt:    s.now(),
+		URL:          s.url("/%s/users/%s", s.Account, id),
+	}
+	s.users = append(s.users, user)
+	return user
+}
+
+// AddBoard adds a board created by the signed-in user
+func (s *Server) AddBoard(name string) fizzy.Board {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return *s.addBoard(name, nil)
+}
+
+// AddColumn adds a column to a board
+func (s *Server) AddColumn(boardID, name string) fizzy.Column {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return *s.addColumn(boardID, name)
+}
+
+// AddCard adds a card to a board
+func (s *Server) AddCard(boardID, title, body string) fizzy.Card {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return *s.addCard(boardID, title, body)
+}
+
+// UpdateCard applies fn to the stored card with the given number,
+// for setting up state the API cannot produce directly
+func (s *Server) UpdateCard(ref string, fn func(*fizzy.Card)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if card := s.findCard(ref); card != nil {
+		fn(card)
+	}
+}
+
+// AddComment adds a comment by the signed-in user to a card
+func (s *Server) AddComment(cardRef, body string) fizzy.Comment {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return *s.addComment(s.findCard(cardRef), body)
+}
+
+// AddReaction adds a reaction by the signed-in user to a comment
+func (s *Server) AddReaction(commentID, content string) fizzy.Reaction {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return *s.addReaction(commentID, content)
+}
+
+// AddStep adds a checklist step to a card
+func (s *Server) AddStep(cardRef, content string, completed bool) fizzy.Step {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return *s.addStep(s.findCard(cardRef), content, completed)
+}
+
+// AddTag adds a tag to the account
+func (s *Server) AddTag(name, color string) fizzy.Tag {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return *s.addTag(name, color)
+}
+
+// AddNotification adds an unread notification about a card
+func (s *Server) AddNotification(cardRef, kind string) fizzy.Notification {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n := &fizzy.Notification{
+		ID:        s.nextID(),
+		Type:      kind,
+		CreatedAt: s.now(),
+	}
+	if card := s.findCard(cardRef); card != nil {
+		n.CardID = card.ID
+	}
+	s.notifications = append(s.notifications, n)
+	return *n
+}
+
+func (s *Server) addBoard(name string, description *string) *fizzy.Board {
+	id := s.nextID()
+	now := s.now()
+	creator := s.me
+	board := &fizzy.Board{
+		ID:          id,
+		Name:        name,
+		Description: description,
+		AllAccess:   true,
+		Position:    len(s.boards) + 1,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+		URL:         s.url("/%s/boards/%s", s.Account, id),
+		Creator:     &creator,
+	}
+	s.boards = append(s.boards, board)
+	return board
+}
+
+func (s *Server) addColumn(boardID, name string) *fizzy.Column {
+	position := 1
+	for _, c := range s.columns {
+		if c.BoardID == boardID {
+			position++
+		}
+	}
+	now := s.now()
+	column := &fizzy.Column{
+		ID:        s.nextID(),
+		BoardID:   boardID,
+		Name:      name,
+		Position:  position,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	s.columns = append(s.columns, column)
+	return column
+}
+
+func (s *Server) addCard(boardID, title, body string) *fizzy.Card {
+	s.cardNumber++
+	now := s.now()
+	creator := s.me
+	card := &fizzy.Card{
+		ID:           s.nextID(),
+		Number:       s.cardNumber,
+		BoardID:      boardID,
+		Title:        title,
+		Status:       "published",
+		Position:     len(s.cards) + 1,
+		LastActiveAt: &now,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+		Creator:      &creator,
+		URL:          s.url("/%s/cards/%d", s.Account, s.cardNumber),
+	}
+	card.CommentsURL = card.URL + "/comments"
+	if board := s.findBoard(boardID); board != nil {
+		b := *board
+		card.Board = &b
+	}
+	if body != "" {
+		s.setDescription(card, body)
+	}
+	s.cards = append(s.cards, card)
+	return card
+}
+
+func (s *Server) addComment(card *fizzy.Card, body string) *fizzy.Comment {
+	now := s.now()
+	creator := s.me
+	comment := &fizzy.Comment{
+		ID:        s.nextID(),
+		Body:      plainText(body),
+		PlainText: plainText(body),
+		HTML:      s.richText(body),
+		CreatedAt: now,
+		UpdatedAt: now,
+		Creator:   &creator,
+	}
+	if card != nil {
+		comment.CardID = card.ID
+		card.CommentsCount++
+		s.touch(card)
+	}
+	s.comments = append(s.comments, comment)
+	return comment
+}
+
+func (s *Server) addReaction(commentID, content string) *fizzy.Reaction {
+	creator := s.me
+	reaction := &fizzy.Reaction{
+		ID:        s.nextID(),
+		Content:   content,
+		CreatedAt: s.now(),
+		Creator:   &creator,
+	}
+	s.reactions[commentID] = append(s.reactions[commentID], reaction)
+	return reaction
+}
+
+func (s *Server) addStep(card *fizzy.Card, content string, completed bool) *fizzy.Step {
+	now := s.now()
+	step := &fizzy.Step{
+		ID:        s.nextID(),
+		Content:   content,
+		Completed: completed,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if card != nil {
+		step.CardID = card.ID
+		for _, st := range s.steps {
+			if st.CardID == card.ID {
+				step.Position++
+			}
+		}
+		s.touch(card)
+	}
+	step.Position++
+	s.steps = append(s.steps, step)
+	return step
+}
+
+func (s *Server) addTag(name, color string) *fizzy.Tag {
+	if color == "" {
+		color = "#888888"
+	}
+	tag := &fizzy.Tag{ID: s.nextID(), Name: name, Color: color}
+	s.tags = append(s.tags, tag)
+	return tag
+}
+
+// touch records activity on a card
+func (s *Server) touch(card *fizzy.Card) {
+	now := s.now()
+	card.UpdatedAt = now
+	card.LastActiveAt = &now
+}
+
+func (s *Server) findBoard(id string) *fizzy.Board {
+	for _, b := range s.boards {
+		if b.ID == id {
+			return b
+		}
+	}
+	return nil
+}
+
+func (s *Server) findColumn(boardID, id string) *fizzy.Column {
+	for _, c := range s.columns {
+		if c.ID == id && (boardID == "" || c.BoardID == boardID) {
+			return c
+		}
+	}
+	return nil
+}
+
+// findCard looks a card up by number, as the API addresses cards
+func (s *Server) findCard(ref string) *fizzy.Card {
+	for _, c := range s.cards {
+		if strconv.Itoa(c.Number) == ref {
+			return c
+		}
+	}
+	return nil
+}
+
+func (s *Server) findComment(card *fizzy.Card, id string) *fizzy.Comment {
+	for _, c := range s.comments {
+		if c.ID == id && c.CardID == card.ID {
+			return c
+		}
+	}
+	return nil
+}
+
+func (s *Server) findStep(card *fizzy.Card, id string) *fizzy.Step {
+	for _, st := range s.steps {
+		if st.ID == id && st.CardID == card.ID {
+			return st
+		}
+	}
+	return nil
+}
+
+func (s *Server) findUser(id string) *fizzy.User {
+	for i := range s.users {
+		if s.users[i].ID == id {
+			return &s.users[i]
+		}
+	}
+	return nil
+}
+
+var tagPattern = regexp.MustCompile(`<[^>]*>`)
+
+// plainText strips markup from rich text
+func plainText(body string) string {
+	text := tagPattern.ReplaceAllString(body, "")
+	return strings.TrimSpace(html.UnescapeString(text))
+}
+
+// richText converts a plain text body to HTML paragraphs, leaving markup as
+// is apart from filling in attachments
+func (s *Server) richText(body string) string {
+	if strings.Contains(body, "<") {
+		return s.renderAttachments(body)
+	}
+	var buf bytes.Buffer
+	for _, para := range strings.Split(strings.TrimSpace(body), "\n\n") {
+		if para == "" {
+			continue
+		}
+		lines := strings.Split(para, "\n")
+		for i := range lines {
+			lines[i] = html.EscapeString(lines[i])
+		}
+		fmt.Fprintf(&buf, "<p>%s</p>", strings.Join(lines, "<br>"))
+	}
+	return buf.String()
+}
+
+var attachmentPattern = regexp.MustCompile(`<action-text-attachment sgid="([^"]*)"[^>]*>`)
+
+// renderAttachments fills in the URL, name, size and type of attachments
+// embedded by blob ID, as Action Text does when it renders them
+func (s *Server) renderAttachments(body string) string {
+	return attachmentPattern.ReplaceAllStringFunc(body, func(tag string) string {
+		upload, ok := s.uploads[attachmentPattern.FindStringSubmatch(tag)[1]]
+		if !ok {
+			return tag
+		}
+		return fmt.Sprintf(`<action-text-attachment sgid="%s" content-type="%s" url="%s" filename="%s" filesize="%d">`,
+			html.EscapeString(upload.BlobID), html.EscapeString(upload.ContentType),
+			html.EscapeString(s.blobURL(upload)), html.EscapeString(upload.Filename), len(upload.Data))
+	})
+}
+
+// blobURL is where an upload is served from
+func (s *Server) blobURL(upload *Upload) string {
+	return s.Server.URL + "/rails/active_storage/blobs/" + upload.BlobID + "/" + url.PathEscape(upload.Filename)
+}
+
+func (s *Server) setDescription(card *fizzy.Card, body string) {
+	text := plainText(body)
+	rich := s.richText(body)
+	card.Description = &text
+	card.DescriptionHTML = &rich
+}
@@ -0,0 +1,328 @@
+// Package fakefizzy provides an in-memory HTTP server that mimics the parts
+// of the Fizzy API used by libfizz-go, for testing fizz without a network.
+package fakefizzy
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/visionik/libfizz-go/fizzy"
+)
+
+// Defaults used by New
+const (
+	DefaultToken   = "fake-token"
+	DefaultAccount = "6130737"
+)
+
+// epoch is the fake clock's starting time; it advances one minute per write
+var epoch = time.Date(2026, time.January, 5, 9, 0, 0, 0, time.UTC)
+
+// Fault describes an injected error response
+type Fault struct {
+	// Method to match, or "" for any
+	Method string
+	// Path substring to match, or "" for any
+	Path string
+	// Status code to return
+	Status int
+	// Body to return; defaults to the status text
+	Body string
+	// Header values added to the response
+	Header http.Header
+	// Times the fault fires before it is removed; 0 means always
+	Times int
+}
+
+// Server is an in-memory fake of the Fizzy API
+type Server struct {
+	*httptest.Server
+
+	// Token is the bearer token requests must carry
+	Token string
+	// Account is the account slug used in URL paths
+	Account string
+	// PageSize splits card lists into pages when greater than zero; a
+	// per_page parameter overrides it
+	PageSize int
+	// ETags enables ETag/If-None-Match handling on GET responses
+	ETags bool
+	// PublicURL is the base of the url fields in responses; it defaults to
+	// the server's own address. Use SetPublicURL to change it.
+	PublicURL string
+
+	mu            sync.Mutex
+	clock         time.Time
+	seq           int
+	cardNumber    int
+	me            fizzy.User
+	users         []fizzy.User
+	boards        []*fizzy.Board
+	columns       []*fizzy.Column
+	cards         []*fizzy.Card
+	comments      []*fizzy.Comment
+	reactions     map[string][]*fizzy.Reaction
+	steps         []*fizzy.Step
+	tags          []*fizzy.Tag
+	notifications []*fizzy.Notification
+	watchers      map[string]bool
+	uploads       map[string]*Upload
+	faults        []*Fault
+	requests      []string
+}
+
+// Upload is a file received through the direct upload endpoints
+type Upload struct {
+	BlobID      string
+	Filename    string
+	ContentType string
+	// Checksum is the base64 MD5 the uploader declared, which downloads
+	// carry as their ETag
+	Checksum string
+	Data     []byte
+}
+
+// New starts a fake server with a signed-in user and no boards
+func New() *Server {
+	s := &Server{
+		Token:     DefaultToken,
+		Account:   DefaultAccount,
+		clock:     epoch,
+		reactions: map[string][]*fizzy.Reaction{},
+		watchers:  map[string]bool{},
+		uploads:   map[string]*Upload{},
+	}
+	s.Server = httptest.NewServer(s)
+	s.me = s.AddUser("Fizz Tester")
+	return s
+}
+
+// Me returns the signed-in user
+func (s *Server) Me() fizzy.User {
+	return s.me
+}
+
+// SetPublicURL changes the base of the url fields in responses, including
+// those of records that already exist
+func (s *Server) SetPublicURL(base string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	old := s.url("")
+	s.PublicURL = base
+	rebase := func(u string) string {
+		if strings.HasPrefix(u, old) {
+			return base + strings.TrimPrefix(u, old)
+		}
+		return u
+	}
+
+	s.me.URL = rebase(s.me.URL)
+	for i := range s.users {
+		s.users[i].URL = rebase(s.users[i].URL)
+	}
+	for _, b := range s.boards {
+		b.URL = rebase(b.URL)
+	}
+	for _, c := range s.cards {
+		c.URL = rebase(c.URL)
+		c.CommentsURL = rebase(c.CommentsURL)
+	}
+}
+
+// Inject adds a fault that matching requests receive instead of a response
+func (s *Server) Inject(f Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = append(s.faults, &f)
+}
+
+// Requests returns the method and path of every request received so far
+func (s *Server) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.requests...)
+}
+
+// Upload returns the file stored under blobID, if any
+func (s *Server) Upload(blobID string) (*Upload, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	u, ok := s.uploads[blobID]
+	return u, ok
+}
+
+// now advances the fake clock; the caller must hold s.mu
+func (s *Server) now() time.Time {
+	s.clock = s.clock.Add(time.Minute)
+	return s.clock
+}
+
+// nextID returns a new Fizzy-style identifier; the caller must hold s.mu
+func (s *Server) nextID() string {
+	s.seq++
+	id := strconv.FormatInt(int64(s.seq), 36)
+	return "03f" + strings.Repeat("0", 22-len(id)) + id
+}
+
+// url builds a link to a resource
+func (s *Server) url(format string, args ...interface{}) string {
+	base := s.PublicURL
+	if base == "" {
+		base = s.Server.URL
+	}
+	return base + fmt.Sprintf(format, args...)
+}
+
+// ServeHTTP implements http.Handler
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests = append(s.requests, r.Method+" "+r.URL.RequestURI())
+
+	if s.fault(w, r) {
+		return
+	}
+
+	// Direct upload targets are authorized by their URL, as on S3
+	if strings.HasPrefix(r.URL.Path, "/uploads/") || strings.HasPrefix(r.URL.Path, "/rails/active_storage/blobs/") {
+		s.serveBlob(w, r)
+		return
+	}
+
+	if r.Header.Get("Authorization") != "Bearer "+s.Token {
+		writeError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	last := len(parts) - 1
+	parts[last] = strings.TrimSuffix(parts[last], ".json")
+
+	if parts[0] == "my" {
+		s.serveMy(w, r, parts[1:])
+		return
+	}
+	if parts[0] != s.Account || len(parts) < 2 {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	rest := parts[2:]
+	switch parts[1] {
+	case "boards":
+		s.serveBoards(w, r, rest)
+	case "cards":
+		s.serveCards(w, r, rest)
+	case "tags":
+		s.serveTags(w, r, rest)
+	case "users":
+		s.serveUsers(w, r, rest)
+	case "rails":
+		s.serveDirectUploads(w, r, rest)
+	default:
+		writeError(w, http.StatusNotFound, "Not Found")
+	}
+}
+
+// fault writes an injected error if one matches r; the caller must hold s.mu
+func (s *Server) fault(w http.ResponseWriter, r *http.Request) bool {
+	for i, f := range s.faults {
+		if f.Method != "" && f.Method != r.Method {
+			continue
+		}
+		if f.Path != "" && !strings.Contains(r.URL.Path, f.Path) {
+			continue
+		}
+
+		if f.Times > 0 {
+			f.Times--
+			if f.Times == 0 {
+				s.faults = append(s.faults[:i], s.faults[i+1:]...)
+			}
+		}
+
+		for key, values := range f.Header {
+			for _, v := range values {
+				w.Header().Add(key, v)
+			}
+		}
+		body := f.Body
+		if body == "" {
+			body = http.StatusText(f.Status)
+		}
+		writeError(w, f.Status, body)
+		return true
+	}
+	return false
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(status)
+	fmt.Fprint(w, message)
+}
+
+// writeJSON writes v as the response body, honouring If-None-Match when
+// ETags are enabled
+func (s *Server) writeJSON(w http.ResponseWriter, r *http.Request, status int, v interface{}) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if s.ETags && r.Method == http.MethodGet {
+		sum := sha1.Sum(data)
+		etag := `W/"` + hex.EncodeToString(sum[:8]) + `"`
+		w.Header().Set("ETag", etag)
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	w.Write(data)
+}
+
+// created answers a create request with a Location for the new resource
+func created(w http.ResponseWriter, location string) {
+	w.Header().Set("Location", location)
+	w.WriteHeader(http.StatusCreated)
+}
+
+func noContent(w http.ResponseWriter) {
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func methodNotAllowed(w http.ResponseWriter) {
+	writeError(w, http.StatusMethodNotAllowed, "Method Not Allowed")
+}
+
+// decode reads a JSON request body, unwrapping the resource envelope
+// ({"card": {...}}) when key is not empty
+func decode(r *http.Request, key string, v interface{}) error {
+	if key == "" {
+		return json.NewDecoder(r.Body).Decode(v)
+	}
+	var envelope map[string]json.RawMessage
+	if err := json.NewDecoder(r.Body).Decode(&envelope); err != nil {
+		return err
+	}
+	raw, ok := envelope[key]
+	if !ok {
+		return fmt.Errorf("missing %q object", key)
+	}
+	return json.Unmarshal(raw, v)
+}
@@ -0,0 +1,148 @@
+package fakefizzy
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/visionik/libfizz-go/fizzy"
+)
+
+// get makes an authorized request to the fake and returns the response and
+// its body
+func get(t *testing.T, s *Server, path string, header http.Header) (*http.Response, string) {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodGet, s.URL+path, nil)
+	require.NoError(t, err)
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	req.Header.Set("Authorization", "Bearer "+s.Token)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp, string(body)
+}
+
+func titles(t *testing.T, body string) []string {
+	t.Helper()
+	var cards []fizzy.Card
+	require.NoError(t, json.Unmarshal([]byte(body), &cards))
+	list := make([]string, len(cards))
+	for i, c := range cards {
+		list[i] = c.Title
+	}
+	return list
+}
+
+func newSeeded(t *testing.T) *Server {
+	s := New()
+	t.Cleanup(s.Close)
+	board := s.AddBoard("Platform")
+	for _, title := range []string{"One", "Two", "Three", "Four", "Five"} {
+		s.AddCard(board.ID, title, "")
+	}
+	return s
+}
+
+func TestPagination(t *testing.T) {
+	s := newSeeded(t)
+	cards := "/" + s.Account + "/cards.json"
+
+	resp, body := get(t, s, cards, nil)
+	assert.Len(t, titles(t, body), 5)
+	assert.Empty(t, resp.Header.Get("Link"), "one page without a page size")
+
+	s.PageSize = 2
+	resp, body = get(t, s, cards+"?status=published", nil)
+	assert.Equal(t, []string{"One", "Two"}, titles(t, body))
+	assert.Equal(t, `<`+s.URL+cards+`?page=2&status=published>; rel="next"`, resp.Header.Get("Link"),
+		"the next link keeps the filters")
+
+	resp, body = get(t, s, cards+"?page=3", nil)
+	assert.Equal(t, []string{"Five"}, titles(t, body))
+	assert.Empty(t, resp.Header.Get("Link"), "no next link on the last page")
+
+	// per_page overrides the page size
+	resp, body = get(t, s, cards+"?per_page=4", nil)
+	assert.Equal(t, []string{"One", "Two", "Three", "Four"}, titles(t, body))
+	assert.Contains(t, resp.Header.Get("Link"), "page=2")
+
+	_, body = get(t, s, cards+"?page=9", nil)
+	assert.Empty(t, titles(t, body), "past the end is an empty page")
+}
+
+func TestCardsByNumber(t *testing.T) {
+	s := newSeeded(t)
+	var card fizzy.Card
+	s.UpdateCard("2", func(c *fizzy.Card) { card = *c })
+
+	resp, body := get(t, s, "/"+s.Account+"/cards/2.json", nil)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, body, `"title":"Two"`)
+
+	// As in the real API, a card's ID does not address it
+	resp, _ = get(t, s, "/"+s.Account+"/cards/"+card.ID+".json", nil)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	resp, _ = get(t, s, "/"+s.Account+"/cards/"+card.ID+"/steps", nil)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestETags(t *testing.T) {
+	s := newSeeded(t)
+	cards := "/" + s.Account + "/cards.json"
+
+	resp, _ := get(t, s, cards, nil)
+	assert.Empty(t, resp.Header.Get("ETag"), "off unless enabled")
+
+	s.ETags = true
+	resp, body := get(t, s, cards, nil)
+	etag := resp.Header.Get("ETag")
+	require.NotEmpty(t, etag)
+	assert.NotEmpty(t, body)
+
+	resp, body = get(t, s, cards, http.Header{"If-None-Match": {etag}})
+	assert.Equal(t, http.StatusNotModified, resp.StatusCode)
+	assert.Empty(t, body)
+
+	// A change gives the list a new ETag
+	s.UpdateCard("1", func(c *fizzy.Card) { c.Title = "Renamed" })
+	resp, body = get(t, s, cards, http.Header{"If-None-Match": {etag}})
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.NotEqual(t, etag, resp.Header.Get("ETag"))
+	assert.Contains(t, body, "Renamed")
+}
+
+func TestFaults(t *testing.T) {
+	s := newSeeded(t)
+	cards := "/" + s.Account + "/cards.json"
+
+	s.Inject(Fault{Method: http.MethodGet, Path: "/cards", Status: http.StatusTooManyRequests,
+		Header: http.Header{"Retry-After": {"3"}}, Times: 2})
+	s.Inject(Fault{Method: http.MethodPost, Status: http.StatusInternalServerError})
+
+	for range 2 {
+		resp, body := get(t, s, cards, nil)
+		assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+		assert.Equal(t, "3", resp.Header.Get("Retry-After"))
+		assert.Equal(t, "Too Many Requests", body)
+	}
+	resp, _ := get(t, s, cards, nil)
+	assert.Equal(t, http.StatusOK, resp.StatusCode, "the fault is gone after firing twice")
+
+	// Other methods and paths are not matched
+	resp, _ = get(t, s, "/"+s.Account+"/boards.json", nil)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	assert.Equal(t, []string{
+		"GET " + cards,
+		"GET " + cards,
+		"GET " + cards,
+		"GET /" + s.Account + "/boards.json",
+	}, s.Requests())
+}
@@ -0,0 +1,45 @@
+package format
+
+import (
+	"math"
+	"strings"
+)
+
+var sparkTicks = []rune("▁▂▃▄▅▆▇█")
+
+// Sparkline draws values as a line of block characters scaled between zero
+// and the largest value
+func Sparkline(values []float64) string {
+	top := 0.0
+	for _, v := range values {
+		top = max(top, v)
+	}
+	var b strings.Builder
+	for _, v := range values {
+		i := 0
+		if top > 0 && v > 0 {
+			i = int(math.Round(v / top * float64(len(sparkTicks)-1)))
+		}
+		b.WriteRune(sparkTicks[i])
+	}
+	return b.String()
+}
+
+var barEighths = []rune(" ▏▎▍▌▋▊▉")
+
+// Bar draws value as a horizontal bar up to width cells long for top, using
+// eighth blocks for the remainder
+func Bar(value, top float64, width int) string {
+	if top <= 0 || value <= 0 || width <= 0 {
+		return ""
+	}
+	eighths := int(math.Round(math.Min(value/top, 1) * float64(width*8)))
+	if eighths == 0 {
+		eighths = 1
+	}
+	bar := strings.Repeat("█", eighths/8)
+	if rest := eighths % 8; rest > 0 {
+		bar += string(barEighths[rest])
+	}
+	return bar
+}
@@ -0,0 +1,43 @@
+package format
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSparkline(t *testing.T) {
+	assert.Equal(t, "▁▃▅█", Sparkline([]float64{0, 1, 2, 3.5}))
+	assert.Equal(t, "▁▁", Sparkline([]float64{0, 0}))
+}
+
+func TestBar(t *testing.T) {
+	assert.Equal(t, "██████████", Bar(4, 4, 10))
+	assert.Equal(t, "█████", Bar(2, 4, 10))
+	assert.Equal(t, "▏", Bar(0.01, 4, 10))
+	assert.Equal(t, "", Bar(0, 4, 10))
+}
+
+func TestCSVFormatter(t *testing.T) {
+	type row struct {
+		Name  string `json:"name"`
+		Count int    `json:"count,omitempty"`
+		Skip  string `json:"-"`
+	}
+	var buf bytes.Buffer
+	f := &CSVFormatter{Writer: &buf}
+	assert.NoError(t, f.Format([]row{{"a, b", 1, "x"}, {"c", 2, "y"}}))
+	assert.Equal(t, "name,count\n\"a, b\",1\nc,2\n", buf.String())
+}
+
+func TestJSONLinesFormatter(t *testing.T) {
+	type row struct {
+		Name string `json:"name"`
+	}
+	var buf bytes.Buffer
+	f := &JSONLinesFormatter{Writer: &buf}
+	assert.NoError(t, f.Format([]row{{"a"}, {"b"}}))
+	assert.NoError(t, f.Format(row{"c"}))
+	assert.Equal(t, "{\"name\":\"a\"}\n{\"name\":\"b\"}\n{\"name\":\"c\"}\n", buf.String())
+}
@@ -0,0 +1,86 @@
+package format
+
+import (
+	"encoding/csv"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// CSVFormatter formats output as comma-separated values with a header row
+type CSVFormatter struct {
+	Writer io.Writer
+}
+
+// Format writes a slice of records as one row each, or a single record as
+// field,value rows
+func (f *CSVFormatter) Format(data interface{}) error {
+	if data == nil {
+		return nil
+	}
+	w := csv.NewWriter(f.Writer)
+
+	v := reflect.ValueOf(data)
+	for v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	switch {
+	case v.Kind() == reflect.Slice:
+		elem := v.Type().Elem()
+		for elem.Kind() == reflect.Ptr {
+			elem = elem.Elem()
+		}
+		if elem.Kind() != reflect.Struct {
+			w.Write([]string{"value"})
+			for i := 0; i < v.Len(); i++ {
+				w.Write([]string{formatValue(v.Index(i))})
+			}
+			break
+		}
+		fields := csvFields(elem)
+		header := make([]string, len(fields))
+		for i, field := range fields {
+			header[i] = csvName(elem.Field(field))
+		}
+		w.Write(header)
+		for i := 0; i < v.Len(); i++ {
+			item := reflect.Indirect(v.Index(i))
+			row := make([]string, len(fields))
+			if item.IsValid() {
+				for j, field := range fields {
+					row[j] = formatValue(item.Field(field))
+				}
+			}
+			w.Write(row)
+		}
+	case v.Kind() == reflect.Struct:
+		w.Write([]string{"field", "value"})
+		for _, field := range csvFields(v.Type()) {
+			w.Write([]string{csvName(v.Type().Field(field)), formatValue(v.Field(field))})
+		}
+	default:
+		w.Write([]string{formatValue(v)})
+	}
+
+	w.Flush()
+	return w.Error()
+}
+
+// csvFields returns the indexes of the exported fields of t
+func csvFields(t reflect.Type) []int {
+	var fields []int
+	for i := 0; i < t.NumField(); i++ {
+		if field := t.Field(i); field.IsExported() && field.Tag.Get("json") != "-" {
+			fields = append(fields, i)
+		}
+	}
+	return fields
+}
+
+// csvName returns a field's JSON name, so CSV headers match JSON keys
+func csvName(field reflect.StructField) string {
+	if name, _, _ := strings.Cut(field.Tag.Get("json"), ","); name != "" {
+		return name
+	}
+	return strings.ToLower(field.Name)
+}
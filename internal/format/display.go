@@ -1,8 +1,22 @@
 package format
 
 import (
+	"fmt"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/fatih/color"
+	"github.com/visionik/fizz/internal/attachment"
+	"github.com/visionik/fizz/internal/config"
+	"github.com/visionik/fizz/internal/housekeep"
+	"github.com/visionik/fizz/internal/httpcache"
+	"github.com/visionik/fizz/internal/importer"
+	"github.com/visionik/fizz/internal/plugin"
+	"github.com/visionik/fizz/internal/progress"
+	"github.com/visionik/fizz/internal/schedule"
+	"github.com/visionik/fizz/internal/templates"
+	"github.com/visionik/fizz/internal/wip"
 	"github.com/visionik/libfizz-go/fizzy"
 )
 
@@ -210,3 +224,265 @@ func ToBoardDisplaySlice(boards []fizzy.Board) []BoardDisplay {
 	}
 	return displays
 }
+
+// TemplateDisplay represents a card template for table display
+type TemplateDisplay struct {
+	Name        string `json:"name"`
+	Title       string `json:"title"`
+	Vars        string `json:"vars,omitempty"`
+	Source      string `json:"source"`
+	Description string `json:"description,omitempty"`
+}
+
+// ToTemplateDisplaySlice converts a slice of Templates to TemplateDisplay
+func ToTemplateDisplaySlice(list []templates.Template) []TemplateDisplay {
+	displays := make([]TemplateDisplay, len(list))
+	for i, t := range list {
+		displays[i] = TemplateDisplay{
+			Name:        t.Name,
+			Title:       truncate(t.Title, 40),
+			Vars:        formatNames(t.Variables(), 30),
+			Source:      t.Source,
+			Description: truncate(t.Description, 40),
+		}
+	}
+	return displays
+}
+
+// PluginDisplay represents a plugin for table display
+type PluginDisplay struct {
+	Name   string `json:"name"`
+	Path   string `json:"path"`
+	Status string `json:"status"`
+}
+
+// ToPluginDisplaySlice converts a slice of Plugins to PluginDisplay
+func ToPluginDisplaySlice(list []plugin.Plugin) []PluginDisplay {
+	displays := make([]PluginDisplay, len(list))
+	for i, p := range list {
+		status := "active"
+		if p.Shadowed != "" {
+			status = "shadowed by " + p.Shadowed
+		}
+		displays[i] = PluginDisplay{Name: p.Name, Path: p.Path, Status: status}
+	}
+	return displays
+}
+
+// AttachmentDisplay represents an attachment for table display
+type AttachmentDisplay struct {
+	Name   string `json:"name"`
+	Size   string `json:"size"`
+	Type   string `json:"type"`
+	Source string `json:"source"`
+	URL    string `json:"url"`
+}
+
+// ToAttachmentDisplaySlice converts a slice of Attachments to AttachmentDisplay
+func ToAttachmentDisplaySlice(list []attachment.Attachment) []AttachmentDisplay {
+	displays := make([]AttachmentDisplay, len(list))
+	for i, a := range list {
+		displays[i] = AttachmentDisplay{Name: a.Name, Size: displaySize(a.Size), Type: a.ContentType, Source: a.Source, URL: a.URL}
+	}
+	return displays
+}
+
+// DownloadDisplay represents a downloaded attachment for table display
+type DownloadDisplay struct {
+	Name     string `json:"name"`
+	Path     string `json:"path"`
+	Size     string `json:"size"`
+	Status   string `json:"status"`
+	Checksum string `json:"checksum"`
+}
+
+// ToDownloadDisplaySlice converts a slice of download Results to DownloadDisplay
+func ToDownloadDisplaySlice(list []attachment.Result) []DownloadDisplay {
+	displays := make([]DownloadDisplay, len(list))
+	for i, r := range list {
+		displays[i] = DownloadDisplay{Name: r.Name, Path: r.Path, Size: displaySize(r.Size), Status: r.Status, Checksum: r.Checksum}
+	}
+	return displays
+}
+
+// displaySize formats a size in bytes, or "-" when it is unknown
+func displaySize(n int64) string {
+	if n <= 0 {
+		return "-"
+	}
+	return progress.Bytes(n)
+}
+
+// CacheDisplay represents cached API responses for table display
+type CacheDisplay struct {
+	Scope     string `json:"scope"`
+	Responses int    `json:"responses"`
+	Size      string `json:"size"`
+	Oldest    string `json:"oldest"`
+	Newest    string `json:"newest"`
+}
+
+// ToCacheDisplay converts cache Stats to CacheDisplay
+func ToCacheDisplay(scope string, s httpcache.Stats) CacheDisplay {
+	d := CacheDisplay{Scope: scope, Responses: s.Responses, Size: displaySize(s.Bytes), Oldest: "-", Newest: "-"}
+	if s.Oldest != nil {
+		d.Oldest = s.Oldest.Local().Format("2006-01-02 15:04")
+	}
+	if s.Newest != nil {
+		d.Newest = s.Newest.Local().Format("2006-01-02 15:04")
+	}
+	return d
+}
+
+// ScheduleDisplay represents a schedule rule for table display
+type ScheduleDisplay struct {
+	Name    string `json:"name"`
+	Cron    string `json:"cron"`
+	Card    string `json:"card"`
+	LastRun string `json:"last_run"`
+	Next    string `json:"next"`
+}
+
+// ToScheduleDisplay converts a schedule rule, its last handled occurrence and
+// its next one to ScheduleDisplay
+func ToScheduleDisplay(rule schedule.Rule, last, next time.Time) ScheduleDisplay {
+	display := ScheduleDisplay{
+		Name:    rule.Name,
+		Cron:    rule.Cron,
+		Card:    truncate(rule.Title, 40),
+		LastRun: "never",
+		Next:    "never",
+	}
+	if rule.Template != "" && rule.Title == "" {
+		display.Card = "template " + rule.Template
+	}
+	if !last.IsZero() {
+		display.LastRun = last.Format("2006-01-02 15:04")
+	}
+	if !next.IsZero() {
+		display.Next = next.Format("2006-01-02 15:04")
+	}
+	return display
+}
+
+// OccurrenceDisplay represents an upcoming scheduled card for table display
+type OccurrenceDisplay struct {
+	When     string `json:"when"`
+	Schedule string `json:"schedule"`
+	Title    string `json:"title"`
+}
+
+// HousekeepDisplay represents a card a housekeeping rule acts on for table
+// display
+type HousekeepDisplay struct {
+	Rule    string `json:"rule"`
+	Card    int    `json:"card"`
+	Title   string `json:"title"`
+	Column  string `json:"column"`
+	Idle    string `json:"idle"`
+	Actions string `json:"actions"`
+}
+
+// ImportDisplay represents a card an import creates for table display
+type ImportDisplay struct {
+	Title    string `json:"title"`
+	Column   string `json:"column"`
+	Tags     string `json:"tags"`
+	Steps    int    `json:"steps"`
+	Comments int    `json:"comments"`
+	Action   string `json:"action"`
+}
+
+// ToImportDisplay converts an imported card and what happens to it to
+// ImportDisplay
+func ToImportDisplay(card importer.Card, action string) ImportDisplay {
+	return ImportDisplay{
+		Title:    truncate(card.Title, 40),
+		Column:   card.Column,
+		Tags:     strings.Join(card.Tags, ", "),
+		Steps:    len(card.Steps),
+		Comments: len(card.Comments),
+		Action:   action,
+	}
+}
+
+// HousekeepLogDisplay represents an audit log entry for table display
+type HousekeepLogDisplay struct {
+	Time    string `json:"time"`
+	Rule    string `json:"rule"`
+	Card    int    `json:"card"`
+	Title   string `json:"title"`
+	Actions string `json:"actions"`
+}
+
+// ToHousekeepLogDisplay converts an audit log entry to HousekeepLogDisplay
+func ToHousekeepLogDisplay(e housekeep.Entry) HousekeepLogDisplay {
+	actions := strings.Join(e.Actions, ", ")
+	if e.Error != "" {
+		actions += " (failed: " + e.Error + ")"
+	}
+	return HousekeepLogDisplay{
+		Time:    e.Time.Local().Format("2006-01-02 15:04"),
+		Rule:    e.Rule,
+		Card:    e.Card,
+		Title:   truncate(e.Title, 40),
+		Actions: actions,
+	}
+}
+
+// ColumnWIPDisplay represents a column with its card count and WIP limits
+// for table display
+type ColumnWIPDisplay struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Position  int    `json:"position"`
+	Cards     string `json:"cards"`
+	PerPerson string `json:"per_person"`
+}
+
+// ToColumnWIPDisplay converts a column, its open card count and its limit
+// (nil for none) to ColumnWIPDisplay, coloring counts at or over the limit
+func ToColumnWIPDisplay(col fizzy.Column, count wip.Count, limit *config.WIPLimit) ColumnWIPDisplay {
+	display := ColumnWIPDisplay{
+		ID:       col.ID,
+		Name:     col.Name,
+		Position: col.Position,
+		Cards:    strconv.Itoa(count.Total),
+	}
+	if limit == nil {
+		return display
+	}
+	if limit.Limit > 0 {
+		display.Cards = colorizeWIP(count.Total, limit.Limit)
+	}
+	if limit.PerPerson > 0 {
+		// Show the busiest assignee
+		busiest := ""
+		for id, n := range count.ByAssignee {
+			if busiest == "" || n > count.ByAssignee[busiest] ||
+				(n == count.ByAssignee[busiest] && count.Names[id] < count.Names[busiest]) {
+				busiest = id
+			}
+		}
+		if busiest == "" {
+			display.PerPerson = colorizeWIP(0, limit.PerPerson)
+		} else {
+			display.PerPerson = count.Names[busiest] + " " + colorizeWIP(count.ByAssignee[busiest], limit.PerPerson)
+		}
+	}
+	return display
+}
+
+// colorizeWIP formats a count against its limit, green under it, yellow at
+// it and red over it when colors are enabled
+func colorizeWIP(count, limit int) string {
+	s := fmt.Sprintf("%d/%d", count, limit)
+	switch {
+	case count > limit:
+		return color.RedString(s)
+	case count == limit:
+		return color.YellowString(s)
+	default:
+		return color.GreenString(s)
+	}
+}
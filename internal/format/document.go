@@ -0,0 +1,144 @@
+package format
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/visionik/libfizz-go/fizzy"
+)
+
+// CardDocument is a card with everything shown by `fizz cards show`
+type CardDocument struct {
+	Card     fizzy.Card        `json:"card" yaml:"card"`
+	Column   string            `json:"column,omitempty" yaml:"column,omitempty"`
+	Steps    []fizzy.Step      `json:"steps" yaml:"steps"`
+	Comments []CommentDocument `json:"comments" yaml:"comments"`
+}
+
+// CommentDocument is a comment with its reactions
+type CommentDocument struct {
+	fizzy.Comment `yaml:",inline"`
+	Reactions     []fizzy.Reaction `json:"reactions" yaml:"reactions"`
+}
+
+var (
+	headingStyle = color.New(color.Bold)
+	labelStyle   = color.New(color.Faint)
+	doneStyle    = color.New(color.FgGreen)
+	goldenStyle  = color.New(color.FgYellow)
+)
+
+// WriteCardDocument renders doc as a readable document: a header with the
+// card's fields, its description, the step checklist and the comment thread
+func WriteCardDocument(w io.Writer, doc CardDocument) error {
+	card := doc.Card
+	var b strings.Builder
+
+	title := fmt.Sprintf("#%d %s", card.Number, card.Title)
+	if card.Golden {
+		title += " " + goldenStyle.Sprint("★")
+	}
+	b.WriteString(headingStyle.Sprint(title) + "\n\n")
+
+	status := card.Status
+	if card.Closed {
+		status = "closed"
+	}
+	field := func(label, value string) {
+		if value == "" {
+			return
+		}
+		fmt.Fprintf(&b, "%s %s\n", labelStyle.Sprintf("%-10s", label+":"), value)
+	}
+	field("Status", colorizeStatus(status))
+	if card.Board != nil {
+		field("Board", card.Board.Name)
+	}
+	field("Column", doc.Column)
+	field("Assignees", userNames(card.Assignees))
+	tags := make([]string, 0, len(card.Tags))
+	for _, t := range card.Tags {
+		tags = append(tags, "#"+t.Name)
+	}
+	field("Tags", strings.Join(tags, " "))
+	created := card.CreatedAt.Format("2006-01-02 15:04")
+	if card.Creator != nil {
+		created += " by " + card.Creator.Name
+	}
+	field("Created", created)
+	field("URL", card.URL)
+
+	if description := cardDescription(card); description != "" {
+		b.WriteString("\n" + description + "\n")
+	}
+
+	if len(doc.Steps) > 0 {
+		done := 0
+		for _, s := range doc.Steps {
+			if s.Completed {
+				done++
+			}
+		}
+		b.WriteString("\n" + headingStyle.Sprintf("Steps (%d/%d)", done, len(doc.Steps)) + "\n")
+		for _, s := range doc.Steps {
+			if s.Completed {
+				fmt.Fprintf(&b, "  %s %s\n", doneStyle.Sprint("✓"), labelStyle.Sprint(s.Content))
+			} else {
+				fmt.Fprintf(&b, "  ☐ %s\n", s.Content)
+			}
+		}
+	}
+
+	if len(doc.Comments) > 0 {
+		b.WriteString("\n" + headingStyle.Sprintf("Comments (%d)", len(doc.Comments)) + "\n")
+		for _, c := range doc.Comments {
+			author := "Unknown"
+			if c.Creator != nil {
+				author = c.Creator.Name
+			}
+			fmt.Fprintf(&b, "\n%s %s\n", headingStyle.Sprint(author), labelStyle.Sprint("· "+c.CreatedAt.Format("2006-01-02 15:04")))
+
+			body := c.HTML
+			if body == "" {
+				body = c.Body
+			}
+			for _, line := range strings.Split(RichText(body), "\n") {
+				b.WriteString(strings.TrimRight("  "+line, " ") + "\n")
+			}
+			if summary := reactionSummary(c.Reactions); summary != "" {
+				b.WriteString("  " + summary + "\n")
+			}
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func userNames(users []fizzy.User) string {
+	names := make([]string, 0, len(users))
+	for _, u := range users {
+		names = append(names, u.Name)
+	}
+	return strings.Join(names, ", ")
+}
+
+// reactionSummary counts reactions by emoji in order of first use, e.g.
+// "👍 2  🎉 1"
+func reactionSummary(reactions []fizzy.Reaction) string {
+	var order []string
+	counts := map[string]int{}
+	for _, r := range reactions {
+		if counts[r.Content] == 0 {
+			order = append(order, r.Content)
+		}
+		counts[r.Content]++
+	}
+	parts := make([]string, len(order))
+	for i, content := range order {
+		parts[i] = fmt.Sprintf("%s %d", content, counts[content])
+	}
+	return strings.Join(parts, "  ")
+}
@@ -0,0 +1,229 @@
+package format
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/visionik/libfizz-go/fizzy"
+)
+
+// Export is the cards written by `fizz export`, grouped by board and column
+type Export struct {
+	Boards []ExportBoard `json:"boards" yaml:"boards"`
+}
+
+// ExportBoard is a board's columns in board order
+type ExportBoard struct {
+	Board   fizzy.Board    `json:"board" yaml:"board"`
+	Columns []ExportColumn `json:"columns" yaml:"columns"`
+}
+
+// ExportColumn is a column, or a pseudo-column such as Triage for cards in
+// none, with its cards
+type ExportColumn struct {
+	Name  string         `json:"name" yaml:"name"`
+	Cards []CardDocument `json:"cards" yaml:"cards"`
+}
+
+// Count returns the number of cards in the export
+func (e Export) Count() int {
+	n := 0
+	for _, b := range e.Boards {
+		for _, col := range b.Columns {
+			n += len(col.Cards)
+		}
+	}
+	return n
+}
+
+// WriteMarkdown renders an export as a Markdown document: a heading per
+// board, then per column, then per card with its fields, description, steps
+// and comments. Colors must be off so rich text comes out as Markdown.
+func WriteMarkdown(w io.Writer, e Export) error {
+	var b strings.Builder
+	for i, board := range e.Boards {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "# %s\n", board.Board.Name)
+		if board.Board.Description != nil && *board.Board.Description != "" {
+			b.WriteString("\n" + RichText(*board.Board.Description) + "\n")
+		}
+		for _, col := range board.Columns {
+			fmt.Fprintf(&b, "\n## %s\n", col.Name)
+			for _, doc := range col.Cards {
+				writeMarkdownCard(&b, doc)
+			}
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeMarkdownCard(b *strings.Builder, doc CardDocument) {
+	card := doc.Card
+	fmt.Fprintf(b, "\n### #%d %s\n\n", card.Number, card.Title)
+
+	fmt.Fprintf(b, "- Status: %s\n", cardStatus(card))
+	if names := userNames(card.Assignees); names != "" {
+		fmt.Fprintf(b, "- Assignees: %s\n", names)
+	}
+	if tags := tagNames(card.Tags, "#"); len(tags) > 0 {
+		fmt.Fprintf(b, "- Tags: %s\n", strings.Join(tags, " "))
+	}
+	created := card.CreatedAt.Format("2006-01-02")
+	if card.Creator != nil {
+		created += " by " + card.Creator.Name
+	}
+	fmt.Fprintf(b, "- Created: %s\n", created)
+	if card.ClosedAt != nil {
+		fmt.Fprintf(b, "- Closed: %s\n", card.ClosedAt.Format("2006-01-02"))
+	}
+	if card.URL != "" {
+		fmt.Fprintf(b, "- URL: <%s>\n", card.URL)
+	}
+
+	if description := cardDescription(card); description != "" {
+		b.WriteString("\n" + description + "\n")
+	}
+
+	if len(doc.Steps) > 0 {
+		b.WriteString("\n**Steps**\n\n")
+		for _, s := range doc.Steps {
+			check := " "
+			if s.Completed {
+				check = "x"
+			}
+			fmt.Fprintf(b, "- [%s] %s\n", check, s.Content)
+		}
+	}
+
+	if len(doc.Comments) > 0 {
+		b.WriteString("\n**Comments**\n")
+		for _, c := range doc.Comments {
+			author := "Unknown"
+			if c.Creator != nil {
+				author = c.Creator.Name
+			}
+			fmt.Fprintf(b, "\n> **%s** · %s\n>\n", author, c.CreatedAt.Format("2006-01-02 15:04"))
+			body := c.HTML
+			if body == "" {
+				body = c.Body
+			}
+			for _, line := range strings.Split(RichText(body), "\n") {
+				b.WriteString(strings.TrimRight("> "+line, " ") + "\n")
+			}
+		}
+	}
+}
+
+// ExportRow is a card as one spreadsheet row
+type ExportRow struct {
+	Number     int    `json:"number"`
+	Title      string `json:"title"`
+	Board      string `json:"board"`
+	Column     string `json:"column"`
+	Status     string `json:"status"`
+	Tags       string `json:"tags"`
+	Assignees  string `json:"assignees"`
+	Creator    string `json:"creator"`
+	Created    string `json:"created"`
+	LastActive string `json:"last_active"`
+	Closed     string `json:"closed"`
+	Steps      string `json:"steps"`
+	Comments   int    `json:"comments"`
+	URL        string `json:"url"`
+	Body       string `json:"body"`
+}
+
+// ToExportRows flattens an export into one row per card
+func ToExportRows(e Export) []ExportRow {
+	rows := []ExportRow{}
+	for _, board := range e.Boards {
+		for _, col := range board.Columns {
+			for _, doc := range col.Cards {
+				card := doc.Card
+				row := ExportRow{
+					Number:     card.Number,
+					Title:      card.Title,
+					Board:      board.Board.Name,
+					Column:     col.Name,
+					Status:     cardStatus(card),
+					Tags:       strings.Join(tagNames(card.Tags, ""), ", "),
+					Assignees:  userNames(card.Assignees),
+					Created:    card.CreatedAt.Format(time.RFC3339),
+					LastActive: LastActive(card).Format(time.RFC3339),
+					Comments:   card.CommentsCount,
+					URL:        card.URL,
+					Body:       cardDescription(card),
+				}
+				if card.Creator != nil {
+					row.Creator = card.Creator.Name
+				}
+				if card.ClosedAt != nil {
+					row.Closed = card.ClosedAt.Format(time.RFC3339)
+				}
+				if len(doc.Steps) > 0 {
+					done := 0
+					for _, s := range doc.Steps {
+						if s.Completed {
+							done++
+						}
+					}
+					row.Steps = fmt.Sprintf("%d/%d", done, len(doc.Steps))
+				}
+				if len(doc.Comments) > row.Comments {
+					row.Comments = len(doc.Comments)
+				}
+				rows = append(rows, row)
+			}
+		}
+	}
+	return rows
+}
+
+// LastActive returns the time of a card's last activity
+func LastActive(card fizzy.Card) time.Time {
+	switch {
+	case card.LastActiveAt != nil:
+		return *card.LastActiveAt
+	case !card.UpdatedAt.IsZero():
+		return card.UpdatedAt
+	default:
+		return card.CreatedAt
+	}
+}
+
+// cardStatus describes a card's state in words: open, closed or not now
+func cardStatus(card fizzy.Card) string {
+	switch {
+	case card.Closed:
+		return "closed"
+	case card.Status == "not_now":
+		return "not now"
+	case card.Status == "published" || card.Status == "":
+		return "open"
+	default:
+		return card.Status
+	}
+}
+
+func cardDescription(card fizzy.Card) string {
+	if card.DescriptionHTML != nil && *card.DescriptionHTML != "" {
+		return RichText(*card.DescriptionHTML)
+	}
+	if card.Description != nil {
+		return RichText(*card.Description)
+	}
+	return ""
+}
+
+func tagNames(tags []fizzy.Tag, prefix string) []string {
+	names := make([]string, 0, len(tags))
+	for _, t := range tags {
+		names = append(names, prefix+t.Name)
+	}
+	return names
+}
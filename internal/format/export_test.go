@@ -0,0 +1,57 @@
+package format
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/visionik/libfizz-go/fizzy"
+)
+
+func TestToCalendarEvents(t *testing.T) {
+	last := time.Date(2026, 3, 1, 12, 0, 0, 0, time.Local)
+	cards := []fizzy.Card{
+		{ID: "a", Number: 1, Title: "Open", Status: "published", LastActiveAt: &last},
+		{ID: "b", Number: 2, Title: "Parked", Status: "not_now", LastActiveAt: &last},
+		{ID: "c", Number: 3, Title: "Done", Status: "published", Closed: true, LastActiveAt: &last},
+	}
+
+	events := ToCalendarEvents(cards, 30)
+	require.Len(t, events, 2)
+	assert.Equal(t, "b-postponed@fizz", events[0].UID)
+	assert.Equal(t, "Postponed: #2 Parked", events[0].Summary)
+	assert.Equal(t, "2026-03-01", events[0].Date.Format("2006-01-02"))
+	assert.Equal(t, "a-due@fizz", events[1].UID)
+	assert.Equal(t, "2026-03-31", events[1].Date.Format("2006-01-02"))
+
+	assert.Len(t, ToCalendarEvents(cards, 0), 1)
+}
+
+func TestWriteICS(t *testing.T) {
+	events := []CalendarEvent{{
+		UID:         "a-due@fizz",
+		Date:        time.Date(2026, 3, 31, 0, 0, 0, 0, time.Local),
+		Summary:     "Due: #1 Fix login, then; ship",
+		Description: strings.Repeat("é", 50) + "\nnext",
+		Categories:  []string{"bug", "a,b"},
+	}}
+	var buf bytes.Buffer
+	require.NoError(t, WriteICS(&buf, "Platform", events, time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)))
+	out := buf.String()
+
+	assert.Contains(t, out, "DTSTAMP:20260301T090000Z\r\n")
+	assert.Contains(t, out, "DTSTART;VALUE=DATE:20260331\r\nDTEND;VALUE=DATE:20260401\r\n")
+	assert.Contains(t, out, `SUMMARY:Due: #1 Fix login\, then\; ship`+"\r\n")
+	assert.Contains(t, out, `CATEGORIES:bug,a\,b`+"\r\n")
+
+	for _, line := range strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n") {
+		assert.LessOrEqual(t, len(line), 75, line)
+		assert.True(t, utf8.ValidString(line), line)
+	}
+	unfolded := strings.ReplaceAll(out, "\r\n ", "")
+	assert.Contains(t, unfolded, "DESCRIPTION:"+strings.Repeat("é", 50)+`\nnext`+"\r\n")
+}
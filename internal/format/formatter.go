@@ -22,9 +22,13 @@ func NewFormatter(format string, writer io.Writer) (Formatter, error) {
 		return &TableFormatter{Writer: writer}, nil
 	case "json":
 		return &JSONFormatter{Writer: writer}, nil
+	case "jsonl":
+		return &JSONLinesFormatter{Writer: writer}, nil
 	case "yaml":
 		return &YAMLFormatter{Writer: writer}, nil
+	case "csv":
+		return &CSVFormatter{Writer: writer}, nil
 	default:
-		return nil, fmt.Errorf("unsupported format: %s (supported: table, json, yaml)", format)
+		return nil, fmt.Errorf("unsupported format: %s (supported: table, json, jsonl, yaml, csv)", format)
 	}
 }
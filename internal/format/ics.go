@@ -0,0 +1,124 @@
+package format
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/visionik/libfizz-go/fizzy"
+)
+
+// CalendarEvent is an all-day calendar entry for a card
+type CalendarEvent struct {
+	UID         string
+	Date        time.Time
+	Summary     string
+	Description string
+	URL         string
+	Categories  []string
+}
+
+// ToCalendarEvents returns an event for each postponed card, on the day of
+// its last activity, and for each open card on the day it falls due:
+// dueAfter days after its last activity, when Fizzy would move it to Not
+// Now. A dueAfter of 0 leaves open cards out. Events are sorted by date.
+func ToCalendarEvents(cards []fizzy.Card, dueAfter int) []CalendarEvent {
+	var events []CalendarEvent
+	for _, card := range cards {
+		event := CalendarEvent{
+			Description: cardDescription(card),
+			URL:         card.URL,
+			Categories:  tagNames(card.Tags, ""),
+		}
+		last := LastActive(card).Local()
+		switch {
+		case card.Closed:
+			continue
+		case card.Status == "not_now":
+			event.UID = card.ID + "-postponed@fizz"
+			event.Date = last
+			event.Summary = fmt.Sprintf("Postponed: #%d %s", card.Number, card.Title)
+		case dueAfter > 0:
+			event.UID = card.ID + "-due@fizz"
+			event.Date = last.AddDate(0, 0, dueAfter)
+			event.Summary = fmt.Sprintf("Due: #%d %s", card.Number, card.Title)
+		default:
+			continue
+		}
+		events = append(events, event)
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].Date.Before(events[j].Date) })
+	return events
+}
+
+// WriteICS writes events as an iCalendar (RFC 5545) calendar called name,
+// stamped with now
+func WriteICS(w io.Writer, name string, events []CalendarEvent, now time.Time) error {
+	var b strings.Builder
+	line := func(s string) {
+		b.WriteString(foldLine(s) + "\r\n")
+	}
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//fizz//Fizzy export//EN")
+	line("CALSCALE:GREGORIAN")
+	line("X-WR-CALNAME:" + escapeText(name))
+
+	stamp := now.UTC().Format("20060102T150405Z")
+	for _, e := range events {
+		line("BEGIN:VEVENT")
+		line("UID:" + e.UID)
+		line("DTSTAMP:" + stamp)
+		line("DTSTART;VALUE=DATE:" + e.Date.Format("20060102"))
+		line("DTEND;VALUE=DATE:" + e.Date.AddDate(0, 0, 1).Format("20060102"))
+		line("SUMMARY:" + escapeText(e.Summary))
+		if e.Description != "" {
+			line("DESCRIPTION:" + escapeText(e.Description))
+		}
+		if e.URL != "" {
+			line("URL:" + e.URL)
+		}
+		if len(e.Categories) > 0 {
+			escaped := make([]string, len(e.Categories))
+			for i, c := range e.Categories {
+				escaped[i] = escapeText(c)
+			}
+			line("CATEGORIES:" + strings.Join(escaped, ","))
+		}
+		line("END:VEVENT")
+	}
+	line("END:VCALENDAR")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+var textEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+// escapeText escapes an iCalendar TEXT value
+func escapeText(s string) string {
+	return textEscaper.Replace(s)
+}
+
+// foldLine splits a content line longer than 75 octets into continuation
+// lines starting with a space, without breaking a UTF-8 sequence
+func foldLine(s string) string {
+	const limit = 75
+	var b strings.Builder
+	width := limit
+	for len(s) > width {
+		cut := width
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		b.WriteString(s[:cut] + "\r\n ")
+		s = s[cut:]
+		// The leading space counts toward the next line's length
+		width = limit - 1
+	}
+	b.WriteString(s)
+	return b.String()
+}
@@ -3,6 +3,7 @@ package format
 import (
 	"encoding/json"
 	"io"
+	"reflect"
 )
 
 // JSONFormatter formats output as JSON
@@ -16,3 +17,25 @@ func (f *JSONFormatter) Format(data interface{}) error {
 	encoder.SetIndent("", "  ")
 	return encoder.Encode(data)
 }
+
+// JSONLinesFormatter formats output as JSON Lines: one compact JSON value
+// per line, one for each element of a slice
+type JSONLinesFormatter struct {
+	Writer io.Writer
+}
+
+// Format outputs a slice as one line per element, or anything else as a
+// single line
+func (f *JSONLinesFormatter) Format(data interface{}) error {
+	encoder := json.NewEncoder(f.Writer)
+	v := reflect.ValueOf(data)
+	if v.Kind() != reflect.Slice {
+		return encoder.Encode(data)
+	}
+	for i := 0; i < v.Len(); i++ {
+		if err := encoder.Encode(v.Index(i).Interface()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
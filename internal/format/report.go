@@ -0,0 +1,142 @@
+package format
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/visionik/fizz/internal/chart"
+	"github.com/visionik/fizz/internal/report"
+)
+
+// barWidth is the length of the longest bar in report charts
+const barWidth = 30
+
+// WriteReport renders a report for the terminal with sparkline and bar charts
+func WriteReport(w io.Writer, r *report.Report) error {
+	var b strings.Builder
+
+	section := func(title string) {
+		if b.Len() > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString(headingStyle.Sprint(title) + "\n")
+	}
+	// bars draws labelled bars aligned on the longest label
+	bars := func(labels []string, values []float64, format func(float64) string) {
+		width, top := 0, 0.0
+		for i, label := range labels {
+			width = max(width, len([]rune(label)))
+			top = max(top, values[i])
+		}
+		for i, label := range labels {
+			pad := strings.Repeat(" ", width-len([]rune(label)))
+			line := fmt.Sprintf("  %s%s  %s %s", label, pad, Bar(values[i], top, barWidth), format(values[i]))
+			b.WriteString(strings.TrimRight(line, " ") + "\n")
+		}
+	}
+	count := func(v float64) string { return strconv.Itoa(int(v)) }
+
+	section(fmt.Sprintf("Throughput (cards closed per week, %s to %s)",
+		r.From.Format("2006-01-02"), r.To.Format("2006-01-02")))
+	weekly := make([]float64, len(r.Throughput))
+	labels := make([]string, len(r.Throughput))
+	total := 0
+	for i, wk := range r.Throughput {
+		weekly[i] = float64(wk.Closed)
+		labels[i] = wk.Start.Format("2006-01-02")
+		total += wk.Closed
+	}
+	avg := 0.0
+	if len(weekly) > 0 {
+		avg = float64(total) / float64(len(weekly))
+	}
+	fmt.Fprintf(&b, "  %s  %d closed, %.1f per week\n", Sparkline(weekly), total, avg)
+	bars(labels, weekly, count)
+
+	stats := func(title string, s report.Stats) {
+		section(fmt.Sprintf("%s (%d cards)", title, s.Count))
+		if s.Count == 0 {
+			b.WriteString(labelStyle.Sprint("  no closed cards in this period") + "\n")
+			return
+		}
+		fmt.Fprintf(&b, "  p50 %s  p85 %s  p95 %s  mean %s  max %s\n",
+			formatDays(s.P50), formatDays(s.P85), formatDays(s.P95), formatDays(s.Mean), formatDays(s.Max))
+	}
+	stats("Lead time, created to closed", r.LeadTime)
+	if r.CycleTime != nil {
+		stats("Cycle time, started to closed", *r.CycleTime)
+	} else {
+		section("Cycle time")
+		b.WriteString(labelStyle.Sprint("  not available: run 'fizz sync' daily to record when cards leave triage") + "\n")
+	}
+
+	section("Work in progress")
+	if len(r.WIP) == 0 {
+		b.WriteString(labelStyle.Sprint("  no open cards") + "\n")
+	} else {
+		labels = make([]string, len(r.WIP))
+		values := make([]float64, len(r.WIP))
+		boards := map[string]bool{}
+		for _, wip := range r.WIP {
+			boards[wip.Board] = true
+		}
+		for i, wip := range r.WIP {
+			labels[i] = wip.Column
+			if len(boards) > 1 && wip.Board != "" {
+				labels[i] = wip.Board + " / " + wip.Column
+			}
+			values[i] = float64(wip.Cards)
+		}
+		bars(labels, values, count)
+	}
+
+	section("Age of open cards")
+	labels = make([]string, len(r.Aging))
+	values := make([]float64, len(r.Aging))
+	for i, bucket := range r.Aging {
+		labels[i], values[i] = bucket.Label, float64(bucket.Cards)
+	}
+	bars(labels, values, count)
+
+	if len(r.Oldest) > 0 {
+		section("Oldest open cards")
+		fmt.Fprintf(&b, "  %s\n", labelStyle.Sprintf("%6s  %-8s %-8s %-16s %s", "#", "AGE", "IDLE", "COLUMN", "TITLE"))
+		for _, c := range r.Oldest {
+			fmt.Fprintf(&b, "  %6d  %-8s %-8s %-16s %s\n",
+				c.Number, formatDays(c.Age), formatDays(c.Idle), truncate(c.Column, 16), truncate(c.Title, 50))
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// formatDays shows a number of days compactly, e.g. "3.5d"
+func formatDays(days float64) string {
+	return strconv.FormatFloat(days, 'f', 1, 64) + "d"
+}
+
+// WriteChartTable prints a chart's data as a table with a row per day
+func WriteChartTable(w io.Writer, c *chart.Chart) error {
+	table := tablewriter.NewWriter(w)
+	header := []any{"Date"}
+	for _, s := range c.Series {
+		header = append(header, s.Name)
+	}
+	table.Header(header...)
+	for d, label := range c.Labels {
+		row := []string{label}
+		for _, s := range c.Series {
+			v := 0.0
+			if d < len(s.Values) {
+				v = s.Values[d]
+			}
+			row = append(row, strconv.FormatFloat(v, 'f', -1, 64))
+		}
+		table.Append(row)
+	}
+	return table.Render()
+}
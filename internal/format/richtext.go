@@ -0,0 +1,303 @@
+package format
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/fatih/color"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+var (
+	boldStyle   = color.New(color.Bold)
+	italicStyle = color.New(color.Italic)
+	strikeStyle = color.New(color.CrossedOut)
+	codeStyle   = color.New(color.FgCyan)
+	linkStyle   = color.New(color.FgBlue, color.Underline)
+	faintStyle  = color.New(color.Faint)
+
+	spaceRun = regexp.MustCompile(`\s+`)
+)
+
+// RichText converts Fizzy rich text (HTML) to markdown for the terminal.
+// Emphasis is styled with color when enabled and spelled out with markdown
+// markers otherwise, so piped output stays readable.
+func RichText(source string) string {
+	if !isHTML(source) {
+		return strings.TrimSpace(source)
+	}
+
+	root, err := html.Parse(strings.NewReader("<body>" + source + "</body>"))
+	if err != nil {
+		return strings.TrimSpace(source)
+	}
+
+	lines := renderBlocks(findBody(root))
+	return strings.Join(trimBlank(collapseBlank(lines)), "\n")
+}
+
+// isHTML reports whether text starts with a known HTML tag, so that plain
+// text such as "a < b" is left as it is
+func isHTML(text string) bool {
+	text, ok := strings.CutPrefix(strings.TrimSpace(text), "<")
+	if !ok {
+		return false
+	}
+	end := strings.IndexFunc(text, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-')
+	})
+	if end < 0 {
+		return false
+	}
+	name := strings.ToLower(text[:end])
+	return atom.Lookup([]byte(name)) != 0 || name == "action-text-attachment"
+}
+
+func findBody(n *html.Node) *html.Node {
+	if n.Type == html.ElementNode && n.DataAtom == atom.Body {
+		return n
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if body := findBody(c); body != nil {
+			return body
+		}
+	}
+	return nil
+}
+
+// renderBlocks renders the children of n as block content, one entry per
+// output line with "" between paragraphs
+func renderBlocks(n *html.Node) []string {
+	if n == nil {
+		return nil
+	}
+
+	var lines []string
+	var inline strings.Builder
+	flush := func() {
+		text := strings.TrimSpace(inline.String())
+		inline.Reset()
+		if text == "" {
+			return
+		}
+		for _, line := range strings.Split(text, "\n") {
+			lines = append(lines, strings.TrimSpace(line))
+		}
+		lines = append(lines, "")
+	}
+	block := func(b []string) {
+		flush()
+		lines = append(lines, b...)
+		lines = append(lines, "")
+	}
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type != html.ElementNode {
+			inline.WriteString(renderInline(c))
+			continue
+		}
+
+		switch c.DataAtom {
+		case atom.P, atom.Div, atom.Section, atom.Article:
+			block(renderBlocks(c))
+		case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+			level := int(c.Data[1] - '0')
+			text := strings.Join(trimBlank(renderBlocks(c)), " ")
+			block([]string{boldStyle.Sprint(strings.Repeat("#", level) + " " + text)})
+		case atom.Ul, atom.Ol:
+			block(renderList(c))
+		case atom.Blockquote:
+			block(prefixLines(trimBlank(collapseBlank(renderBlocks(c))), "> ", "> "))
+		case atom.Pre:
+			block(renderPre(c))
+		case atom.Hr:
+			block([]string{faintStyle.Sprint("───")})
+		case atom.Figure:
+			block([]string{renderInline(c)})
+		default:
+			inline.WriteString(renderInline(c))
+		}
+	}
+	flush()
+
+	return lines
+}
+
+// renderList renders ul/ol items with hanging indents; nested lists are
+// indented under their item
+func renderList(n *html.Node) []string {
+	var lines []string
+	number := 0
+	for li := n.FirstChild; li != nil; li = li.NextSibling {
+		if li.Type != html.ElementNode || li.DataAtom != atom.Li {
+			continue
+		}
+		number++
+
+		marker := "- "
+		if n.DataAtom == atom.Ol {
+			marker = fmt.Sprintf("%d. ", number)
+		}
+		var item []string
+		for _, line := range renderBlocks(li) {
+			if line != "" {
+				item = append(item, line)
+			}
+		}
+		if len(item) == 0 {
+			item = []string{""}
+		}
+		lines = append(lines, prefixLines(item, marker, strings.Repeat(" ", len(marker)))...)
+	}
+	return lines
+}
+
+func renderPre(n *html.Node) []string {
+	text := strings.Trim(textContent(n), "\n")
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		lines = append(lines, "    "+codeStyle.Sprint(line))
+	}
+	return lines
+}
+
+// renderInline renders n as inline text; <br> becomes a newline
+func renderInline(n *html.Node) string {
+	switch n.Type {
+	case html.TextNode:
+		return spaceRun.ReplaceAllString(n.Data, " ")
+	case html.ElementNode:
+	default:
+		return ""
+	}
+
+	children := func() string {
+		var b strings.Builder
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			b.WriteString(renderInline(c))
+		}
+		return b.String()
+	}
+
+	switch n.DataAtom {
+	case atom.Br:
+		return "\n"
+	case atom.Strong, atom.B:
+		return emphasize(children(), "**", boldStyle)
+	case atom.Em, atom.I:
+		return emphasize(children(), "_", italicStyle)
+	case atom.S, atom.Del, atom.Strike:
+		return emphasize(children(), "~~", strikeStyle)
+	case atom.Code:
+		return emphasize(textContent(n), "`", codeStyle)
+	case atom.A:
+		text := strings.TrimSpace(children())
+		href := attr(n, "href")
+		if href == "" || href == text {
+			return linkStyle.Sprint(text)
+		}
+		return "[" + text + "](" + linkStyle.Sprint(href) + ")"
+	case atom.Img:
+		return faintStyle.Sprintf("[image: %s]", firstNonEmpty(attr(n, "alt"), attr(n, "src")))
+	case atom.Figure:
+		if caption := strings.TrimSpace(textContent(n)); caption != "" {
+			return faintStyle.Sprintf("[attachment: %s]", caption)
+		}
+		return children()
+	case atom.Li, atom.P, atom.Div:
+		return children() + "\n"
+	}
+
+	// Action Text embeds files as <action-text-attachment>
+	if n.Data == "action-text-attachment" {
+		name := firstNonEmpty(attr(n, "caption"), attr(n, "filename"), attr(n, "url"))
+		return faintStyle.Sprintf("[attachment: %s]", name)
+	}
+	return children()
+}
+
+// emphasize styles text when color is on and wraps it in marker otherwise
+func emphasize(text, marker string, style *color.Color) string {
+	if strings.TrimSpace(text) == "" {
+		return text
+	}
+	if color.NoColor {
+		return marker + text + marker
+	}
+	return style.Sprint(text)
+}
+
+func textContent(n *html.Node) string {
+	if n.Type == html.TextNode {
+		return n.Data
+	}
+	var b strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.ElementNode && c.DataAtom == atom.Br {
+			b.WriteString("\n")
+			continue
+		}
+		b.WriteString(textContent(c))
+	}
+	return b.String()
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// prefixLines puts first before the first line and rest before the others
+func prefixLines(lines []string, first, rest string) []string {
+	out := make([]string, len(lines))
+	for i, line := range lines {
+		prefix := rest
+		if i == 0 {
+			prefix = first
+		}
+		if line == "" {
+			out[i] = strings.TrimRight(prefix, " ")
+			continue
+		}
+		out[i] = prefix + line
+	}
+	return out
+}
+
+// collapseBlank removes runs of blank lines
+func collapseBlank(lines []string) []string {
+	var out []string
+	for _, line := range lines {
+		if line == "" && (len(out) == 0 || out[len(out)-1] == "") {
+			continue
+		}
+		out = append(out, line)
+	}
+	return out
+}
+
+// trimBlank removes leading and trailing blank lines
+func trimBlank(lines []string) []string {
+	for len(lines) > 0 && lines[0] == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
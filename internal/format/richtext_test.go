@@ -0,0 +1,39 @@
+package format
+
+import (
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRichText(t *testing.T) {
+	noColor := color.NoColor
+	color.NoColor = true
+	t.Cleanup(func() { color.NoColor = noColor })
+
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"plain text", "  Just text\n", "Just text"},
+		{"comparison", "a < b && c > d", "a < b && c > d"},
+		{"not a tag", "<3 thanks & <b or c", "<3 thanks & <b or c"},
+		{"paragraphs", "<p>One</p><p>Two<br>lines</p>", "One\n\nTwo\nlines"},
+		{"trix div", "<div>First line<br><br>Second &amp; last</div>", "First line\n\nSecond & last"},
+		{"emphasis", "<div><strong>bold</strong>, <em>italic</em>, <del>gone</del> and <code>x := 1</code></div>", "**bold**, _italic_, ~~gone~~ and `x := 1`"},
+		{"heading", "<h1>Plan</h1><div>Details</div>", "# Plan\n\nDetails"},
+		{"link", `<div>See <a href="https://example.com/doc">the doc</a> or <a href="https://example.com">https://example.com</a></div>`, "See [the doc](https://example.com/doc) or https://example.com"},
+		{"lists", "<ul><li>Apples</li><li>Pears<ol><li>Conference</li><li>Comice</li></ol></li></ul>", "- Apples\n- Pears\n  1. Conference\n  2. Comice"},
+		{"quote", "<blockquote>Quoted<br>twice</blockquote><div>after</div>", "> Quoted\n> twice\n\nafter"},
+		{"code block", "<pre>func main() {\n\tfmt.Println(1)\n}</pre>", "    func main() {\n    \tfmt.Println(1)\n    }"},
+		{"attachment", `<div>Log:</div><action-text-attachment filename="build.log" content-type="text/plain"></action-text-attachment>`, "Log:\n\n[attachment: build.log]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, RichText(tt.in))
+		})
+	}
+}
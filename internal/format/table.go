@@ -5,9 +5,12 @@ import (
 	"io"
 	"reflect"
 	"strings"
+	"time"
 
 	"github.com/fatih/color"
 	"github.com/olekukonko/tablewriter"
+	"github.com/olekukonko/tablewriter/pkg/twwidth"
+	"github.com/olekukonko/tablewriter/tw"
 )
 
 // TableFormatter formats output as a table
@@ -89,7 +92,7 @@ func (f *TableFormatter) formatSingle(data interface{}) error {
 		}
 
 		fieldName := field.Name
-		fieldValue := fmt.Sprintf("%v", v.Field(i).Interface())
+		fieldValue := formatValue(v.Field(i))
 
 		// Add color for certain fields
 		if strings.Contains(strings.ToLower(fieldName), "status") {
@@ -140,7 +143,7 @@ func (f *TableFormatter) getRow(v reflect.Value) ([]string, error) {
 			continue
 		}
 
-		value := fmt.Sprintf("%v", v.Field(i).Interface())
+		value := formatValue(v.Field(i))
 
 		// Add color for status fields
 		if strings.Contains(strings.ToLower(field.Name), "status") {
@@ -152,7 +155,44 @@ func (f *TableFormatter) getRow(v reflect.Value) ([]string, error) {
 	return row, nil
 }
 
+// formatValue renders a field value for a table cell. Pointers are
+// followed, nested records are shown by name and lists are joined.
+func formatValue(v reflect.Value) string {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return ""
+		}
+		return formatValue(v.Elem())
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return string(v.Bytes())
+		}
+		parts := make([]string, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			parts[i] = formatValue(v.Index(i))
+		}
+		return strings.Join(parts, ", ")
+	case reflect.Struct:
+		if t, ok := v.Interface().(time.Time); ok {
+			if t.IsZero() {
+				return ""
+			}
+			return t.Format("2006-01-02 15:04")
+		}
+		if name := v.FieldByName("Name"); name.IsValid() && name.Kind() == reflect.String {
+			return name.String()
+		}
+	}
+	return fmt.Sprintf("%v", v.Interface())
+}
+
 func (f *TableFormatter) colorizeStatus(status string) string {
+	return colorizeStatus(status)
+}
+
+// colorizeStatus colors a status by meaning when colors are enabled
+func colorizeStatus(status string) string {
 	// Check if colors are disabled
 	if color.NoColor {
 		return status
@@ -172,3 +212,72 @@ func (f *TableFormatter) colorizeStatus(status string) string {
 		return status
 	}
 }
+
+// TableStream writes a table whose rows arrive in batches, such as the
+// pages of a long list, so the first rows show before the rest are fetched.
+// The header and the first batch fix the column widths; longer cells in
+// later batches wrap.
+type TableStream struct {
+	Writer io.Writer
+
+	formatter TableFormatter
+	table     *tablewriter.Table
+}
+
+// Append writes a slice of records as rows
+func (s *TableStream) Append(data interface{}) error {
+	v := reflect.ValueOf(data)
+	if v.Kind() != reflect.Slice || v.Len() == 0 {
+		return nil
+	}
+	rows := make([][]string, v.Len())
+	for i := range rows {
+		row, err := s.formatter.getRow(v.Index(i))
+		if err != nil {
+			return err
+		}
+		rows[i] = row
+	}
+
+	if s.table == nil {
+		headers, err := s.formatter.getHeaders(v.Index(0))
+		if err != nil {
+			return err
+		}
+		widths := tw.NewMapper[int, int]()
+		for col, header := range headers {
+			width := twwidth.Width(header)
+			for _, row := range rows {
+				width = max(width, twwidth.Width(row[col]))
+			}
+			// Cells are padded by a space on either side
+			widths.Set(col, width+2)
+		}
+		s.table = tablewriter.NewTable(s.Writer,
+			tablewriter.WithStreaming(tw.StreamConfig{Enable: true}),
+			tablewriter.WithColumnWidths(widths))
+		if err := s.table.Start(); err != nil {
+			return err
+		}
+		headerAny := make([]any, len(headers))
+		for i, h := range headers {
+			headerAny[i] = h
+		}
+		s.table.Header(headerAny...)
+	}
+	for _, row := range rows {
+		if err := s.table.Append(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close ends the table, or says there were no results if no rows came
+func (s *TableStream) Close() error {
+	if s.table == nil {
+		fmt.Fprintln(s.Writer, "No results found")
+		return nil
+	}
+	return s.table.Close()
+}
@@ -0,0 +1,78 @@
+package format
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "rewrite golden files")
+
+// golden compares got with testdata/golden/name.golden, or rewrites it
+// when -update is set
+func golden(t *testing.T, name, got string) {
+	t.Helper()
+	path := filepath.Join("testdata", "golden", name+".golden")
+	if *update {
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(got), 0o644))
+		return
+	}
+	want, err := os.ReadFile(path)
+	require.NoError(t, err, "missing golden file; run go test ./internal/format -update")
+	assert.Equal(t, string(want), got, "output differs from %s", path)
+}
+
+type cellUser struct {
+	ID   string
+	Name string
+}
+
+type cellRow struct {
+	Title   string
+	Created time.Time
+	Closed  time.Time
+	Creator cellUser
+	Tags    []cellUser
+	Parent  *int
+}
+
+// TestTableCells shows how table cells render times, nested records, lists
+// and pointers, next to the fmt %v cells they replaced
+func TestTableCells(t *testing.T) {
+	rows := []cellRow{
+		{
+			Title:   "Upgrade the database cluster",
+			Created: time.Date(2026, 1, 5, 9, 13, 0, 0, time.UTC),
+			Creator: cellUser{ID: "u1", Name: "Jordan Lee"},
+			Tags:    []cellUser{{ID: "t1", Name: "infra"}, {ID: "t2", Name: "bug"}},
+		},
+	}
+
+	var beforeOut bytes.Buffer
+	before := tablewriter.NewWriter(&beforeOut)
+	before.Header("Title", "Created", "Closed", "Creator", "Tags", "Parent")
+	for _, row := range rows {
+		v := reflect.ValueOf(row)
+		cells := make([]string, v.NumField())
+		for i := range cells {
+			cells[i] = fmt.Sprintf("%v", v.Field(i).Interface())
+		}
+		before.Append(cells)
+	}
+	require.NoError(t, before.Render())
+	golden(t, "table_cells.before", beforeOut.String())
+
+	var after bytes.Buffer
+	require.NoError(t, (&TableFormatter{Writer: &after}).Format(rows))
+	golden(t, "table_cells.after", after.String())
+}
@@ -0,0 +1,284 @@
+// Package git runs the git commands behind fizz's git integration. It only
+// needs a local repository; remotes are used for commit links when present.
+package git
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+// Repo is a git working tree
+type Repo struct {
+	// Dir is the directory git runs in; "" is the working directory
+	Dir string
+}
+
+// Commit is one entry of a commit range
+type Commit struct {
+	SHA     string
+	Subject string
+}
+
+// Git config keys fizz stores on branches it starts
+const (
+	cardKey = "fizz-card"
+	baseKey = "fizz-base"
+)
+
+// Run runs git with args and returns its trimmed output
+func (r Repo) Run(args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = r.Dir
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = err.Error()
+		}
+		return "", fmt.Errorf("git %s: %s", args[0], msg)
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// Check returns an error if r is not inside a git working tree
+func (r Repo) Check() error {
+	if _, err := exec.LookPath("git"); err != nil {
+		return errors.New("git is not installed")
+	}
+	if _, err := r.Run("rev-parse", "--is-inside-work-tree"); err != nil {
+		return errors.New("not a git repository")
+	}
+	return nil
+}
+
+// CurrentBranch returns the checked-out branch, or "" on a detached HEAD
+func (r Repo) CurrentBranch() string {
+	branch, err := r.Run("symbolic-ref", "--quiet", "--short", "HEAD")
+	if err != nil {
+		return ""
+	}
+	return branch
+}
+
+// BranchExists reports whether a local branch exists
+func (r Repo) BranchExists(name string) bool {
+	_, err := r.Run("rev-parse", "--verify", "--quiet", "refs/heads/"+name)
+	return err == nil
+}
+
+// Start checks out branch, creating it from HEAD if needed, and records the
+// card and starting commit on it. It reports whether the branch was created.
+func (r Repo) Start(branch string, number int) (bool, error) {
+	if _, err := r.Run("check-ref-format", "--branch", branch); err != nil {
+		return false, fmt.Errorf("invalid branch name %q", branch)
+	}
+
+	created := !r.BranchExists(branch)
+	if created {
+		base, err := r.Run("rev-parse", "HEAD")
+		if err != nil {
+			return false, err
+		}
+		if _, err := r.Run("checkout", "-b", branch); err != nil {
+			return false, err
+		}
+		if _, err := r.Run("config", "branch."+branch+"."+baseKey, base); err != nil {
+			return false, err
+		}
+	} else if _, err := r.Run("checkout", branch); err != nil {
+		return false, err
+	}
+
+	if _, err := r.Run("config", "branch."+branch+"."+cardKey, strconv.Itoa(number)); err != nil {
+		return false, err
+	}
+	return created, nil
+}
+
+// RecordedCard returns the card Start recorded on a branch
+func (r Repo) RecordedCard(branch string) (int, bool) {
+	if branch == "" {
+		return 0, false
+	}
+	value, err := r.Run("config", "branch."+branch+"."+cardKey)
+	if err != nil {
+		return 0, false
+	}
+	n, err := strconv.Atoi(value)
+	return n, err == nil
+}
+
+var leadingNumber = regexp.MustCompile(`^(\d+)(?:[/_-]|$)`)
+
+// BranchCard guesses a branch's card from a number at the very start of its
+// name, as in 42-fix-login. It is only a guess: names such as 2026-release
+// match too, so callers confirm it before writing to the card.
+func BranchCard(branch string) (int, bool) {
+	m := leadingNumber.FindStringSubmatch(branch)
+	if m == nil {
+		return 0, false
+	}
+	n, err := strconv.Atoi(m[1])
+	return n, err == nil && n > 0
+}
+
+// Base returns where the current branch's commits start: the commit
+// recorded by Start, or the merge base with the default branch
+func (r Repo) Base(branch string) (string, error) {
+	if branch != "" {
+		if base, err := r.Run("config", "branch."+branch+"."+baseKey); err == nil {
+			return base, nil
+		}
+	}
+	for _, ref := range []string{"origin/HEAD", "origin/main", "origin/master", "main", "master"} {
+		if ref == branch {
+			continue
+		}
+		if _, err := r.Run("rev-parse", "--verify", "--quiet", ref); err != nil {
+			continue
+		}
+		return r.Run("merge-base", "HEAD", ref)
+	}
+	return "", errors.New("cannot find where this branch starts; pass a range such as main..HEAD")
+}
+
+// Log returns the commits in a revision range, oldest first
+func (r Repo) Log(revRange string) ([]Commit, error) {
+	out, err := r.Run("log", "--reverse", "--format=%H%x09%s", revRange)
+	if err != nil {
+		return nil, err
+	}
+	var commits []Commit
+	for _, line := range strings.Split(out, "\n") {
+		if line == "" {
+			continue
+		}
+		sha, subject, _ := strings.Cut(line, "\t")
+		commits = append(commits, Commit{SHA: sha, Subject: subject})
+	}
+	return commits, nil
+}
+
+// HooksDir returns the directory git runs hooks from, honouring core.hooksPath
+func (r Repo) HooksDir() (string, error) {
+	dir, err := r.Run("rev-parse", "--git-path", "hooks")
+	if err != nil {
+		return "", err
+	}
+	if filepath.IsAbs(dir) {
+		return dir, nil
+	}
+	base := r.Dir
+	if base == "" {
+		if base, err = os.Getwd(); err != nil {
+			return "", err
+		}
+	}
+	return filepath.Join(base, dir), nil
+}
+
+// AddTrailer adds "key: value" to the commit message in file unless a
+// trailer with that key is already there
+func (r Repo) AddTrailer(file, key, value string) error {
+	_, err := r.Run("interpret-trailers", "--in-place", "--if-exists", "doNothing",
+		"--trailer", key+": "+value, file)
+	return err
+}
+
+// WebURL returns the browsable URL of the origin remote, or "" if there is
+// none or it is not a recognisable host
+func (r Repo) WebURL() string {
+	remote, err := r.Run("remote", "get-url", "origin")
+	if err != nil {
+		return ""
+	}
+	return webURL(remote)
+}
+
+var scpRemote = regexp.MustCompile(`^(?:[\w.-]+@)?([\w.-]+):(.+)$`)
+
+// webURL converts a remote such as git@github.com:org/repo.git to
+// https://github.com/org/repo
+func webURL(remote string) string {
+	remote = strings.TrimSuffix(strings.TrimSpace(remote), ".git")
+	if strings.Contains(remote, "://") {
+		u, err := url.Parse(remote)
+		if err != nil || u.Host == "" {
+			return ""
+		}
+		switch u.Scheme {
+		case "http", "https", "ssh", "git":
+		default:
+			return ""
+		}
+		return "https://" + u.Hostname() + u.Path
+	}
+	if m := scpRemote.FindStringSubmatch(remote); m != nil && !strings.HasPrefix(m[2], "/") {
+		return "https://" + m[1] + "/" + m[2]
+	}
+	return ""
+}
+
+// BranchData is what branch templates can use
+type BranchData struct {
+	Number int
+	Title  string
+	ID     string
+	Board  string
+	User   string
+}
+
+// BranchName renders a branch template such as "{{.Number}}-{{slug .Title}}"
+func BranchName(tmpl string, data BranchData) (string, error) {
+	t, err := template.New("branch").Funcs(template.FuncMap{
+		"slug":  Slug,
+		"lower": strings.ToLower,
+	}).Option("missingkey=error").Parse(tmpl)
+	if err != nil {
+		return "", fmt.Errorf("invalid branch template: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("invalid branch template: %w", err)
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
+
+// maxSlug keeps branch names a comfortable length
+const maxSlug = 50
+
+// Slug turns text into a lowercase, hyphenated branch-name fragment
+func Slug(text string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(text) {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			b.WriteRune(r)
+			dash = false
+		} else if !dash && b.Len() > 0 {
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	slug := strings.TrimSuffix(b.String(), "-")
+	if len(slug) > maxSlug {
+		slug = slug[:maxSlug]
+		if i := strings.LastIndex(slug, "-"); i > maxSlug/2 {
+			slug = slug[:i]
+		}
+		slug = strings.TrimSuffix(slug, "-")
+	}
+	return slug
+}
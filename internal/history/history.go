@@ -0,0 +1,292 @@
+// Package history stores daily snapshots of boards so charts and metrics
+// can look at how a board changed over time, which the API does not expose.
+package history
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/visionik/fizz/internal/config"
+	"github.com/visionik/libfizz-go/fizzy"
+)
+
+// Version is the snapshot format written by this version of fizz
+const Version = 1
+
+// dayFormat names snapshot files, one per board per day
+const dayFormat = "2006-01-02"
+
+// fileSuffix ends every snapshot file name
+const fileSuffix = ".json.gz"
+
+// Snapshot is the state of one board at one time
+type Snapshot struct {
+	Version int       `json:"version"`
+	TakenAt time.Time `json:"taken_at"`
+	Board   Board     `json:"board"`
+	Columns []Column  `json:"columns"`
+	Cards   []Card    `json:"cards"`
+}
+
+// Board identifies the board a snapshot is of
+type Board struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// Column is a board column at snapshot time
+type Column struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Position int    `json:"position"`
+}
+
+// Card is the part of a card's state that snapshots keep
+type Card struct {
+	ID        string     `json:"id"`
+	Number    int        `json:"number"`
+	Title     string     `json:"title"`
+	Status    string     `json:"status"`
+	Closed    bool       `json:"closed,omitempty"`
+	ColumnID  string     `json:"column_id,omitempty"`
+	Tags      []string   `json:"tags,omitempty"`
+	Assignees []string   `json:"assignees,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	ClosedAt  *time.Time `json:"closed_at,omitempty"`
+}
+
+// HasAssignee reports whether the card is assigned to the user ID
+func (c Card) HasAssignee(id string) bool {
+	for _, a := range c.Assignees {
+		if a == id {
+			return true
+		}
+	}
+	return false
+}
+
+// New builds a snapshot of a board from its columns and cards
+func New(board fizzy.Board, columns []fizzy.Column, cards []fizzy.Card, at time.Time) *Snapshot {
+	s := &Snapshot{
+		Version: Version,
+		TakenAt: at,
+		Board:   Board{ID: board.ID, Name: board.Name},
+		Columns: make([]Column, len(columns)),
+		Cards:   make([]Card, len(cards)),
+	}
+	for i, col := range columns {
+		s.Columns[i] = Column{ID: col.ID, Name: col.Name, Position: col.Position}
+	}
+	for i, card := range cards {
+		c := Card{
+			ID:        card.ID,
+			Number:    card.Number,
+			Title:     card.Title,
+			Status:    card.Status,
+			Closed:    card.Closed || card.ClosedAt != nil,
+			CreatedAt: card.CreatedAt,
+			ClosedAt:  card.ClosedAt,
+		}
+		if card.ColumnID != nil {
+			c.ColumnID = *card.ColumnID
+		}
+		for _, tag := range card.Tags {
+			c.Tags = append(c.Tags, tag.Name)
+		}
+		for _, user := range card.Assignees {
+			c.Assignees = append(c.Assignees, user.ID)
+		}
+		s.Cards[i] = c
+	}
+	sort.Slice(s.Columns, func(i, j int) bool { return s.Columns[i].Position < s.Columns[j].Position })
+	return s
+}
+
+// Day returns the local date a snapshot counts for
+func (s *Snapshot) Day() string {
+	return s.TakenAt.Local().Format(dayFormat)
+}
+
+// Store keeps snapshots as gzipped JSON files, one directory per board and
+// one file per day
+type Store struct {
+	Dir string
+}
+
+// DefaultStore returns the store in $FIZZ_HISTORY_DIR, or the history
+// directory of the fizz config directory
+func DefaultStore() (*Store, error) {
+	if dir := os.Getenv("FIZZ_HISTORY_DIR"); dir != "" {
+		return &Store{Dir: dir}, nil
+	}
+	dir, err := config.Dir()
+	if err != nil {
+		return nil, err
+	}
+	return &Store{Dir: filepath.Join(dir, "history")}, nil
+}
+
+// Save writes s as its board's snapshot for the day, replacing an earlier
+// one from the same day, and returns the file's path
+func (st *Store) Save(s *Snapshot) (string, error) {
+	if s.Board.ID == "" || strings.ContainsAny(s.Board.ID, `/\`) || strings.HasPrefix(s.Board.ID, ".") {
+		return "", fmt.Errorf("invalid board ID %q", s.Board.ID)
+	}
+	dir := filepath.Join(st.Dir, s.Board.ID)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create history directory: %w", err)
+	}
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if err := json.NewEncoder(zw).Encode(s); err != nil {
+		return "", fmt.Errorf("failed to encode snapshot: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return "", fmt.Errorf("failed to encode snapshot: %w", err)
+	}
+
+	path := filepath.Join(dir, s.Day()+fileSuffix)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, buf.Bytes(), 0o644); err != nil {
+		return "", fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return "", fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	return path, nil
+}
+
+// Boards returns the IDs of boards with snapshots
+func (st *Store) Boards() ([]string, error) {
+	entries, err := os.ReadDir(st.Dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history: %w", err)
+	}
+	var ids []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			ids = append(ids, entry.Name())
+		}
+	}
+	return ids, nil
+}
+
+// Load returns a board's snapshots for the days from..to (inclusive, as
+// YYYY-MM-DD; "" leaves that end open), oldest first
+func (st *Store) Load(boardID, from, to string) ([]*Snapshot, error) {
+	dir := filepath.Join(st.Dir, boardID)
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history: %w", err)
+	}
+
+	var snaps []*Snapshot
+	for _, entry := range entries {
+		day, ok := strings.CutSuffix(entry.Name(), fileSuffix)
+		if !ok || (from != "" && day < from) || (to != "" && day > to) {
+			continue
+		}
+		s, err := read(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		snaps = append(snaps, s)
+	}
+	sort.Slice(snaps, func(i, j int) bool { return snaps[i].TakenAt.Before(snaps[j].TakenAt) })
+	return snaps, nil
+}
+
+func read(path string) (*Snapshot, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot: %w", err)
+	}
+	defer f.Close()
+
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot %s: %w", path, err)
+	}
+	data, err := io.ReadAll(zr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot %s: %w", path, err)
+	}
+	s := &Snapshot{}
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("failed to parse snapshot %s: %w", path, err)
+	}
+	if s.Version > Version {
+		return nil, fmt.Errorf("snapshot %s has format version %d; upgrade fizz to read it", path, s.Version)
+	}
+	return s, nil
+}
+
+// Days returns every date from from to to, inclusive, as YYYY-MM-DD
+func Days(from, to time.Time) []string {
+	var days []string
+	start := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, from.Location())
+	end := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, from.Location())
+	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+		days = append(days, d.Format(dayFormat))
+	}
+	return days
+}
+
+// Daily picks, for each day, the latest snapshot taken on or before it.
+// Days before the first snapshot get nil.
+func Daily(snaps []*Snapshot, days []string) []*Snapshot {
+	out := make([]*Snapshot, len(days))
+	i := 0
+	var current *Snapshot
+	for d, day := range days {
+		for i < len(snaps) && snaps[i].Day() <= day {
+			current = snaps[i]
+			i++
+		}
+		out[d] = current
+	}
+	return out
+}
+
+// Starts estimates when work started on each card: the first snapshot that
+// shows it in a column. Only cards seen in triage first, or created after
+// history began, are included, since for the rest the start is unknown.
+func Starts(snaps []*Snapshot) map[string]time.Time {
+	starts := map[string]time.Time{}
+	if len(snaps) == 0 {
+		return starts
+	}
+	begin := snaps[0].TakenAt
+	triaged := map[string]bool{}
+	for _, s := range snaps {
+		for _, c := range s.Cards {
+			if _, ok := starts[c.ID]; ok {
+				continue
+			}
+			switch {
+			case c.Closed || c.Status == "not_now":
+			case c.ColumnID == "":
+				triaged[c.ID] = true
+			case triaged[c.ID] || c.CreatedAt.After(begin):
+				starts[c.ID] = s.TakenAt
+			}
+		}
+	}
+	return starts
+}
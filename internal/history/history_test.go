@@ -0,0 +1,105 @@
+package history
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/visionik/fizz/internal/kanban"
+	"github.com/visionik/libfizz-go/fizzy"
+)
+
+func at(day, hour int) time.Time {
+	return time.Date(2026, time.March, day, hour, 0, 0, 0, time.Local)
+}
+
+func snapshot(day int, cards ...Card) *Snapshot {
+	return &Snapshot{
+		Version: Version,
+		TakenAt: at(day, 18),
+		Board:   Board{ID: "b1", Name: "Platform"},
+		Columns: []Column{{ID: "c1", Name: "Doing", Position: 1}},
+		Cards:   cards,
+	}
+}
+
+func TestNew(t *testing.T) {
+	doing := "c1"
+	s := New(
+		fizzy.Board{ID: "b1", Name: "Platform"},
+		[]fizzy.Column{{ID: "c2", Name: "Done", Position: 2}, {ID: "c1", Name: "Doing", Position: 1}},
+		[]fizzy.Card{{
+			ID: "k1", Number: 7, Title: "Ship it", Status: "published", ColumnID: &doing,
+			Tags:      []fizzy.Tag{{ID: "t1", Name: "milestone-4"}},
+			Assignees: []fizzy.User{{ID: "u1", Name: "Jordan"}},
+		}},
+		at(2, 9),
+	)
+	assert.Equal(t, "Doing", s.Columns[0].Name)
+	assert.Equal(t, Card{ID: "k1", Number: 7, Title: "Ship it", Status: "published", ColumnID: "c1",
+		Tags: []string{"milestone-4"}, Assignees: []string{"u1"}}, s.Cards[0])
+	assert.True(t, kanban.HasTag(s.Cards[0].Tags, "#Milestone-4"))
+	assert.Equal(t, "2026-03-02", s.Day())
+}
+
+func TestStoreSaveLoad(t *testing.T) {
+	store := &Store{Dir: t.TempDir()}
+
+	for _, day := range []int{3, 1, 2} {
+		_, err := store.Save(snapshot(day, Card{ID: "k1"}))
+		require.NoError(t, err)
+	}
+	// A later snapshot on the same day replaces the earlier one
+	later := snapshot(2, Card{ID: "k1"}, Card{ID: "k2"})
+	later.TakenAt = at(2, 20)
+	path, err := store.Save(later)
+	require.NoError(t, err)
+	assert.FileExists(t, path)
+
+	snaps, err := store.Load("b1", "2026-03-02", "")
+	require.NoError(t, err)
+	require.Len(t, snaps, 2)
+	assert.Equal(t, "2026-03-02", snaps[0].Day())
+	assert.Len(t, snaps[0].Cards, 2)
+	assert.Equal(t, "2026-03-03", snaps[1].Day())
+
+	boards, err := store.Boards()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"b1"}, boards)
+
+	snaps, err = store.Load("missing", "", "")
+	require.NoError(t, err)
+	assert.Empty(t, snaps)
+
+	_, err = store.Save(&Snapshot{Board: Board{ID: "../x"}})
+	assert.Error(t, err)
+}
+
+func TestDaily(t *testing.T) {
+	s2, s4 := snapshot(2), snapshot(4)
+	days := Days(at(1, 12), at(5, 8))
+	assert.Equal(t, []string{"2026-03-01", "2026-03-02", "2026-03-03", "2026-03-04", "2026-03-05"}, days)
+	assert.Equal(t, []*Snapshot{nil, s2, s2, s4, s4}, Daily([]*Snapshot{s2, s4}, days))
+}
+
+func TestStarts(t *testing.T) {
+	snaps := []*Snapshot{
+		snapshot(1,
+			Card{ID: "triaged", CreatedAt: at(1, 9)},
+			Card{ID: "already-started", ColumnID: "c1", CreatedAt: at(1, 9)},
+		),
+		snapshot(2,
+			Card{ID: "triaged", CreatedAt: at(1, 9)},
+			Card{ID: "already-started", ColumnID: "c1", CreatedAt: at(1, 9)},
+			Card{ID: "new", ColumnID: "c1", CreatedAt: at(2, 10)},
+		),
+		snapshot(3,
+			Card{ID: "triaged", ColumnID: "c1", CreatedAt: at(1, 9)},
+		),
+	}
+	assert.Equal(t, map[string]time.Time{
+		"new":     at(2, 18),
+		"triaged": at(3, 18),
+	}, Starts(snaps))
+}
@@ -0,0 +1,221 @@
+// Package housekeep finds open cards nobody has touched for a while and
+// decides what configured rules do to them.
+package housekeep
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/visionik/fizz/internal/config"
+	"github.com/visionik/fizz/internal/kanban"
+	"github.com/visionik/libfizz-go/fizzy"
+)
+
+// LogFileName is the audit log in the config directory, one JSON entry per
+// line
+const LogFileName = "housekeep.log"
+
+// Rule is a validated housekeeping rule
+type Rule struct {
+	config.HousekeepRule
+}
+
+// Rules validates the rules in settings
+func Rules(settings *config.Settings) ([]Rule, error) {
+	seen := map[string]bool{}
+	rules := make([]Rule, 0, len(settings.Housekeeping))
+	for i, hr := range settings.Housekeeping {
+		if hr.Name == "" {
+			return nil, fmt.Errorf("housekeeping rule %d has no name", i+1)
+		}
+		if seen[hr.Name] {
+			return nil, fmt.Errorf("housekeeping rule %s is defined twice", hr.Name)
+		}
+		seen[hr.Name] = true
+
+		if hr.IdleDays < 1 {
+			return nil, fmt.Errorf("housekeeping rule %s needs idle_days of at least 1", hr.Name)
+		}
+		a := hr.Actions
+		if a.Tag == "" && a.Comment == "" && !a.Postpone && !a.Close {
+			return nil, fmt.Errorf("housekeeping rule %s has no actions", hr.Name)
+		}
+		if a.Postpone && a.Close {
+			return nil, fmt.Errorf("housekeeping rule %s cannot both postpone and close", hr.Name)
+		}
+		hr.Actions.Tag = strings.TrimPrefix(a.Tag, "#")
+		rules = append(rules, Rule{HousekeepRule: hr})
+	}
+	return rules, nil
+}
+
+// Find returns the rule called name
+func Find(rules []Rule, name string) (*Rule, error) {
+	for i := range rules {
+		if rules[i].Name == name {
+			return &rules[i], nil
+		}
+	}
+	return nil, fmt.Errorf("housekeeping rule %s not found", name)
+}
+
+// Match reports whether the rule applies to an open card in the named
+// column (kanban.TriageColumn when it is in none). Cards already carrying the
+// rule's tag are skipped, since tagging them again would remove it.
+func (r Rule) Match(card fizzy.Card, column string, now time.Time) bool {
+	if card.Closed || card.ClosedAt != nil || card.Status == "not_now" {
+		return false
+	}
+	if r.Board != "" && kanban.BoardID(&card) != r.Board {
+		return false
+	}
+	if r.Column != "" && !strings.EqualFold(column, r.Column) &&
+		(card.ColumnID == nil || *card.ColumnID != r.Column) {
+		return false
+	}
+	for _, tag := range r.Tags {
+		if !kanban.HasTag(card.Tags, tag) {
+			return false
+		}
+	}
+	if r.Actions.Tag != "" && kanban.HasTag(card.Tags, r.Actions.Tag) {
+		return false
+	}
+	return IdleDays(card, now) >= r.IdleDays
+}
+
+// Describe lists what the rule does, in the order it does it
+func (r Rule) Describe() []string {
+	var actions []string
+	if r.Actions.Tag != "" {
+		actions = append(actions, "tag "+r.Actions.Tag)
+	}
+	if r.Actions.Comment != "" {
+		actions = append(actions, "comment")
+	}
+	if r.Actions.Postpone {
+		actions = append(actions, "postpone")
+	}
+	if r.Actions.Close {
+		actions = append(actions, "close")
+	}
+	return actions
+}
+
+// Removes reports whether the rule takes cards off the board, so later
+// rules should not see them
+func (r Rule) Removes() bool {
+	return r.Actions.Postpone || r.Actions.Close
+}
+
+// Comment returns the rule's comment for a card, with {{days}}, {{rule}}
+// and {{title}} filled in
+func (r Rule) Comment(card fizzy.Card, now time.Time) string {
+	return strings.NewReplacer(
+		"{{days}}", strconv.Itoa(IdleDays(card, now)),
+		"{{rule}}", r.Name,
+		"{{title}}", card.Title,
+	).Replace(r.Actions.Comment)
+}
+
+// IdleDays returns the whole days since the card's last activity
+func IdleDays(card fizzy.Card, now time.Time) int {
+	last := card.CreatedAt
+	switch {
+	case card.LastActiveAt != nil:
+		last = *card.LastActiveAt
+	case !card.UpdatedAt.IsZero():
+		last = card.UpdatedAt
+	}
+	if now.Before(last) {
+		return 0
+	}
+	return int(now.Sub(last).Hours() / 24)
+}
+
+// Entry is one card a rule acted on, as recorded in the audit log
+type Entry struct {
+	Time     time.Time `json:"time"`
+	Rule     string    `json:"rule"`
+	CardID   string    `json:"card_id"`
+	Card     int       `json:"card"`
+	Title    string    `json:"title"`
+	IdleDays int       `json:"idle_days"`
+	Actions  []string  `json:"actions"`
+	Error    string    `json:"error,omitempty"`
+}
+
+// LogPath returns the audit log's location
+func LogPath() (string, error) {
+	dir, err := config.Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, LogFileName), nil
+}
+
+// Record appends entries to the audit log
+func Record(entries ...Entry) error {
+	path, err := LogPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to write housekeeping log: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to write housekeeping log: %w", err)
+	}
+	defer f.Close()
+
+	enc := json.NewEncoder(f)
+	for _, e := range entries {
+		if err := enc.Encode(e); err != nil {
+			return fmt.Errorf("failed to write housekeeping log: %w", err)
+		}
+	}
+	return nil
+}
+
+// ReadLog returns the audit log's entries, oldest first; a missing log has
+// none
+func ReadLog() ([]Entry, error) {
+	path, err := LogPath()
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read housekeeping log: %w", err)
+	}
+	defer f.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(strings.TrimSpace(scanner.Text())) == 0 {
+			continue
+		}
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			return nil, fmt.Errorf("failed to parse %s line %d: %w", path, line, err)
+		}
+		entries = append(entries, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read housekeeping log: %w", err)
+	}
+	return entries, nil
+}
@@ -0,0 +1,74 @@
+package housekeep
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/visionik/fizz/internal/config"
+	"github.com/visionik/libfizz-go/fizzy"
+)
+
+func TestMatch(t *testing.T) {
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	last := now.AddDate(0, 0, -100)
+	column := "col1"
+	card := fizzy.Card{
+		ID:           "c1",
+		BoardID:      "b1",
+		Status:       "published",
+		ColumnID:     &column,
+		LastActiveAt: &last,
+		Tags:         []fizzy.Tag{{ID: "t1", Name: "infra"}},
+	}
+	rule := func(hr config.HousekeepRule) Rule {
+		hr.Name = "r"
+		if hr.IdleDays == 0 {
+			hr.IdleDays = 90
+		}
+		rules, err := Rules(&config.Settings{Housekeeping: []config.HousekeepRule{hr}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return rules[0]
+	}
+	comment := config.HousekeepActions{Comment: "idle"}
+
+	assert.True(t, rule(config.HousekeepRule{Actions: comment}).Match(card, "Backlog", now))
+	assert.False(t, rule(config.HousekeepRule{IdleDays: 101, Actions: comment}).Match(card, "Backlog", now))
+	assert.True(t, rule(config.HousekeepRule{Column: "backlog", Actions: comment}).Match(card, "Backlog", now))
+	assert.True(t, rule(config.HousekeepRule{Column: "col1", Actions: comment}).Match(card, "Backlog", now))
+	assert.False(t, rule(config.HousekeepRule{Column: "Doing", Actions: comment}).Match(card, "Backlog", now))
+	assert.False(t, rule(config.HousekeepRule{Board: "b2", Actions: comment}).Match(card, "Backlog", now))
+	assert.True(t, rule(config.HousekeepRule{Tags: []string{"#Infra"}, Actions: comment}).Match(card, "Backlog", now))
+	assert.False(t, rule(config.HousekeepRule{Tags: []string{"bug"}, Actions: comment}).Match(card, "Backlog", now))
+
+	// A card with the rule's tag is left alone, since tagging toggles
+	tagged := rule(config.HousekeepRule{Actions: config.HousekeepActions{Tag: "#infra"}})
+	assert.False(t, tagged.Match(card, "Backlog", now))
+
+	postponed := card
+	postponed.Status = "not_now"
+	assert.False(t, rule(config.HousekeepRule{Actions: comment}).Match(postponed, "Backlog", now))
+}
+
+func TestIdleDays(t *testing.T) {
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	card := fizzy.Card{CreatedAt: now.AddDate(0, 0, -30)}
+	assert.Equal(t, 30, IdleDays(card, now))
+
+	card.UpdatedAt = now.AddDate(0, 0, -10).Add(time.Hour)
+	assert.Equal(t, 9, IdleDays(card, now))
+
+	active := now.AddDate(0, 0, -5)
+	card.LastActiveAt = &active
+	assert.Equal(t, 5, IdleDays(card, now))
+	assert.Equal(t, 0, IdleDays(card, active.Add(-time.Hour)))
+}
+
+func TestComment(t *testing.T) {
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	r := Rule{config.HousekeepRule{Name: "stale", Actions: config.HousekeepActions{Comment: "{{title}}: idle {{days}} days ({{rule}})"}}}
+	card := fizzy.Card{Title: "Fix it", CreatedAt: now.AddDate(0, 0, -42)}
+	assert.Equal(t, "Fix it: idle 42 days (stale)", r.Comment(card, now))
+}
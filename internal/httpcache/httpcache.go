@@ -0,0 +1,397 @@
+// Package httpcache keeps API responses on disk so repeated reads, such as
+// listing the cards of a big board, revalidate with ETag or Last-Modified
+// instead of downloading everything again. Each account and token has its
+// own directory, so no response is ever served to another account.
+package httpcache
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/visionik/fizz/internal/config"
+)
+
+// Header tells how a response was served: Hit from the cache without
+// asking the API, Revalidated from the cache after a 304
+const (
+	Header      = "X-Fizz-Cache"
+	Hit         = "hit"
+	Revalidated = "revalidated"
+)
+
+// writtenFile is touched in an account's directory when fizz changes
+// something through it; responses stored before then are revalidated
+// even if they are fresh
+const writtenFile = "written"
+
+// MaxAge is how long a response is kept without being stored or
+// revalidated again
+const MaxAge = 30 * 24 * time.Hour
+
+// prunedFile is touched in an account's directory when responses older
+// than MaxAge are removed from it, which saving does at most every
+// pruneEvery
+const (
+	prunedFile = "pruned"
+	pruneEvery = 24 * time.Hour
+)
+
+// Dir returns the directory holding every account's cached responses
+func Dir() (string, error) {
+	dir, err := config.CacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "http"), nil
+}
+
+// AccountDir returns the directory for the responses one account sees
+// with one token, named by a hash so neither appears on disk
+func AccountDir(account, token string) (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(account + "\n" + token))
+	return filepath.Join(dir, hex.EncodeToString(sum[:12])), nil
+}
+
+// entry is the file format of a cached response
+type entry struct {
+	URL      string      `json:"url"`
+	Header   http.Header `json:"header"`
+	Body     []byte      `json:"body"`
+	StoredAt time.Time   `json:"stored_at"`
+}
+
+// Transport serves JSON GETs from the cache in Dir. A response younger than
+// TTL is served without asking the API; older ones are revalidated. Errors
+// reading or writing the cache only mean asking the API.
+type Transport struct {
+	Next  http.RoundTripper
+	Dir   string
+	Debug bool
+
+	mu       sync.RWMutex
+	disabled bool
+	ttl      time.Duration
+
+	// now is the clock; tests replace it
+	now func() time.Time
+}
+
+// NewTransport returns a transport caching in dir on top of next
+func NewTransport(next http.RoundTripper, dir string, disabled bool, ttl time.Duration, debug bool) *Transport {
+	return &Transport{Next: next, Dir: dir, Debug: debug, disabled: disabled, ttl: ttl, now: time.Now}
+}
+
+// Configure turns the cache off, or on with responses fresh for ttl
+func (t *Transport) Configure(disabled bool, ttl time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.disabled, t.ttl = disabled, ttl
+}
+
+func (t *Transport) settings() (bool, time.Duration) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.disabled, t.ttl
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	disabled, ttl := t.settings()
+	if !cacheable(req) {
+		resp, err := t.Next.RoundTrip(req)
+		if err == nil && req.Method != http.MethodGet && req.Method != http.MethodHead && resp.StatusCode < 400 {
+			t.touch()
+		}
+		return resp, err
+	}
+	if disabled {
+		return t.Next.RoundTrip(req)
+	}
+
+	path := t.path(req)
+	e := t.load(path)
+	now := t.now()
+	if e != nil && ttl > 0 && now.Sub(e.StoredAt) < ttl && e.StoredAt.After(t.written()) {
+		t.logf("Cache hit: %s", req.URL)
+		return e.response(req, Hit), nil
+	}
+
+	out := req
+	if e != nil {
+		out = req.Clone(req.Context())
+		if etag := e.Header.Get("ETag"); etag != "" {
+			out.Header.Set("If-None-Match", etag)
+		}
+		if modified := e.Header.Get("Last-Modified"); modified != "" {
+			out.Header.Set("If-Modified-Since", modified)
+		}
+	}
+	resp, err := t.Next.RoundTrip(out)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && e != nil:
+		resp.Body.Close()
+		for _, key := range []string{"ETag", "Last-Modified", "Date"} {
+			if value := resp.Header.Get(key); value != "" {
+				e.Header.Set(key, value)
+			}
+		}
+		e.StoredAt = now
+		t.save(path, e)
+		t.logf("Cache revalidated: %s", req.URL)
+		return e.response(req, Revalidated), nil
+	case resp.StatusCode == http.StatusOK && storable(resp, ttl):
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		t.save(path, &entry{URL: req.URL.String(), Header: resp.Header, Body: body, StoredAt: now})
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+		return resp, nil
+	default:
+		return resp, nil
+	}
+}
+
+// cacheable reports whether req is an API read the cache may answer. The
+// caller's own conditional requests and file downloads pass through.
+func cacheable(req *http.Request) bool {
+	return req.Method == http.MethodGet &&
+		strings.Contains(req.Header.Get("Accept"), "application/json") &&
+		req.Header.Get("Range") == "" &&
+		req.Header.Get("If-None-Match") == "" &&
+		req.Header.Get("If-Modified-Since") == ""
+}
+
+// storable reports whether a response is worth keeping: it can be
+// revalidated, or the TTL lets it be served as it is
+func storable(resp *http.Response, ttl time.Duration) bool {
+	if strings.Contains(resp.Header.Get("Cache-Control"), "no-store") {
+		return false
+	}
+	return ttl > 0 || resp.Header.Get("ETag") != "" || resp.Header.Get("Last-Modified") != ""
+}
+
+// path names a response's file by a hash of its URL and what it accepts
+func (t *Transport) path(req *http.Request) string {
+	sum := sha256.Sum256([]byte(req.URL.String() + "\n" + req.Header.Get("Accept")))
+	return filepath.Join(t.Dir, hex.EncodeToString(sum[:16])+".json")
+}
+
+func (t *Transport) load(path string) *entry {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var e entry
+	if json.Unmarshal(data, &e) != nil {
+		return nil
+	}
+	return &e
+}
+
+// save writes an entry, through a rename so a concurrent read never sees
+// half a file
+func (t *Transport) save(path string, e *entry) {
+	data, err := json.Marshal(e)
+	if err != nil || os.MkdirAll(t.Dir, 0o700) != nil {
+		return
+	}
+	f, err := os.CreateTemp(t.Dir, "*.tmp")
+	if err != nil {
+		return
+	}
+	_, err = f.Write(data)
+	if f.Close() == nil && err == nil {
+		err = os.Rename(f.Name(), path)
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return
+	}
+	t.prune()
+}
+
+// prune removes responses older than MaxAge, unless that was done less than
+// pruneEvery ago
+func (t *Transport) prune() {
+	path := filepath.Join(t.Dir, prunedFile)
+	now := t.now()
+	if info, err := os.Stat(path); err == nil && now.Sub(info.ModTime()) < pruneEvery {
+		return
+	}
+	if os.WriteFile(path, nil, 0o600) != nil || os.Chtimes(path, now, now) != nil {
+		return
+	}
+	if removed, err := Prune(t.Dir, now.Add(-MaxAge)); err == nil && removed > 0 {
+		t.logf("Cache pruned %d response(s)", removed)
+	}
+}
+
+// touch records that fizz changed something, so fresh responses are
+// revalidated before they are served again
+func (t *Transport) touch() {
+	if os.MkdirAll(t.Dir, 0o700) != nil {
+		return
+	}
+	path := filepath.Join(t.Dir, writtenFile)
+	now := t.now()
+	if err := os.Chtimes(path, now, now); errors.Is(err, fs.ErrNotExist) {
+		os.WriteFile(path, nil, 0o600)
+		os.Chtimes(path, now, now)
+	}
+}
+
+// written returns when fizz last changed something, or the zero time
+func (t *Transport) written() time.Time {
+	info, err := os.Stat(filepath.Join(t.Dir, writtenFile))
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
+
+func (t *Transport) logf(format string, args ...interface{}) {
+	if t.Debug {
+		log.Printf(format, args...)
+	}
+}
+
+// response rebuilds the stored response for req
+func (e *entry) response(req *http.Request, how string) *http.Response {
+	header := e.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	header.Set(Header, how)
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(e.Body)),
+		ContentLength: int64(len(e.Body)),
+		Request:       req,
+	}
+}
+
+// Stats describes what is cached
+type Stats struct {
+	Dir       string     `json:"dir"`
+	Accounts  int        `json:"accounts"`
+	Responses int        `json:"responses"`
+	Bytes     int64      `json:"bytes"`
+	Oldest    *time.Time `json:"oldest,omitempty"`
+	Newest    *time.Time `json:"newest,omitempty"`
+}
+
+// Info counts the responses cached under dir, in one account's directory
+// or in the directory of every account
+func Info(dir string) (Stats, error) {
+	stats := Stats{Dir: dir}
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != dir && filepath.Dir(path) == dir {
+				stats.Accounts++
+			}
+			return nil
+		}
+		if filepath.Ext(path) != ".json" {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		stats.Responses++
+		stats.Bytes += info.Size()
+		modified := info.ModTime()
+		if stats.Oldest == nil || modified.Before(*stats.Oldest) {
+			stats.Oldest = &modified
+		}
+		if stats.Newest == nil || modified.After(*stats.Newest) {
+			stats.Newest = &modified
+		}
+		return nil
+	})
+	if err != nil {
+		return stats, fmt.Errorf("failed to read cache: %w", err)
+	}
+	return stats, nil
+}
+
+// Prune removes the responses cached under dir that were last stored
+// before cutoff, and any temporary files left as old, and returns how many
+// responses it removed
+func Prune(dir string, cutoff time.Time) (int, error) {
+	removed := 0
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		ext := filepath.Ext(path)
+		if d.IsDir() || (ext != ".json" && ext != ".tmp") {
+			return nil
+		}
+		info, err := d.Info()
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if !info.ModTime().Before(cutoff) {
+			return nil
+		}
+		if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+		if ext == ".json" {
+			removed++
+		}
+		return nil
+	})
+	if err != nil {
+		return removed, fmt.Errorf("failed to prune cache: %w", err)
+	}
+	return removed, nil
+}
+
+// Clear removes everything cached under dir
+func Clear(dir string) error {
+	if err := os.RemoveAll(dir); err != nil {
+		return fmt.Errorf("failed to clear cache: %w", err)
+	}
+	return nil
+}
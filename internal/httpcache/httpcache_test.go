@@ -0,0 +1,207 @@
+package httpcache
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// server answers /cards with an ETag and /boards with a Last-Modified
+// date, honouring conditional requests, and records what it was sent
+type server struct {
+	*httptest.Server
+	body     string
+	requests []string
+}
+
+func newServer(t *testing.T) *server {
+	s := &server{body: `[{"id":1}]`}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.requests = append(s.requests, r.Method+" "+r.URL.Path+" "+r.Header.Get("If-None-Match")+r.Header.Get("If-Modified-Since"))
+		switch {
+		case r.Method != http.MethodGet:
+			s.body = `[{"id":2}]`
+			w.WriteHeader(http.StatusNoContent)
+		case r.URL.Path == "/cards":
+			etag := `"` + s.body + `"`
+			w.Header().Set("ETag", etag)
+			if r.Header.Get("If-None-Match") == etag {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			io.WriteString(w, s.body)
+		case r.URL.Path == "/boards":
+			w.Header().Set("Last-Modified", "Sun, 01 Mar 2026 09:00:00 GMT")
+			if r.Header.Get("If-Modified-Since") != "" {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			io.WriteString(w, `[]`)
+		default:
+			w.Header().Set("Cache-Control", "no-store")
+			io.WriteString(w, `{}`)
+		}
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func do(t *testing.T, rt http.RoundTripper, method, url string) (*http.Response, string) {
+	t.Helper()
+	req, err := http.NewRequest(method, url, nil)
+	require.NoError(t, err)
+	req.Header.Set("Accept", "application/json")
+	resp, err := rt.RoundTrip(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp, string(body)
+}
+
+func TestTransportRevalidates(t *testing.T) {
+	srv := newServer(t)
+	rt := NewTransport(http.DefaultTransport, t.TempDir(), false, 0, false)
+
+	_, body := do(t, rt, http.MethodGet, srv.URL+"/cards")
+	assert.Equal(t, `[{"id":1}]`, body)
+
+	resp, body := do(t, rt, http.MethodGet, srv.URL+"/cards")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, Revalidated, resp.Header.Get(Header))
+	assert.Equal(t, `[{"id":1}]`, body)
+
+	resp, _ = do(t, rt, http.MethodGet, srv.URL+"/boards")
+	assert.Empty(t, resp.Header.Get(Header))
+	resp, body = do(t, rt, http.MethodGet, srv.URL+"/boards")
+	assert.Equal(t, Revalidated, resp.Header.Get(Header))
+	assert.Equal(t, `[]`, body)
+
+	assert.Equal(t, []string{
+		`GET /cards `,
+		`GET /cards "[{"id":1}]"`,
+		`GET /boards `,
+		`GET /boards Sun, 01 Mar 2026 09:00:00 GMT`,
+	}, srv.requests)
+}
+
+func TestTransportTTL(t *testing.T) {
+	srv := newServer(t)
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	rt := NewTransport(http.DefaultTransport, t.TempDir(), false, time.Minute, false)
+	rt.now = func() time.Time { return now }
+
+	do(t, rt, http.MethodGet, srv.URL+"/cards")
+	resp, _ := do(t, rt, http.MethodGet, srv.URL+"/cards")
+	assert.Equal(t, Hit, resp.Header.Get(Header))
+	assert.Len(t, srv.requests, 1)
+
+	// A change made through fizz makes fresh responses stale
+	now = now.Add(time.Second)
+	do(t, rt, http.MethodPost, srv.URL+"/cards")
+	now = now.Add(time.Second)
+	_, body := do(t, rt, http.MethodGet, srv.URL+"/cards")
+	assert.Equal(t, `[{"id":2}]`, body)
+	assert.Len(t, srv.requests, 3)
+
+	// Past the TTL the response is revalidated
+	now = now.Add(2 * time.Minute)
+	resp, _ = do(t, rt, http.MethodGet, srv.URL+"/cards")
+	assert.Equal(t, Revalidated, resp.Header.Get(Header))
+
+	// Disabled, the cache is neither read nor written
+	rt.Configure(true, time.Minute)
+	resp, _ = do(t, rt, http.MethodGet, srv.URL+"/cards")
+	assert.Empty(t, resp.Header.Get(Header))
+	assert.Empty(t, srv.requests[len(srv.requests)-1][len("GET /cards "):])
+}
+
+func TestTransportKeepsAccountsApart(t *testing.T) {
+	srv := newServer(t)
+	t.Setenv("FIZZ_CACHE_DIR", t.TempDir())
+	first, err := AccountDir("111", "token-a")
+	require.NoError(t, err)
+	second, err := AccountDir("222", "token-a")
+	require.NoError(t, err)
+	assert.NotEqual(t, first, second)
+	other, err := AccountDir("111", "token-b")
+	require.NoError(t, err)
+	assert.NotEqual(t, first, other)
+
+	do(t, NewTransport(http.DefaultTransport, first, false, time.Hour, false), http.MethodGet, srv.URL+"/cards")
+	resp, _ := do(t, NewTransport(http.DefaultTransport, second, false, time.Hour, false), http.MethodGet, srv.URL+"/cards")
+	assert.Empty(t, resp.Header.Get(Header))
+	assert.Len(t, srv.requests, 2)
+}
+
+func TestInfoAndClear(t *testing.T) {
+	srv := newServer(t)
+	dir := t.TempDir()
+	rt := NewTransport(http.DefaultTransport, filepath.Join(dir, "account"), false, 0, false)
+
+	do(t, rt, http.MethodGet, srv.URL+"/cards")
+	do(t, rt, http.MethodGet, srv.URL+"/boards")
+	// Responses that may not be stored are not
+	do(t, rt, http.MethodGet, srv.URL+"/identity")
+
+	stats, err := Info(dir)
+	require.NoError(t, err)
+	assert.Equal(t, 1, stats.Accounts)
+	assert.Equal(t, 2, stats.Responses)
+	assert.Positive(t, stats.Bytes)
+	require.NotNil(t, stats.Oldest)
+
+	require.NoError(t, Clear(dir))
+	stats, err = Info(dir)
+	require.NoError(t, err)
+	assert.Zero(t, stats.Responses)
+	assert.True(t, strings.HasPrefix(stats.Dir, dir))
+}
+
+func TestPrune(t *testing.T) {
+	srv := newServer(t)
+	dir := t.TempDir()
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	rt := NewTransport(http.DefaultTransport, dir, false, 0, false)
+	rt.now = func() time.Time { return now }
+
+	old := filepath.Join(dir, "old.json")
+	recent := filepath.Join(dir, "recent.json")
+	leftover := filepath.Join(dir, "leftover.tmp")
+	for path, age := range map[string]time.Duration{old: MaxAge + time.Hour, recent: MaxAge - time.Hour, leftover: MaxAge + time.Hour} {
+		require.NoError(t, os.WriteFile(path, []byte(`{}`), 0o600))
+		require.NoError(t, os.Chtimes(path, now.Add(-age), now.Add(-age)))
+	}
+
+	// Saving a response prunes what has not been stored for MaxAge
+	do(t, rt, http.MethodGet, srv.URL+"/cards")
+	assert.NoFileExists(t, old)
+	assert.NoFileExists(t, leftover)
+	assert.FileExists(t, recent)
+
+	// but only once a day
+	require.NoError(t, os.Chtimes(recent, now.Add(-MaxAge-time.Hour), now.Add(-MaxAge-time.Hour)))
+	now = now.Add(time.Hour)
+	do(t, rt, http.MethodGet, srv.URL+"/boards")
+	assert.FileExists(t, recent)
+	now = now.Add(pruneEvery)
+	do(t, rt, http.MethodGet, srv.URL+"/boards")
+	assert.NoFileExists(t, recent)
+
+	stats, err := Info(dir)
+	require.NoError(t, err)
+	assert.Equal(t, 2, stats.Responses)
+
+	// Saved responses carry the real time
+	removed, err := Prune(dir, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, 2, removed)
+}
@@ -0,0 +1,148 @@
+package importer
+
+import (
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// Fields are the card fields a CSV column can map to
+var Fields = []string{"id", "title", "body", "column", "tags", "steps", "comments", "closed"}
+
+// closedValues are the values of a closed column that close a card
+var closedValues = map[string]bool{"true": true, "yes": true, "y": true, "1": true, "closed": true, "done": true, "resolved": true}
+
+// listSeparator splits tags and steps within one cell
+var listSeparator = regexp.MustCompile(`[;\n]+`)
+
+// tagSeparator also allows commas between tags
+var tagSeparator = regexp.MustCompile(`[,;\n]+`)
+
+// ParseMapping parses --map values: field=Header pairs, several to a value
+// when separated by commas
+func ParseMapping(values []string) (map[string]string, error) {
+	mapping := map[string]string{}
+	for _, value := range values {
+		pairs, err := csv.NewReader(strings.NewReader(value)).Read()
+		if err != nil {
+			return nil, fmt.Errorf("invalid --map %q: %w", value, err)
+		}
+		for _, pair := range pairs {
+			field, header, ok := strings.Cut(pair, "=")
+			if !ok || field == "" || header == "" {
+				return nil, fmt.Errorf("invalid --map %q: use field=Header", pair)
+			}
+			mapping[strings.ToLower(strings.TrimSpace(field))] = header
+		}
+	}
+	return mapping, nil
+}
+
+// CSV reads cards from a CSV file with a header row. mapping maps card
+// fields to header names; fields it leaves out map to a header of the same
+// name, ignoring case. A header that repeats, as Jira's Labels and Comment
+// do, contributes every cell. Rows without an id are matched in the ledger
+// by title and body.
+func CSV(r io.Reader, mapping map[string]string) ([]Card, error) {
+	known := map[string]bool{}
+	for _, f := range Fields {
+		known[f] = true
+	}
+	for field := range mapping {
+		if !known[field] {
+			return nil, fmt.Errorf("unknown field %q in --map; use %s", field, strings.Join(Fields, ", "))
+		}
+	}
+
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+	if len(header) > 0 {
+		header[0] = strings.TrimPrefix(header[0], "\ufeff")
+	}
+
+	columns := map[string][]int{}
+	for _, field := range Fields {
+		name, mapped := mapping[field]
+		if !mapped {
+			name = field
+		}
+		for i, h := range header {
+			if strings.EqualFold(strings.TrimSpace(h), name) {
+				columns[field] = append(columns[field], i)
+			}
+		}
+		if mapped && len(columns[field]) == 0 {
+			return nil, fmt.Errorf("column %q not found in CSV header", name)
+		}
+	}
+	if len(columns["title"]) == 0 {
+		return nil, fmt.Errorf("no title column; map one with --map title=<header>")
+	}
+
+	var cards []Card
+	for line := 2; ; line++ {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CSV: %w", err)
+		}
+		cells := func(field string) []string {
+			var values []string
+			for _, i := range columns[field] {
+				if i < len(row) && strings.TrimSpace(row[i]) != "" {
+					values = append(values, strings.TrimSpace(row[i]))
+				}
+			}
+			return values
+		}
+		first := func(field string) string {
+			if values := cells(field); len(values) > 0 {
+				return values[0]
+			}
+			return ""
+		}
+
+		card := Card{
+			Title:    first("title"),
+			Body:     first("body"),
+			Column:   first("column"),
+			Comments: cells("comments"),
+			Closed:   closedValues[strings.ToLower(first("closed"))],
+		}
+		if card.Title == "" {
+			return nil, fmt.Errorf("line %d has no title", line)
+		}
+		var labels []string
+		for _, cell := range cells("tags") {
+			labels = append(labels, tagSeparator.Split(cell, -1)...)
+		}
+		card.Tags = tagNames(labels)
+		for _, cell := range cells("steps") {
+			for _, step := range listSeparator.Split(cell, -1) {
+				if step = strings.TrimSpace(step); step != "" {
+					card.Steps = append(card.Steps, Step{Content: step})
+				}
+			}
+		}
+
+		if id := first("id"); id != "" {
+			card.Key = "csv:" + id
+		} else {
+			sum := sha256.Sum256([]byte(card.Title + "\x00" + card.Body))
+			card.Key = "csv:" + hex.EncodeToString(sum[:8])
+		}
+		cards = append(cards, card)
+	}
+	return cards, nil
+}
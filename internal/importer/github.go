@@ -0,0 +1,103 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// githubIssue is an issue as the REST API returns it, or as
+// `gh issue list --json` writes it
+type githubIssue struct {
+	ID      json.RawMessage `json:"id"`
+	Number  int             `json:"number"`
+	Title   string          `json:"title"`
+	Body    string          `json:"body"`
+	State   string          `json:"state"`
+	HTMLURL string          `json:"html_url"`
+	URL     string          `json:"url"`
+	Labels  []struct {
+		Name string `json:"name"`
+	} `json:"labels"`
+	// Comments is a count from the REST API and a list from gh
+	Comments    json.RawMessage `json:"comments"`
+	PullRequest json.RawMessage `json:"pull_request"`
+}
+
+type githubComment struct {
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"createdAt"`
+	Author    struct {
+		Login string `json:"login"`
+	} `json:"author"`
+}
+
+// taskItem matches a Markdown task list item
+var taskItem = regexp.MustCompile(`^\s*[-*+] \[([ xX])\] (.+?)\s*$`)
+
+// GitHubIssues reads a JSON array of issues saved from the REST API
+// (GET /repos/{owner}/{repo}/issues) or from `gh issue list --json
+// number,title,body,state,labels,comments,url`. Labels become tags, task
+// list items in the body steps, and comments, which only gh includes,
+// comments. Closed issues are closed and pull requests are skipped.
+func GitHubIssues(r io.Reader) ([]Card, error) {
+	var issues []githubIssue
+	if err := json.NewDecoder(r).Decode(&issues); err != nil {
+		return nil, fmt.Errorf("failed to parse GitHub issues: %w", err)
+	}
+
+	cards := make([]Card, 0, len(issues))
+	for _, issue := range issues {
+		if len(issue.PullRequest) > 0 && string(issue.PullRequest) != "null" {
+			continue
+		}
+
+		card := Card{
+			Title:  issue.Title,
+			Closed: strings.EqualFold(issue.State, "closed"),
+		}
+
+		// The REST API's url is the API resource, gh's the web page
+		link := issue.HTMLURL
+		if link == "" && !strings.Contains(issue.URL, "api.github.com") {
+			link = issue.URL
+		}
+		switch {
+		case link != "":
+			card.Key = "github:" + link
+		case len(issue.ID) > 0:
+			card.Key = "github:" + strings.Trim(string(issue.ID), `"`)
+		default:
+			card.Key = "github:#" + strconv.Itoa(issue.Number)
+		}
+
+		var body []string
+		for _, line := range strings.Split(strings.ReplaceAll(issue.Body, "\r\n", "\n"), "\n") {
+			if m := taskItem.FindStringSubmatch(line); m != nil {
+				card.Steps = append(card.Steps, Step{Content: m[2], Completed: m[1] != " "})
+				continue
+			}
+			body = append(body, line)
+		}
+		card.Body = withSource(strings.TrimSpace(strings.Join(body, "\n")), link)
+
+		var labels []string
+		for _, l := range issue.Labels {
+			labels = append(labels, l.Name)
+		}
+		card.Tags = tagNames(labels)
+
+		var comments []githubComment
+		if json.Unmarshal(issue.Comments, &comments) == nil {
+			for _, c := range comments {
+				card.Comments = append(card.Comments, attributed(c.Author.Login, c.CreatedAt, c.Body))
+			}
+		}
+		cards = append(cards, card)
+	}
+	return cards, nil
+}
@@ -0,0 +1,168 @@
+// Package importer reads cards from other tools' exports (Trello board JSON,
+// GitHub issues JSON and CSV) and keeps a ledger of what has been imported,
+// so an import can be run again without creating duplicates.
+package importer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/visionik/fizz/internal/config"
+)
+
+// LedgerFileName is the file in the config directory recording imported
+// cards
+const LedgerFileName = "import-ledger.json"
+
+// Card is a card to create from an export
+type Card struct {
+	// Key identifies the item in its source, such as trello:<card id>
+	Key      string   `json:"key"`
+	Title    string   `json:"title"`
+	Body     string   `json:"body,omitempty"`
+	Column   string   `json:"column,omitempty"`
+	Tags     []string `json:"tags,omitempty"`
+	Steps    []Step   `json:"steps,omitempty"`
+	Comments []string `json:"comments,omitempty"`
+	Closed   bool     `json:"closed,omitempty"`
+}
+
+// Step is a checklist item
+type Step struct {
+	Content   string `json:"content"`
+	Completed bool   `json:"completed,omitempty"`
+}
+
+var tagSpace = regexp.MustCompile(`\s+`)
+
+// TagName turns a label into a tag name: lowercase, with dashes for spaces
+// and without a leading #
+func TagName(label string) string {
+	name := strings.TrimPrefix(strings.TrimSpace(label), "#")
+	return strings.ToLower(tagSpace.ReplaceAllString(name, "-"))
+}
+
+// tagNames converts labels to tag names, dropping empty and repeated ones
+func tagNames(labels []string) []string {
+	var tags []string
+	seen := map[string]bool{}
+	for _, label := range labels {
+		if name := TagName(label); name != "" && !seen[name] {
+			seen[name] = true
+			tags = append(tags, name)
+		}
+	}
+	return tags
+}
+
+// withSource appends a link to the original item to a body
+func withSource(body, url string) string {
+	if url == "" {
+		return body
+	}
+	if body == "" {
+		return "Imported from " + url
+	}
+	return strings.TrimRight(body, "\n") + "\n\nImported from " + url
+}
+
+// attributed prefixes an imported comment with its author and date
+func attributed(author string, at time.Time, text string) string {
+	var by string
+	switch {
+	case author != "" && !at.IsZero():
+		by = fmt.Sprintf("%s wrote on %s:", author, at.Format("2006-01-02"))
+	case author != "":
+		by = author + " wrote:"
+	default:
+		return text
+	}
+	return by + "\n\n" + text
+}
+
+// Entry records a card created by an import
+type Entry struct {
+	CardID     string    `json:"card_id"`
+	Card       int       `json:"card"`
+	Title      string    `json:"title"`
+	ImportedAt time.Time `json:"imported_at"`
+}
+
+// Ledger records the cards imported so far, by board and source key
+type Ledger struct {
+	Boards map[string]map[string]Entry `json:"boards"`
+	path   string
+}
+
+// LedgerPath returns the ledger's location
+func LedgerPath() (string, error) {
+	dir, err := config.Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, LedgerFileName), nil
+}
+
+// LoadLedger reads the ledger; a missing file is an empty ledger
+func LoadLedger() (*Ledger, error) {
+	path, err := LedgerPath()
+	if err != nil {
+		return nil, err
+	}
+	l := &Ledger{Boards: map[string]map[string]Entry{}, path: path}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return l, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read import ledger: %w", err)
+	}
+	if err := json.Unmarshal(data, l); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if l.Boards == nil {
+		l.Boards = map[string]map[string]Entry{}
+	}
+	return l, nil
+}
+
+// Get returns the card imported for key into a board
+func (l *Ledger) Get(board, key string) (Entry, bool) {
+	e, ok := l.Boards[board][key]
+	return e, ok
+}
+
+// Record adds a card to the ledger and saves it
+func (l *Ledger) Record(board, key string, e Entry) error {
+	if l.Boards[board] == nil {
+		l.Boards[board] = map[string]Entry{}
+	}
+	l.Boards[board][key] = e
+	return l.save()
+}
+
+// save writes the ledger atomically
+func (l *Ledger) save() error {
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode import ledger: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(l.path), 0o755); err != nil {
+		return fmt.Errorf("failed to write import ledger: %w", err)
+	}
+	tmp := l.path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write import ledger: %w", err)
+	}
+	if err := os.Rename(tmp, l.path); err != nil {
+		return fmt.Errorf("failed to write import ledger: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+package importer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func open(t *testing.T, name string) *os.File {
+	t.Helper()
+	f, err := os.Open(filepath.Join("testdata", name))
+	require.NoError(t, err)
+	t.Cleanup(func() { f.Close() })
+	return f
+}
+
+func TestTrello(t *testing.T) {
+	cards, err := Trello(open(t, "trello.json"))
+	require.NoError(t, err)
+	assert.Equal(t, []Card{
+		{
+			Key:    "trello:card-login",
+			Title:  "Login fails with SSO",
+			Body:   "Imported from https://trello.com/c/GhIjKl34",
+			Column: "To Do",
+		},
+		{
+			Key:    "trello:card-db",
+			Title:  "Upgrade the database cluster",
+			Body:   "Move to the new major version.\n\nImported from https://trello.com/c/AbCdEf12",
+			Column: "Doing",
+			Tags:   []string{"infra", "red"},
+			Steps: []Step{
+				{Content: "Prepare: Take a backup", Completed: true},
+				{Content: "Prepare: Announce the window"},
+				{Content: "Rollout: Upgrade replicas"},
+			},
+			Comments: []string{
+				"Sam Park wrote on 2024-03-01:\n\nWhich version?",
+				"Jordan Lee wrote on 2024-03-02:\n\nWindow booked for Saturday.",
+			},
+		},
+		{Key: "trello:card-cdn", Title: "Try a second CDN", Column: "Old ideas", Closed: true},
+	}, cards)
+
+	_, err = Trello(strings.NewReader("[]"))
+	assert.ErrorContains(t, err, "failed to parse Trello export")
+}
+
+func TestGitHubIssues(t *testing.T) {
+	cards, err := GitHubIssues(open(t, "github-rest.json"))
+	require.NoError(t, err)
+	assert.Equal(t, []Card{
+		{
+			Key:   "github:https://github.com/acme/fizz/issues/12",
+			Title: "Crash on empty config",
+			Body:  "fizz panics when config.yaml is empty.\n\nImported from https://github.com/acme/fizz/issues/12",
+			Tags:  []string{"bug", "good-first-issue"},
+			Steps: []Step{{Content: "Reproduce", Completed: true}, {Content: "Fix the parser"}},
+		},
+		{
+			Key:    "github:https://github.com/acme/fizz/issues/14",
+			Title:  "Docs typo",
+			Body:   "Imported from https://github.com/acme/fizz/issues/14",
+			Tags:   []string{"docs"},
+			Closed: true,
+		},
+	}, cards)
+
+	// gh's output has the same key, so either format recognizes the other's
+	// imports
+	cards, err = GitHubIssues(open(t, "github-gh.json"))
+	require.NoError(t, err)
+	require.Len(t, cards, 1)
+	assert.Equal(t, "github:https://github.com/acme/fizz/issues/12", cards[0].Key)
+	assert.Equal(t, []string{"jlee wrote on 2024-05-01:\n\nSame here."}, cards[0].Comments)
+	assert.False(t, cards[0].Closed)
+}
+
+func TestCSV(t *testing.T) {
+	data := "\ufeffIssue key,Summary,Description,Status,Labels,Labels,Comment,Comment,Checklist\n" +
+		"OPS-1,Rotate keys,Yearly rotation,Done,security,Ops Team,First,,a; b\n" +
+		"OPS-2,Patch hosts,,In Progress,,,,,\n"
+	cards, err := CSV(strings.NewReader(data), map[string]string{
+		"id": "Issue key", "title": "summary", "body": "Description", "closed": "Status", "tags": "Labels", "comments": "Comment", "steps": "Checklist",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []Card{
+		{
+			Key: "csv:OPS-1", Title: "Rotate keys", Body: "Yearly rotation", Closed: true,
+			Tags: []string{"security", "ops-team"}, Comments: []string{"First"},
+			Steps: []Step{{Content: "a"}, {Content: "b"}},
+		},
+		{Key: "csv:OPS-2", Title: "Patch hosts"},
+	}, cards)
+
+	// Headers named like fields need no mapping; rows without an ID get a
+	// key from their content
+	cards, err = CSV(strings.NewReader("Title,Tags,Column\nOne,\"a, b\",Backlog\n"), nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, cards[0].Tags)
+	assert.Equal(t, "Backlog", cards[0].Column)
+	assert.Regexp(t, `^csv:[0-9a-f]{16}$`, cards[0].Key)
+
+	_, err = CSV(strings.NewReader("Summary\nx\n"), map[string]string{"name": "Summary"})
+	assert.EqualError(t, err, `unknown field "name" in --map; use id, title, body, column, tags, steps, comments, closed`)
+	_, err = CSV(strings.NewReader("Summary\nx\n"), map[string]string{"title": "Name"})
+	assert.EqualError(t, err, `column "Name" not found in CSV header`)
+	_, err = CSV(strings.NewReader("Summary\nx\n"), nil)
+	assert.EqualError(t, err, "no title column; map one with --map title=<header>")
+}
+
+func TestParseMapping(t *testing.T) {
+	mapping, err := ParseMapping([]string{`id=Issue key,Title=Summary`, `"tags=Labels, all"`})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"id": "Issue key", "title": "Summary", "tags": "Labels, all"}, mapping)
+
+	_, err = ParseMapping([]string{"title"})
+	assert.EqualError(t, err, `invalid --map "title": use field=Header`)
+}
+
+func TestLedger(t *testing.T) {
+	t.Setenv("FIZZ_CONFIG_DIR", t.TempDir())
+	l, err := LoadLedger()
+	require.NoError(t, err)
+	_, ok := l.Get("board", "trello:1")
+	assert.False(t, ok)
+
+	entry := Entry{CardID: "c1", Card: 7, Title: "One", ImportedAt: time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)}
+	require.NoError(t, l.Record("board", "trello:1", entry))
+
+	l, err = LoadLedger()
+	require.NoError(t, err)
+	got, ok := l.Get("board", "trello:1")
+	assert.True(t, ok)
+	assert.Equal(t, entry, got)
+	_, ok = l.Get("other-board", "trello:1")
+	assert.False(t, ok)
+}
@@ -0,0 +1,133 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+)
+
+// trelloBoard is the part of a Trello board export that becomes cards
+type trelloBoard struct {
+	Lists []struct {
+		ID     string `json:"id"`
+		Name   string `json:"name"`
+		Closed bool   `json:"closed"`
+	} `json:"lists"`
+	Cards []struct {
+		ID     string  `json:"id"`
+		Name   string  `json:"name"`
+		Desc   string  `json:"desc"`
+		IDList string  `json:"idList"`
+		Closed bool    `json:"closed"`
+		Pos    float64 `json:"pos"`
+		URL    string  `json:"shortUrl"`
+		Labels []struct {
+			Name  string `json:"name"`
+			Color string `json:"color"`
+		} `json:"labels"`
+	} `json:"cards"`
+	Checklists []struct {
+		IDCard     string  `json:"idCard"`
+		Name       string  `json:"name"`
+		Pos        float64 `json:"pos"`
+		CheckItems []struct {
+			Name  string  `json:"name"`
+			State string  `json:"state"`
+			Pos   float64 `json:"pos"`
+		} `json:"checkItems"`
+	} `json:"checklists"`
+	Actions []struct {
+		Type string    `json:"type"`
+		Date time.Time `json:"date"`
+		Data struct {
+			Text string `json:"text"`
+			Card struct {
+				ID string `json:"id"`
+			} `json:"card"`
+		} `json:"data"`
+		MemberCreator struct {
+			FullName string `json:"fullName"`
+		} `json:"memberCreator"`
+	} `json:"actions"`
+}
+
+// Trello reads a Trello board export (Menu → Print, export and share →
+// Export as JSON). Lists become columns, labels tags, checklist items steps
+// and comments comments; cards that are archived, or in an archived list,
+// are closed.
+func Trello(r io.Reader) ([]Card, error) {
+	var board trelloBoard
+	if err := json.NewDecoder(r).Decode(&board); err != nil {
+		return nil, fmt.Errorf("failed to parse Trello export: %w", err)
+	}
+
+	type list struct {
+		name   string
+		closed bool
+		order  int
+	}
+	lists := map[string]list{}
+	for i, l := range board.Lists {
+		lists[l.ID] = list{name: l.Name, closed: l.Closed, order: i}
+	}
+
+	// Checklists and their items come in position order
+	sort.SliceStable(board.Checklists, func(i, j int) bool { return board.Checklists[i].Pos < board.Checklists[j].Pos })
+	checklists := map[string]int{}
+	for _, cl := range board.Checklists {
+		checklists[cl.IDCard]++
+	}
+	steps := map[string][]Step{}
+	for _, cl := range board.Checklists {
+		sort.SliceStable(cl.CheckItems, func(i, j int) bool { return cl.CheckItems[i].Pos < cl.CheckItems[j].Pos })
+		for _, item := range cl.CheckItems {
+			content := item.Name
+			// With several checklists, each step says which it came from
+			if checklists[cl.IDCard] > 1 {
+				content = cl.Name + ": " + content
+			}
+			steps[cl.IDCard] = append(steps[cl.IDCard], Step{Content: content, Completed: item.State == "complete"})
+		}
+	}
+
+	// Exports list actions newest first
+	sort.SliceStable(board.Actions, func(i, j int) bool { return board.Actions[i].Date.Before(board.Actions[j].Date) })
+	comments := map[string][]string{}
+	for _, a := range board.Actions {
+		if a.Type == "commentCard" {
+			comments[a.Data.Card.ID] = append(comments[a.Data.Card.ID], attributed(a.MemberCreator.FullName, a.Date, a.Data.Text))
+		}
+	}
+
+	sort.SliceStable(board.Cards, func(i, j int) bool {
+		a, b := board.Cards[i], board.Cards[j]
+		if lists[a.IDList].order != lists[b.IDList].order {
+			return lists[a.IDList].order < lists[b.IDList].order
+		}
+		return a.Pos < b.Pos
+	})
+	cards := make([]Card, 0, len(board.Cards))
+	for _, c := range board.Cards {
+		var labels []string
+		for _, l := range c.Labels {
+			if l.Name != "" {
+				labels = append(labels, l.Name)
+			} else {
+				labels = append(labels, l.Color)
+			}
+		}
+		cards = append(cards, Card{
+			Key:      "trello:" + c.ID,
+			Title:    c.Name,
+			Body:     withSource(c.Desc, c.URL),
+			Column:   lists[c.IDList].name,
+			Tags:     tagNames(labels),
+			Steps:    steps[c.ID],
+			Comments: comments[c.ID],
+			Closed:   c.Closed || lists[c.IDList].closed,
+		})
+	}
+	return cards, nil
+}
@@ -0,0 +1,55 @@
+// Package kanban holds what fizz's reports, exports and rules agree on about
+// cards on a board: where a card sits and which tags it carries.
+package kanban
+
+import (
+	"strings"
+
+	"github.com/visionik/libfizz-go/fizzy"
+)
+
+// Names of the places a card can be outside its board's columns
+const (
+	// TriageColumn holds open cards not yet in a column
+	TriageColumn = "Triage"
+	// NotNowColumn holds postponed cards
+	NotNowColumn = "Not now"
+	// ClosedColumn holds closed cards
+	ClosedColumn = "Closed"
+)
+
+// BoardID returns the ID of the board a card is on, from board_id or the
+// embedded board
+func BoardID(card *fizzy.Card) string {
+	if card.BoardID != "" {
+		return card.BoardID
+	}
+	if card.Board != nil {
+		return card.Board.ID
+	}
+	return ""
+}
+
+// Tag is a tag as the API returns it, or by name as snapshots keep it
+type Tag interface {
+	fizzy.Tag | string
+}
+
+// HasTag reports whether tags include the one named, by name with or
+// without its # and in any case, or by ID
+func HasTag[T Tag](tags []T, name string) bool {
+	name = strings.TrimPrefix(name, "#")
+	for _, tag := range tags {
+		switch t := any(tag).(type) {
+		case fizzy.Tag:
+			if t.ID == name || strings.EqualFold(t.Name, name) {
+				return true
+			}
+		case string:
+			if strings.EqualFold(t, name) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,28 @@
+package kanban
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/visionik/libfizz-go/fizzy"
+)
+
+func TestBoardID(t *testing.T) {
+	assert.Equal(t, "b1", BoardID(&fizzy.Card{BoardID: "b1", Board: &fizzy.Board{ID: "b2"}}))
+	assert.Equal(t, "b2", BoardID(&fizzy.Card{Board: &fizzy.Board{ID: "b2"}}))
+	assert.Empty(t, BoardID(&fizzy.Card{}))
+}
+
+func TestHasTag(t *testing.T) {
+	tags := []fizzy.Tag{{ID: "t1", Name: "Infra"}}
+	assert.True(t, HasTag(tags, "infra"))
+	assert.True(t, HasTag(tags, "#INFRA"))
+	assert.True(t, HasTag(tags, "t1"))
+	assert.False(t, HasTag(tags, "bug"))
+
+	// Snapshots keep tags by name
+	names := []string{"Milestone-4"}
+	assert.True(t, HasTag(names, "#milestone-4"))
+	assert.False(t, HasTag(names, "t1"))
+	assert.False(t, HasTag([]string(nil), "infra"))
+}
@@ -0,0 +1,79 @@
+// Package pager pipes long output through $PAGER when writing to a terminal.
+package pager
+
+import (
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"syscall"
+
+	"github.com/mattn/go-isatty"
+)
+
+// Command returns the pager to run, or "" for none. $PAGER wins; set it
+// empty or to "cat" to turn paging off.
+func Command() string {
+	pager, ok := os.LookupEnv("PAGER")
+	if !ok {
+		if runtime.GOOS == "windows" {
+			return "more"
+		}
+		return "less"
+	}
+	if strings.TrimSpace(pager) == "cat" {
+		return ""
+	}
+	return strings.TrimSpace(pager)
+}
+
+// Run calls write with a writer that feeds the pager, or with out itself
+// when out is not a terminal or no pager is configured
+func Run(out io.Writer, write func(io.Writer) error) error {
+	f, ok := out.(*os.File)
+	if !ok || !isatty.IsTerminal(f.Fd()) {
+		return write(out)
+	}
+	pager := Command()
+	if pager == "" {
+		return write(out)
+	}
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		fields := strings.Fields(pager)
+		cmd = exec.Command(fields[0], fields[1:]...)
+	} else {
+		cmd = exec.Command("sh", "-c", pager)
+	}
+	cmd.Stdout = f
+	cmd.Stderr = os.Stderr
+	// Quit if one screen, keep colors and leave the text on screen, as git does
+	cmd.Env = os.Environ()
+	if _, ok := os.LookupEnv("LESS"); !ok {
+		cmd.Env = append(cmd.Env, "LESS=FRX")
+	}
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return write(out)
+	}
+	if err := cmd.Start(); err != nil {
+		return write(out)
+	}
+
+	err = write(stdin)
+	stdin.Close()
+	waitErr := cmd.Wait()
+
+	// Quitting the pager early closes the pipe; that is not an error
+	if errors.Is(err, syscall.EPIPE) {
+		err = nil
+	}
+	if err != nil {
+		return err
+	}
+	return waitErr
+}
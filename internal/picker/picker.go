@@ -0,0 +1,314 @@
+// Package picker is a small fuzzy finder for choosing a board, card or other
+// item on the terminal when a command is missing one, without depending on
+// an external tool such as fzf.
+package picker
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/term"
+)
+
+// ErrCanceled is returned when the picker is closed without a choice
+var ErrCanceled = errors.New("canceled")
+
+// maxVisible is the number of matches shown at once
+const maxVisible = 10
+
+// Item is one choice: Value is returned, Label is shown and searched
+type Item struct {
+	Value string
+	Label string
+}
+
+// Filter returns the items whose labels contain the letters of query in
+// order, best matches first. An empty query matches everything in order.
+func Filter(items []Item, query string) []Item {
+	type scored struct {
+		item  Item
+		score int
+	}
+	var matches []scored
+	for _, item := range items {
+		if s, ok := Score(query, item.Label); ok {
+			matches = append(matches, scored{item, s})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].score > matches[j].score })
+
+	out := make([]Item, len(matches))
+	for i, m := range matches {
+		out[i] = m.item
+	}
+	return out
+}
+
+// Score reports whether the letters of query appear in text in order,
+// ignoring case, and how well: runs of consecutive letters and letters at
+// the start of words score higher, and gaps and longer texts lower
+func Score(query, text string) (int, bool) {
+	q := []rune(strings.ToLower(query))
+	if len(q) == 0 {
+		return 0, true
+	}
+	t := []rune(strings.ToLower(text))
+
+	// Matching greedily from each place the first letter appears finds
+	// "api" in "Payments API" as a run rather than scattered
+	best, found := 0, false
+	for start := range t {
+		if t[start] != q[0] {
+			continue
+		}
+		if score, ok := scoreFrom(q, t, start); ok && (!found || score > best) {
+			best, found = score, true
+		}
+	}
+	if !found {
+		return 0, false
+	}
+	return best*10 - len(t)/10, true
+}
+
+// scoreFrom matches q in t greedily from start
+func scoreFrom(q, t []rune, start int) (int, bool) {
+	score, qi, last := 0, 0, -1
+	for ti := start; ti < len(t) && qi < len(q); ti++ {
+		if t[ti] != q[qi] {
+			continue
+		}
+		switch {
+		case last == ti-1:
+			score += 5
+		case last >= 0:
+			score -= min(ti-last-1, 3)
+		}
+		if ti == 0 || (!unicode.IsLetter(t[ti-1]) && !unicode.IsDigit(t[ti-1])) {
+			score += 3
+		}
+		last = ti
+		qi++
+	}
+	return score, qi == len(q)
+}
+
+// Pick shows the picker on the terminal tty, drawing it on out, and returns
+// the chosen item. Typing narrows the list, the arrow keys or Ctrl-N and
+// Ctrl-P move, Enter chooses and Esc or Ctrl-C cancels.
+func Pick(tty *os.File, out io.Writer, prompt string, items []Item) (Item, error) {
+	if len(items) == 0 {
+		return Item{}, errors.New("nothing to choose from")
+	}
+	state, err := term.MakeRaw(int(tty.Fd()))
+	if err != nil {
+		return Item{}, fmt.Errorf("failed to set up terminal: %w", err)
+	}
+	defer term.Restore(int(tty.Fd()), state)
+
+	width, _, err := term.GetSize(int(tty.Fd()))
+	if err != nil {
+		width = 0
+	}
+	return run(tty, out, width, prompt, items)
+}
+
+// picker is the state of a picker being shown
+type picker struct {
+	prompt  string
+	items   []Item
+	query   string
+	matches []Item
+	// cursor is the selected match and top the first one shown
+	cursor, top int
+	// width truncates labels so none wraps, which would throw off redrawing;
+	// 0 leaves them alone
+	width int
+}
+
+// run reads keys from in until a choice is made, drawing on out
+func run(in io.Reader, out io.Writer, width int, prompt string, items []Item) (Item, error) {
+	p := &picker{prompt: prompt, items: items, matches: items, width: width}
+	p.draw(out)
+
+	buf := make([]byte, 64)
+	var pending []byte
+	for {
+		n, err := in.Read(buf)
+		if n == 0 && err != nil {
+			p.clear(out)
+			if errors.Is(err, io.EOF) {
+				return Item{}, ErrCanceled
+			}
+			return Item{}, err
+		}
+		pending = append(pending, buf[:n]...)
+
+		for len(pending) > 0 {
+			k, size := nextKey(pending)
+			if size == 0 {
+				break
+			}
+			pending = pending[size:]
+
+			switch k.code {
+			case keyEnter:
+				if len(p.matches) == 0 {
+					continue
+				}
+				choice := p.matches[p.cursor]
+				p.clear(out)
+				fmt.Fprintf(out, "%s%s\r\n", p.prompt, choice.Label)
+				return choice, nil
+			case keyCancel:
+				p.clear(out)
+				return Item{}, ErrCanceled
+			default:
+				p.handle(k)
+			}
+		}
+		p.draw(out)
+	}
+}
+
+// Keys other than text
+const (
+	keyNone = iota
+	keyEnter
+	keyCancel
+	keyUp
+	keyDown
+	keyBackspace
+	keyClear
+	keyText
+)
+
+// key is a decoded key press; r is set for keyText
+type key struct {
+	code int
+	r    rune
+}
+
+// nextKey decodes the key at the start of b and its length in bytes; a
+// length of 0 means more input is needed
+func nextKey(b []byte) (key, int) {
+	switch b[0] {
+	case '\r', '\n':
+		return key{code: keyEnter}, 1
+	case 3: // Ctrl-C
+		return key{code: keyCancel}, 1
+	case 0x1b:
+		if len(b) == 1 {
+			// A lone escape: arrow keys arrive as one read
+			return key{code: keyCancel}, 1
+		}
+		if len(b) >= 3 && (b[1] == '[' || b[1] == 'O') {
+			switch b[2] {
+			case 'A':
+				return key{code: keyUp}, 3
+			case 'B':
+				return key{code: keyDown}, 3
+			}
+			return key{code: keyNone}, 3
+		}
+		return key{code: keyNone}, 2
+	case 16: // Ctrl-P
+		return key{code: keyUp}, 1
+	case 14, '\t': // Ctrl-N
+		return key{code: keyDown}, 1
+	case 127, 8:
+		return key{code: keyBackspace}, 1
+	case 21: // Ctrl-U
+		return key{code: keyClear}, 1
+	}
+	if b[0] < 0x20 {
+		return key{code: keyNone}, 1
+	}
+	if !utf8.FullRune(b) {
+		return key{}, 0
+	}
+	r, size := utf8.DecodeRune(b)
+	return key{code: keyText, r: r}, size
+}
+
+// handle applies a key other than Enter and cancel
+func (p *picker) handle(k key) {
+	switch k.code {
+	case keyUp:
+		if p.cursor > 0 {
+			p.cursor--
+		}
+	case keyDown:
+		if p.cursor < len(p.matches)-1 {
+			p.cursor++
+		}
+	case keyBackspace:
+		if p.query != "" {
+			_, size := utf8.DecodeLastRuneInString(p.query)
+			p.setQuery(p.query[:len(p.query)-size])
+		}
+	case keyClear:
+		p.setQuery("")
+	case keyText:
+		p.setQuery(p.query + string(k.r))
+	}
+
+	if p.cursor < p.top {
+		p.top = p.cursor
+	}
+	if p.cursor >= p.top+maxVisible {
+		p.top = p.cursor - maxVisible + 1
+	}
+}
+
+func (p *picker) setQuery(query string) {
+	p.query = query
+	p.matches = Filter(p.items, query)
+	p.cursor, p.top = 0, 0
+}
+
+// draw shows the query line and the visible matches below it, leaving the
+// cursor after the query
+func (p *picker) draw(out io.Writer) {
+	var b strings.Builder
+	b.WriteString("\r\x1b[J")
+	b.WriteString(p.prompt + p.query)
+
+	end := min(p.top+maxVisible, len(p.matches))
+	for i := p.top; i < end; i++ {
+		marker := "  "
+		if i == p.cursor {
+			marker = "> "
+		}
+		b.WriteString("\r\n" + p.truncate(marker+p.matches[i].Label))
+	}
+	lines := end - p.top
+	b.WriteString(fmt.Sprintf("\r\n  %d/%d", len(p.matches), len(p.items)))
+	lines++
+
+	fmt.Fprintf(&b, "\x1b[%dA\r", lines)
+	if col := utf8.RuneCountInString(p.prompt + p.query); col > 0 {
+		fmt.Fprintf(&b, "\x1b[%dC", col)
+	}
+	io.WriteString(out, b.String())
+}
+
+// truncate shortens line to fit the terminal
+func (p *picker) truncate(line string) string {
+	if p.width <= 1 || utf8.RuneCountInString(line) < p.width {
+		return line
+	}
+	runes := []rune(line)
+	return string(runes[:p.width-2]) + "…"
+}
+
+// clear erases the picker
+func (p *picker) clear(out io.Writer) {
+	io.WriteString(out, "\r\x1b[J")
+}
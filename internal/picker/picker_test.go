@@ -0,0 +1,46 @@
+package picker
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var boards = []Item{
+	{Value: "b1", Label: "Platform"},
+	{Value: "b2", Label: "Design system"},
+	{Value: "b3", Label: "Payments API"},
+}
+
+func TestFilter(t *testing.T) {
+	assert.Equal(t, boards, Filter(boards, ""))
+	assert.Equal(t, []Item{boards[2], boards[0]}, Filter(boards, "pa"))
+	assert.Equal(t, []Item{boards[1]}, Filter(boards, "dsys"))
+	assert.Empty(t, Filter(boards, "xyz"))
+
+	// Consecutive letters and word starts beat scattered ones
+	first, _ := Score("api", "Payments API")
+	second, _ := Score("api", "a plain item")
+	assert.Greater(t, first, second)
+}
+
+func TestRun(t *testing.T) {
+	var out strings.Builder
+	item, err := run(strings.NewReader("pay\x1b[B\x1b[A\r"), &out, 0, "Board: ", boards)
+	require.NoError(t, err)
+	assert.Equal(t, "b3", item.Value)
+	assert.True(t, strings.HasSuffix(out.String(), "\r\x1b[JBoard: Payments API\r\n"))
+
+	// Backspace widens the search again; Ctrl-N moves down
+	item, err = run(strings.NewReader("sx\x7f\x7f\x0e\r"), io.Discard, 0, "", boards)
+	require.NoError(t, err)
+	assert.Equal(t, "b2", item.Value)
+
+	_, err = run(strings.NewReader("pl\x1b"), io.Discard, 0, "", boards)
+	assert.ErrorIs(t, err, ErrCanceled)
+	_, err = run(strings.NewReader("pl"), io.Discard, 0, "", boards)
+	assert.ErrorIs(t, err, ErrCanceled)
+}
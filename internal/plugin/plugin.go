@@ -0,0 +1,298 @@
+// Package plugin finds and runs fizz plugins: executables named fizz-<name>
+// in the plugins directory or on PATH, which `fizz <name>` runs like git and
+// kubectl run theirs.
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+
+	"github.com/visionik/fizz/internal/config"
+)
+
+// Prefix starts the file name of every plugin
+const Prefix = "fizz-"
+
+// DirName is the plugins directory inside the config directory
+const DirName = "plugins"
+
+// ContextFD is the file descriptor plugins read their JSON context from
+const ContextFD = 3
+
+// ContextVersion is the version of the Context format
+const ContextVersion = 1
+
+var validName = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// Plugin is an executable found for a plugin name
+type Plugin struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+	// Shadowed is the path of the plugin of the same name that runs instead
+	// of this one, found earlier in the search
+	Shadowed string `json:"shadowed,omitempty"`
+}
+
+// Dir returns the plugins directory
+func Dir() (string, error) {
+	dir, err := config.Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, DirName), nil
+}
+
+// ValidateName checks that name can name a plugin
+func ValidateName(name string) error {
+	if !validName.MatchString(name) {
+		return fmt.Errorf("invalid plugin name %q: use lowercase letters, digits, - and _", name)
+	}
+	return nil
+}
+
+// searchPath returns the directories searched for plugins, the plugins
+// directory first and then PATH
+func searchPath() []string {
+	var dirs []string
+	if dir, err := Dir(); err == nil {
+		dirs = append(dirs, dir)
+	}
+	return append(dirs, filepath.SplitList(os.Getenv("PATH"))...)
+}
+
+// List returns every plugin found, in search order. A plugin found again
+// later in the search is listed with the path that shadows it.
+func List() []Plugin {
+	var plugins []Plugin
+	first := map[string]string{}
+	seenDir := map[string]bool{}
+	for _, dir := range searchPath() {
+		if dir == "" || seenDir[dir] {
+			continue
+		}
+		seenDir[dir] = true
+
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			name, ok := pluginName(entry.Name())
+			if !ok {
+				continue
+			}
+			path := filepath.Join(dir, entry.Name())
+			if !isExecutable(path) {
+				continue
+			}
+			p := Plugin{Name: name, Path: path, Shadowed: first[name]}
+			if p.Shadowed == "" {
+				first[name] = path
+			}
+			plugins = append(plugins, p)
+		}
+	}
+	return plugins
+}
+
+// Find returns the plugin that runs for name
+func Find(name string) (Plugin, bool) {
+	if ValidateName(name) != nil {
+		return Plugin{}, false
+	}
+	for _, p := range List() {
+		if p.Name == name && p.Shadowed == "" {
+			return p, true
+		}
+	}
+	return Plugin{}, false
+}
+
+// pluginName returns the plugin name a file name stands for
+func pluginName(file string) (string, bool) {
+	if !strings.HasPrefix(file, Prefix) {
+		return "", false
+	}
+	name := strings.TrimPrefix(file, Prefix)
+	if runtime.GOOS == "windows" {
+		name = strings.TrimSuffix(name, filepath.Ext(name))
+	}
+	return name, validName.MatchString(name)
+}
+
+func isExecutable(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil || !info.Mode().IsRegular() {
+		return false
+	}
+	if runtime.GOOS == "windows" {
+		ext := strings.ToLower(filepath.Ext(path))
+		return ext == ".exe" || ext == ".bat" || ext == ".cmd"
+	}
+	return info.Mode().Perm()&0o111 != 0
+}
+
+// Install copies the executable at src into the plugins directory as the
+// plugin name; an existing plugin of that name is replaced only with force
+func Install(src, name string, force bool) (Plugin, error) {
+	if err := ValidateName(name); err != nil {
+		return Plugin{}, err
+	}
+	info, err := os.Stat(src)
+	if err != nil {
+		return Plugin{}, fmt.Errorf("failed to read plugin: %w", err)
+	}
+	if !info.Mode().IsRegular() {
+		return Plugin{}, fmt.Errorf("%s is not a file", src)
+	}
+	dir, err := Dir()
+	if err != nil {
+		return Plugin{}, err
+	}
+
+	file := Prefix + name
+	if runtime.GOOS == "windows" {
+		file += filepath.Ext(src)
+	}
+	dest := filepath.Join(dir, file)
+	if _, err := os.Stat(dest); err == nil && !force {
+		return Plugin{}, fmt.Errorf("plugin %q is already installed at %s; use --force to replace it", name, dest)
+	}
+
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return Plugin{}, fmt.Errorf("failed to read plugin: %w", err)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return Plugin{}, fmt.Errorf("failed to create plugins directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(dir, ".install-*")
+	if err != nil {
+		return Plugin{}, fmt.Errorf("failed to install plugin: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return Plugin{}, fmt.Errorf("failed to install plugin: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return Plugin{}, fmt.Errorf("failed to install plugin: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), 0o755); err != nil {
+		return Plugin{}, fmt.Errorf("failed to install plugin: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), dest); err != nil {
+		return Plugin{}, fmt.Errorf("failed to install plugin: %w", err)
+	}
+	return Plugin{Name: name, Path: dest}, nil
+}
+
+// NameFromFile suggests a plugin name for an executable: its file name
+// without the fizz- prefix or an extension
+func NameFromFile(path string) string {
+	name := strings.TrimPrefix(filepath.Base(path), Prefix)
+	return strings.TrimSuffix(name, filepath.Ext(name))
+}
+
+// Context is the resolved configuration a plugin receives, as JSON on file
+// descriptor ContextFD and, field by field, in environment variables
+type Context struct {
+	Version   int      `json:"version"`
+	Plugin    string   `json:"plugin"`
+	Args      []string `json:"args"`
+	Token     string   `json:"token,omitempty"`
+	Account   string   `json:"account,omitempty"`
+	BaseURL   string   `json:"base_url"`
+	Format    string   `json:"format"`
+	Debug     bool     `json:"debug"`
+	NoInput   bool     `json:"no_input"`
+	ConfigDir string   `json:"config_dir,omitempty"`
+	// Executable is the fizz binary, for plugins that run fizz commands
+	Executable string `json:"executable,omitempty"`
+}
+
+// Environ returns the context as environment variables
+func (c Context) Environ() []string {
+	env := []string{
+		"FIZZ_PLUGIN=" + c.Plugin,
+		"FIZZY_BASE_URL=" + c.BaseURL,
+		"FIZZ_FORMAT=" + c.Format,
+		"FIZZ_DEBUG=" + boolEnv(c.Debug),
+		"FIZZ_NO_INPUT=" + boolEnv(c.NoInput),
+	}
+	if c.Token != "" {
+		env = append(env, "FIZZY_TOKEN="+c.Token, "FIZZY_ACCOUNT="+c.Account)
+	}
+	if c.ConfigDir != "" {
+		env = append(env, "FIZZ_CONFIG_DIR="+c.ConfigDir)
+	}
+	if c.Executable != "" {
+		env = append(env, "FIZZ_BIN="+c.Executable)
+	}
+	return env
+}
+
+func boolEnv(b bool) string {
+	if b {
+		return "1"
+	}
+	return ""
+}
+
+// ExitError reports that a plugin exited with a non-zero status
+type ExitError struct {
+	Name string
+	Code int
+}
+
+func (e *ExitError) Error() string {
+	return fmt.Sprintf("plugin %s exited with status %d", e.Name, e.Code)
+}
+
+// Run runs p with the context's arguments and the given streams
+func (p Plugin) Run(ctx context.Context, c Context, in io.Reader, out, errOut io.Writer) error {
+	cmd := exec.CommandContext(ctx, p.Path, c.Args...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = in, out, errOut
+	cmd.Env = append(os.Environ(), c.Environ()...)
+
+	// Windows cannot pass extra descriptors, so plugins there only get the
+	// environment
+	if runtime.GOOS != "windows" {
+		data, err := json.Marshal(c)
+		if err != nil {
+			return err
+		}
+		r, w, err := os.Pipe()
+		if err != nil {
+			return fmt.Errorf("failed to pass plugin context: %w", err)
+		}
+		defer r.Close()
+		cmd.ExtraFiles = []*os.File{r}
+		cmd.Env = append(cmd.Env, fmt.Sprintf("FIZZ_CONTEXT_FD=%d", ContextFD))
+		// A plugin that never reads the context leaves this blocked until
+		// the read end closes when it exits
+		go func() {
+			w.Write(data)
+			w.Close()
+		}()
+	}
+
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() > 0 {
+		return &ExitError{Name: p.Name, Code: exitErr.ExitCode()}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to run plugin %s: %w", p.Name, err)
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package plugin
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFind(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("plugins are found by executable bit")
+	}
+	t.Setenv("FIZZ_CONFIG_DIR", t.TempDir())
+	bin := t.TempDir()
+	t.Setenv("PATH", bin)
+
+	for file, mode := range map[string]os.FileMode{
+		"fizz-sync":      0o755,
+		"fizz-notes.txt": 0o644,
+		"fizz-Bad":       0o755,
+		"other":          0o755,
+	} {
+		require.NoError(t, os.WriteFile(filepath.Join(bin, file), []byte("#!/bin/sh\n"), mode))
+	}
+
+	assert.Equal(t, []Plugin{{Name: "sync", Path: filepath.Join(bin, "fizz-sync")}}, List())
+	p, ok := Find("sync")
+	assert.True(t, ok)
+	assert.Equal(t, filepath.Join(bin, "fizz-sync"), p.Path)
+	_, ok = Find("notes.txt")
+	assert.False(t, ok)
+	_, ok = Find("../sync")
+	assert.False(t, ok)
+
+	assert.Equal(t, "sync", NameFromFile("/tmp/fizz-sync.py"))
+	_, err := Install(filepath.Join(bin, "fizz-sync"), "Sync", false)
+	assert.EqualError(t, err, `invalid plugin name "Sync": use lowercase letters, digits, - and _`)
+}
+
+func TestContextEnviron(t *testing.T) {
+	env := Context{Plugin: "sync", BaseURL: "https://app.fizzy.do", Format: "json", NoInput: true}.Environ()
+	assert.Contains(t, env, "FIZZ_FORMAT=json")
+	assert.Contains(t, env, "FIZZ_NO_INPUT=1")
+	assert.Contains(t, env, "FIZZ_DEBUG=")
+	assert.NotContains(t, env, "FIZZY_TOKEN=")
+}
@@ -0,0 +1,160 @@
+// Package progress draws a progress bar for long transfers on a terminal.
+package progress
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mattn/go-isatty"
+)
+
+// MinSize is the smallest transfer worth a bar; smaller ones finish before
+// it would be seen
+const MinSize = 1 << 20
+
+// barWidth is the number of cells in the bar itself
+const barWidth = 30
+
+// redrawInterval limits how often the bar is redrawn
+const redrawInterval = 100 * time.Millisecond
+
+// Enabled reports whether out is a terminal that can show a bar
+func Enabled(out io.Writer) bool {
+	f, ok := out.(*os.File)
+	return ok && (isatty.IsTerminal(f.Fd()) || isatty.IsCygwinTerminal(f.Fd()))
+}
+
+// Bar shows how much of a transfer of known size is done, redrawing one line
+type Bar struct {
+	out   io.Writer
+	label string
+	total int64
+
+	mu    sync.Mutex
+	done  int64
+	drawn time.Time
+}
+
+// New returns a bar for total bytes, labeled with label
+func New(out io.Writer, label string, total int64) *Bar {
+	return &Bar{out: out, label: label, total: total}
+}
+
+// Set records that n bytes are done
+func (b *Bar) Set(n int64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.done = n
+	if time.Since(b.drawn) >= redrawInterval || n >= b.total {
+		b.draw()
+	}
+}
+
+// Add records n more bytes done
+func (b *Bar) Add(n int64) {
+	b.mu.Lock()
+	done := b.done + n
+	b.mu.Unlock()
+	b.Set(done)
+}
+
+// Finish draws the final state and ends the line
+func (b *Bar) Finish() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.draw()
+	fmt.Fprintln(b.out)
+}
+
+func (b *Bar) draw() {
+	b.drawn = time.Now()
+	fmt.Fprintf(b.out, "\r\x1b[K%s", Render(b.label, b.done, b.total))
+}
+
+// Render formats a bar for done of total bytes
+func Render(label string, done, total int64) string {
+	fraction := 1.0
+	if total > 0 {
+		fraction = min(float64(done)/float64(total), 1)
+	}
+	filled := int(fraction * barWidth)
+	return fmt.Sprintf("%s [%s%s] %3.0f%% %s/%s", label,
+		strings.Repeat("=", filled), strings.Repeat(" ", barWidth-filled),
+		fraction*100, Bytes(done), Bytes(total))
+}
+
+// Bytes formats a size in bytes for people
+func Bytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	value, prefix := float64(n)/unit, 0
+	for value >= unit && prefix < 4 {
+		value /= unit
+		prefix++
+	}
+	return fmt.Sprintf("%.1f %cB", value, "KMGTP"[prefix])
+}
+
+// Reader counts what is read from r on the bar
+func (b *Bar) Reader(r io.Reader) io.Reader {
+	return &reader{r: r, bar: b}
+}
+
+type reader struct {
+	r   io.Reader
+	bar *Bar
+}
+
+func (r *reader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.bar.Add(int64(n))
+	return n, err
+}
+
+// Counter shows how many items of an unknown total have arrived, such as
+// the cards of a list fetched page by page
+type Counter struct {
+	out   io.Writer
+	label string
+
+	mu    sync.Mutex
+	done  int
+	pages int
+	shown bool
+}
+
+// NewCounter returns a counter labeled with label
+func NewCounter(out io.Writer, label string) *Counter {
+	return &Counter{out: out, label: label}
+}
+
+// Page records a page of n more items and redraws the count
+func (c *Counter) Page(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.done += n
+	c.pages++
+	c.shown = true
+	fmt.Fprintf(c.out, "\r\x1b[K%s", RenderCount(c.label, c.done, c.pages))
+}
+
+// Clear erases the count, so other output can be written on its line
+func (c *Counter) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.shown {
+		fmt.Fprint(c.out, "\r\x1b[K")
+		c.shown = false
+	}
+}
+
+// RenderCount formats a count of done items over pages
+func RenderCount(label string, done, pages int) string {
+	return fmt.Sprintf("%s: %d (page %d)", label, done, pages)
+}
@@ -0,0 +1,47 @@
+package progress
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRender(t *testing.T) {
+	assert.Equal(t, "shot.png [===============               ]  50% 1.0 MB/2.0 MB",
+		Render("shot.png", 1<<20, 2<<20))
+	assert.Equal(t, "empty [==============================] 100% 0 B/0 B", Render("empty", 0, 0))
+}
+
+func TestBytes(t *testing.T) {
+	assert.Equal(t, "512 B", Bytes(512))
+	assert.Equal(t, "1.5 KB", Bytes(1536))
+	assert.Equal(t, "3.2 GB", Bytes(3435973837))
+}
+
+func TestBarReader(t *testing.T) {
+	var out bytes.Buffer
+	bar := New(&out, "data", 10)
+	_, err := io.Copy(io.Discard, bar.Reader(strings.NewReader("0123456789")))
+	require.NoError(t, err)
+	bar.Finish()
+
+	// The last redraw before the newline shows the transfer complete
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\r\x1b[K")
+	assert.Contains(t, lines[len(lines)-1], "100% 10 B/10 B")
+	assert.False(t, Enabled(&out))
+}
+
+func TestCounter(t *testing.T) {
+	var out bytes.Buffer
+	counter := NewCounter(&out, "Fetching cards")
+	counter.Clear()
+	assert.Empty(t, out.String(), "nothing to clear before the first page")
+	counter.Page(50)
+	counter.Page(20)
+	counter.Clear()
+	assert.Equal(t, "\r\x1b[KFetching cards: 50 (page 1)\r\x1b[KFetching cards: 70 (page 2)\r\x1b[K", out.String())
+}
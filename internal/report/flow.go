@@ -0,0 +1,174 @@
+package report
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/visionik/fizz/internal/chart"
+	"github.com/visionik/fizz/internal/history"
+	"github.com/visionik/fizz/internal/kanban"
+)
+
+// ErrNoHistory is returned when no snapshots cover the requested days
+var ErrNoHistory = errors.New("no history for this period; run 'fizz sync' to take daily snapshots")
+
+// CFD builds a cumulative flow diagram of one board: for each day, the
+// number of cards in each state, stacked with closed cards at the bottom, then the
+// columns from last to first, then triage and not now. Days before the
+// first snapshot are dropped.
+func CFD(title string, snaps []*history.Snapshot, days []string) (*chart.Chart, error) {
+	daily, days := trimDays(history.Daily(snaps, days), days)
+	if len(days) == 0 {
+		return nil, ErrNoHistory
+	}
+
+	// Column order comes from the latest snapshot; columns that only
+	// existed earlier keep their old position
+	position := map[string]int{}
+	for _, s := range daily {
+		for _, col := range s.Columns {
+			position[col.Name] = col.Position
+		}
+	}
+	var columns []string
+	for name := range position {
+		columns = append(columns, name)
+	}
+	sort.Slice(columns, func(i, j int) bool {
+		if position[columns[i]] != position[columns[j]] {
+			return position[columns[i]] > position[columns[j]]
+		}
+		return columns[i] < columns[j]
+	})
+	// States are keyed apart from column names, which may be the same as
+	// a state's label
+	type state struct{ key, label string }
+	states := []state{{"closed", kanban.ClosedColumn}}
+	for _, name := range columns {
+		states = append(states, state{"column:" + name, name})
+	}
+	states = append(states, state{"triage", kanban.TriageColumn}, state{"not_now", kanban.NotNowColumn})
+
+	counts := make(map[string][]float64, len(states))
+	for _, st := range states {
+		counts[st.key] = make([]float64, len(days))
+	}
+	for d, s := range daily {
+		names := map[string]string{}
+		for _, col := range s.Columns {
+			names[col.ID] = col.Name
+		}
+		for _, card := range s.Cards {
+			key := "triage"
+			switch {
+			case card.Closed:
+				key = "closed"
+			case card.Status == "not_now":
+				key = "not_now"
+			case card.ColumnID != "":
+				if name, ok := names[card.ColumnID]; ok {
+					key = "column:" + name
+				}
+			}
+			counts[key][d]++
+		}
+	}
+
+	c := &chart.Chart{Title: title, Labels: days, Stacked: true}
+	for _, st := range states {
+		if st.key == "not_now" && sum(counts[st.key]) == 0 {
+			continue
+		}
+		c.Series = append(c.Series, chart.Series{Name: st.label, Values: counts[st.key]})
+	}
+	return c, nil
+}
+
+// Burndown builds a burndown chart of the cards matching match across
+// boards: the open cards remaining each day, the total scope, and an ideal
+// line from the first day's remaining cards to zero on due (a day in days
+// or later; "" means the last day)
+func Burndown(title string, boards [][]*history.Snapshot, days []string, match func(history.Card) bool, due string) (*chart.Chart, error) {
+	remaining := make([]float64, len(days))
+	scope := make([]float64, len(days))
+	covered := make([]bool, len(days))
+	for _, snaps := range boards {
+		for d, s := range history.Daily(snaps, days) {
+			if s == nil {
+				continue
+			}
+			covered[d] = true
+			for _, card := range s.Cards {
+				if !match(card) {
+					continue
+				}
+				scope[d]++
+				if !card.Closed {
+					remaining[d]++
+				}
+			}
+		}
+	}
+
+	first := 0
+	for first < len(days) && !covered[first] {
+		first++
+	}
+	days, remaining, scope = days[first:], remaining[first:], scope[first:]
+	if len(days) == 0 {
+		return nil, ErrNoHistory
+	}
+
+	// The ideal line reaches zero on the due day, counted in days from the
+	// first day
+	span := len(days) - 1
+	if due != "" {
+		span = dayDiff(days[0], due)
+	}
+	ideal := make([]float64, len(days))
+	for d := range ideal {
+		if span > 0 {
+			ideal[d] = math.Max(0, math.Round(remaining[0]*(1-float64(d)/float64(span))*10)/10)
+		}
+	}
+
+	return &chart.Chart{
+		Title:  title,
+		Labels: days,
+		Series: []chart.Series{
+			{Name: "Remaining", Values: remaining},
+			{Name: "Scope", Values: scope},
+			{Name: "Ideal", Values: ideal, Dashed: true},
+		},
+	}, nil
+}
+
+// trimDays drops the days before the first snapshot
+func trimDays(daily []*history.Snapshot, days []string) ([]*history.Snapshot, []string) {
+	for i, s := range daily {
+		if s != nil {
+			return daily[i:], days[i:]
+		}
+	}
+	return nil, nil
+}
+
+// dayDiff returns the number of days from one YYYY-MM-DD date to another
+func dayDiff(from, to string) int {
+	a, errA := time.Parse("2006-01-02", from)
+	b, errB := time.Parse("2006-01-02", to)
+	if errA != nil || errB != nil {
+		return 0
+	}
+	return int(math.Round(b.Sub(a).Hours() / 24))
+}
+
+func sum(values []float64) float64 {
+	total := 0.0
+	for _, v := range values {
+		total += v
+	}
+	return total
+}
@@ -0,0 +1,68 @@
+package report
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/visionik/fizz/internal/chart"
+	"github.com/visionik/fizz/internal/history"
+	"github.com/visionik/fizz/internal/kanban"
+)
+
+func snap(day int, cards ...history.Card) *history.Snapshot {
+	return &history.Snapshot{
+		TakenAt: time.Date(2026, time.March, day, 18, 0, 0, 0, time.Local),
+		Board:   history.Board{ID: "b1", Name: "Platform"},
+		Columns: []history.Column{{ID: "c1", Name: "Doing", Position: 1}, {ID: "c2", Name: "Review", Position: 2}},
+		Cards:   cards,
+	}
+}
+
+func TestCFD(t *testing.T) {
+	snaps := []*history.Snapshot{
+		snap(2, history.Card{ID: "a"}, history.Card{ID: "b", ColumnID: "c1"}),
+		snap(4, history.Card{ID: "a", ColumnID: "c2"}, history.Card{ID: "b", Closed: true}, history.Card{ID: "c", Status: "not_now"}),
+	}
+	days := []string{"2026-03-01", "2026-03-02", "2026-03-03", "2026-03-04"}
+
+	c, err := CFD("Flow", snaps, days)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"2026-03-02", "2026-03-03", "2026-03-04"}, c.Labels)
+	assert.True(t, c.Stacked)
+	assert.Equal(t, []chart.Series{
+		{Name: "Closed", Values: []float64{0, 0, 1}},
+		{Name: "Review", Values: []float64{0, 0, 1}},
+		{Name: "Doing", Values: []float64{1, 1, 0}},
+		{Name: "Triage", Values: []float64{1, 1, 0}},
+		{Name: "Not now", Values: []float64{0, 0, 1}},
+	}, c.Series)
+
+	_, err = CFD("Flow", snaps, []string{"2026-02-01"})
+	assert.ErrorIs(t, err, ErrNoHistory)
+}
+
+func TestBurndown(t *testing.T) {
+	tagged := func(id string, closed bool) history.Card {
+		return history.Card{ID: id, Closed: closed, Tags: []string{"m4"}}
+	}
+	board1 := []*history.Snapshot{
+		snap(1, tagged("a", false), tagged("b", false), history.Card{ID: "x"}),
+		snap(3, tagged("a", true), tagged("b", false), tagged("c", false)),
+	}
+	board2 := []*history.Snapshot{
+		snap(2, tagged("d", false)),
+	}
+	days := []string{"2026-03-01", "2026-03-02", "2026-03-03", "2026-03-04"}
+	match := func(c history.Card) bool { return kanban.HasTag(c.Tags, "m4") }
+
+	c, err := Burndown("Burndown", [][]*history.Snapshot{board1, board2}, days, match, "2026-03-05")
+	require.NoError(t, err)
+	assert.Equal(t, days, c.Labels)
+	assert.Equal(t, []chart.Series{
+		{Name: "Remaining", Values: []float64{2, 3, 3, 3}},
+		{Name: "Scope", Values: []float64{2, 3, 4, 4}},
+		{Name: "Ideal", Values: []float64{2, 1.5, 1, 0.5}, Dashed: true},
+	}, c.Series)
+}
@@ -0,0 +1,358 @@
+// Package report computes flow metrics for a set of cards: weekly
+// throughput, lead and cycle time percentiles, work in progress per column
+// and the age of open cards.
+package report
+
+import (
+	"math"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/visionik/fizz/internal/kanban"
+	"github.com/visionik/libfizz-go/fizzy"
+)
+
+// Day is the unit durations are reported in
+const Day = 24 * time.Hour
+
+// Options controls what a report covers
+type Options struct {
+	// Now is the end of the report; zero means time.Now
+	Now time.Time
+	// Weeks of throughput, and of closed cards for lead and cycle time
+	Weeks int
+	// Oldest is how many of the oldest open cards to list
+	Oldest int
+	// Columns names and orders the columns cards are in
+	Columns []fizzy.Column
+	// Starts maps card IDs to when work on them started, from column
+	// history; without it cycle time is not reported
+	Starts map[string]time.Time
+}
+
+// Report holds the metrics computed from a set of cards
+type Report struct {
+	From       time.Time  `json:"from"`
+	To         time.Time  `json:"to"`
+	Cards      int        `json:"cards"`
+	Throughput []Week     `json:"throughput"`
+	LeadTime   Stats      `json:"lead_time"`
+	CycleTime  *Stats     `json:"cycle_time"`
+	WIP        []WIP      `json:"wip"`
+	Aging      []Bucket   `json:"aging"`
+	Oldest     []OpenCard `json:"oldest"`
+}
+
+// Week is the number of cards closed in the week starting Start (a Monday)
+type Week struct {
+	Start  time.Time `json:"start"`
+	Closed int       `json:"closed"`
+}
+
+// Stats summarises durations, in days
+type Stats struct {
+	Count int     `json:"count"`
+	Mean  float64 `json:"mean_days"`
+	P50   float64 `json:"p50_days"`
+	P85   float64 `json:"p85_days"`
+	P95   float64 `json:"p95_days"`
+	Max   float64 `json:"max_days"`
+}
+
+// WIP is the number of open cards in a column
+type WIP struct {
+	Board  string `json:"board,omitempty"`
+	Column string `json:"column"`
+	Cards  int    `json:"cards"`
+}
+
+// Bucket counts open cards by age
+type Bucket struct {
+	Label string `json:"label"`
+	Cards int    `json:"cards"`
+}
+
+// OpenCard is an open card with its age and time since last activity, in days
+type OpenCard struct {
+	Number int     `json:"number"`
+	Title  string  `json:"title"`
+	Column string  `json:"column"`
+	Age    float64 `json:"age_days"`
+	Idle   float64 `json:"idle_days"`
+}
+
+var agingBuckets = []struct {
+	label string
+	max   time.Duration
+}{
+	{"< 1 week", 7 * Day},
+	{"1-2 weeks", 14 * Day},
+	{"2-4 weeks", 28 * Day},
+	{"1-3 months", 91 * Day},
+	{"> 3 months", math.MaxInt64},
+}
+
+// Build computes a report over cards
+func Build(cards []fizzy.Card, opts Options) *Report {
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	weeks := opts.Weeks
+	if weeks < 1 {
+		weeks = 12
+	}
+
+	first := WeekStart(now).AddDate(0, 0, -7*(weeks-1))
+	r := &Report{From: first, To: now, Cards: len(cards)}
+
+	r.Throughput = make([]Week, weeks)
+	for i := range r.Throughput {
+		r.Throughput[i].Start = first.AddDate(0, 0, 7*i)
+	}
+
+	columns := map[string]fizzy.Column{}
+	for _, col := range opts.Columns {
+		columns[col.ID] = col
+	}
+	wip := map[string]*WIP{}
+	r.Aging = make([]Bucket, len(agingBuckets))
+	for i, b := range agingBuckets {
+		r.Aging[i].Label = b.label
+	}
+
+	var lead, cycle []time.Duration
+	var open []OpenCard
+	for _, card := range cards {
+		if card.Closed || card.ClosedAt != nil {
+			if card.ClosedAt == nil || card.ClosedAt.Before(first) || card.ClosedAt.After(now) {
+				continue
+			}
+			closed := card.ClosedAt.In(now.Location())
+			week := int(math.Round(WeekStart(closed).Sub(first).Hours() / 24 / 7))
+			r.Throughput[week].Closed++
+			lead = append(lead, closed.Sub(card.CreatedAt))
+			if start, ok := opts.Starts[card.ID]; ok && !start.After(closed) {
+				cycle = append(cycle, closed.Sub(start))
+			}
+			continue
+		}
+
+		column, board := columnOf(card, columns)
+		key := board + "\x00" + column
+		if wip[key] == nil {
+			wip[key] = &WIP{Board: board, Column: column}
+		}
+		wip[key].Cards++
+
+		age := now.Sub(card.CreatedAt)
+		for i, b := range agingBuckets {
+			if age < b.max {
+				r.Aging[i].Cards++
+				break
+			}
+		}
+		idle := age
+		if card.LastActiveAt != nil {
+			idle = now.Sub(*card.LastActiveAt)
+		}
+		open = append(open, OpenCard{
+			Number: card.Number,
+			Title:  card.Title,
+			Column: column,
+			Age:    round(days(age)),
+			Idle:   round(days(idle)),
+		})
+	}
+
+	r.LeadTime = Summarize(lead)
+	if opts.Starts != nil {
+		stats := Summarize(cycle)
+		r.CycleTime = &stats
+	}
+
+	r.WIP = orderWIP(wip, opts.Columns)
+
+	sort.SliceStable(open, func(i, j int) bool { return open[i].Age > open[j].Age })
+	limit := opts.Oldest
+	if limit <= 0 {
+		limit = 10
+	}
+	if len(open) > limit {
+		open = open[:limit]
+	}
+	r.Oldest = open
+	return r
+}
+
+// columnOf names the column a card is in and its board, if known
+func columnOf(card fizzy.Card, columns map[string]fizzy.Column) (string, string) {
+	board := ""
+	if card.Board != nil {
+		board = card.Board.Name
+	}
+	switch {
+	case card.Status == "not_now":
+		return kanban.NotNowColumn, board
+	case card.ColumnID == nil || *card.ColumnID == "":
+		return kanban.TriageColumn, board
+	}
+	if col, ok := columns[*card.ColumnID]; ok {
+		return col.Name, board
+	}
+	return *card.ColumnID, board
+}
+
+// orderWIP lists columns in board order: triage first, then the board's
+// columns, then not now
+func orderWIP(wip map[string]*WIP, columns []fizzy.Column) []WIP {
+	rank := map[string]int{kanban.TriageColumn: -1, kanban.NotNowColumn: math.MaxInt32}
+	for _, col := range columns {
+		if _, ok := rank[col.Name]; !ok {
+			rank[col.Name] = col.Position
+		}
+	}
+
+	list := make([]WIP, 0, len(wip))
+	for _, w := range wip {
+		list = append(list, *w)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Board != list[j].Board {
+			return list[i].Board < list[j].Board
+		}
+		ri, rj := rank[list[i].Column], rank[list[j].Column]
+		if ri != rj {
+			return ri < rj
+		}
+		return list[i].Column < list[j].Column
+	})
+	return list
+}
+
+// Summarize computes the mean, percentiles and maximum of durations
+func Summarize(durations []time.Duration) Stats {
+	stats := Stats{Count: len(durations)}
+	if len(durations) == 0 {
+		return stats
+	}
+
+	values := make([]float64, len(durations))
+	sum := 0.0
+	for i, d := range durations {
+		values[i] = days(d)
+		sum += values[i]
+	}
+	sort.Float64s(values)
+
+	stats.Mean = round(sum / float64(len(values)))
+	stats.P50 = round(Percentile(values, 50))
+	stats.P85 = round(Percentile(values, 85))
+	stats.P95 = round(Percentile(values, 95))
+	stats.Max = round(values[len(values)-1])
+	return stats
+}
+
+// Percentile returns the p-th percentile of sorted values, interpolating
+// between neighbouring ranks
+func Percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	pos := p / 100 * float64(len(sorted)-1)
+	lo := int(math.Floor(pos))
+	hi := int(math.Ceil(pos))
+	return sorted[lo] + (sorted[hi]-sorted[lo])*(pos-float64(lo))
+}
+
+// WeekStart returns midnight on the Monday of t's week
+func WeekStart(t time.Time) time.Time {
+	offset := (int(t.Weekday()) + 6) % 7
+	return time.Date(t.Year(), t.Month(), t.Day()-offset, 0, 0, 0, 0, t.Location())
+}
+
+// Filter returns the cards carrying all of tags (by name or ID) and assigned
+// to any of assignees (by ID or name)
+func Filter(cards []fizzy.Card, tags, assignees []string) []fizzy.Card {
+	if len(tags) == 0 && len(assignees) == 0 {
+		return cards
+	}
+	var out []fizzy.Card
+	for _, card := range cards {
+		missing := slices.ContainsFunc(tags, func(tag string) bool {
+			return !kanban.HasTag(card.Tags, tag)
+		})
+		if !missing && hasAnyAssignee(card, assignees) {
+			out = append(out, card)
+		}
+	}
+	return out
+}
+
+func hasAnyAssignee(card fizzy.Card, assignees []string) bool {
+	if len(assignees) == 0 {
+		return true
+	}
+	for _, want := range assignees {
+		for _, u := range card.Assignees {
+			if u.ID == want || strings.EqualFold(u.Name, want) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Row is one line of a report in long form, for CSV
+type Row struct {
+	Section string `json:"section"`
+	Key     string `json:"key"`
+	Label   string `json:"label"`
+	Value   string `json:"value"`
+}
+
+// Rows flattens the report into section, key, label and value rows
+func (r *Report) Rows() []Row {
+	var rows []Row
+	add := func(section, key, label string, value float64) {
+		rows = append(rows, Row{section, key, label, strconv.FormatFloat(value, 'f', -1, 64)})
+	}
+
+	for _, w := range r.Throughput {
+		add("throughput", w.Start.Format("2006-01-02"), "closed", float64(w.Closed))
+	}
+	stats := func(section string, s Stats) {
+		add(section, "count", "cards", float64(s.Count))
+		add(section, "mean", "days", s.Mean)
+		add(section, "p50", "days", s.P50)
+		add(section, "p85", "days", s.P85)
+		add(section, "p95", "days", s.P95)
+		add(section, "max", "days", s.Max)
+	}
+	stats("lead_time", r.LeadTime)
+	if r.CycleTime != nil {
+		stats("cycle_time", *r.CycleTime)
+	}
+	for _, w := range r.WIP {
+		add("wip", w.Column, w.Board, float64(w.Cards))
+	}
+	for _, b := range r.Aging {
+		add("aging", b.Label, "cards", float64(b.Cards))
+	}
+	for _, c := range r.Oldest {
+		add("oldest", strconv.Itoa(c.Number), c.Title, c.Age)
+	}
+	return rows
+}
+
+func days(d time.Duration) float64 {
+	return d.Hours() / 24
+}
+
+// round keeps one decimal place
+func round(v float64) float64 {
+	return math.Round(v*10) / 10
+}
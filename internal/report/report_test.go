@@ -0,0 +1,101 @@
+package report
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/visionik/fizz/internal/kanban"
+	"github.com/visionik/libfizz-go/fizzy"
+)
+
+func date(day int, hour int) time.Time {
+	return time.Date(2026, time.March, day, hour, 0, 0, 0, time.UTC)
+}
+
+func closedCard(id string, created, closed time.Time) fizzy.Card {
+	return fizzy.Card{ID: id, Closed: true, Status: "closed", CreatedAt: created, ClosedAt: &closed}
+}
+
+func TestBuild(t *testing.T) {
+	doing, done := "col-doing", "col-done"
+	columns := []fizzy.Column{
+		{ID: done, Name: "Done", Position: 3},
+		{ID: doing, Name: "Doing", Position: 2},
+	}
+	cards := []fizzy.Card{
+		closedCard("a", date(2, 9), date(3, 9)),   // 1 day, week of Mar 2
+		closedCard("b", date(2, 9), date(5, 9)),   // 3 days, week of Mar 2
+		closedCard("c", date(9, 9), date(14, 9)),  // 5 days, week of Mar 9
+		closedCard("d", date(1, 9), date(16, 21)), // 15.5 days, week of Mar 16
+		closedCard("old", date(1, 9), date(1, 10)),
+		{ID: "e", Number: 5, Title: "Stale", Status: "published", ColumnID: &doing, CreatedAt: date(1, 12)},
+		{ID: "f", Number: 6, Title: "Fresh", Status: "published", ColumnID: &doing, CreatedAt: date(17, 12)},
+		{ID: "g", Number: 7, Title: "Waiting", Status: "published", CreatedAt: date(10, 12)},
+		{ID: "h", Number: 8, Title: "Later", Status: "not_now", ColumnID: &done, CreatedAt: date(10, 12)},
+	}
+
+	r := Build(cards, Options{
+		Now:     date(18, 12),
+		Weeks:   3,
+		Oldest:  2,
+		Columns: columns,
+		Starts:  map[string]time.Time{"c": date(12, 9)},
+	})
+
+	assert.Equal(t, []Week{
+		{Start: date(2, 0), Closed: 2},
+		{Start: date(9, 0), Closed: 1},
+		{Start: date(16, 0), Closed: 1},
+	}, r.Throughput)
+	assert.Equal(t, Stats{Count: 4, Mean: 6.1, P50: 4, P85: 10.8, P95: 13.9, Max: 15.5}, r.LeadTime)
+	assert.Equal(t, &Stats{Count: 1, Mean: 2, P50: 2, P85: 2, P95: 2, Max: 2}, r.CycleTime)
+	assert.Equal(t, []WIP{
+		{Column: kanban.TriageColumn, Cards: 1},
+		{Column: "Doing", Cards: 2},
+		{Column: kanban.NotNowColumn, Cards: 1},
+	}, r.WIP)
+	assert.Equal(t, []Bucket{
+		{"< 1 week", 1}, {"1-2 weeks", 2}, {"2-4 weeks", 1}, {"1-3 months", 0}, {"> 3 months", 0},
+	}, r.Aging)
+	assert.Equal(t, []OpenCard{
+		{Number: 5, Title: "Stale", Column: "Doing", Age: 17, Idle: 17},
+		{Number: 7, Title: "Waiting", Column: kanban.TriageColumn, Age: 8, Idle: 8},
+	}, r.Oldest)
+}
+
+func TestBuildWithoutHistory(t *testing.T) {
+	r := Build(nil, Options{Now: date(18, 12), Weeks: 2})
+	assert.Nil(t, r.CycleTime)
+	assert.Equal(t, Stats{}, r.LeadTime)
+	assert.Len(t, r.Throughput, 2)
+}
+
+func TestFilter(t *testing.T) {
+	bug := fizzy.Tag{ID: "t1", Name: "bug"}
+	jordan := fizzy.User{ID: "u1", Name: "Jordan Lee"}
+	cards := []fizzy.Card{
+		{ID: "a", Tags: []fizzy.Tag{bug}, Assignees: []fizzy.User{jordan}},
+		{ID: "b", Tags: []fizzy.Tag{bug}},
+		{ID: "c", Assignees: []fizzy.User{jordan}},
+	}
+
+	ids := func(cards []fizzy.Card) []string {
+		var out []string
+		for _, c := range cards {
+			out = append(out, c.ID)
+		}
+		return out
+	}
+	assert.Equal(t, []string{"a", "b"}, ids(Filter(cards, []string{"#bug"}, nil)))
+	assert.Equal(t, []string{"a", "c"}, ids(Filter(cards, nil, []string{"jordan lee"})))
+	assert.Equal(t, []string{"a"}, ids(Filter(cards, []string{"t1"}, []string{"u1"})))
+}
+
+func TestPercentile(t *testing.T) {
+	values := []float64{1, 2, 3, 4}
+	assert.Equal(t, 2.5, Percentile(values, 50))
+	assert.Equal(t, 1.0, Percentile(values, 0))
+	assert.Equal(t, 4.0, Percentile(values, 100))
+	assert.Equal(t, 0.0, Percentile(nil, 50))
+}
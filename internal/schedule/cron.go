@@ -0,0 +1,170 @@
+// Package schedule evaluates cron rules that create recurring cards and keeps
+// track of which occurrences have already been handled.
+package schedule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Cron is a parsed five-field cron expression: minute, hour, day of month,
+// month and day of week
+type Cron struct {
+	minute, hour, dom, month, dow uint64
+	// domAny and dowAny record a "*" day field; when both day fields are
+	// restricted, a day matching either one matches, as in cron(8)
+	domAny, dowAny bool
+}
+
+var macros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+var monthNames = map[string]int{
+	"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+	"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+}
+
+var dayNames = map[string]int{
+	"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+}
+
+// ParseCron parses a standard cron expression such as "0 9 * * mon" or a
+// macro such as @weekly
+func ParseCron(expr string) (*Cron, error) {
+	spec := strings.TrimSpace(expr)
+	if m, ok := macros[strings.ToLower(spec)]; ok {
+		spec = m
+	}
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("invalid cron expression %q: want 5 fields (minute hour day month weekday)", expr)
+	}
+
+	c := &Cron{}
+	var err error
+	if c.minute, err = parseField(fields[0], 0, 59, nil); err != nil {
+		return nil, fmt.Errorf("invalid cron expression %q: minute: %w", expr, err)
+	}
+	if c.hour, err = parseField(fields[1], 0, 23, nil); err != nil {
+		return nil, fmt.Errorf("invalid cron expression %q: hour: %w", expr, err)
+	}
+	if c.dom, err = parseField(fields[2], 1, 31, nil); err != nil {
+		return nil, fmt.Errorf("invalid cron expression %q: day of month: %w", expr, err)
+	}
+	if c.month, err = parseField(fields[3], 1, 12, monthNames); err != nil {
+		return nil, fmt.Errorf("invalid cron expression %q: month: %w", expr, err)
+	}
+	// Sunday may be written as 7
+	if c.dow, err = parseField(fields[4], 0, 7, dayNames); err != nil {
+		return nil, fmt.Errorf("invalid cron expression %q: day of week: %w", expr, err)
+	}
+	if c.dow&(1<<7) != 0 {
+		c.dow = c.dow&^(1<<7) | 1
+	}
+	c.domAny = strings.HasPrefix(fields[2], "*")
+	c.dowAny = strings.HasPrefix(fields[4], "*")
+	return c, nil
+}
+
+// parseField parses a comma-separated list of values, ranges and steps into
+// a bit set
+func parseField(field string, min, max int, names map[string]int) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rng, stepText, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepText)
+			if err != nil || n < 1 {
+				return 0, fmt.Errorf("invalid step %q", stepText)
+			}
+			step = n
+		}
+
+		lo, hi := min, max
+		if rng != "*" {
+			loText, hiText, isRange := strings.Cut(rng, "-")
+			var err error
+			if lo, err = parseValue(loText, min, max, names); err != nil {
+				return 0, err
+			}
+			hi = lo
+			if isRange {
+				if hi, err = parseValue(hiText, min, max, names); err != nil {
+					return 0, err
+				}
+				if hi < lo {
+					return 0, fmt.Errorf("invalid range %q", rng)
+				}
+			} else if hasStep {
+				hi = max
+			}
+		}
+
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << v
+		}
+	}
+	return bits, nil
+}
+
+func parseValue(text string, min, max int, names map[string]int) (int, error) {
+	if v, ok := names[strings.ToLower(text)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(text)
+	if err != nil || v < min || v > max {
+		return 0, fmt.Errorf("invalid value %q (want %d-%d)", text, min, max)
+	}
+	return v, nil
+}
+
+// maxSearch bounds Next for expressions that never match, such as Feb 30
+const maxSearch = 5 * 366 * 24 * time.Hour
+
+// Next returns the first time matching c strictly after t, in t's location,
+// or the zero time if there is none within five years
+func (c *Cron) Next(t time.Time) time.Time {
+	loc := t.Location()
+	limit := t.Add(maxSearch)
+	t = t.Truncate(time.Minute).Add(time.Minute)
+
+	for t.Before(limit) {
+		switch {
+		case c.month&(1<<int(t.Month())) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+		case !c.matchDay(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+		case c.hour&(1<<t.Hour()) == 0:
+			next := time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+			if !next.After(t) {
+				// A DST change repeated the hour
+				next = t.Truncate(time.Hour).Add(time.Hour)
+			}
+			t = next
+		case c.minute&(1<<t.Minute()) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+func (c *Cron) matchDay(t time.Time) bool {
+	dom := c.dom&(1<<t.Day()) != 0
+	dow := c.dow&(1<<int(t.Weekday())) != 0
+	if c.domAny || c.dowAny {
+		return dom && dow
+	}
+	return dom || dow
+}
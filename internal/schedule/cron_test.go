@@ -0,0 +1,87 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/visionik/fizz/internal/config"
+)
+
+func at(s string) time.Time {
+	t, err := time.Parse("2006-01-02 15:04", s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestCronNext(t *testing.T) {
+	tests := []struct {
+		expr, from, want string
+	}{
+		{"0 9 * * mon", "2026-10-18 12:00", "2026-10-19 09:00"},
+		{"0 9 * * 1", "2026-10-19 09:00", "2026-10-26 09:00"},
+		{"*/15 * * * *", "2026-10-18 12:07", "2026-10-18 12:15"},
+		{"30 8-10/2 * * *", "2026-10-18 08:30", "2026-10-18 10:30"},
+		{"@monthly", "2026-10-18 12:00", "2026-11-01 00:00"},
+		{"0 0 29 feb *", "2026-01-01 00:00", "2028-02-29 00:00"},
+		{"0 12 1 * fri", "2026-10-18 00:00", "2026-10-23 12:00"},
+		{"0 0 * * 7", "2026-10-18 12:00", "2026-10-25 00:00"},
+		{"0 6 1,15 jan-mar *", "2026-10-18 00:00", "2027-01-01 06:00"},
+	}
+	for _, tt := range tests {
+		c, err := ParseCron(tt.expr)
+		require.NoError(t, err, tt.expr)
+		assert.Equal(t, at(tt.want), c.Next(at(tt.from)), tt.expr)
+	}
+
+	c, err := ParseCron("0 0 30 feb *")
+	require.NoError(t, err)
+	assert.True(t, c.Next(at("2026-01-01 00:00")).IsZero())
+}
+
+func TestParseCronErrors(t *testing.T) {
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "* * 0 * *", "5-1 * * * *", "*/0 * * * *", "* * * foo *"} {
+		_, err := ParseCron(expr)
+		assert.Error(t, err, expr)
+	}
+}
+
+func TestRuleDue(t *testing.T) {
+	rules, err := Rules(&config.Settings{Schedules: []config.ScheduleRule{
+		{Name: "handoff", Cron: "0 9 * * mon", Timezone: "UTC", Board: "b", Title: "Handoff {{date}}"},
+	}})
+	require.NoError(t, err)
+	rule := rules[0]
+
+	// A new rule catches up on the past day only
+	due, ok := rule.Due(time.Time{}, at("2026-10-19 09:05"))
+	assert.True(t, ok)
+	assert.Equal(t, at("2026-10-19 09:00"), due)
+	_, ok = rule.Due(time.Time{}, at("2026-10-21 09:05"))
+	assert.False(t, ok)
+
+	// Handled occurrences are not due again; missed ones collapse into the latest
+	_, ok = rule.Due(at("2026-10-19 09:00"), at("2026-10-19 23:00"))
+	assert.False(t, ok)
+	due, ok = rule.Due(at("2026-10-05 09:00"), at("2026-10-21 09:05"))
+	assert.True(t, ok)
+	assert.Equal(t, at("2026-10-19 09:00"), due)
+
+	card, err := rule.Card(due)
+	require.NoError(t, err)
+	assert.Equal(t, "Handoff 2026-10-19", card.Title)
+}
+
+func TestRulesValidation(t *testing.T) {
+	_, err := Rules(&config.Settings{Schedules: []config.ScheduleRule{{Name: "a", Cron: "@daily"}}})
+	assert.EqualError(t, err, "schedule a needs a template or a title")
+
+	_, err = Rules(&config.Settings{Schedules: []config.ScheduleRule{
+		{Name: "a", Cron: "@daily", Title: "x"},
+		{Name: "a", Cron: "@daily", Title: "y"},
+	}})
+	assert.EqualError(t, err, "schedule a is defined twice")
+}
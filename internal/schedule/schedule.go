@@ -0,0 +1,254 @@
+package schedule
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/visionik/fizz/internal/config"
+	"github.com/visionik/fizz/internal/templates"
+)
+
+// StateFileName is the file in the config directory recording handled
+// occurrences
+const StateFileName = "schedule-state.json"
+
+// catchUp is how far back a rule with no recorded run looks for a due
+// occurrence, so a new rule does not create a stale card
+const catchUp = 24 * time.Hour
+
+// maxBacklog bounds the search for the latest missed occurrence
+const maxBacklog = 366 * 24 * time.Hour
+
+// staleLock is how old a lock file must be before it is assumed abandoned
+const staleLock = 10 * time.Minute
+
+// Rule is a validated schedule rule
+type Rule struct {
+	config.ScheduleRule
+	cron *Cron
+	loc  *time.Location
+}
+
+// Rules validates the rules in settings
+func Rules(settings *config.Settings) ([]Rule, error) {
+	seen := map[string]bool{}
+	rules := make([]Rule, 0, len(settings.Schedules))
+	for i, sr := range settings.Schedules {
+		if sr.Name == "" {
+			return nil, fmt.Errorf("schedule %d has no name", i+1)
+		}
+		if seen[sr.Name] {
+			return nil, fmt.Errorf("schedule %s is defined twice", sr.Name)
+		}
+		seen[sr.Name] = true
+
+		if sr.Template == "" && sr.Title == "" {
+			return nil, fmt.Errorf("schedule %s needs a template or a title", sr.Name)
+		}
+		cron, err := ParseCron(sr.Cron)
+		if err != nil {
+			return nil, fmt.Errorf("schedule %s: %w", sr.Name, err)
+		}
+		loc := time.Local
+		if sr.Timezone != "" {
+			if loc, err = time.LoadLocation(sr.Timezone); err != nil {
+				return nil, fmt.Errorf("schedule %s: invalid timezone %q", sr.Name, sr.Timezone)
+			}
+		}
+		rules = append(rules, Rule{ScheduleRule: sr, cron: cron, loc: loc})
+	}
+	return rules, nil
+}
+
+// Find returns the rule called name
+func Find(rules []Rule, name string) (*Rule, error) {
+	for i := range rules {
+		if rules[i].Name == name {
+			return &rules[i], nil
+		}
+	}
+	return nil, fmt.Errorf("schedule %s not found", name)
+}
+
+// Next returns the rule's first occurrence after t, in the rule's timezone
+func (r Rule) Next(t time.Time) time.Time {
+	return r.cron.Next(t.In(r.loc))
+}
+
+// Upcoming returns the rule's next n occurrences after t
+func (r Rule) Upcoming(t time.Time, n int) []time.Time {
+	var times []time.Time
+	for len(times) < n {
+		if t = r.Next(t); t.IsZero() {
+			break
+		}
+		times = append(times, t)
+	}
+	return times
+}
+
+// Due returns the latest occurrence after last and at or before now. Missed
+// occurrences collapse into one, so a machine that was off creates a single
+// card. With no last run only the past day is considered.
+func (r Rule) Due(last, now time.Time) (time.Time, bool) {
+	start := last
+	if start.IsZero() {
+		start = now.Add(-catchUp)
+	}
+	if floor := now.Add(-maxBacklog); start.Before(floor) {
+		start = floor
+	}
+
+	var due time.Time
+	for t := r.Next(start); !t.IsZero() && !t.After(now); t = r.Next(t) {
+		due = t
+	}
+	return due, !due.IsZero()
+}
+
+// Card returns the card the rule creates for the occurrence at, rendered
+// with the rule's variables and {{date}} set to the occurrence's date
+func (r Rule) Card(at time.Time) (*templates.Template, error) {
+	t := &templates.Template{Name: r.Name}
+	if r.Template != "" {
+		var err error
+		if t, err = templates.Load(r.Template); err != nil {
+			return nil, fmt.Errorf("schedule %s: %w", r.Name, err)
+		}
+	}
+
+	if r.Board != "" {
+		t.Board = r.Board
+	}
+	if r.Title != "" {
+		t.Title = r.Title
+	}
+	if r.Body != "" {
+		t.Body = r.Body
+	}
+	if r.Tags != nil {
+		t.Tags = r.Tags
+	}
+	if r.Assignees != nil {
+		t.Assignees = r.Assignees
+	}
+	if r.Column != "" {
+		t.Column = r.Column
+	}
+	if r.Steps != nil {
+		t.Steps = r.Steps
+	}
+
+	vars := map[string]string{"date": at.Format("2006-01-02")}
+	for k, v := range r.Vars {
+		vars[k] = v
+	}
+	card, err := t.Render(vars)
+	if err != nil {
+		return nil, fmt.Errorf("schedule %s: %w", r.Name, err)
+	}
+	if card.Board == "" {
+		return nil, fmt.Errorf("schedule %s has no board", r.Name)
+	}
+	return card, nil
+}
+
+// RuleState records the last occurrence handled for a rule
+type RuleState struct {
+	Last  time.Time `json:"last"`
+	Card  int       `json:"card,omitempty"`
+	RanAt time.Time `json:"ran_at"`
+}
+
+// State is the schedule's record of handled occurrences, by rule name
+type State struct {
+	Rules map[string]RuleState `json:"rules"`
+	path  string
+}
+
+// StatePath returns the state file's location
+func StatePath() (string, error) {
+	dir, err := config.Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, StateFileName), nil
+}
+
+// LoadState reads the state file; a missing file is an empty state
+func LoadState() (*State, error) {
+	path, err := StatePath()
+	if err != nil {
+		return nil, err
+	}
+	s := &State{Rules: map[string]RuleState{}, path: path}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schedule state: %w", err)
+	}
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if s.Rules == nil {
+		s.Rules = map[string]RuleState{}
+	}
+	return s, nil
+}
+
+// Save writes the state file atomically
+func (s *State) Save() error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode schedule state: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return fmt.Errorf("failed to write schedule state: %w", err)
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write schedule state: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("failed to write schedule state: %w", err)
+	}
+	return nil
+}
+
+// Lock stops two runs from handling the same occurrence. It returns a
+// function that releases the lock.
+func Lock() (func(), error) {
+	path, err := StatePath()
+	if err != nil {
+		return nil, err
+	}
+	path += ".lock"
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to lock schedule state: %w", err)
+	}
+
+	for attempt := 0; attempt < 2; attempt++ {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+		if err == nil {
+			fmt.Fprintf(f, "%d\n", os.Getpid())
+			f.Close()
+			return func() { os.Remove(path) }, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, fmt.Errorf("failed to lock schedule state: %w", err)
+		}
+		info, statErr := os.Stat(path)
+		if statErr != nil || time.Since(info.ModTime()) < staleLock {
+			break
+		}
+		os.Remove(path)
+	}
+	return nil, fmt.Errorf("another schedule run is in progress (remove %s if it is not)", path)
+}
@@ -0,0 +1,212 @@
+// Package shell holds the parts of the interactive fizz shell that do not
+// depend on the command tree: splitting input into words, persistent
+// history and completion of a word from candidates.
+package shell
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/visionik/fizz/internal/config"
+)
+
+// HistoryFileName is the shell history in the config directory
+const HistoryFileName = "shell_history"
+
+// maxHistory bounds the lines kept in memory and in the file
+const maxHistory = 1000
+
+// Split breaks a line into words like a POSIX shell: whitespace separates
+// words, single quotes keep text literally, and double quotes and
+// backslashes escape
+func Split(line string) ([]string, error) {
+	var words []string
+	var word strings.Builder
+	inWord := false
+	var quote rune
+	escaped := false
+
+	for _, r := range line {
+		switch {
+		case escaped:
+			word.WriteRune(r)
+			escaped = false
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case r == '\\':
+			escaped, inWord = true, true
+		case quote == '"':
+			if r == '"' {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote, inWord = r, true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote", quote)
+	}
+	if escaped {
+		return nil, errors.New("line ends with a backslash")
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
+
+// Quote returns word quoted for Split if it needs to be
+func Quote(word string) string {
+	if word != "" && !strings.ContainsAny(word, " \t\n'\"\\") {
+		return word
+	}
+	return "'" + strings.ReplaceAll(word, "'", `'\''`) + "'"
+}
+
+// History is the shell's input history, kept in a file so it survives
+// between sessions. It implements term.History.
+type History struct {
+	lines []string
+	path  string
+	// saved counts the lines in the file
+	saved int
+}
+
+// LoadHistory reads the history file in the config directory; a missing
+// file is an empty history
+func LoadHistory() (*History, error) {
+	dir, err := config.Dir()
+	if err != nil {
+		return nil, err
+	}
+	h := &History{path: filepath.Join(dir, HistoryFileName)}
+
+	f, err := os.Open(h.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return h, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read shell history: %w", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		h.saved++
+		if line := scanner.Text(); line != "" {
+			h.lines = append(h.lines, line)
+		}
+	}
+	if len(h.lines) > maxHistory {
+		h.lines = h.lines[len(h.lines)-maxHistory:]
+	}
+	return h, scanner.Err()
+}
+
+// Add records a line, skipping blanks and repeats of the last line, and
+// appends it to the file, which is rewritten with the last maxHistory lines
+// once it holds that many. Failing to write the file only loses history, so
+// errors are ignored.
+func (h *History) Add(line string) {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.Contains(line, "\n") || (len(h.lines) > 0 && h.lines[len(h.lines)-1] == line) {
+		return
+	}
+	h.lines = append(h.lines, line)
+	if len(h.lines) > maxHistory {
+		h.lines = h.lines[1:]
+	}
+
+	if h.path == "" {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(h.path), 0o755); err != nil {
+		return
+	}
+	if h.saved >= maxHistory {
+		h.rewrite()
+		return
+	}
+	f, err := os.OpenFile(h.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	if _, err := fmt.Fprintln(f, line); err == nil {
+		h.saved++
+	}
+}
+
+// rewrite replaces the file with the lines in memory
+func (h *History) rewrite() {
+	tmp, err := os.CreateTemp(filepath.Dir(h.path), HistoryFileName+"-*")
+	if err != nil {
+		return
+	}
+	defer os.Remove(tmp.Name())
+	w := bufio.NewWriter(tmp)
+	for _, line := range h.lines {
+		fmt.Fprintln(w, line)
+	}
+	err = w.Flush()
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil && os.Rename(tmp.Name(), h.path) == nil {
+		h.saved = len(h.lines)
+	}
+}
+
+// Len returns the number of lines in the history
+func (h *History) Len() int {
+	return len(h.lines)
+}
+
+// At returns a line, 0 being the most recent
+func (h *History) At(i int) string {
+	return h.lines[len(h.lines)-1-i]
+}
+
+// Complete completes word from candidates. It returns the word extended as
+// far as all matching candidates agree, whether that finished the word (a
+// single match), and the matches.
+func Complete(word string, candidates []string) (string, bool, []string) {
+	var matches []string
+	for _, c := range candidates {
+		if strings.HasPrefix(c, word) {
+			matches = append(matches, c)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return word, false, nil
+	case 1:
+		return matches[0], true, matches
+	}
+	prefix := matches[0]
+	for _, m := range matches[1:] {
+		for !strings.HasPrefix(m, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	return prefix, false, matches
+}
@@ -0,0 +1,101 @@
+package shell
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplit(t *testing.T) {
+	words, err := Split(`cards create --title "Fix it" 'a \ b' c\ d  `)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"cards", "create", "--title", "Fix it", `a \ b`, "c d"}, words)
+
+	words, err = Split(`--body=""`)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"--body="}, words)
+
+	_, err = Split(`say "hi`)
+	assert.EqualError(t, err, `unterminated " quote`)
+
+	for _, word := range []string{"plain", "two words", "it's", ""} {
+		words, err := Split(Quote(word))
+		require.NoError(t, err)
+		assert.Equal(t, []string{word}, words)
+	}
+}
+
+func TestComplete(t *testing.T) {
+	word, done, matches := Complete("ca", []string{"cards", "cache", "columns"})
+	assert.Equal(t, "ca", word)
+	assert.False(t, done)
+	assert.Equal(t, []string{"cards", "cache"}, matches)
+
+	word, done, _ = Complete("co", []string{"cards", "columns", "comments"})
+	assert.Equal(t, "co", word)
+	assert.False(t, done)
+
+	word, done, _ = Complete("col", []string{"cards", "columns", "comments"})
+	assert.Equal(t, "columns", word)
+	assert.True(t, done)
+
+	word, _, _ = Complete("b", []string{"boards list", "boards show"})
+	assert.Equal(t, "boards ", word)
+}
+
+func TestHistory(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("FIZZ_CONFIG_DIR", dir)
+
+	h, err := LoadHistory()
+	require.NoError(t, err)
+	h.Add("boards list")
+	h.Add("boards list")
+	h.Add("  ")
+	h.Add("use board Platform")
+	assert.Equal(t, 2, h.Len())
+	assert.Equal(t, "use board Platform", h.At(0))
+
+	data, err := os.ReadFile(filepath.Join(dir, HistoryFileName))
+	require.NoError(t, err)
+	assert.Equal(t, "boards list\nuse board Platform\n", string(data))
+
+	h, err = LoadHistory()
+	require.NoError(t, err)
+	assert.Equal(t, "boards list", h.At(1))
+}
+
+func TestHistoryCap(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("FIZZ_CONFIG_DIR", dir)
+	path := filepath.Join(dir, HistoryFileName)
+
+	var old strings.Builder
+	for i := 1; i <= maxHistory+500; i++ {
+		fmt.Fprintf(&old, "cards get %d\n", i)
+	}
+	require.NoError(t, os.WriteFile(path, []byte(old.String()), 0o600))
+
+	h, err := LoadHistory()
+	require.NoError(t, err)
+	assert.Equal(t, maxHistory, h.Len())
+
+	// Saving keeps the file to the last maxHistory lines
+	h.Add("boards list")
+	h.Add("use board Platform")
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	require.Len(t, lines, maxHistory)
+	assert.Equal(t, "cards get 503", lines[0])
+	assert.Equal(t, []string{"boards list", "use board Platform"}, lines[maxHistory-2:])
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "no temporary files are left behind")
+}
@@ -0,0 +1,288 @@
+// Package templates stores card templates as YAML files, in the user's
+// config directory and in a project's .fizz/templates directory.
+package templates
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/visionik/fizz/internal/config"
+	"gopkg.in/yaml.v3"
+)
+
+// ProjectDirName is where a project keeps its templates, relative to the
+// directory holding it
+var ProjectDirName = filepath.Join(".fizz", "templates")
+
+// Sources of a template
+const (
+	SourceUser    = "user"
+	SourceProject = "project"
+)
+
+// ErrNotFound is returned for a template that does not exist
+var ErrNotFound = errors.New("template not found")
+
+// Template describes a card to create. Text fields may contain {{var}}
+// placeholders.
+type Template struct {
+	Name        string            `json:"name" yaml:"-"`
+	Description string            `json:"description,omitempty" yaml:"description,omitempty"`
+	Board       string            `json:"board,omitempty" yaml:"board,omitempty"`
+	Title       string            `json:"title" yaml:"title"`
+	Body        string            `json:"body,omitempty" yaml:"body,omitempty"`
+	Tags        []string          `json:"tags,omitempty" yaml:"tags,omitempty"`
+	Assignees   []string          `json:"assignees,omitempty" yaml:"assignees,omitempty"`
+	Column      string            `json:"column,omitempty" yaml:"column,omitempty"`
+	Steps       []string          `json:"steps,omitempty" yaml:"steps,omitempty"`
+	Vars        map[string]string `json:"vars,omitempty" yaml:"vars,omitempty"`
+	Source      string            `json:"source" yaml:"-"`
+	Path        string            `json:"path" yaml:"-"`
+}
+
+var validName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]*$`)
+
+// ValidateName checks that name can be used as a template file name
+func ValidateName(name string) error {
+	if !validName.MatchString(name) {
+		return fmt.Errorf("invalid template name %q: use letters, digits, '.', '_' and '-'", name)
+	}
+	return nil
+}
+
+// UserDir returns the directory holding the user's templates
+func UserDir() (string, error) {
+	dir, err := config.Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "templates"), nil
+}
+
+// ProjectDir returns the nearest .fizz/templates directory, or "" if there
+// is none
+func ProjectDir() string {
+	return config.FindUp(ProjectDirName)
+}
+
+// dirs returns the template directories by source, project first so its
+// templates win
+func dirs() ([][2]string, error) {
+	var out [][2]string
+	if dir := ProjectDir(); dir != "" {
+		out = append(out, [2]string{SourceProject, dir})
+	}
+	dir, err := UserDir()
+	if err != nil {
+		return nil, err
+	}
+	return append(out, [2]string{SourceUser, dir}), nil
+}
+
+// List returns all templates sorted by name. A project template hides a user
+// template with the same name.
+func List() ([]Template, error) {
+	sources, err := dirs()
+	if err != nil {
+		return nil, err
+	}
+
+	seen := map[string]bool{}
+	var list []Template
+	for _, src := range sources {
+		entries, err := os.ReadDir(src[1])
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read templates: %w", err)
+		}
+		for _, entry := range entries {
+			name, ok := strings.CutSuffix(entry.Name(), ".yaml")
+			if !ok || entry.IsDir() || seen[name] {
+				continue
+			}
+			t, err := read(filepath.Join(src[1], entry.Name()), name, src[0])
+			if err != nil {
+				return nil, err
+			}
+			seen[name] = true
+			list = append(list, *t)
+		}
+	}
+
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list, nil
+}
+
+// Load returns the named template, preferring the project's
+func Load(name string) (*Template, error) {
+	if err := ValidateName(name); err != nil {
+		return nil, err
+	}
+	sources, err := dirs()
+	if err != nil {
+		return nil, err
+	}
+	for _, src := range sources {
+		t, err := read(filepath.Join(src[1], name+".yaml"), name, src[0])
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		return t, err
+	}
+	return nil, fmt.Errorf("%w: %s", ErrNotFound, name)
+}
+
+func read(path, name, source string) (*Template, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	t := &Template{}
+	if err := yaml.Unmarshal(data, t); err != nil {
+		return nil, fmt.Errorf("failed to parse template %s: %w", path, err)
+	}
+	t.Name, t.Source, t.Path = name, source, path
+	return t, nil
+}
+
+// Save writes t to dir, refusing to replace an existing template unless
+// overwrite is set
+func Save(t *Template, dir string, overwrite bool) error {
+	if err := ValidateName(t.Name); err != nil {
+		return err
+	}
+	if strings.TrimSpace(t.Title) == "" {
+		return errors.New("template title is required")
+	}
+
+	path := filepath.Join(dir, t.Name+".yaml")
+	if _, err := os.Stat(path); err == nil && !overwrite {
+		return fmt.Errorf("template %s already exists at %s; use --force to replace it", t.Name, path)
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(t); err != nil {
+		return fmt.Errorf("failed to encode template: %w", err)
+	}
+	enc.Close()
+	data := buf.Bytes()
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create templates directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write template: %w", err)
+	}
+	t.Path = path
+	return nil
+}
+
+// Delete removes the named template file, preferring the project's
+func Delete(name string) (*Template, error) {
+	t, err := Load(name)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Remove(t.Path); err != nil {
+		return nil, fmt.Errorf("failed to delete template: %w", err)
+	}
+	return t, nil
+}
+
+var placeholder = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_-]*)\s*\}\}`)
+
+// Render returns a copy of t with placeholders replaced. Values come from
+// vars, then the template's own defaults, then the built-in date
+// (YYYY-MM-DD). Unknown placeholders are an error.
+func (t Template) Render(vars map[string]string) (*Template, error) {
+	values := map[string]string{"date": time.Now().Format("2006-01-02")}
+	for k, v := range t.Vars {
+		values[k] = v
+	}
+	for k, v := range vars {
+		values[k] = v
+	}
+
+	missing := map[string]bool{}
+	sub := func(s string) string {
+		return placeholder.ReplaceAllStringFunc(s, func(m string) string {
+			name := placeholder.FindStringSubmatch(m)[1]
+			v, ok := values[name]
+			if !ok {
+				missing[name] = true
+			}
+			return v
+		})
+	}
+	subAll := func(list []string) []string {
+		out := make([]string, 0, len(list))
+		for _, s := range list {
+			if s = strings.TrimSpace(sub(s)); s != "" {
+				out = append(out, s)
+			}
+		}
+		return out
+	}
+
+	out := t
+	out.Title = strings.TrimSpace(sub(t.Title))
+	out.Body = strings.TrimSpace(sub(t.Body))
+	out.Board = sub(t.Board)
+	out.Column = sub(t.Column)
+	out.Tags = subAll(t.Tags)
+	out.Assignees = subAll(t.Assignees)
+	out.Steps = subAll(t.Steps)
+
+	if len(missing) > 0 {
+		names := make([]string, 0, len(missing))
+		for name := range missing {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("template %s needs --var for: %s", t.Name, strings.Join(names, ", "))
+	}
+	return &out, nil
+}
+
+// Variables returns the placeholder names used by t, sorted
+func (t Template) Variables() []string {
+	seen := map[string]bool{}
+	texts := append([]string{t.Title, t.Body, t.Board, t.Column}, t.Tags...)
+	texts = append(texts, t.Assignees...)
+	texts = append(texts, t.Steps...)
+	for _, text := range texts {
+		for _, m := range placeholder.FindAllStringSubmatch(text, -1) {
+			seen[m[1]] = true
+		}
+	}
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ParseVars parses key=value pairs from --var flags
+func ParseVars(pairs []string) (map[string]string, error) {
+	vars := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		key, value, ok := strings.Cut(pair, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid --var %q: use key=value", pair)
+		}
+		vars[key] = value
+	}
+	return vars, nil
+}
@@ -0,0 +1,96 @@
+package templates
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("FIZZ_CONFIG_DIR", dir)
+	t.Chdir(t.TempDir())
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "templates"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "templates", "release.yaml"), []byte(`description: Ship a version
+board: b1
+title: Release {{version}}
+body: |
+  Cut {{ version }} on {{date}}.
+tags: [release]
+assignees: [me]
+column: Doing
+steps:
+  - Tag {{version}}
+  - Publish notes
+vars:
+  version: "1.0"
+`), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "templates", "broken.yaml"), []byte("title: [unclosed\n"), 0o644))
+
+	tmpl, err := Load("release")
+	require.NoError(t, err)
+	assert.Equal(t, &Template{
+		Name:        "release",
+		Description: "Ship a version",
+		Board:       "b1",
+		Title:       "Release {{version}}",
+		Body:        "Cut {{ version }} on {{date}}.\n",
+		Tags:        []string{"release"},
+		Assignees:   []string{"me"},
+		Column:      "Doing",
+		Steps:       []string{"Tag {{version}}", "Publish notes"},
+		Vars:        map[string]string{"version": "1.0"},
+		Source:      SourceUser,
+		Path:        filepath.Join(dir, "templates", "release.yaml"),
+	}, tmpl)
+	assert.Equal(t, []string{"date", "version"}, tmpl.Variables())
+
+	_, err = Load("broken")
+	assert.ErrorContains(t, err, "failed to parse template")
+	_, err = Load("missing")
+	assert.ErrorIs(t, err, ErrNotFound)
+	_, err = Load("../escape")
+	assert.EqualError(t, err, `invalid template name "../escape": use letters, digits, '.', '_' and '-'`)
+}
+
+func TestRender(t *testing.T) {
+	tmpl := Template{
+		Name:      "release",
+		Title:     " Release {{version}} ",
+		Body:      "Cut {{ version }} for {{team}} on {{date}}.",
+		Board:     "{{board}}",
+		Tags:      []string{"release", "{{extra}}"},
+		Assignees: []string{"{{owner}}"},
+		Steps:     []string{"Tag {{version}}", "  "},
+		Vars:      map[string]string{"version": "1.0", "team": "core", "board": "b1", "owner": "me"},
+	}
+
+	// Flags win over the template's defaults; empty list items are dropped
+	out, err := tmpl.Render(map[string]string{"version": "1.4", "extra": ""})
+	require.NoError(t, err)
+	assert.Equal(t, "Release 1.4", out.Title)
+	assert.Equal(t, "Cut 1.4 for core on "+time.Now().Format("2006-01-02")+".", out.Body)
+	assert.Equal(t, "b1", out.Board)
+	assert.Equal(t, []string{"release"}, out.Tags)
+	assert.Equal(t, []string{"me"}, out.Assignees)
+	assert.Equal(t, []string{"Tag 1.4"}, out.Steps)
+	assert.Equal(t, " Release {{version}} ", tmpl.Title, "the template itself is unchanged")
+
+	_, err = tmpl.Render(nil)
+	assert.EqualError(t, err, "template release needs --var for: extra")
+}
+
+func TestParseVars(t *testing.T) {
+	vars, err := ParseVars([]string{"version=1.4", " team =core", "note=a=b", "empty="})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"version": "1.4", "team": "core", "note": "a=b", "empty": ""}, vars)
+
+	_, err = ParseVars([]string{"version"})
+	assert.EqualError(t, err, `invalid --var "version": use key=value`)
+	_, err = ParseVars([]string{"=1.4"})
+	assert.EqualError(t, err, `invalid --var "=1.4": use key=value`)
+}
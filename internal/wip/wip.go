@@ -0,0 +1,107 @@
+// Package wip checks changes to a board against the work in progress limits
+// declared in the config file.
+package wip
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/visionik/fizz/internal/config"
+	"github.com/visionik/libfizz-go/fizzy"
+)
+
+// Limit returns the limit for a column on a board, or nil if it has none.
+// A limit naming the board wins over one for every board.
+func Limit(settings config.WIPSettings, boardID string, col fizzy.Column) *config.WIPLimit {
+	var found *config.WIPLimit
+	for i, l := range settings.Limits {
+		if l.Board != "" && l.Board != boardID {
+			continue
+		}
+		if l.Column != col.ID && !strings.EqualFold(l.Column, col.Name) {
+			continue
+		}
+		if found == nil || (found.Board == "" && l.Board != "") {
+			found = &settings.Limits[i]
+		}
+	}
+	return found
+}
+
+// Count is the number of open cards in a column, in total and by assignee
+type Count struct {
+	Total      int
+	ByAssignee map[string]int
+	// Names maps the assignees' IDs to their names
+	Names map[string]string
+}
+
+// CountColumn counts the open cards in a column, leaving out the card with
+// ID skip, which is the one being changed
+func CountColumn(cards []fizzy.Card, columnID, skip string) Count {
+	count := Count{ByAssignee: map[string]int{}, Names: map[string]string{}}
+	for _, card := range cards {
+		if card.ID == skip || card.Closed || card.ClosedAt != nil || card.Status == "not_now" {
+			continue
+		}
+		if card.ColumnID == nil || *card.ColumnID != columnID {
+			continue
+		}
+		count.Total++
+		for _, user := range card.Assignees {
+			count.ByAssignee[user.ID]++
+			count.Names[user.ID] = user.Name
+		}
+	}
+	return count
+}
+
+// Violation is a limit a change would exceed
+type Violation struct {
+	Column string
+	// Assignee is set for a per-person limit
+	Assignee string
+	Count    int
+	Limit    int
+}
+
+func (v Violation) String() string {
+	if v.Assignee != "" {
+		return fmt.Sprintf("%s would have %d cards in %s, over the per-person WIP limit of %d", v.Assignee, v.Count, v.Column, v.Limit)
+	}
+	return fmt.Sprintf("%s would have %d cards, over its WIP limit of %d", v.Column, v.Count, v.Limit)
+}
+
+// CheckMove returns the limits moving card into a column would exceed,
+// given the column's count without it
+func CheckMove(limit config.WIPLimit, column string, count Count, card fizzy.Card) []Violation {
+	var violations []Violation
+	if limit.Limit > 0 && count.Total+1 > limit.Limit {
+		violations = append(violations, Violation{Column: column, Count: count.Total + 1, Limit: limit.Limit})
+	}
+	if limit.PerPerson > 0 {
+		for _, user := range card.Assignees {
+			if n := count.ByAssignee[user.ID] + 1; n > limit.PerPerson {
+				violations = append(violations, Violation{Column: column, Assignee: user.Name, Count: n, Limit: limit.PerPerson})
+			}
+		}
+	}
+	return violations
+}
+
+// CheckAssign returns the per-person limit assigning a card in a column to
+// a user would exceed, given the column's count without the card
+func CheckAssign(limit config.WIPLimit, column string, count Count, userID string) []Violation {
+	if limit.PerPerson <= 0 {
+		return nil
+	}
+	n := count.ByAssignee[userID] + 1
+	if n <= limit.PerPerson {
+		return nil
+	}
+	who := count.Names[userID]
+	if who == "" {
+		who = userID
+	}
+	return []Violation{{Column: column, Assignee: who, Count: n, Limit: limit.PerPerson}}
+}
@@ -0,0 +1,47 @@
+package wip
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/visionik/fizz/internal/config"
+	"github.com/visionik/libfizz-go/fizzy"
+)
+
+func TestLimit(t *testing.T) {
+	settings := config.WIPSettings{Limits: []config.WIPLimit{
+		{Column: "doing", Limit: 3},
+		{Board: "b1", Column: "c2", Limit: 2},
+		{Board: "b2", Column: "Doing", Limit: 1},
+	}}
+	doing := fizzy.Column{ID: "c2", Name: "Doing"}
+
+	assert.Equal(t, 2, Limit(settings, "b1", doing).Limit)
+	assert.Equal(t, 1, Limit(settings, "b2", doing).Limit)
+	assert.Equal(t, 3, Limit(settings, "b3", doing).Limit)
+	assert.Nil(t, Limit(settings, "b1", fizzy.Column{ID: "c3", Name: "Done"}))
+}
+
+func TestCheck(t *testing.T) {
+	col := "c1"
+	ada := fizzy.User{ID: "u1", Name: "Ada"}
+	cards := []fizzy.Card{
+		{ID: "1", ColumnID: &col, Assignees: []fizzy.User{ada}},
+		{ID: "2", ColumnID: &col},
+		{ID: "3", ColumnID: &col, Closed: true, Assignees: []fizzy.User{ada}},
+		{ID: "4", Assignees: []fizzy.User{ada}},
+	}
+	count := CountColumn(cards, col, "2")
+	assert.Equal(t, 1, count.Total)
+	assert.Equal(t, 1, count.ByAssignee["u1"])
+
+	limit := config.WIPLimit{Limit: 2, PerPerson: 1}
+	assert.Empty(t, CheckMove(limit, "Doing", count, fizzy.Card{ID: "5"}))
+	assert.Equal(t, []Violation{{Column: "Doing", Assignee: "Ada", Count: 2, Limit: 1}},
+		CheckMove(limit, "Doing", count, fizzy.Card{ID: "5", Assignees: []fizzy.User{ada}}))
+
+	count = CountColumn(cards, col, "")
+	assert.Equal(t, "Doing would have 3 cards, over its WIP limit of 2", CheckMove(limit, "Doing", count, fizzy.Card{})[0].String())
+	assert.Equal(t, "Ada would have 2 cards in Doing, over the per-person WIP limit of 1", CheckAssign(limit, "Doing", count, "u1")[0].String())
+	assert.Empty(t, CheckAssign(config.WIPLimit{Limit: 1}, "Doing", count, "u1"))
+}
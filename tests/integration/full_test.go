@@ -1,44 +1,38 @@
-//go:build integration
-// +build integration
-
 package integration
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"testing"
-	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
-	"github.com/visionik/fizz/internal/client"
-	"github.com/visionik/fizz/internal/config"
+	"github.com/visionik/fizz/cmd"
 	"github.com/visionik/libfizz-go/fizzy"
 )
 
-// TestFullIntegration runs a complete integration test of all fizz features
-// It creates a dedicated test board and cleans it up afterward
-func TestFullIntegration(t *testing.T) {
-	// Setup: Create client
-	cfg, err := config.LoadFromEnv()
-	require.NoError(t, err, "FIZZY_TOKEN and FIZZY_ACCOUNT must be set")
-
-	c, err := client.New(cfg, true) // debug mode
-	require.NoError(t, err, "Failed to create client")
+// cassettePath is the recording replayed when no live run is requested
+const cassettePath = "testdata/full.yaml"
 
-	ctx := context.Background()
+// testBoardName is fixed so that replayed runs match the recording
+const testBoardName = "FIZZ_INTEGRATION_TEST"
 
-	// Create a unique test board name with timestamp
-	testBoardName := fmt.Sprintf("FIZZ_INTEGRATION_TEST_%d", time.Now().Unix())
-	
+// TestFullIntegration runs a complete integration test of all fizz commands
+// It creates a dedicated test board and cleans it up afterward
+func TestFullIntegration(t *testing.T) {
 	t.Run("Identity", func(t *testing.T) {
-		testIdentity(t, ctx, c)
+		testIdentity(t)
 	})
 
 	var testBoardID string
 	t.Run("Boards", func(t *testing.T) {
-		testBoardID = testBoards(t, ctx, c, testBoardName)
+		testBoardID = testBoards(t, testBoardName)
 	})
 
 	// Skip subsequent tests if board creation failed
@@ -49,73 +43,103 @@ func TestFullIntegration(t *testing.T) {
 	// Ensure cleanup happens even if tests fail
 	defer func() {
 		t.Logf("Cleaning up test board: %s", testBoardID)
-		err := c.Boards.Delete(ctx, testBoardID)
-		if err != nil {
+		if _, err := fizz("boards", "delete", testBoardID); err != nil {
 			t.Logf("Warning: Failed to delete test board %s: %v", testBoardID, err)
 		}
 	}()
 
-	var testCardID string
+	var testColumnID string
+	t.Run("Columns", func(t *testing.T) {
+		testColumnID = testColumns(t, testBoardID)
+	})
+
+	var testCard string
 	t.Run("Cards", func(t *testing.T) {
-		testCardID = testCards(t, ctx, c, testBoardID)
+		testCard = testCards(t, testBoardID, testColumnID)
 	})
 
-	if testCardID != "" {
+	if testCard != "" {
 		t.Run("Comments", func(t *testing.T) {
-			testComments(t, ctx, c, testCardID)
+			testComments(t, testCard)
 		})
 
 		t.Run("Steps", func(t *testing.T) {
-			testSteps(t, ctx, c, testCardID)
+			testSteps(t, testCard)
 		})
-	}
 
-	t.Run("Columns", func(t *testing.T) {
-		testColumns(t, ctx, c, testBoardID)
-	})
+		t.Run("DeleteCard", func(t *testing.T) {
+			out := mustFizz(t, "cards", "delete", testCard)
+			assert.Contains(t, out, "deleted successfully")
+		})
+	}
 
 	t.Run("Tags", func(t *testing.T) {
-		testTags(t, ctx, c)
+		testTags(t)
 	})
 
 	t.Run("Users", func(t *testing.T) {
-		testUsers(t, ctx, c)
+		testUsers(t)
 	})
 
 	t.Run("Notifications", func(t *testing.T) {
-		testNotifications(t, ctx, c)
+		testNotifications(t)
+	})
+
+	t.Run("Uploads", func(t *testing.T) {
+		testUploads(t)
 	})
 }
 
-func testIdentity(t *testing.T, ctx context.Context, c *client.Client) {
+// fizz runs a fizz command and returns its standard output
+func fizz(args ...string) (string, error) {
+	var out, errOut bytes.Buffer
+	err := cmd.Run(context.Background(), args, &out, &errOut)
+	if err != nil {
+		return out.String(), fmt.Errorf("fizz %s: %w\n%s", strings.Join(args, " "), err, errOut.String())
+	}
+	return out.String(), nil
+}
+
+// mustFizz runs a fizz command and fails the test if it errors
+func mustFizz(t *testing.T, args ...string) string {
+	t.Helper()
+	out, err := fizz(args...)
+	require.NoError(t, err)
+	return out
+}
+
+// fizzJSON runs a fizz command with JSON output and decodes it into v
+func fizzJSON(t *testing.T, v interface{}, args ...string) {
+	t.Helper()
+	out := mustFizz(t, append(args, "--format=json")...)
+	require.NoError(t, json.Unmarshal([]byte(out), v), "invalid JSON output: %s", out)
+}
+
+func testIdentity(t *testing.T) {
 	t.Log("Testing identity get...")
-	
-	identity, err := c.Identity.Get(ctx)
-	require.NoError(t, err, "Failed to get identity")
-	assert.NotNil(t, identity, "Identity should not be nil")
+
+	var identity fizzy.Identity
+	fizzJSON(t, &identity, "identity", "get")
 	assert.NotEmpty(t, identity.Accounts, "Should have at least one account")
-	
+
 	t.Logf("✓ Identity verified: %d account(s)", len(identity.Accounts))
 }
 
-func testBoards(t *testing.T, ctx context.Context, c *client.Client, boardName string) string {
+func testBoards(t *testing.T, boardName string) string {
 	t.Log("Testing boards...")
-	
+
 	// List boards
 	t.Log("  - Listing boards")
-	boards, err := c.Boards.List(ctx)
-	require.NoError(t, err, "Failed to list boards")
+	var boards []fizzy.Board
+	fizzJSON(t, &boards, "boards", "list")
 	initialCount := len(boards)
 	t.Logf("  ✓ Found %d existing boards", initialCount)
 
 	// Create test board
 	t.Logf("  - Creating test board: %s", boardName)
-	description := "Automated integration test board - safe to delete"
-	board, err := c.Boards.Create(ctx, &fizzy.BoardCreateOptions{
-		Name:        boardName,
-		Description: &description,
-	})
-	require.NoError(t, err, "Failed to create board")
+	var board fizzy.Board
+	fizzJSON(t, &board, "boards", "create", "--name="+boardName,
+		"--description=Automated integration test board - safe to delete")
 	require.NotEmpty(t, board.ID, "Board ID should not be empty")
 	assert.Equal(t, boardName, board.Name, "Board name mismatch")
 	testBoardID := board.ID
@@ -123,21 +147,20 @@ func testBoards(t *testing.T, ctx context.Context, c *client.Client, boardName s
 
 	// Get board
 	t.Log("  - Getting board by ID")
-	fetchedBoard, err := c.Boards.Get(ctx, testBoardID)
-	require.NoError(t, err, "Failed to get board")
+	var fetchedBoard fizzy.Board
+	fizzJSON(t, &fetchedBoard, "boards", "get", testBoardID)
 	assert.Equal(t, testBoardID, fetchedBoard.ID, "Board ID mismatch")
 	assert.Equal(t, boardName, fetchedBoard.Name, "Board name mismatch")
+	out := mustFizz(t, "boards", "get", testBoardID)
+	assert.Contains(t, out, boardName, "Table output should include board name")
 	t.Log("  ✓ Board retrieved successfully")
 
 	// Update board
 	t.Log("  - Updating board")
 	newName := boardName + "_UPDATED"
 	newDesc := "Updated description"
-	updatedBoard, err := c.Boards.Update(ctx, testBoardID, &fizzy.BoardUpdateOptions{
-		Name:        &newName,
-		Description: &newDesc,
-	})
-	require.NoError(t, err, "Failed to update board")
+	var updatedBoard fizzy.Board
+	fizzJSON(t, &updatedBoard, "boards", "update", testBoardID, "--name="+newName, "--description="+newDesc)
 	assert.Equal(t, newName, updatedBoard.Name, "Board name not updated")
 	if updatedBoard.Description != nil {
 		assert.Equal(t, newDesc, *updatedBoard.Description, "Board description not updated")
@@ -148,8 +171,7 @@ func testBoards(t *testing.T, ctx context.Context, c *client.Client, boardName s
 
 	// Verify board in list
 	t.Log("  - Verifying board appears in list")
-	boards, err = c.Boards.List(ctx)
-	require.NoError(t, err, "Failed to list boards")
+	fizzJSON(t, &boards, "boards", "list")
 	assert.Equal(t, initialCount+1, len(boards), "Board count should increase by 1")
 	found := false
 	for _, b := range boards {
@@ -165,130 +187,182 @@ func testBoards(t *testing.T, ctx context.Context, c *client.Client, boardName s
 	return testBoardID
 }
 
-func testCards(t *testing.T, ctx context.Context, c *client.Client, boardID string) string {
+func testColumns(t *testing.T, boardID string) string {
+	t.Log("Testing columns...")
+
+	// List columns
+	t.Log("  - Listing columns")
+	var initialColumns []fizzy.Column
+	fizzJSON(t, &initialColumns, "columns", "list", boardID)
+	initialCount := len(initialColumns)
+	t.Logf("  ✓ Found %d existing column(s)", initialCount)
+
+	// Create a column that is deleted again
+	t.Log("  - Creating column")
+	var column fizzy.Column
+	fizzJSON(t, &column, "columns", "create", boardID, "--name=Test Column")
+	require.NotEmpty(t, column.ID, "Column ID should not be empty")
+	columnID := column.ID
+	t.Logf("  ✓ Created column: %s", columnID)
+
+	// Get column
+	t.Log("  - Getting column")
+	var fetchedColumn fizzy.Column
+	fizzJSON(t, &fetchedColumn, "columns", "get", boardID, columnID)
+	assert.Equal(t, columnID, fetchedColumn.ID, "Column ID mismatch")
+	t.Log("  ✓ Column retrieved")
+
+	// Update column
+	t.Log("  - Updating column")
+	newName := "Updated Column Name"
+	var updatedColumn fizzy.Column
+	fizzJSON(t, &updatedColumn, "columns", "update", boardID, columnID, "--name="+newName)
+	assert.Equal(t, newName, updatedColumn.Name, "Column name not updated")
+	t.Log("  ✓ Column updated")
+
+	// Delete column
+	t.Log("  - Deleting column")
+	out := mustFizz(t, "columns", "delete", boardID, columnID)
+	assert.Contains(t, out, "deleted successfully")
+	t.Log("  ✓ Column deleted")
+
+	// Verify column deleted
+	var finalColumns []fizzy.Column
+	fizzJSON(t, &finalColumns, "columns", "list", boardID)
+	assert.Equal(t, initialCount, len(finalColumns), "Column count should be back to initial")
+	t.Log("  ✓ Column deletion verified")
+
+	// Create the column cards are moved into
+	t.Log("  - Creating target column for cards")
+	var doing fizzy.Column
+	fizzJSON(t, &doing, "columns", "create", boardID, "--name=Doing")
+	require.NotEmpty(t, doing.ID, "Column ID should not be empty")
+
+	t.Log("✓ Columns tests passed")
+	return doing.ID
+}
+
+func testCards(t *testing.T, boardID, columnID string) string {
 	t.Log("Testing cards...")
 
 	// Create card
 	t.Log("  - Creating card")
-	body := "This is a test card created by integration tests"
-	card, err := c.Cards.Create(ctx, &fizzy.CardCreateOptions{
-		BoardID: boardID,
-		Title:   "Integration Test Card",
-		Body:    &body,
-	})
-	require.NoError(t, err, "Failed to create card")
+	var card fizzy.Card
+	fizzJSON(t, &card, "cards", "create", "--board="+boardID, "--title=Integration Test Card",
+		"--body=This is a test card created by integration tests")
 	require.NotEmpty(t, card.ID, "Card ID should not be empty")
 	assert.Equal(t, "Integration Test Card", card.Title, "Card title mismatch")
-	testCardID := card.ID
-	t.Logf("  ✓ Created card: %s (number: %d)", testCardID, card.Number)
+	testCard := strconv.Itoa(card.Number)
+	t.Logf("  ✓ Created card: %s (number: %d)", card.ID, card.Number)
 
 	// List cards (all)
 	t.Log("  - Listing all cards")
-	allCards, err := c.Cards.ListAll(ctx, nil)
-	require.NoError(t, err, "Failed to list all cards")
+	var allCards []fizzy.Card
+	fizzJSON(t, &allCards, "cards", "list")
 	t.Logf("  ✓ Listed %d total cards", len(allCards))
 
 	// List cards by board
 	t.Log("  - Listing cards by board")
-	boardCards, err := c.Cards.ListAll(ctx, &fizzy.CardListOptions{
-		BoardID: boardID,
-	})
-	require.NoError(t, err, "Failed to list board cards")
+	var boardCards []fizzy.Card
+	fizzJSON(t, &boardCards, "cards", "list", "--board="+boardID, "--limit=10")
 	assert.GreaterOrEqual(t, len(boardCards), 1, "Should have at least our test card")
 	t.Logf("  ✓ Found %d cards on test board", len(boardCards))
 
 	// Get card
-	t.Log("  - Getting card by ID")
-	fetchedCard, err := c.Cards.Get(ctx, testCardID)
-	require.NoError(t, err, "Failed to get card")
-	assert.Equal(t, testCardID, fetchedCard.ID, "Card ID mismatch")
+	t.Log("  - Getting card by number")
+	var fetchedCard fizzy.Card
+	fizzJSON(t, &fetchedCard, "cards", "get", testCard)
+	assert.Equal(t, card.ID, fetchedCard.ID, "Card ID mismatch")
+	out := mustFizz(t, "cards", "get", testCard)
+	assert.Contains(t, out, "Integration Test Card", "Table output should include card title")
 	t.Log("  ✓ Card retrieved successfully")
 
 	// Update card
 	t.Log("  - Updating card")
-	newTitle := "Updated Test Card"
-	newBody := "Updated body content"
-	updatedCard, err := c.Cards.Update(ctx, testCardID, &fizzy.CardUpdateOptions{
-		Title: &newTitle,
-		Body:  &newBody,
-	})
-	require.NoError(t, err, "Failed to update card")
-	assert.Equal(t, newTitle, updatedCard.Title, "Card title not updated")
-	assert.Equal(t, newBody, *updatedCard.Body, "Card body not updated")
+	var updatedCard fizzy.Card
+	fizzJSON(t, &updatedCard, "cards", "update", testCard, "--title=Updated Test Card", "--body=Updated body content")
+	assert.Equal(t, "Updated Test Card", updatedCard.Title, "Card title not updated")
 	t.Log("  ✓ Card updated successfully")
 
 	// Test card actions
 	t.Log("  - Testing card actions")
 
-	// Close card
 	t.Log("    - Closing card")
-	err = c.Cards.Close(ctx, testCardID)
-	require.NoError(t, err, "Failed to close card")
-	closedCard, _ := c.Cards.Get(ctx, testCardID)
+	assert.Contains(t, mustFizz(t, "cards", "close", testCard), "closed successfully")
+	var closedCard fizzy.Card
+	fizzJSON(t, &closedCard, "cards", "get", testCard)
 	assert.NotNil(t, closedCard.ClosedAt, "Card should have closed_at timestamp")
 	t.Log("    ✓ Card closed")
 
-	// Reopen card
 	t.Log("    - Reopening card")
-	err = c.Cards.Reopen(ctx, testCardID)
-	require.NoError(t, err, "Failed to reopen card")
-	reopenedCard, _ := c.Cards.Get(ctx, testCardID)
+	assert.Contains(t, mustFizz(t, "cards", "reopen", testCard), "reopened successfully")
+	var reopenedCard fizzy.Card
+	fizzJSON(t, &reopenedCard, "cards", "get", testCard)
 	assert.Nil(t, reopenedCard.ClosedAt, "Card should not have closed_at after reopen")
 	t.Log("    ✓ Card reopened")
 
-	// Watch card
+	t.Log("    - Postponing and triaging card")
+	assert.Contains(t, mustFizz(t, "cards", "postpone", testCard), "postponed successfully")
+	assert.Contains(t, mustFizz(t, "cards", "triage", testCard), "triaged successfully")
+	t.Log("    ✓ Card postponed and triaged")
+
+	t.Log("    - Moving card")
+	assert.Contains(t, mustFizz(t, "cards", "move", testCard, "--column="+columnID), "moved to column")
+	t.Log("    ✓ Card moved")
+
+	t.Log("    - Tagging card")
+	assert.Contains(t, mustFizz(t, "cards", "tag", testCard, "integration"), "tagged with")
+	t.Log("    ✓ Card tagged")
+
+	t.Log("    - Assigning card")
+	var users []fizzy.User
+	fizzJSON(t, &users, "users", "list")
+	require.NotEmpty(t, users, "Should have at least one user")
+	assert.Contains(t, mustFizz(t, "cards", "assign", testCard, users[0].ID), "assigned to")
+	t.Log("    ✓ Card assigned")
+
 	t.Log("    - Watching card")
-	err = c.Cards.Watch(ctx, testCardID)
-	require.NoError(t, err, "Failed to watch card")
+	assert.Contains(t, mustFizz(t, "cards", "watch", testCard), "Now watching")
 	t.Log("    ✓ Card watched")
 
-	// Unwatch card
 	t.Log("    - Unwatching card")
-	err = c.Cards.Unwatch(ctx, testCardID)
-	require.NoError(t, err, "Failed to unwatch card")
+	assert.Contains(t, mustFizz(t, "cards", "unwatch", testCard), "Stopped watching")
 	t.Log("    ✓ Card unwatched")
 
-	// Mark golden
 	t.Log("    - Marking card as golden")
-	err = c.Cards.MarkGolden(ctx, testCardID)
-	require.NoError(t, err, "Failed to mark card as golden")
-	goldenCard, _ := c.Cards.Get(ctx, testCardID)
+	assert.Contains(t, mustFizz(t, "cards", "golden", testCard), "marked as golden")
+	var goldenCard fizzy.Card
+	fizzJSON(t, &goldenCard, "cards", "get", testCard)
 	assert.True(t, goldenCard.Golden, "Card should be golden")
 	t.Log("    ✓ Card marked golden")
 
-	// Unmark golden
 	t.Log("    - Unmarking golden")
-	err = c.Cards.UnmarkGolden(ctx, testCardID)
-	require.NoError(t, err, "Failed to unmark golden")
-	normalCard, _ := c.Cards.Get(ctx, testCardID)
+	assert.Contains(t, mustFizz(t, "cards", "ungolden", testCard), "Removed golden status")
+	var normalCard fizzy.Card
+	fizzJSON(t, &normalCard, "cards", "get", testCard)
 	assert.False(t, normalCard.Golden, "Card should not be golden")
 	t.Log("    ✓ Card unmarked golden")
 
 	t.Log("✓ Cards tests passed")
-	return testCardID
+	return testCard
 }
 
-func testComments(t *testing.T, ctx context.Context, c *client.Client, cardID string) {
+func testComments(t *testing.T, card string) {
 	t.Log("Testing comments...")
 
 	// Create comment
 	t.Log("  - Creating comment")
-	comment, err := c.Comments.Create(ctx, cardID, &fizzy.CommentCreateOptions{
-		Body: "This is a test comment",
-	})
-	require.NoError(t, err, "Failed to create comment")
+	var comment fizzy.Comment
+	fizzJSON(t, &comment, "comments", "create", card, "--body=This is a test comment")
 	require.NotEmpty(t, comment.ID, "Comment ID should not be empty")
 	commentID := comment.ID
 	t.Logf("  ✓ Created comment: %s", commentID)
 
 	// List comments
 	t.Log("  - Listing comments")
-	comments, err := c.Comments.List(ctx, cardID)
-	require.NoError(t, err, "Failed to list comments")
+	var comments []fizzy.Comment
+	fizzJSON(t, &comments, "comments", "list", card)
 	assert.GreaterOrEqual(t, len(comments), 1, "Should have at least one comment")
-	t.Logf("  ✓ Listed %d comment(s)", len(comments))
-
-	// Verify our comment is in the list
 	found := false
 	for _, cmt := range comments {
 		if cmt.ID == commentID {
@@ -301,191 +375,115 @@ func testComments(t *testing.T, ctx context.Context, c *client.Client, cardID st
 
 	// Update comment
 	t.Log("  - Updating comment")
-	updatedComment, err := c.Comments.Update(ctx, cardID, commentID, &fizzy.CommentUpdateOptions{
-		Body: "Updated comment text",
-	})
-	require.NoError(t, err, "Failed to update comment")
+	var updatedComment fizzy.Comment
+	fizzJSON(t, &updatedComment, "comments", "update", card, commentID, "--body=Updated comment text")
 	assert.Contains(t, updatedComment.Body, "Updated", "Comment not updated")
 	t.Log("  ✓ Comment updated")
 
 	// Test reactions on comment
 	t.Log("  - Testing reactions")
-	t.Log("    - Creating reaction")
-	reaction, err := c.Reactions.Create(ctx, cardID, commentID, &fizzy.ReactionCreateOptions{
-		Content: "👍",
-	})
-	require.NoError(t, err, "Failed to create reaction")
-	reactionID := reaction.ID
-	t.Logf("    ✓ Created reaction: %s", reactionID)
+	var reaction fizzy.Reaction
+	fizzJSON(t, &reaction, "reactions", "create", card, commentID, "--emoji=👍")
+	require.NotEmpty(t, reaction.ID, "Reaction ID should not be empty")
+	t.Logf("    ✓ Created reaction: %s", reaction.ID)
 
-	t.Log("    - Listing reactions")
-	reactions, err := c.Reactions.List(ctx, cardID, commentID)
-	require.NoError(t, err, "Failed to list reactions")
+	var reactions []fizzy.Reaction
+	fizzJSON(t, &reactions, "reactions", "list", card, commentID)
 	assert.GreaterOrEqual(t, len(reactions), 1, "Should have at least one reaction")
 	t.Log("    ✓ Reactions listed")
 
-	t.Log("    - Deleting reaction")
-	err = c.Reactions.Delete(ctx, cardID, commentID, reactionID)
-	require.NoError(t, err, "Failed to delete reaction")
+	assert.Contains(t, mustFizz(t, "reactions", "delete", card, commentID, reaction.ID), "deleted successfully")
 	t.Log("    ✓ Reaction deleted")
 
 	// Delete comment
 	t.Log("  - Deleting comment")
-	err = c.Comments.Delete(ctx, cardID, commentID)
-	require.NoError(t, err, "Failed to delete comment")
+	assert.Contains(t, mustFizz(t, "comments", "delete", card, commentID), "deleted successfully")
 	t.Log("  ✓ Comment deleted")
 
 	t.Log("✓ Comments tests passed")
 }
 
-func testSteps(t *testing.T, ctx context.Context, c *client.Client, cardID string) {
+func testSteps(t *testing.T, card string) {
 	t.Log("Testing steps...")
 
 	// Create step
 	t.Log("  - Creating step")
-	completed := false
-	step, err := c.Steps.Create(ctx, cardID, &fizzy.StepCreateOptions{
-		Content:   "Test checklist item",
-		Completed: &completed,
-	})
-	require.NoError(t, err, "Failed to create step")
+	var step fizzy.Step
+	fizzJSON(t, &step, "steps", "create", card, "--content=Test checklist item")
 	require.NotEmpty(t, step.ID, "Step ID should not be empty")
 	stepID := step.ID
 	t.Logf("  ✓ Created step: %s", stepID)
 
 	// List steps
 	t.Log("  - Listing steps")
-	steps, err := c.Steps.List(ctx, cardID)
-	require.NoError(t, err, "Failed to list steps")
+	var steps []fizzy.Step
+	fizzJSON(t, &steps, "steps", "list", card)
 	assert.GreaterOrEqual(t, len(steps), 1, "Should have at least one step")
 	t.Logf("  ✓ Listed %d step(s)", len(steps))
 
 	// Get step
 	t.Log("  - Getting step")
-	fetchedStep, err := c.Steps.Get(ctx, cardID, stepID)
-	require.NoError(t, err, "Failed to get step")
+	var fetchedStep fizzy.Step
+	fizzJSON(t, &fetchedStep, "steps", "get", card, stepID)
 	assert.Equal(t, stepID, fetchedStep.ID, "Step ID mismatch")
 	t.Log("  ✓ Step retrieved")
 
 	// Update step - mark as completed
 	t.Log("  - Updating step (marking complete)")
-	completedTrue := true
-	updatedStep, err := c.Steps.Update(ctx, cardID, stepID, &fizzy.StepUpdateOptions{
-		Completed: &completedTrue,
-	})
-	require.NoError(t, err, "Failed to update step")
+	var updatedStep fizzy.Step
+	fizzJSON(t, &updatedStep, "steps", "update", card, stepID, "--completed=true")
 	assert.True(t, updatedStep.Completed, "Step should be completed")
 	t.Log("  ✓ Step marked complete")
 
 	// Update step content
 	t.Log("  - Updating step content")
 	newContent := "Updated checklist item"
-	updatedStep, err = c.Steps.Update(ctx, cardID, stepID, &fizzy.StepUpdateOptions{
-		Content: &newContent,
-	})
-	require.NoError(t, err, "Failed to update step content")
+	fizzJSON(t, &updatedStep, "steps", "update", card, stepID, "--content="+newContent)
 	assert.Equal(t, newContent, updatedStep.Content, "Step content not updated")
 	t.Log("  ✓ Step content updated")
 
 	// Delete step
 	t.Log("  - Deleting step")
-	err = c.Steps.Delete(ctx, cardID, stepID)
-	require.NoError(t, err, "Failed to delete step")
+	assert.Contains(t, mustFizz(t, "steps", "delete", card, stepID), "deleted successfully")
 	t.Log("  ✓ Step deleted")
 
 	t.Log("✓ Steps tests passed")
 }
 
-func testColumns(t *testing.T, ctx context.Context, c *client.Client, boardID string) {
-	t.Log("Testing columns...")
-
-	// List columns
-	t.Log("  - Listing columns")
-	initialColumns, err := c.Columns.List(ctx, boardID)
-	require.NoError(t, err, "Failed to list columns")
-	initialCount := len(initialColumns)
-	t.Logf("  ✓ Found %d existing column(s)", initialCount)
-
-	// Create column
-	t.Log("  - Creating column")
-	column, err := c.Columns.Create(ctx, boardID, &fizzy.ColumnCreateOptions{
-		Name: "Test Column",
-	})
-	require.NoError(t, err, "Failed to create column")
-	require.NotEmpty(t, column.ID, "Column ID should not be empty")
-	columnID := column.ID
-	t.Logf("  ✓ Created column: %s", columnID)
-
-	// Get column
-	t.Log("  - Getting column")
-	fetchedColumn, err := c.Columns.Get(ctx, boardID, columnID)
-	require.NoError(t, err, "Failed to get column")
-	assert.Equal(t, columnID, fetchedColumn.ID, "Column ID mismatch")
-	t.Log("  ✓ Column retrieved")
-
-	// Update column
-	t.Log("  - Updating column")
-	newName := "Updated Column Name"
-	updatedColumn, err := c.Columns.Update(ctx, boardID, columnID, &fizzy.ColumnUpdateOptions{
-		Name: &newName,
-	})
-	require.NoError(t, err, "Failed to update column")
-	assert.Equal(t, newName, updatedColumn.Name, "Column name not updated")
-	t.Log("  ✓ Column updated")
-
-	// Delete column
-	t.Log("  - Deleting column")
-	err = c.Columns.Delete(ctx, boardID, columnID)
-	require.NoError(t, err, "Failed to delete column")
-	t.Log("  ✓ Column deleted")
-
-	// Verify column deleted
-	finalColumns, err := c.Columns.List(ctx, boardID)
-	require.NoError(t, err, "Failed to list columns after delete")
-	assert.Equal(t, initialCount, len(finalColumns), "Column count should be back to initial")
-	t.Log("  ✓ Column deletion verified")
-
-	t.Log("✓ Columns tests passed")
-}
-
-func testTags(t *testing.T, ctx context.Context, c *client.Client) {
+func testTags(t *testing.T) {
 	t.Log("Testing tags...")
 
 	// List tags
 	t.Log("  - Listing tags")
-	initialTags, err := c.Tags.List(ctx)
-	require.NoError(t, err, "Failed to list tags")
+	var initialTags []fizzy.Tag
+	fizzJSON(t, &initialTags, "tags", "list")
 	initialCount := len(initialTags)
 	t.Logf("  ✓ Found %d existing tag(s)", initialCount)
 
 	// Create tag
 	t.Log("  - Creating tag")
-	color := "#FF5733"
-	tag, err := c.Tags.Create(ctx, &fizzy.TagCreateOptions{
-		Name:  "test-tag",
-		Color: &color,
-	})
-	require.NoError(t, err, "Failed to create tag")
+	var tag fizzy.Tag
+	fizzJSON(t, &tag, "tags", "create", "--name=test-tag", "--color=#FF5733")
 	require.NotEmpty(t, tag.ID, "Tag ID should not be empty")
 	assert.Equal(t, "test-tag", tag.Name, "Tag name mismatch")
 	t.Logf("  ✓ Created tag: %s", tag.ID)
 
 	// Verify tag in list
-	tags, err := c.Tags.List(ctx)
-	require.NoError(t, err, "Failed to list tags after creation")
+	var tags []fizzy.Tag
+	fizzJSON(t, &tags, "tags", "list")
 	assert.Equal(t, initialCount+1, len(tags), "Tag count should increase by 1")
 	t.Log("  ✓ Tag appears in list")
 
 	t.Log("✓ Tags tests passed")
 }
 
-func testUsers(t *testing.T, ctx context.Context, c *client.Client) {
+func testUsers(t *testing.T) {
 	t.Log("Testing users...")
 
 	// List users
 	t.Log("  - Listing users")
-	users, err := c.Users.List(ctx)
-	require.NoError(t, err, "Failed to list users")
+	var users []fizzy.User
+	fizzJSON(t, &users, "users", "list")
 	assert.NotEmpty(t, users, "Should have at least one user")
 	t.Logf("  ✓ Listed %d user(s)", len(users))
 
@@ -499,13 +497,13 @@ func testUsers(t *testing.T, ctx context.Context, c *client.Client) {
 	t.Log("✓ Users tests passed")
 }
 
-func testNotifications(t *testing.T, ctx context.Context, c *client.Client) {
+func testNotifications(t *testing.T) {
 	t.Log("Testing notifications...")
 
 	// List notifications
 	t.Log("  - Listing notifications")
-	notifications, err := c.Notifications.List(ctx)
-	require.NoError(t, err, "Failed to list notifications")
+	var notifications []fizzy.Notification
+	fizzJSON(t, &notifications, "notifications", "list")
 	t.Logf("  ✓ Listed %d notification(s)", len(notifications))
 
 	// If there are notifications, test read operations
@@ -513,30 +511,45 @@ func testNotifications(t *testing.T, ctx context.Context, c *client.Client) {
 		notif := notifications[0]
 		t.Logf("  - Testing notification operations on ID: %s", notif.ID)
 
-		// Mark as read
-		t.Log("    - Marking notification as read")
-		err = c.Notifications.Read(ctx, notif.ID)
-		require.NoError(t, err, "Failed to mark notification as read")
+		assert.Contains(t, mustFizz(t, "notifications", "read", notif.ID), "marked as read")
 		t.Log("    ✓ Notification marked read")
 
-		// Mark as unread
-		t.Log("    - Marking notification as unread")
-		err = c.Notifications.Unread(ctx, notif.ID)
-		require.NoError(t, err, "Failed to mark notification as unread")
+		assert.Contains(t, mustFizz(t, "notifications", "unread", notif.ID), "marked as unread")
 		t.Log("    ✓ Notification marked unread")
 	} else {
 		t.Log("  ℹ No notifications to test read/unread operations")
 	}
 
+	assert.Contains(t, mustFizz(t, "notifications", "read-all"), "All notifications marked as read")
+	t.Log("  ✓ All notifications marked read")
+
 	t.Log("✓ Notifications tests passed")
 }
 
-// TestMain runs before all tests
+func testUploads(t *testing.T) {
+	t.Log("Testing uploads...")
+
+	path := filepath.Join(t.TempDir(), "integration.txt")
+	require.NoError(t, os.WriteFile(path, []byte("fizz integration upload\n"), 0o644))
+
+	out := mustFizz(t, "uploads", "create", path)
+	assert.Contains(t, out, "File uploaded successfully")
+	t.Log("  ✓ File uploaded")
+
+	t.Log("✓ Uploads tests passed")
+}
+
+// TestMain selects between replaying the recorded cassette (the default)
+// and a live run against the API
 func TestMain(m *testing.M) {
-	// Verify environment variables are set
-	if os.Getenv("FIZZY_TOKEN") == "" || os.Getenv("FIZZY_ACCOUNT") == "" {
+	if os.Getenv("FIZZ_RECORD") == "" && os.Getenv("FIZZ_REPLAY") == "" {
+		os.Setenv("FIZZ_REPLAY", cassettePath)
+	}
+
+	// Verify environment variables are set for live runs
+	if os.Getenv("FIZZ_REPLAY") == "" && (os.Getenv("FIZZY_TOKEN") == "" || os.Getenv("FIZZY_ACCOUNT") == "") {
 		fmt.Println("ERROR: FIZZY_TOKEN and FIZZY_ACCOUNT environment variables must be set")
-		fmt.Println("Set them before running integration tests:")
+		fmt.Println("Set them before recording integration tests:")
 		fmt.Println("  export FIZZY_TOKEN=your_token")
 		fmt.Println("  export FIZZY_ACCOUNT=your_account")
 		os.Exit(1)